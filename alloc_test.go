@@ -112,3 +112,45 @@ func TestAllocsFillBytes(t *testing.T) {
 		t.Errorf("FillBytes allocated %v times, expected 0", allocs)
 	}
 }
+
+func TestAllocsRandomizerStringPooled(t *testing.T) {
+	engine := fastrand.NewEngine()
+	payload := "hello world {RAND;16;ABL} test {RAND;8;DIGIT} end"
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = engine.RandomizerString(payload)
+	})
+
+	if allocs > 1 {
+		t.Errorf("RandomizerString allocated %v times, expected <= 1 with pooled buffers", allocs)
+	}
+}
+
+func TestAllocsRandomizerAppendEncodedInput(t *testing.T) {
+	engine := fastrand.NewEngine()
+	payload := []byte("id=%7BRAND%3B8%3BDIGIT%7D&name=&lbrace;RAND&semi;6&semi;ABL&rbrace;")
+	dst := make([]byte, 0, 512)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		dst = engine.RandomizerAppend(dst[:0], payload)
+	})
+
+	if allocs > 0 {
+		t.Errorf("RandomizerAppend with encoded input allocated %v times, expected 0", allocs)
+	}
+}
+
+func TestAllocsRandomizerAppendCharClass(t *testing.T) {
+	engine := fastrand.NewEngine()
+	payload := "token={RAND;12;[a-f0-9_-]}"
+	dst := make([]byte, 0, 64)
+	dst = engine.RandomizerAppendString(dst, payload)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		dst = engine.RandomizerAppendString(dst[:0], payload)
+	})
+
+	if allocs > 0 {
+		t.Errorf("RandomizerAppendString with an inline charset allocated %v times, expected 0", allocs)
+	}
+}
@@ -0,0 +1,69 @@
+package fastrand
+
+import (
+	"bytes"
+	"fmt"
+)
+
+const (
+	// base32Std is the RFC 4648 base32 alphabet.
+	base32Std = "ABCDEFGHIJKLMNOPQRSTUVWXYZ234567"
+	// base32Crockford is Crockford's base32 alphabet, which leaves out I,
+	// L, O and U so codes read aloud or typed by hand stay unambiguous.
+	base32Crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+)
+
+// base32Alphabet returns the alphabet a BASE32 keyword's alphabet
+// parameter names, std by default, and false for an unknown name.
+func base32Alphabet(params []byte) (string, bool) {
+	v, ok := keywordParam(params, "alphabet")
+	switch {
+	case !ok, bytes.EqualFold(v, []byte("std")), bytes.EqualFold(v, []byte("standard")):
+		return base32Std, true
+	case bytes.EqualFold(v, []byte("crockford")):
+		return base32Crockford, true
+	}
+	return base32Std, false
+}
+
+// appendBase32 appends byteLength random bytes encoded in base32 with
+// alphabet, padded with '=' to a multiple of eight characters when pad is
+// set, so the value decodes like a real secret of that many bytes.
+func (e *FastEngine) appendBase32(out *[]byte, byteLength int, alphabet string, pad bool) {
+	for byteLength > 0 {
+		n := min(byteLength, 5)
+		// Five bytes are 40 bits, eight characters of five bits each; a
+		// final shorter group keeps its leading n bytes.
+		group := e.next() & (1<<40 - 1) &^ (1<<(8*(5-n)) - 1)
+		chars := (8*n + 4) / 5
+		for i := range chars {
+			*out = append(*out, alphabet[group>>(35-5*i)&31])
+		}
+		if pad {
+			for range 8 - chars {
+				*out = append(*out, '=')
+			}
+		}
+		byteLength -= n
+	}
+}
+
+// expandBase32 appends the value of a BASE32 keyword.
+func (e *FastEngine) expandBase32(out *[]byte, length int, kw *keywordSpec) {
+	if length <= 0 {
+		length = e.defaultLength
+	}
+	alphabet, _ := base32Alphabet(kw.params)
+	v, _ := keywordParam(kw.params, "pad")
+	e.appendBase32(out, length, alphabet, paramBool(v))
+}
+
+// checkBase32Params returns why the alphabet parameter of a BASE32 keyword
+// is unknown, or "".
+func checkBase32Params(params []byte) string {
+	if _, ok := base32Alphabet(params); !ok {
+		v, _ := keywordParam(params, "alphabet")
+		return fmt.Sprintf("unknown alphabet %q: want std or crockford", v)
+	}
+	return ""
+}
@@ -0,0 +1,70 @@
+package fastrand_test
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBase32Keyword(t *testing.T) {
+	for n := 1; n <= 40; n++ {
+		tag := "{RAND;" + itoa(n) + ";BASE32}"
+		out := fastrand.RandomizerString(tag)
+		require.Regexp(t, `^[A-Z2-7]+$`, out, tag)
+		decoded, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(out)
+		require.NoError(t, err, tag)
+		assert.Len(t, decoded, n, tag)
+
+		padded := fastrand.RandomizerString("{RAND;" + itoa(n) + ";BASE32(pad=true)}")
+		assert.Zero(t, len(padded)%8, padded)
+		decoded, err = base32.StdEncoding.DecodeString(padded)
+		require.NoError(t, err, padded)
+		assert.Len(t, decoded, n)
+	}
+	assert.Len(t, fastrand.RandomizerString("{RAND;20;BASE32}"), 32, "a 160-bit TOTP secret")
+	assert.Len(t, fastrand.RandomizerString("{RAND;base32}"), 26, "the default length is 16 bytes")
+}
+
+func TestBase32KeywordCrockford(t *testing.T) {
+	crockford := base32.NewEncoding("0123456789ABCDEFGHJKMNPQRSTVWXYZ").WithPadding(base32.NoPadding)
+	seen := map[rune]bool{}
+	for i := 0; i < 200; i++ {
+		out := fastrand.RandomizerString("{RAND;10;BASE32(alphabet=crockford)}")
+		require.Len(t, out, 16)
+		require.False(t, strings.ContainsAny(out, "ILOU"), out)
+		_, err := crockford.DecodeString(out)
+		require.NoError(t, err, out)
+		for _, c := range out {
+			seen[c] = true
+		}
+	}
+	assert.Len(t, seen, 32, "every character of the alphabet is used")
+	assert.Regexp(t, `^[0-9a-hjkmnp-tv-z]{16}$`, fastrand.RandomizerString("{RAND;10;BASE32(alphabet=Crockford);lower}"))
+}
+
+func TestBase32KeywordStrict(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithStrictParsing(true))
+	_, err := engine.RandomizerErr([]byte("{RAND;BASE32(alphabet=hex)}"))
+	assert.ErrorContains(t, err, `unknown alphabet "hex"`)
+	_, err = engine.RandomizerErr([]byte("{RAND;BASE32(alphabet=std,pad=true,len=20)}"))
+	assert.NoError(t, err)
+
+	bits, err := fastrand.TagEntropy("{RAND;20;BASE32}")
+	require.NoError(t, err)
+	assert.Equal(t, 160.0, bits)
+}
+
+func TestAllocsBase32Keyword(t *testing.T) {
+	engine := fastrand.NewEngine()
+	payload := []byte("secret={RAND;20;BASE32(pad=true)}")
+	dst := make([]byte, 0, 64)
+	dst = engine.RandomizerAppend(dst, payload)
+	allocs := testing.AllocsPerRun(100, func() {
+		dst = engine.RandomizerAppend(dst[:0], payload)
+	})
+	assert.Zero(t, allocs)
+}
@@ -0,0 +1,153 @@
+package fastrand
+
+import (
+	"bytes"
+	"encoding/base64"
+)
+
+var (
+	base64Open  = []byte("{RAND-BASE64")
+	base64Close = []byte("{/RAND-BASE64}")
+	base64Raw   = []byte(";raw")
+)
+
+// minBase64Run is the length of the shortest base64 run that can hold a
+// tag: "{RAND}" encodes to eight characters.
+const minBase64Run = 8
+
+func isBase64Char(c byte) bool {
+	return c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '+' || c == '/'
+}
+
+// nextBase64Blob returns the bounds of the first run of standard base64 in
+// payload at or after cursor whose decoded content holds a tag, leaving the
+// content in decoded, and whether the run is unpadded. start is -1 when
+// there is none.
+func (e *FastEngine) nextBase64Blob(payload []byte, cursor int, decoded *[]byte) (start, end int, raw bool) {
+	for i := cursor; i < len(payload); {
+		if !isBase64Char(payload[i]) {
+			i++
+			continue
+		}
+		start, end = i, i
+		for end < len(payload) && isBase64Char(payload[end]) {
+			end++
+		}
+		n := end - start
+		for pad := 0; pad < 2 && end < len(payload) && payload[end] == '='; pad++ {
+			end++
+		}
+		i = end
+		enc, unpadded := base64.StdEncoding, false
+		switch {
+		case end-start < minBase64Run:
+			continue
+		case end-start > n || n%4 == 0:
+			if (end-start)%4 != 0 {
+				continue
+			}
+		case n%4 == 1:
+			continue
+		default:
+			enc, unpadded = base64.RawStdEncoding, true
+		}
+		var err error
+		if *decoded, err = enc.AppendDecode((*decoded)[:0], payload[start:end]); err != nil {
+			continue
+		}
+		if bytes.Contains(*decoded, startTag) || bytes.Contains(*decoded, refTag) || e.hasDialectTags(*decoded) {
+			return start, end, unpadded
+		}
+	}
+	return -1, -1, false
+}
+
+// unwrapBase64 rewrites every base64 run in payload that decodes to text
+// with tags into a {RAND-BASE64} block holding the decoded, normalized text,
+// which expandBase64 encodes again after expanding it. Like normalized, it
+// returns the result in a pooled buffer when it rewrote anything, releasing
+// scratch, which may hold payload.
+func (e *FastEngine) unwrapBase64(payload []byte, scratch *[]byte) ([]byte, *[]byte) {
+	decoded := e.bufferPool.Get(len(payload))
+	var rewritten *[]byte
+	cursor := 0
+	for {
+		start, end, raw := e.nextBase64Blob(payload, cursor, decoded)
+		if start == -1 {
+			break
+		}
+		if rewritten == nil {
+			rewritten = e.bufferPool.Get(2 * len(payload))
+			*rewritten = (*rewritten)[:0]
+		}
+		*rewritten = append(*rewritten, payload[cursor:start]...)
+		*rewritten = append(*rewritten, base64Open...)
+		if raw {
+			*rewritten = append(*rewritten, base64Raw...)
+		}
+		*rewritten = append(*rewritten, endTag)
+		body := *decoded
+		if len(e.dialects) > 0 {
+			body = e.rewriteDialects(nil, body)
+		}
+		*rewritten = normalizeInto(*rewritten, body, e.inputEncoding)
+		*rewritten = append(*rewritten, base64Close...)
+		cursor = end
+	}
+	e.bufferPool.Put(decoded)
+	if rewritten == nil {
+		return payload, scratch
+	}
+	*rewritten = append(*rewritten, payload[cursor:]...)
+	e.release(scratch)
+	return *rewritten, rewritten
+}
+
+// parseBase64Block parses a {RAND-BASE64} tag, which excludes the closing
+// brace, and reports whether its content is encoded without padding.
+func parseBase64Block(tag []byte) (raw, ok bool) {
+	switch rest := tag[len(base64Open):]; {
+	case len(rest) == 0:
+		return false, true
+	case bytes.Equal(rest, base64Raw):
+		return true, true
+	}
+	return false, false
+}
+
+// expandBase64 expands a base64 block whose opening tag spans
+// payload[start:cursor] and writes the result base64-encoded as template
+// text. It returns the offset after the block, or false when the tag is
+// malformed or unclosed and must be copied literally.
+func (e *FastEngine) expandBase64(payload []byte, out *[]byte, start, cursor int, x *expansion) (int, bool) {
+	raw, ok := parseBase64Block(payload[start : cursor-1])
+	if !ok {
+		return 0, false
+	}
+	bodyEnd := bytes.Index(payload[cursor:], base64Close)
+	if bodyEnd == -1 {
+		return 0, false
+	}
+	from := len(*out)
+	x.wrapped++
+	e.expandPayload(payload[cursor:cursor+bodyEnd], out, x)
+	x.wrapped--
+	e.encodeBase64From(out, from, raw, x)
+	return cursor + bodyEnd + len(base64Close), true
+}
+
+// encodeBase64From replaces the output written since start by its base64
+// encoding, written as template text.
+func (e *FastEngine) encodeBase64From(out *[]byte, start int, raw bool, x *expansion) {
+	enc := base64.StdEncoding
+	if raw {
+		enc = base64.RawStdEncoding
+	}
+	n := len(*out) - start
+	scratch := e.bufferPool.Get(n + enc.EncodedLen(n))
+	*scratch = append((*scratch)[:0], (*out)[start:]...)
+	*scratch = enc.AppendEncode(*scratch, (*scratch)[:n])
+	*out = (*out)[:start]
+	e.writeLiteral(out, (*scratch)[n:], x)
+	e.bufferPool.Put(scratch)
+}
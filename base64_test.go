@@ -0,0 +1,70 @@
+package fastrand_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func b64(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) }
+
+func decodeField(t *testing.T, out, field string) string {
+	t.Helper()
+	var doc map[string]string
+	require.NoError(t, json.Unmarshal([]byte(out), &doc), out)
+	raw, err := base64.StdEncoding.DecodeString(doc[field])
+	require.NoError(t, err, doc[field])
+	return string(raw)
+}
+
+func TestInputEncodingBase64(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithInputEncoding(fastrand.RandomizerEncodingBase64))
+	payload := `{"user":"{RAND;4;DIGIT}","blob":"` + b64(`{"id":"{RAND;8;HEX}","name":"fixed"}`) + `","plain":"` + b64("no tags here") + `"}`
+	for i := 0; i < 20; i++ {
+		out := engine.RandomizerString(payload)
+		assert.Regexp(t, `"user":"[0-9]{4}"`, out)
+		assert.Regexp(t, `^\{"id":"[0-9a-f]{16}","name":"fixed"\}$`, decodeField(t, out, "blob"))
+		assert.Equal(t, "no tags here", decodeField(t, out, "plain"))
+	}
+	blob := b64(`{"id":"{RAND;8;HEX}","name":"fixed"}`)
+	assert.Contains(t, fastrand.RandomizerString(payload), blob, "base64 is only decoded when enabled")
+}
+
+func TestInputEncodingBase64Unpadded(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithInputEncoding(fastrand.RandomizerEncodingBase64))
+	blob := base64.RawStdEncoding.EncodeToString([]byte("k={RAND;5;ABL}"))
+	out := engine.RandomizerString("x " + blob + " y")
+	m := regexp.MustCompile(`^x (\S+) y$`).FindStringSubmatch(out)
+	require.NotNil(t, m, out)
+	raw, err := base64.RawStdEncoding.DecodeString(m[1])
+	require.NoError(t, err)
+	assert.Regexp(t, `^k=[a-z]{5}$`, string(raw))
+}
+
+func TestInputEncodingBase64Variables(t *testing.T) {
+	engine := fastrand.NewEngine(
+		fastrand.WithInputEncoding(fastrand.RandomizerEncodingBase64|fastrand.RandomizerEncodingURL),
+		fastrand.WithOutputEncoding(fastrand.RandomizerEncodingJSON),
+	)
+	payload := `{"id":"{RAND;6;DIGIT;VAR=id}","b":"` + b64(`"{REF;id}"`) + `"}`
+	out := engine.RandomizerString(payload)
+	var doc map[string]string
+	require.NoError(t, json.Unmarshal([]byte(out), &doc))
+	assert.Equal(t, `"`+doc["id"]+`"`, decodeField(t, out, "b"), "values inside blobs are not escaped for the outer document")
+}
+
+func TestInputEncodingBase64Template(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithInputEncoding(fastrand.RandomizerEncodingBase64), fastrand.WithStrictParsing(true))
+	tmpl, err := engine.Compile([]byte(`{"blob":"` + b64("{RAND;3;DIGIT}-{RAND;3;ABU}") + `"}`))
+	require.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		assert.Regexp(t, `^[0-9]{3}-[A-Z]{3}$`, decodeField(t, tmpl.ExecuteString(), "blob"))
+	}
+	require.NoError(t, engine.Validate([]byte(b64("{RAND;3;DIGIT}"))))
+	assert.Error(t, engine.Validate([]byte(b64("{RAND;3;NOPE}"))), "tags inside blobs are checked")
+}
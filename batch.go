@@ -0,0 +1,54 @@
+package fastrand
+
+import (
+	"bytes"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// RandomizerBatch expands payloads with the default engine. See
+// FastEngine.RandomizerBatch.
+func RandomizerBatch(payloads [][]byte, parallelism int) [][]byte {
+	return defaultEngine.Load().RandomizerBatch(payloads, parallelism)
+}
+
+// RandomizerBatch expands every payload on up to parallelism goroutines and
+// returns the results in the order of payloads. A parallelism of 0 or less
+// uses GOMAXPROCS. Each worker expands into one pooled scratch buffer and
+// copies the result out at its final size, so the batch allocates little
+// beyond the results themselves. As with Randomizer, a payload without tags
+// may be returned as is.
+func (e *FastEngine) RandomizerBatch(payloads [][]byte, parallelism int) [][]byte {
+	results := make([][]byte, len(payloads))
+	if parallelism <= 0 {
+		parallelism = runtime.GOMAXPROCS(0)
+	}
+	parallelism = min(parallelism, len(payloads))
+
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for range parallelism {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			buf := e.bufferPool.Get(512)
+			defer e.bufferPool.Put(buf)
+			for {
+				i := int(next.Add(1) - 1)
+				if i >= len(payloads) {
+					return
+				}
+				payload := payloads[i]
+				if !bytes.ContainsAny(payload, tagChars) && !e.transformsPayload() {
+					results[i] = payload
+					continue
+				}
+				*buf = e.RandomizerAppend((*buf)[:0], payload)
+				results[i] = bytes.Clone(*buf)
+			}
+		}()
+	}
+	wg.Wait()
+	return results
+}
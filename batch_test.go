@@ -0,0 +1,46 @@
+package fastrand_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRandomizerBatch(t *testing.T) {
+	payloads := make([][]byte, 1000)
+	for i := range payloads {
+		payloads[i] = []byte(fmt.Sprintf("%d:{RAND;%d;DIGIT}", i, i%50+1))
+	}
+	for _, parallelism := range []int{0, 1, 4, 5000} {
+		t.Run(fmt.Sprint(parallelism), func(t *testing.T) {
+			results := fastrand.RandomizerBatch(payloads, parallelism)
+			require.Len(t, results, len(payloads))
+			for i, out := range results {
+				prefix := fmt.Sprintf("%d:", i)
+				require.Len(t, out, len(prefix)+i%50+1)
+				assert.Equal(t, prefix, string(out[:len(prefix)]))
+			}
+		})
+	}
+}
+
+func TestRandomizerBatch_IndependentResults(t *testing.T) {
+	results := fastrand.NewEngine().RandomizerBatch([][]byte{[]byte("{RAND;4;DIGIT}"), []byte("{RAND;4;HEX}")}, 1)
+	assert.Regexp(t, "^[0-9]{4}$", string(results[0]), "results do not share the worker buffer")
+	assert.Regexp(t, "^[0-9a-f]{8}$", string(results[1]))
+}
+
+func TestRandomizerBatch_Empty(t *testing.T) {
+	assert.Empty(t, fastrand.RandomizerBatch(nil, 4))
+}
+
+func TestRandomizerBatch_Deterministic(t *testing.T) {
+	payloads := [][]byte{[]byte("{RAND;8;HEX}"), []byte("plain"), []byte("{RAND;UUID}")}
+	a := fastrand.NewEngine(fastrand.WithSeed(7)).RandomizerBatch(payloads, 1)
+	b := fastrand.NewEngine(fastrand.WithSeed(7)).RandomizerBatch(payloads, 1)
+	assert.Equal(t, a, b, "a single worker expands in order")
+	assert.Equal(t, "plain", string(a[1]))
+}
@@ -0,0 +1,73 @@
+package fastrand
+
+import "sync"
+
+// maxPooledBuffer is the largest buffer the default pool keeps; bigger
+// buffers are left to the garbage collector so one huge expansion does not
+// pin memory.
+const maxPooledBuffer = 64 << 10
+
+// BufferPool supplies the scratch buffers an engine uses while expanding
+// templates. Get returns an empty buffer, ideally with at least size bytes
+// of capacity; Put hands it back once the engine no longer references it.
+// Implementations must be safe for concurrent use.
+type BufferPool interface {
+	Get(size int) *[]byte
+	Put(buf *[]byte)
+}
+
+// syncBufferPool is the default BufferPool, backed by a sync.Pool.
+type syncBufferPool struct {
+	pool sync.Pool
+}
+
+func (p *syncBufferPool) Get(size int) *[]byte {
+	buf, _ := p.pool.Get().(*[]byte)
+	if buf == nil {
+		b := make([]byte, 0, size)
+		return &b
+	}
+	if cap(*buf) < size {
+		*buf = make([]byte, 0, size)
+	}
+	*buf = (*buf)[:0]
+	return buf
+}
+
+func (p *syncBufferPool) Put(buf *[]byte) {
+	if cap(*buf) > maxPooledBuffer {
+		return
+	}
+	p.pool.Put(buf)
+}
+
+var defaultBufferPool BufferPool = &syncBufferPool{}
+
+// sizeHint estimates the size of a pooled scratch buffer for a payload of
+// payloadLen bytes. With a pool from WithBufferPool the engine keeps a
+// running average of its recent expansion sizes, so repeated expansions of
+// large, uniform templates get a buffer that fits the first time. Slices
+// returned to callers are sized from the payload alone, so they do not
+// carry the capacity of other expansions.
+func (e *FastEngine) sizeHint(payloadLen int) int {
+	return max(payloadLen+512, int(e.sizeEstimate.Load()))
+}
+
+// recordSize folds the size of an expansion into the running average. Each
+// size is capped at maxPooledBuffer and weighs a quarter, so one outsized
+// expansion cannot inflate the buffers of the calls after it. Concurrent
+// updates may overwrite each other, which only costs the hint some accuracy.
+// The default pool keeps the buffers it hands back at their grown capacity,
+// so the engine skips the shared update when it uses that pool.
+func (e *FastEngine) recordSize(n int) {
+	if e.bufferPool == defaultBufferPool {
+		return
+	}
+	n = min(n, maxPooledBuffer)
+	avg := e.sizeEstimate.Load()
+	if avg == 0 {
+		e.sizeEstimate.Store(int64(n))
+		return
+	}
+	e.sizeEstimate.Store(avg + (int64(n)-avg)/4)
+}
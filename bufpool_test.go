@@ -0,0 +1,98 @@
+package fastrand_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingPool struct {
+	mu       sync.Mutex
+	gets     int
+	puts     int
+	lastSize int
+}
+
+func (p *countingPool) Get(size int) *[]byte {
+	p.mu.Lock()
+	p.gets++
+	p.lastSize = size
+	p.mu.Unlock()
+	b := make([]byte, 0, size)
+	return &b
+}
+
+func (p *countingPool) Put(*[]byte) {
+	p.mu.Lock()
+	p.puts++
+	p.mu.Unlock()
+}
+
+func TestWithBufferPool(t *testing.T) {
+	pool := &countingPool{}
+	engine := fastrand.NewEngine(fastrand.WithBufferPool(pool))
+
+	assert.Regexp(t, `^id=[0-9]{8}$`, engine.RandomizerString("id={RAND;8;DIGIT}"))
+	assert.Regexp(t, `^id=[0-9]{8}$`, string(engine.RandomizerAppend(nil, []byte("id=%7BRAND%3B8%3BDIGIT%7D"))))
+	assert.Regexp(t, `^id=[0-9]{8}$`, string(engine.Randomizer([]byte("id=&lbrace;RAND&semi;8&semi;DIGIT&rbrace;"))))
+	assert.Equal(t, 3, pool.gets)
+	assert.Equal(t, pool.gets, pool.puts, "every buffer should be returned")
+
+	assert.Equal(t, "plain", engine.RandomizerString("plain"))
+	assert.Equal(t, 3, pool.gets, "payloads without tags bypass the pool")
+}
+
+func TestBufferPoolSizeHint(t *testing.T) {
+	pool := &countingPool{}
+	engine := fastrand.NewEngine(fastrand.WithBufferPool(pool), fastrand.WithMaxLength(999))
+
+	tmpl := "{RAND;900;ABL}{RAND;900;ABL}{RAND;900;ABL}{RAND;900;ABL}"
+	_ = engine.RandomizerString(tmpl)
+	_ = engine.RandomizerString(tmpl)
+	assert.GreaterOrEqual(t, pool.lastSize, 3600, "the second expansion should be pre-sized from the first")
+}
+
+func TestBufferPoolSizeHintOutlier(t *testing.T) {
+	pool := &countingPool{}
+	engine := fastrand.NewEngine(fastrand.WithBufferPool(pool), fastrand.WithMaxLength(5000))
+
+	small := "{RAND;100;ABL}"
+	for i := 0; i < 4; i++ {
+		_ = engine.RandomizerString(small)
+	}
+	_ = engine.RandomizerString("{RAND-REPEAT;50}{RAND;4000;ABL}{/RAND-REPEAT}")
+	_ = engine.RandomizerString(small)
+	assert.Less(t, pool.lastSize, 64<<10, "one large expansion should not size the next buffer to match")
+
+	for i := 0; i < 20; i++ {
+		_ = engine.RandomizerString(small)
+	}
+	assert.Less(t, pool.lastSize, 1024, "the hint should decay back after small expansions")
+}
+
+func TestBufferPoolSizeHintCallerSlices(t *testing.T) {
+	pool := &countingPool{}
+	engine := fastrand.NewEngine(fastrand.WithBufferPool(pool), fastrand.WithMaxLength(5000))
+	for i := 0; i < 4; i++ {
+		_ = engine.RandomizerString("{RAND-REPEAT;10}{RAND;4000;ABL}{/RAND-REPEAT}")
+	}
+	require.Greater(t, pool.lastSize, 10000)
+
+	tmpl, err := engine.Compile([]byte("{RAND;8;DIGIT}"))
+	require.NoError(t, err)
+	for _, out := range [][]byte{engine.Randomizer([]byte("{RAND;8;DIGIT}")), tmpl.Execute()} {
+		assert.Len(t, out, 8)
+		assert.Less(t, cap(out), 1024, "returned slices should not take the pooled size hint")
+	}
+}
+
+func TestBufferPoolReset(t *testing.T) {
+	pool := &countingPool{}
+	engine := fastrand.NewEngine(fastrand.WithBufferPool(pool))
+	engine.Reset()
+	_ = engine.RandomizerString("{RAND;8;DIGIT}")
+	assert.Zero(t, pool.gets, "Reset restores the default pool")
+}
@@ -0,0 +1,155 @@
+package fastrand
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"time"
+)
+
+// cardBrand is a card network whose numbers CC generates and the test card
+// numbers its issuers publish for integration testing. CC only ever picks
+// from these lists, so a generated number can never belong to a live
+// account.
+type cardBrand struct {
+	name string
+	pans []string
+}
+
+var cardBrands = []cardBrand{
+	{"VISA", []string{"4111111111111111", "4242424242424242", "4012888888881881"}},
+	{"MASTERCARD", []string{"5555555555554444", "5105105105105100", "2223003122003222"}},
+	{"AMEX", []string{"378282246310005", "371449635398431", "378734493671000"}},
+}
+
+const (
+	// cardSep separates the brands of a CC keyword.
+	cardSep = '|'
+	// cardExpiryMonths is how many months ahead CCEXP expiry dates fall.
+	cardExpiryMonths = 60
+)
+
+// CardNumber returns a random published test card number for one of brands
+// (VISA, MASTERCARD or MC, AMEX), or for any of them when none is known.
+func CardNumber(brands ...string) string {
+	var valid []int
+	for _, name := range brands {
+		if i, ok := cardBrandIndex(s2b(name)); ok {
+			valid = append(valid, i)
+		}
+	}
+	b := cardBrands[int(uint64N(fastUint64, uint64(len(cardBrands))))]
+	if len(valid) > 0 {
+		b = cardBrands[valid[int(uint64N(fastUint64, uint64(len(valid))))]]
+	}
+	return pickWord(fastUint64, b.pans)
+}
+
+// cardBrandIndex returns the index in cardBrands of a brand name, matched
+// case-insensitively; MC stands for MASTERCARD.
+func cardBrandIndex(name []byte) (int, bool) {
+	if bytes.EqualFold(name, []byte("MC")) {
+		name = []byte("MASTERCARD")
+	}
+	for i, b := range cardBrands {
+		if bytes.EqualFold(name, s2b(b.name)) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// cardArgBrands returns a bit set of the brands the '|'-separated argument
+// of a CC keyword names, or of every brand when it names none.
+func cardArgBrands(arg []byte) (set uint8, count int) {
+	for len(arg) > 0 {
+		var item []byte
+		item, arg, _ = bytes.Cut(arg, []byte{cardSep})
+		if i, ok := cardBrandIndex(item); ok && set&(1<<i) == 0 {
+			set |= 1 << i
+			count++
+		}
+	}
+	if count == 0 {
+		return 1<<len(cardBrands) - 1, len(cardBrands)
+	}
+	return set, count
+}
+
+// appendCard appends a test card number of one of the brands the argument of
+// a CC keyword names, such as VISA|AMEX.
+func (e *FastEngine) appendCard(out *[]byte, arg []byte) {
+	set, count := cardArgBrands(arg)
+	r := int(uint64N(e.next, uint64(count)))
+	for i, b := range cardBrands {
+		if set&(1<<i) == 0 {
+			continue
+		}
+		if r == 0 {
+			*out = append(*out, pickWord(e.next, b.pans)...)
+			return
+		}
+		r--
+	}
+}
+
+// cardEntropy returns the min-entropy of a CC keyword, which the brand with
+// the fewest test numbers bounds.
+func cardEntropy(arg []byte) float64 {
+	set, count := cardArgBrands(arg)
+	bits := math.Inf(1)
+	for i, b := range cardBrands {
+		if set&(1<<i) != 0 {
+			bits = min(bits, math.Log2(float64(len(b.pans))))
+		}
+	}
+	return bits + math.Log2(float64(count))
+}
+
+// checkCardArg returns why the argument of a CC keyword names an unknown
+// brand, or "".
+func checkCardArg(arg []byte) string {
+	for len(arg) > 0 {
+		var item []byte
+		item, arg, _ = bytes.Cut(arg, []byte{cardSep})
+		if _, ok := cardBrandIndex(item); !ok {
+			return fmt.Sprintf("unknown card brand %q: want VISA, MASTERCARD, MC or AMEX separated by '|'", item)
+		}
+	}
+	return ""
+}
+
+// appendCardExpiry appends an expiry date in the MM/YY form, one to
+// cardExpiryMonths months after now.
+func (e *FastEngine) appendCardExpiry(out *[]byte, now time.Time) {
+	months := int(now.Month()) + int(uint64N(e.next, cardExpiryMonths))
+	year := now.Year() + months/12
+	month := months%12 + 1
+	*out = append(*out, '0'+byte(month/10), '0'+byte(month%10), '/', '0'+byte(year/10%10), '0'+byte(year%10))
+}
+
+// cvvLength returns the length of the security code of the brand the
+// argument of a CCCVV keyword names: four digits for AMEX, three otherwise.
+func cvvLength(arg []byte) int {
+	if i, ok := cardBrandIndex(arg); ok && cardBrands[i].name == "AMEX" {
+		return 4
+	}
+	return 3
+}
+
+// appendCardCVV appends a card security code for the brand the argument of
+// a CCCVV keyword names.
+func (e *FastEngine) appendCardCVV(out *[]byte, arg []byte) {
+	for i := cvvLength(arg); i > 0; i-- {
+		*out = append(*out, '0'+byte(uint64N(e.next, 10)))
+	}
+}
+
+// checkCVVArg returns why the argument of a CCCVV keyword is not a single
+// known brand, or "".
+func checkCVVArg(arg []byte) string {
+	if _, ok := cardBrandIndex(arg); !ok {
+		return fmt.Sprintf("unknown card brand %q: want VISA, MASTERCARD, MC or AMEX", arg)
+	}
+	return ""
+}
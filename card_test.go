@@ -0,0 +1,117 @@
+package fastrand_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// luhnValid reports whether a card number passes the Luhn check.
+func luhnValid(pan string) bool {
+	sum := 0
+	for i := len(pan) - 1; i >= 0; i-- {
+		d := int(pan[i] - '0')
+		if (len(pan)-i)%2 == 0 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+const (
+	visaPattern       = `^(4111111111111111|4242424242424242|4012888888881881)$`
+	mastercardPattern = `^(5555555555554444|5105105105105100|2223003122003222)$`
+	amexPattern       = `^(378282246310005|371449635398431|378734493671000)$`
+)
+
+func TestCardNumber(t *testing.T) {
+	require.True(t, luhnValid("4242424242424242"))
+	require.False(t, luhnValid("4242424242424241"))
+	for i := 0; i < 300; i++ {
+		pan := fastrand.CardNumber()
+		require.True(t, luhnValid(pan), pan)
+		require.Regexp(t, visaPattern+`|`+mastercardPattern+`|`+amexPattern, pan)
+
+		require.Regexp(t, visaPattern, fastrand.CardNumber("visa"))
+		require.Regexp(t, mastercardPattern, fastrand.CardNumber("MC"))
+		pan = fastrand.CardNumber("AMEX", "DISCOVER")
+		require.Regexp(t, amexPattern, pan)
+		require.True(t, luhnValid(pan), pan)
+	}
+}
+
+func TestCardKeywords(t *testing.T) {
+	now := time.Now()
+	for i := 0; i < 300; i++ {
+		pan := fastrand.RandomizerString("{RAND;CC:VISA|amex}")
+		require.Regexp(t, visaPattern+`|`+amexPattern, pan)
+		require.True(t, luhnValid(pan), pan)
+		require.True(t, luhnValid(fastrand.RandomizerString("{RAND;CC}")))
+
+		exp := fastrand.RandomizerString("{RAND;CCEXP}")
+		require.Regexp(t, `^(0[1-9]|1[0-2])/[0-9]{2}$`, exp)
+		month, _ := strconv.Atoi(exp[:2])
+		year, _ := strconv.Atoi(exp[3:])
+		ahead := (2000+year-now.Year())*12 + month - int(now.Month())
+		require.True(t, ahead >= 1 && ahead <= 60, exp)
+
+		require.Regexp(t, `^[0-9]{3}$`, fastrand.RandomizerString("{RAND;CCCVV}"))
+		require.Regexp(t, `^[0-9]{4}$`, fastrand.RandomizerString("{RAND;CCCVV:amex}"))
+	}
+}
+
+func TestCardExpiryClock(t *testing.T) {
+	at := time.Date(2030, time.December, 15, 12, 0, 0, 0, time.UTC)
+	engine := fastrand.NewEngine(fastrand.WithClock(func() time.Time { return at }))
+	for i := 0; i < 100; i++ {
+		exp := engine.RandomizerString("{RAND;CCEXP}")
+		month, _ := strconv.Atoi(exp[:2])
+		year, _ := strconv.Atoi(exp[3:])
+		ahead := (2000+year-at.Year())*12 + month - int(at.Month())
+		require.True(t, ahead >= 1 && ahead <= 60, exp)
+	}
+
+	a := fastrand.NewEngine(fastrand.WithSeed(5))
+	b := fastrand.NewEngine(fastrand.WithSeed(5))
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, a.RandomizerString("{RAND;CCEXP}"), b.RandomizerString("{RAND;CCEXP}"))
+	}
+}
+
+func TestCardKeywordsStrict(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithStrictParsing(true))
+	for _, payload := range []string{"{RAND;CC:VISA|DISCOVER}", "{RAND;CC:|VISA}", "{RAND;CCCVV:JCB}"} {
+		_, err := engine.RandomizerErr([]byte(payload))
+		assert.ErrorContains(t, err, "unknown card brand", payload)
+	}
+	_, err := engine.RandomizerErr([]byte("{RAND;CC}{RAND;CC:mc|Amex}{RAND;CCEXP}{RAND;CCCVV}{RAND;CCCVV:AMEX}"))
+	assert.NoError(t, err)
+
+	bits, err := fastrand.TagEntropy("{RAND;CC:AMEX}")
+	require.NoError(t, err)
+	assert.InDelta(t, 1.585, bits, 0.001)
+	bits, err = fastrand.TagEntropy("{RAND;CC:VISA|MC}")
+	require.NoError(t, err)
+	assert.InDelta(t, 2.585, bits, 0.001)
+	bits, err = fastrand.TagEntropy("{RAND;CCCVV:AMEX}")
+	require.NoError(t, err)
+	assert.InDelta(t, 4*3.3219, bits, 0.001)
+}
+
+func TestAllocsCardKeywords(t *testing.T) {
+	engine := fastrand.NewEngine()
+	payload := []byte("pan={RAND;CC:VISA|MC}&exp={RAND;CCEXP}&cvv={RAND;CCCVV}")
+	dst := make([]byte, 0, 64)
+	dst = engine.RandomizerAppend(dst, payload)
+	allocs := testing.AllocsPerRun(100, func() {
+		dst = engine.RandomizerAppend(dst[:0], payload)
+	})
+	assert.Zero(t, allocs)
+}
@@ -0,0 +1,87 @@
+package fastrand
+
+import "bytes"
+
+// caseMode is the case modifier of a tag such as {RAND;UUID;upper}, applied
+// to the ASCII letters of the generated value.
+type caseMode uint8
+
+const (
+	caseNone caseMode = iota
+	caseUpper
+	caseLower
+	caseTitle
+)
+
+var caseModeNames = [...]string{caseUpper: "upper", caseLower: "lower", caseTitle: "title"}
+
+// splitCaseModifier splits a trailing upper, lower or title segment off a
+// tag body. The body must have another segment before it, so {RAND;upper}
+// still names a keyword.
+func splitCaseModifier(body []byte) ([]byte, caseMode) {
+	sepIndex := bytes.LastIndexByte(body, sepTag)
+	if sepIndex == -1 {
+		return body, caseNone
+	}
+	seg := body[sepIndex+1:]
+	for mode := caseUpper; mode <= caseTitle; mode++ {
+		if bytes.EqualFold(seg, s2b(caseModeNames[mode])) {
+			return body[:sepIndex], mode
+		}
+	}
+	return body, caseNone
+}
+
+func (m caseMode) String() string {
+	return caseModeNames[m]
+}
+
+// apply changes the case of the ASCII letters in b in place. Title case
+// upper-cases the first letter of every run of letters and digits and
+// lower-cases the rest.
+func (m caseMode) apply(b []byte) {
+	switch m {
+	case caseUpper:
+		for i, c := range b {
+			if 'a' <= c && c <= 'z' {
+				b[i] = c - ('a' - 'A')
+			}
+		}
+	case caseLower:
+		for i, c := range b {
+			if 'A' <= c && c <= 'Z' {
+				b[i] = c + ('a' - 'A')
+			}
+		}
+	case caseTitle:
+		wordStart := true
+		for i, c := range b {
+			switch {
+			case 'a' <= c && c <= 'z':
+				if wordStart {
+					b[i] = c - ('a' - 'A')
+				}
+			case 'A' <= c && c <= 'Z':
+				if !wordStart {
+					b[i] = c + ('a' - 'A')
+				}
+			case '0' <= c && c <= '9':
+			default:
+				wordStart = true
+				continue
+			}
+			wordStart = false
+		}
+	}
+}
+
+// folded returns charset with its ASCII letters mapped to one case, which
+// has the entropy that remains after any case modifier.
+func (m caseMode) folded(charset CharsList) CharsList {
+	if m == caseNone {
+		return charset
+	}
+	folded := bytes.Clone(charset)
+	caseLower.apply(folded)
+	return folded
+}
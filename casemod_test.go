@@ -0,0 +1,54 @@
+package fastrand_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaseModifiers(t *testing.T) {
+	assert.Regexp(t, "^[0-9A-F]{8}-[0-9A-F]{4}-4[0-9A-F]{3}-[89AB][0-9A-F]{3}-[0-9A-F]{12}$",
+		fastrand.RandomizerString("{RAND;UUID;upper}"))
+	assert.Regexp(t, "^[0-9a-f]{24}$", fastrand.RandomizerString("{RAND;12;HEX(upper=true);lower}"))
+	assert.Regexp(t, "^[a-z]{20}$", fastrand.RandomizerString("{RAND;20;ABR;LOWER}"), "modifiers are case-insensitive")
+	assert.Regexp(t, "^[A-Z]{5,20}$", fastrand.RandomizerString("{RAND;5-20;ABR;upper}"))
+	assert.Regexp(t, "^[A-Z][a-z]{19}$", fastrand.RandomizerString("{RAND;20;ABR;title}"))
+	assert.Regexp(t, "^[A-Z]{6}$", fastrand.RandomizerString("{RAND;6;ABL,ABR;upper}"), "choices share the modifier")
+	assert.Len(t, fastrand.RandomizerString("{RAND;12;upper}"), 12, "a modifier can follow a bare length")
+}
+
+func TestCaseModifiers_Title(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithCustomKeyword("NAME", func(int) []byte {
+		return []byte("jOHN o'neil-smith 2nd")
+	}))
+	assert.Equal(t, "John O'Neil-Smith 2nd", engine.RandomizerString("{RAND;NAME;title}"))
+	assert.Equal(t, "JOHN O'NEIL-SMITH 2ND", engine.RandomizerString("{RAND;NAME;upper}"))
+}
+
+func TestCaseModifiers_WithVariable(t *testing.T) {
+	out := fastrand.RandomizerString("{RAND;8;ABL;upper;VAR=id}={REF;id}")
+	parts := strings.Split(out, "=")
+	require.Len(t, parts, 2)
+	assert.Regexp(t, "^[A-Z]{8}$", parts[0])
+	assert.Equal(t, parts[0], parts[1], "the variable holds the transformed value")
+}
+
+func TestCaseModifiers_NotAModifier(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithCustomKeyword("UPPER", func(int) []byte { return []byte("kw") }))
+	assert.Equal(t, "kw", engine.RandomizerString("{RAND;UPPER}"), "a lone segment is still a keyword")
+}
+
+func TestCaseModifiers_Strict(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithStrictParsing(true))
+	assert.NoError(t, engine.Validate([]byte("{RAND;12;HEX;lower}{RAND;UUID;title;VAR=u}")))
+
+	specs, err := engine.Inspect([]byte("{RAND;12;HEX;lower}{RAND;UUID}"))
+	require.NoError(t, err)
+	require.Len(t, specs, 2)
+	assert.Equal(t, "lower", specs[0].Case)
+	assert.Equal(t, "HEX", specs[0].Keywords[0].Name)
+	assert.Equal(t, "", specs[1].Case)
+}
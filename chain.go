@@ -0,0 +1,59 @@
+package fastrand
+
+import "bytes"
+
+// ChainEngine expands payloads with a primary engine and hands the tags
+// whose keyword the primary does not know or has disabled to a fallback
+// engine, so a small engine of team-specific keywords can be layered on a
+// shared base engine. The fallback may itself be a ChainEngine, making
+// chains of any length.
+type ChainEngine struct {
+	primary  *FastEngine
+	fallback Engine
+}
+
+// NewChainEngine returns an engine that expands payloads with primary and
+// delegates tags naming keywords primary cannot expand to fallback. Each
+// such tag is passed to fallback's Randomizer on its own, as {RAND;n;KW}
+// with the length primary drew, and the result is inserted where the tag
+// stood; primary's case modifiers, variables and output encoding apply to
+// it as to any generated value. Tags that name no keyword, such as
+// {RAND;8}, are always expanded by primary, and unknown keywords in choice
+// lists are skipped as usual rather than delegated. A nil fallback leaves
+// primary's unknown keyword policy in charge.
+func NewChainEngine(primary *FastEngine, fallback Engine) *ChainEngine {
+	return &ChainEngine{primary: primary, fallback: fallback}
+}
+
+// Randomizer expands payload through the chain.
+func (c *ChainEngine) Randomizer(payload []byte) []byte {
+	e := c.primary
+	if !bytes.ContainsAny(payload, tagChars) && !e.transformsPayload() {
+		return payload
+	}
+	normalized, scratch := e.normalized(payload)
+	buf := make([]byte, 0, len(normalized)+512)
+	x := expansion{fallback: c.fallback}
+	e.expandInto(normalized, &buf, &x)
+	e.release(scratch)
+	return buf
+}
+
+// RandomizerString is like Randomizer for a string payload.
+func (c *ChainEngine) RandomizerString(payload string) string {
+	return string(c.Randomizer(s2b(payload)))
+}
+
+// appendDelegated appends the expansion of kw by the chain's fallback
+// engine, at the given length.
+func (e *FastEngine) appendDelegated(out *[]byte, kw *keywordSpec, length int, x *expansion) {
+	tag := e.bufferPool.Get(len(startTag) + len(kw.text) + 16)
+	*tag = append((*tag)[:0], startTag...)
+	*tag = append(*tag, sepTag)
+	*tag = strconvAppendUint(*tag, uint64(length), 10)
+	*tag = append(*tag, sepTag)
+	*tag = append(*tag, kw.text...)
+	*tag = append(*tag, endTag)
+	*out = append(*out, x.fallback.Randomizer(*tag)...)
+	e.bufferPool.Put(tag)
+}
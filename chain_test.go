@@ -0,0 +1,61 @@
+package fastrand_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBaseAndTeam(teamOpts ...fastrand.Option) (*fastrand.FastEngine, *fastrand.FastEngine) {
+	base := fastrand.NewEngine(fastrand.WithCustomKeyword("SHARED", func(n int) []byte {
+		return []byte(strings.Repeat("s", n))
+	}))
+	team := fastrand.NewEngine(append([]fastrand.Option{fastrand.WithCustomKeyword("TEAM", func(int) []byte {
+		return []byte("team")
+	})}, teamOpts...)...)
+	return base, team
+}
+
+func TestChainEngine(t *testing.T) {
+	base, team := newBaseAndTeam()
+	var chain fastrand.Engine = fastrand.NewChainEngine(team, base)
+
+	assert.Regexp(t, `^team-sssss-[0-9]{4}$`, chain.RandomizerString("{RAND;TEAM}-{RAND;5;SHARED}-{RAND;4;DIGIT}"))
+	assert.Equal(t, "SSS", chain.RandomizerString("{RAND;3;SHARED;upper}"))
+	assert.Len(t, chain.RandomizerString("{RAND;SHARED}"), 16, "the primary's default length is passed on")
+	assert.Len(t, chain.RandomizerString("{RAND;NOPE}"), 16, "keywords no engine knows get the last engine's fallback")
+	assert.Equal(t, "plain", string(chain.Randomizer([]byte("plain"))))
+
+	out := chain.RandomizerString("{RAND;2-6;SHARED;VAR=s}|{REF;s}")
+	a, b, ok := strings.Cut(out, "|")
+	require.True(t, ok)
+	assert.Equal(t, a, b)
+
+	assert.Len(t, team.RandomizerString("{RAND;3;SHARED}"), 3, "the primary alone is unchanged")
+	assert.NotEqual(t, "sss", team.RandomizerString("{RAND;3;SHARED}"))
+}
+
+func TestChainEngineDisabledKeyword(t *testing.T) {
+	base, team := newBaseAndTeam(fastrand.WithDisabledKeywords("EMAIL"))
+	chain := fastrand.NewChainEngine(team, base)
+	assert.Contains(t, chain.RandomizerString("{RAND;6;EMAIL}"), "@")
+}
+
+func TestChainEnginePolicies(t *testing.T) {
+	base, team := newBaseAndTeam(fastrand.WithUnknownKeywordPolicy(fastrand.UnknownKeywordPassthrough))
+	chain := fastrand.NewChainEngine(team, base)
+	assert.Equal(t, "ssss", chain.RandomizerString("{RAND;4;SHARED}"), "delegation takes precedence over the primary's policy")
+	assert.Equal(t, "{RAND;4;SHARED}", team.RandomizerString("{RAND;4;SHARED}"))
+
+	assert.Equal(t, "{RAND;4;SHARED}", fastrand.NewChainEngine(team, nil).RandomizerString("{RAND;4;SHARED}"))
+}
+
+func TestChainEngineNested(t *testing.T) {
+	base, team := newBaseAndTeam()
+	user := fastrand.NewEngine(fastrand.WithCustomKeyword("USER", func(int) []byte { return []byte("user") }))
+	chain := fastrand.NewChainEngine(user, fastrand.NewChainEngine(team, base))
+	assert.Equal(t, "user/team/ss", chain.RandomizerString("{RAND;USER}/{RAND;TEAM}/{RAND;2;SHARED}"))
+}
@@ -0,0 +1,83 @@
+package fastrand
+
+import "sync"
+
+// maxCachedClasses bounds the inline character class cache so payloads
+// with many distinct classes cannot grow it without limit.
+const maxCachedClasses = 256
+
+// classCache holds parsed inline character classes keyed by their text.
+var classCache struct {
+	sync.RWMutex
+	m map[string]CharsList
+}
+
+// isCharClass reports whether a keyword is an inline character class such
+// as "[a-f0-9_-]".
+func isCharClass(keyword []byte) bool {
+	return len(keyword) >= 2 && keyword[0] == '[' && keyword[len(keyword)-1] == ']'
+}
+
+// charClass returns the charset of an inline character class, parsing it
+// on first use.
+func charClass(keyword []byte) (CharsList, bool) {
+	classCache.RLock()
+	cs, ok := classCache.m[string(keyword)]
+	classCache.RUnlock()
+	if ok {
+		return cs, true
+	}
+	cs, ok = parseCharClass(keyword[1 : len(keyword)-1])
+	if !ok {
+		return nil, false
+	}
+	classCache.Lock()
+	if classCache.m == nil {
+		classCache.m = make(map[string]CharsList)
+	}
+	if len(classCache.m) < maxCachedClasses {
+		classCache.m[string(keyword)] = cs
+	}
+	classCache.Unlock()
+	return cs, true
+}
+
+// parseCharClass parses the body of a character class: single ASCII
+// characters and ranges like "a-z". A '-' at either end is literal, and a
+// backslash escapes the next character. Each character is included once.
+func parseCharClass(body []byte) (CharsList, bool) {
+	var set [128]bool
+	for i := 0; i < len(body); i++ {
+		lo := body[i]
+		if lo == '\\' {
+			if i++; i == len(body) {
+				return nil, false
+			}
+			lo = body[i]
+		}
+		hi := lo
+		if i+2 < len(body) && body[i+1] == '-' {
+			i += 2
+			hi = body[i]
+			if hi == '\\' {
+				if i++; i == len(body) {
+					return nil, false
+				}
+				hi = body[i]
+			}
+		}
+		if lo > hi || hi >= 0x80 {
+			return nil, false
+		}
+		for c := lo; c <= hi; c++ {
+			set[c] = true
+		}
+	}
+	var cs CharsList
+	for c, ok := range set {
+		if ok {
+			cs = append(cs, byte(c))
+		}
+	}
+	return cs, len(cs) > 0
+}
@@ -0,0 +1,55 @@
+package fastrand_test
+
+import (
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInlineCharClass(t *testing.T) {
+	cases := map[string]string{
+		"{RAND;12;[a-f0-9_-]}": `^[a-f0-9_-]{12}$`,
+		"{RAND;8;[-xy]}":       `^[-xy]{8}$`,
+		"{RAND;8;[A-CX-Z]}":    `^[A-CX-Z]{8}$`,
+		`{RAND;8;[\]\\a]}`:     `^[\]\\a]{8}$`,
+		"{RAND;[01]}":          `^[01]{16}$`,
+		"{RAND;4-6;[!-/]}":     `^[!-/]{4,6}$`,
+	}
+	for payload, pattern := range cases {
+		for i := 0; i < 20; i++ {
+			require.Regexp(t, pattern, fastrand.RandomizerString(payload), payload)
+		}
+	}
+}
+
+func TestInlineCharClassCoversAll(t *testing.T) {
+	seen := map[rune]bool{}
+	for _, c := range fastrand.RandomizerString("{RAND;99;[a-c]}{RAND;99;[a-c]}") {
+		seen[c] = true
+	}
+	assert.Equal(t, map[rune]bool{'a': true, 'b': true, 'c': true}, seen)
+}
+
+func TestInlineCharClassChoices(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		require.Regexp(t, `^([xyz]{6}|[0-9]{6})$`, fastrand.RandomizerString("{RAND;6;[x-z],DIGIT}"))
+	}
+}
+
+func TestInlineCharClassInvalid(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithStrictParsing(true))
+	for _, class := range []string{"[]", "[z-a]", `[ab\]`, "[é]"} {
+		_, err := engine.RandomizerErr([]byte("{RAND;8;" + class + "}"))
+		assert.ErrorContains(t, err, "invalid character class", class)
+	}
+	_, err := engine.RandomizerErr([]byte("{RAND;8;[a-f0-9]}"))
+	assert.NoError(t, err)
+}
+
+func TestInlineCharClassEntropy(t *testing.T) {
+	bits, err := fastrand.TagEntropy("{RAND;10;[0-9a-f]}")
+	require.NoError(t, err)
+	assert.InDelta(t, 40.0, bits, 1e-9)
+}
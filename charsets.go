@@ -0,0 +1,71 @@
+package fastrand
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"strings"
+)
+
+// RegisterCharset registers a named charset with the default engine. See
+// FastEngine.RegisterCharset.
+func RegisterCharset(name string, cs CharsList) error {
+	return defaultEngine.Load().RegisterCharset(name, cs)
+}
+
+// RegisterCharset makes cs available to templates as a keyword, so
+// {RAND;10;GREEK} draws 10 characters from the charset registered as
+// "GREEK". Names are case-insensitive, at most 16 letters, digits or
+// underscores, and may not shadow a built-in or custom keyword.
+// Registering a name again replaces its charset. It is safe to call while
+// the engine is in use.
+func (e *FastEngine) RegisterCharset(name string, cs CharsList) error {
+	upper := strings.ToUpper(name)
+	switch {
+	case !validCharsetName(upper):
+		return fmt.Errorf("fastrand: invalid charset name %q", name)
+	case len(cs) == 0:
+		return fmt.Errorf("fastrand: charset %q is empty", name)
+	}
+
+	e.registryMu.Lock()
+	defer e.registryMu.Unlock()
+	keywords := e.keywords.Load()
+	if _, builtin := keywords.enabled[upper]; builtin {
+		return fmt.Errorf("fastrand: charset name %q is a keyword", name)
+	}
+	if _, custom := keywords.custom[upper]; custom {
+		return fmt.Errorf("fastrand: charset name %q is a custom keyword", name)
+	}
+	next := make(map[string]CharsList)
+	if old := e.charsets.Load(); old != nil {
+		maps.Copy(next, *old)
+	}
+	next[upper] = CharsList(bytes.Clone(cs))
+	e.charsets.Store(&next)
+	return nil
+}
+
+func validCharsetName(name string) bool {
+	if len(name) == 0 || len(name) > 16 {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if !(c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// registeredCharset looks up a charset registered under the upper-case
+// name.
+func (e *FastEngine) registeredCharset(name string) (CharsList, bool) {
+	m := e.charsets.Load()
+	if m == nil {
+		return nil, false
+	}
+	cs, ok := (*m)[name]
+	return cs, ok
+}
@@ -0,0 +1,80 @@
+package fastrand_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterCharset(t *testing.T) {
+	engine := fastrand.NewEngine()
+	require.NoError(t, engine.RegisterCharset("base32chars", fastrand.CharsList("ABCDEFGHIJKLMNOPQRSTUVWXYZ234567")))
+	require.NoError(t, engine.RegisterCharset("GREEK", fastrand.CharsList("αβγ")))
+
+	assert.Regexp(t, `^[A-Z2-7]{10}$`, engine.RandomizerString("{RAND;10;BASE32CHARS}"))
+	assert.Regexp(t, `^[A-Z2-7]{10}$`, engine.RandomizerString("{RAND;10;Base32Chars}"))
+	assert.Len(t, engine.RandomizerString("{RAND;10;GREEK}"), 10)
+	assert.Regexp(t, `^([A-Z2-7]{4}|[0-9]{4})$`, engine.RandomizerString("{RAND;4;BASE32CHARS,DIGIT}"))
+
+	bits, err := engine.TagEntropy("{RAND;10;BASE32CHARS}")
+	require.NoError(t, err)
+	assert.InDelta(t, 50.0, bits, 1e-9)
+}
+
+func TestRegisterCharsetReplace(t *testing.T) {
+	engine := fastrand.NewEngine()
+	require.NoError(t, engine.RegisterCharset("BIN", fastrand.CharsList("01")))
+	assert.Regexp(t, `^[01]{8}$`, engine.RandomizerString("{RAND;8;BIN}"))
+	require.NoError(t, engine.RegisterCharset("BIN", fastrand.CharsList("x")))
+	assert.Equal(t, "xxxx", engine.RandomizerString("{RAND;4;BIN}"))
+
+	engine.Reset()
+	assert.NotEqual(t, "xxxx", engine.RandomizerString("{RAND;4;BIN}"))
+}
+
+func TestRegisterCharsetCopies(t *testing.T) {
+	engine := fastrand.NewEngine()
+	cs := fastrand.CharsList("a")
+	require.NoError(t, engine.RegisterCharset("ONE", cs))
+	cs[0] = 'b'
+	assert.Equal(t, "aaa", engine.RandomizerString("{RAND;3;ONE}"))
+}
+
+func TestRegisterCharsetInvalid(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithCustomKeyword("MINE", func(int) []byte { return nil }))
+	assert.ErrorContains(t, engine.RegisterCharset("", fastrand.CharsList("a")), "invalid charset name")
+	assert.ErrorContains(t, engine.RegisterCharset("A-B", fastrand.CharsList("a")), "invalid charset name")
+	assert.ErrorContains(t, engine.RegisterCharset("WAYTOOLONGCHARSETNAME", fastrand.CharsList("a")), "invalid charset name")
+	assert.ErrorContains(t, engine.RegisterCharset("EMPTY", nil), "empty")
+	assert.ErrorContains(t, engine.RegisterCharset("hex", fastrand.CharsList("a")), "is a keyword")
+	assert.ErrorContains(t, engine.RegisterCharset("mine", fastrand.CharsList("a")), "custom keyword")
+}
+
+func TestRegisterCharsetPackageLevel(t *testing.T) {
+	require.NoError(t, fastrand.RegisterCharset("VOWELS", fastrand.CharsList("aeiou")))
+	assert.Regexp(t, `^[aeiou]{12}$`, fastrand.RandomizerString("{RAND;12;VOWELS}"))
+}
+
+func TestRegisterCharsetConcurrent(t *testing.T) {
+	engine := fastrand.NewEngine()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_ = engine.RegisterCharset("AB", fastrand.CharsList("ab"))
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_ = engine.RandomizerString("{RAND;8;AB}")
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,32 @@
+package fastrand
+
+import "time"
+
+// seededEpoch is the instant WithSeed pins the engine clock to, so keywords
+// derived from the current time reproduce along with the rest of the output.
+var seededEpoch = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// WithClock makes the engine read the current time from now instead of
+// time.Now for the now-relative bounds of TIMESTAMP, the iat and exp
+// claims of JWT, the timestamps of UUIDV7 and ULID and the expiry dates of
+// CCEXP. Pass a function returning a fixed time to reproduce them, or nil
+// to restore the wall clock. now must be safe for concurrent use if the
+// engine is.
+func WithClock(now func() time.Time) Option {
+	return func(e *FastEngine) {
+		e.clock = now
+	}
+}
+
+// now returns the engine's current time.
+func (e *FastEngine) now() time.Time {
+	if e.clock != nil {
+		return e.clock()
+	}
+	return time.Now()
+}
+
+// fixedClock returns a clock that always reads t.
+func fixedClock(t time.Time) func() time.Time {
+	return func() time.Time { return t }
+}
@@ -0,0 +1,59 @@
+package fastrand_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const clockTemplate = "{RAND;TIMESTAMP(layout=RFC3339Nano)}|{RAND;TIMESTAMP(layout=unix,min=now-1h,max=now)}"
+
+func TestWithClock(t *testing.T) {
+	at := time.Date(2030, time.June, 15, 12, 0, 0, 0, time.UTC)
+	engine := fastrand.NewEngine(fastrand.WithClock(func() time.Time { return at }))
+	assert.Equal(t, "2030-06-15T12:00:00Z", engine.RandomizerString("{RAND;TIMESTAMP(min=now,max=now)}"))
+
+	out := engine.RandomizerString("{RAND;TIMESTAMP(min=now-24h)}")
+	ts, err := time.Parse(time.RFC3339, out)
+	require.NoError(t, err, out)
+	assert.False(t, ts.Before(at.Add(-24*time.Hour)), out)
+	assert.False(t, ts.After(at), out)
+
+	strict := fastrand.NewEngine(fastrand.WithStrictParsing(true), fastrand.WithClock(func() time.Time { return at }))
+	_, err = strict.RandomizerErr([]byte("{RAND;TIMESTAMP(min=2030-06-16,max=now)}"))
+	assert.Error(t, err, "bounds are checked against the engine clock")
+}
+
+func TestWithSeed_PinsClock(t *testing.T) {
+	a := fastrand.NewEngine(fastrand.WithSeed(11))
+	b := fastrand.NewEngine(fastrand.WithSeed(11))
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, a.RandomizerString(clockTemplate), b.RandomizerString(clockTemplate))
+	}
+	assert.Equal(t, "2025-01-01T00:00:00Z", a.RandomizerString("{RAND;TIMESTAMP(min=now,max=now)}"))
+
+	at := time.Date(2030, time.June, 15, 12, 0, 0, 0, time.UTC)
+	clock := fastrand.WithClock(func() time.Time { return at })
+	for _, engine := range []*fastrand.FastEngine{
+		fastrand.NewEngine(clock, fastrand.WithSeed(11)),
+		fastrand.NewEngine(fastrand.WithSeed(11), clock),
+	} {
+		assert.Equal(t, "2030-06-15T12:00:00Z", engine.RandomizerString("{RAND;TIMESTAMP(min=now,max=now)}"),
+			"WithClock wins over the pinned clock in either order")
+	}
+}
+
+func TestWithClock_CloneAndReset(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithSeed(3))
+	clone := engine.Clone()
+	assert.Equal(t, "2025-01-01T00:00:00Z", clone.RandomizerString("{RAND;TIMESTAMP(min=now,max=now)}"), "Clone keeps the clock")
+
+	engine.Reset()
+	out := engine.RandomizerString("{RAND;TIMESTAMP(min=now,max=now)}")
+	ts, err := time.Parse(time.RFC3339, out)
+	require.NoError(t, err, out)
+	assert.WithinDuration(t, time.Now(), ts, time.Minute, "Reset restores the wall clock")
+}
@@ -0,0 +1,44 @@
+package fastrand_test
+
+import (
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClone(t *testing.T) {
+	base := fastrand.NewEngine(
+		fastrand.WithDefaultLength(4),
+		fastrand.WithDisabledKeywords("EMAIL"),
+		fastrand.WithCustomCharset("ABL", []byte("aeiou")),
+		fastrand.WithCustomKeyword("PING", func(int) []byte { return []byte("pong") }),
+		fastrand.WithMailProviders("base.example"),
+		fastrand.WithSequence("id", 100, 1),
+		fastrand.WithCycle("env", "dev", "prod"),
+	)
+	assert.Equal(t, "100 dev", base.RandomizerString("{RAND;SEQ:id} {RAND;CYCLE:env}"))
+
+	clone := base.Clone()
+	assert.Regexp(t, `^[0-9]{4}$`, clone.RandomizerString("{RAND;DIGIT}"))
+	assert.Regexp(t, `^[aeiou]{6}$`, clone.RandomizerString("{RAND;6;ABL}"))
+	assert.Equal(t, "pong", clone.RandomizerString("{RAND;PING}"))
+	assert.Equal(t, "101 prod", clone.RandomizerString("{RAND;SEQ:id} {RAND;CYCLE:env}"), "continues where the base was")
+	assert.Equal(t, "101 prod", base.RandomizerString("{RAND;SEQ:id} {RAND;CYCLE:env}"), "advances independently")
+	assert.Equal(t, base.MailProviders(), clone.MailProviders())
+
+	clone.Reset()
+	assert.Equal(t, "pong", base.RandomizerString("{RAND;PING}"), "resetting the clone leaves the base alone")
+	assert.Regexp(t, `^[aeiou]{6}$`, base.RandomizerString("{RAND;6;ABL}"))
+	assert.NotContains(t, base.RandomizerString("{RAND;8;EMAIL}"), "@base.example", "EMAIL stays disabled")
+	assert.Regexp(t, `^[a-z]{8}@`, clone.RandomizerString("{RAND;8;EMAIL}"))
+}
+
+func TestCloneSeeded(t *testing.T) {
+	base := fastrand.NewEngine(fastrand.WithSeed(7))
+	tmpl, err := base.Clone().Compile([]byte(seedTemplate))
+	require.NoError(t, err)
+	assert.Equal(t, fastrand.NewEngine(fastrand.WithSeed(7)).RandomizerString(seedTemplate), tmpl.ExecuteString(),
+		"a clone draws from the base's source")
+}
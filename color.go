@@ -0,0 +1,78 @@
+package fastrand
+
+import (
+	"fmt"
+	"math"
+)
+
+// colorForm returns the form the argument of a COLOR keyword names: HEX
+// (the default), RGB or HSL, and whether it is one of them.
+func colorForm(arg []byte) (string, bool) {
+	if len(arg) == 0 {
+		return "HEX", true
+	}
+	var key [4]byte
+	if len(arg) > len(key) {
+		return "HEX", false
+	}
+	switch form := unsafeString(key[:upperASCIIInto(key[:], arg)]); form {
+	case "HEX":
+		return "HEX", true
+	case "RGB":
+		return "RGB", true
+	case "HSL":
+		return "HSL", true
+	}
+	return "HEX", false
+}
+
+// appendColor appends a random CSS color in the form the argument of a
+// COLOR keyword names: #a3f2c1, rgb(163, 242, 193) or hsl(142, 74%, 79%).
+func (e *FastEngine) appendColor(out *[]byte, arg []byte) {
+	form, _ := colorForm(arg)
+	switch form {
+	case "RGB":
+		var rgb [3]byte
+		fillBytes(e.next, rgb[:])
+		*out = append(*out, "rgb("...)
+		for i, c := range rgb {
+			if i > 0 {
+				*out = append(*out, ", "...)
+			}
+			appendUintByte(out, c)
+		}
+		*out = append(*out, ')')
+	case "HSL":
+		*out = append(*out, "hsl("...)
+		*out = strconvAppendUint(*out, uint64N(e.next, 360), 10)
+		*out = append(*out, ", "...)
+		*out = strconvAppendUint(*out, uint64N(e.next, 101), 10)
+		*out = append(*out, "%, "...)
+		*out = strconvAppendUint(*out, uint64N(e.next, 101), 10)
+		*out = append(*out, "%)"...)
+	default:
+		var rgb [3]byte
+		fillBytes(e.next, rgb[:])
+		*out = append(*out, '#')
+		for _, c := range rgb {
+			*out = append(*out, strconvDigits[c>>4], strconvDigits[c&0x0f])
+		}
+	}
+}
+
+// colorEntropy returns the bits of entropy of a COLOR keyword.
+func colorEntropy(arg []byte) float64 {
+	if form, _ := colorForm(arg); form == "HSL" {
+		return math.Log2(360 * 101 * 101)
+	}
+	return 24
+}
+
+// checkColorArg returns why the argument of a COLOR keyword is not a known
+// form, or "".
+func checkColorArg(arg []byte) string {
+	if _, ok := colorForm(arg); !ok {
+		return fmt.Sprintf("invalid color form %q: want hex, rgb or hsl", arg)
+	}
+	return ""
+}
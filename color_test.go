@@ -0,0 +1,63 @@
+package fastrand_test
+
+import (
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColorKeyword(t *testing.T) {
+	rgb := regexp.MustCompile(`^rgb\(([0-9]{1,3}), ([0-9]{1,3}), ([0-9]{1,3})\)$`)
+	hsl := regexp.MustCompile(`^hsl\(([0-9]{1,3}), ([0-9]{1,3})%, ([0-9]{1,3})%\)$`)
+	for i := 0; i < 300; i++ {
+		require.Regexp(t, `^#[0-9a-f]{6}$`, fastrand.RandomizerString("{RAND;COLOR}"))
+		require.Regexp(t, `^#[0-9a-f]{6}$`, fastrand.RandomizerString("{RAND;COLOR:hex}"))
+
+		out := fastrand.RandomizerString("{RAND;COLOR:RGB}")
+		m := rgb.FindStringSubmatch(out)
+		require.Len(t, m, 4, out)
+		for _, v := range m[1:] {
+			n, _ := strconv.Atoi(v)
+			require.LessOrEqual(t, n, 255, out)
+		}
+
+		out = fastrand.RandomizerString("{RAND;COLOR:hsl}")
+		m = hsl.FindStringSubmatch(out)
+		require.Len(t, m, 4, out)
+		h, _ := strconv.Atoi(m[1])
+		s, _ := strconv.Atoi(m[2])
+		l, _ := strconv.Atoi(m[3])
+		require.True(t, h < 360 && s <= 100 && l <= 100, out)
+	}
+	assert.Regexp(t, `^#[0-9A-F]{6}$`, fastrand.RandomizerString("{RAND;COLOR;upper}"))
+}
+
+func TestColorKeywordStrict(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithStrictParsing(true))
+	_, err := engine.RandomizerErr([]byte("{RAND;COLOR:cmyk}"))
+	assert.ErrorContains(t, err, "invalid color form")
+	_, err = engine.RandomizerErr([]byte("{RAND;COLOR}{RAND;COLOR:Hex}{RAND;COLOR:rgb}{RAND;COLOR:HSL}"))
+	assert.NoError(t, err)
+
+	bits, err := fastrand.TagEntropy("{RAND;COLOR:rgb}")
+	require.NoError(t, err)
+	assert.Equal(t, 24.0, bits)
+	bits, err = fastrand.TagEntropy("{RAND;COLOR:hsl}")
+	require.NoError(t, err)
+	assert.InDelta(t, 21.81, bits, 0.01)
+}
+
+func TestAllocsColorKeyword(t *testing.T) {
+	engine := fastrand.NewEngine()
+	payload := []byte("color: {RAND;COLOR}; background: {RAND;COLOR:rgb}; border-color: {RAND;COLOR:hsl}")
+	dst := make([]byte, 0, 128)
+	dst = engine.RandomizerAppend(dst, payload)
+	allocs := testing.AllocsPerRun(100, func() {
+		dst = engine.RandomizerAppend(dst[:0], payload)
+	})
+	assert.Zero(t, allocs)
+}
@@ -0,0 +1,284 @@
+package fastrand
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// EngineConfig is the serializable part of a FastEngine's configuration:
+// lengths, encodings, keyword switches, mail providers, charsets, cycles,
+// XML element names, tag dialects and excluded characters. Custom keyword generators, output encoders, input
+// normalizers, random sources, sequences and buffer pools are functions or
+// runtime state and are not included. Fields left out of a JSON document
+// keep their NewEngine defaults.
+type EngineConfig struct {
+	DefaultLength      int                 `json:"default_length"`
+	MinLength          int                 `json:"min_length"`
+	MaxLength          int                 `json:"max_length"`
+	InputEncodings     []string            `json:"input_encodings"`
+	OutputEncodings    []string            `json:"output_encodings"`
+	Ranges             bool                `json:"ranges"`
+	KeywordChoices     bool                `json:"keyword_choices"`
+	LengthChoices      bool                `json:"length_choices"`
+	LengthDistribution string              `json:"length_distribution"`
+	StrictParsing      bool                `json:"strict_parsing"`
+	RequireKeyword     bool                `json:"require_keyword"`
+	UnknownKeywords    string              `json:"unknown_keywords"`
+	MaxExpansionDepth  int                 `json:"max_expansion_depth"`
+	MaxOutputSize      int                 `json:"max_output_size"`
+	MaxTags            int                 `json:"max_tags"`
+	MaxChoicesPerTag   int                 `json:"max_choices_per_tag"`
+	DisabledKeywords   []string            `json:"disabled_keywords,omitempty"`
+	MailProviders      []string            `json:"mail_providers,omitempty"`
+	UserAgents         []string            `json:"user_agents,omitempty"`
+	FirstNames         []string            `json:"first_names,omitempty"`
+	LastNames          []string            `json:"last_names,omitempty"`
+	CustomCharsets     map[string]string   `json:"custom_charsets,omitempty"`
+	Charsets           map[string]string   `json:"charsets,omitempty"`
+	Cycles             map[string][]string `json:"cycles,omitempty"`
+	XMLElementNames    []string            `json:"xml_element_names,omitempty"`
+	Dialects           []Dialect           `json:"dialects,omitempty"`
+	ExcludedChars      map[string]string   `json:"excluded_chars,omitempty"`
+}
+
+// encodingNames are the names EngineConfig uses for encodings, in flag
+// order.
+var encodingNames = []struct {
+	enc  RandomizerEncoding
+	name string
+}{
+	{RandomizerEncodingURL, "url"},
+	{RandomizerEncodingHTML, "html"},
+	{RandomizerEncodingJSON, "json"},
+	{RandomizerEncodingBase64, "base64"},
+	{RandomizerEncodingUnicode, "unicode"},
+	{RandomizerEncodingURLDouble, "url-double"},
+	{RandomizerEncodingXML, "xml"},
+	{RandomizerEncodingShell, "shell"},
+	{RandomizerEncodingSQL, "sql"},
+}
+
+func encodingFlagNames(enc RandomizerEncoding) []string {
+	names := []string{}
+	for _, n := range encodingNames {
+		if enc&n.enc != 0 {
+			names = append(names, n.name)
+		}
+	}
+	return names
+}
+
+func parseEncodingName(name string) (RandomizerEncoding, error) {
+	for _, n := range encodingNames {
+		if strings.EqualFold(name, n.name) {
+			return n.enc, nil
+		}
+	}
+	return RandomizerEncodingNone, fmt.Errorf("fastrand: unknown encoding %q", name)
+}
+
+// Config returns a snapshot of the engine's serializable configuration.
+func (e *FastEngine) Config() EngineConfig {
+	c := EngineConfig{
+		DefaultLength:      e.defaultLength,
+		MinLength:          e.minLength,
+		MaxLength:          e.maxLength,
+		InputEncodings:     encodingFlagNames(e.inputEncoding),
+		Ranges:             e.rangesEnabled,
+		KeywordChoices:     e.keywordChoicesEnabled,
+		LengthChoices:      e.lengthChoicesEnabled,
+		LengthDistribution: e.lengthDistribution.String(),
+		StrictParsing:      e.strictParsing,
+		RequireKeyword:     e.requireKeyword,
+		UnknownKeywords:    e.unknownKeywords.String(),
+		MaxExpansionDepth:  e.maxExpansionDepth,
+		MaxOutputSize:      e.maxOutputSize,
+		MaxTags:            e.maxTags,
+		MaxChoicesPerTag:   e.maxChoices,
+		XMLElementNames:    slices.Clone(e.xmlNames),
+		Dialects:           slices.Clone(e.dialects),
+	}
+	if e.outputChain != nil {
+		c.OutputEncodings = encodingFlagNames(e.outputEncoding & valueEncodings)
+		for _, enc := range e.outputChain {
+			c.OutputEncodings = append(c.OutputEncodings, encodingFlagNames(enc)...)
+		}
+	} else {
+		c.OutputEncodings = encodingFlagNames(e.outputEncoding)
+	}
+	for kw, enabled := range e.keywords.Load().enabled {
+		if !enabled {
+			c.DisabledKeywords = append(c.DisabledKeywords, kw)
+		}
+	}
+	slices.Sort(c.DisabledKeywords)
+	if !slices.Equal(e.mailProviders, SafeMailProviders) {
+		c.MailProviders = slices.Clone(e.mailProviders)
+	}
+	if !slices.Equal(e.userAgents, SafeUserAgents) {
+		c.UserAgents = slices.Clone(e.userAgents)
+	}
+	if !slices.Equal(e.firstNames, SafeFirstNames) {
+		c.FirstNames = slices.Clone(e.firstNames)
+	}
+	if !slices.Equal(e.lastNames, SafeLastNames) {
+		c.LastNames = slices.Clone(e.lastNames)
+	}
+	if charsets := e.keywords.Load().charsets; len(charsets) > 0 {
+		c.CustomCharsets = make(map[string]string, len(charsets))
+		for kw, cs := range charsets {
+			c.CustomCharsets[kw] = string(cs)
+		}
+	}
+	if excluded := e.keywords.Load().excluded; len(excluded) > 0 {
+		c.ExcludedChars = maps.Clone(excluded)
+	}
+	if m := e.charsets.Load(); m != nil {
+		c.Charsets = make(map[string]string, len(*m))
+		for name, cs := range *m {
+			c.Charsets[name] = string(cs)
+		}
+	}
+	if len(e.cycles) > 0 {
+		c.Cycles = make(map[string][]string, len(e.cycles))
+		for name, cy := range e.cycles {
+			items := make([]string, len(cy.items))
+			for i, item := range cy.items {
+				items[i] = string(item)
+			}
+			c.Cycles[name] = items
+		}
+	}
+	return c
+}
+
+// Options returns the options that configure an engine as c describes, or
+// an error for unknown encoding, distribution or policy names.
+func (c EngineConfig) Options() ([]Option, error) {
+	var input RandomizerEncoding
+	for _, name := range c.InputEncodings {
+		enc, err := parseEncodingName(name)
+		if err != nil {
+			return nil, err
+		}
+		input |= enc
+	}
+	output := make([]RandomizerEncoding, 0, len(c.OutputEncodings))
+	for _, name := range c.OutputEncodings {
+		enc, err := parseEncodingName(name)
+		if err != nil {
+			return nil, err
+		}
+		output = append(output, enc)
+	}
+	dist, ok := parseLengthDistribution([]byte(c.LengthDistribution))
+	if !ok {
+		return nil, fmt.Errorf("fastrand: unknown length distribution %q", c.LengthDistribution)
+	}
+	policy, ok := parseUnknownKeywordPolicy(c.UnknownKeywords)
+	if !ok {
+		return nil, fmt.Errorf("fastrand: unknown keyword policy %q", c.UnknownKeywords)
+	}
+	for _, d := range c.Dialects {
+		if !d.valid() {
+			return nil, fmt.Errorf("fastrand: invalid dialect %q %q %q: want a start ending in RAND, an end and a separator", d.Start, d.End, d.Sep)
+		}
+	}
+
+	opts := []Option{
+		WithDefaultLength(c.DefaultLength),
+		WithMinLength(c.MinLength),
+		WithMaxLength(c.MaxLength),
+		WithInputEncoding(input),
+		WithOutputEncodings(output...),
+		WithRanges(c.Ranges),
+		WithKeywordChoices(c.KeywordChoices),
+		WithLengthChoices(c.LengthChoices),
+		WithLengthDistribution(dist),
+		WithStrictParsing(c.StrictParsing),
+		WithRequireKeyword(c.RequireKeyword),
+		WithUnknownKeywordPolicy(policy),
+		WithMaxExpansionDepth(c.MaxExpansionDepth),
+		WithMaxOutputSize(c.MaxOutputSize),
+		WithMaxTags(c.MaxTags),
+		WithMaxChoicesPerTag(c.MaxChoicesPerTag),
+		WithDisabledKeywords(c.DisabledKeywords...),
+		WithMailProviders(c.MailProviders...),
+		WithUserAgents(c.UserAgents...),
+		WithNameLists(c.FirstNames, c.LastNames),
+	}
+	for _, kw := range slices.Sorted(maps.Keys(c.CustomCharsets)) {
+		opts = append(opts, WithCustomCharset(kw, []byte(c.CustomCharsets[kw])))
+	}
+	for _, kw := range slices.Sorted(maps.Keys(c.ExcludedChars)) {
+		opts = append(opts, WithExcludedChars(kw, c.ExcludedChars[kw]))
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.Cycles)) {
+		opts = append(opts, WithCycle(name, c.Cycles[name]...))
+	}
+	if c.XMLElementNames != nil {
+		opts = append(opts, WithXMLElementNames(c.XMLElementNames...))
+	}
+	for _, d := range c.Dialects {
+		opts = append(opts, WithAdditionalDialect(d.Start, d.End, d.Sep))
+	}
+	return opts, nil
+}
+
+// MarshalConfig returns the engine's Config as JSON, so fleets of workers
+// can share identical configuration through NewEngineFromConfig. It fails
+// if a charset or cycle item is not valid UTF-8 and so cannot survive the
+// round trip.
+func (e *FastEngine) MarshalConfig() ([]byte, error) {
+	c := e.Config()
+	for _, m := range []map[string]string{c.CustomCharsets, c.Charsets, c.ExcludedChars} {
+		for name, cs := range m {
+			if !utf8.ValidString(cs) {
+				return nil, fmt.Errorf("fastrand: charset %q is not valid UTF-8", name)
+			}
+		}
+	}
+	for name, items := range c.Cycles {
+		if slices.ContainsFunc(items, func(s string) bool { return !utf8.ValidString(s) }) {
+			return nil, fmt.Errorf("fastrand: cycle %q is not valid UTF-8", name)
+		}
+	}
+	return json.MarshalIndent(c, "", "  ")
+}
+
+// NewEngineFromConfig builds an engine from JSON produced by MarshalConfig
+// or written by hand, applying opts afterwards, for example to add custom
+// keywords. Fields missing from data keep their defaults. Like
+// NewEngineStrict it rejects inconsistent configuration.
+func NewEngineFromConfig(data []byte, opts ...Option) (*FastEngine, error) {
+	c := NewEngine().Config()
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("fastrand: invalid engine config: %w", err)
+	}
+	return c.NewEngine(opts...)
+}
+
+// NewEngine builds an engine configured as c describes, with opts applied
+// afterwards. It rejects inconsistent configuration as NewEngineStrict
+// does.
+func (c EngineConfig) NewEngine(opts ...Option) (*FastEngine, error) {
+	configOpts, err := c.Options()
+	if err != nil {
+		return nil, err
+	}
+	e := NewEngine(append(configOpts, opts...)...)
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(c.Charsets)) {
+		errs = append(errs, e.RegisterCharset(name, CharsList(c.Charsets[name])))
+	}
+	errs = append(errs, e.validate())
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
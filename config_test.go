@@ -0,0 +1,82 @@
+package fastrand_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalConfigRoundTrip(t *testing.T) {
+	engine := fastrand.NewEngine(
+		fastrand.WithDefaultLength(6),
+		fastrand.WithMaxLength(40),
+		fastrand.WithInputEncoding(fastrand.RandomizerEncodingURL|fastrand.RandomizerEncodingUnicode),
+		fastrand.WithOutputEncodings(fastrand.RandomizerEncodingURL, fastrand.RandomizerEncodingBase64, fastrand.RandomizerEncodingJSON),
+		fastrand.WithRanges(false),
+		fastrand.WithLengthDistribution(fastrand.LengthZipf),
+		fastrand.WithDisabledKeywords("email", "IPV6"),
+		fastrand.WithMailProviders("corp.example"),
+		fastrand.WithCustomCharset("ABL", []byte("xyz")),
+		fastrand.WithCycle("env", "dev", "prod"),
+		fastrand.WithXMLElementNames("item"),
+	)
+	require.NoError(t, engine.RegisterCharset("GREEK", fastrand.CharsList("αβγ")))
+
+	data, err := engine.MarshalConfig()
+	require.NoError(t, err)
+	restored, err := fastrand.NewEngineFromConfig(data)
+	require.NoError(t, err)
+	assert.Equal(t, engine.Config(), restored.Config())
+
+	cfg := restored.Config()
+	assert.Equal(t, []string{"url", "unicode"}, cfg.InputEncodings)
+	assert.Equal(t, []string{"json", "url", "base64"}, cfg.OutputEncodings)
+	assert.Equal(t, []string{"EMAIL", "IPV6"}, cfg.DisabledKeywords)
+	assert.Equal(t, "zipf", cfg.LengthDistribution)
+	assert.Equal(t, []string{"corp.example"}, restored.MailProviders())
+	assert.Equal(t, map[string]string{"GREEK": "αβγ"}, cfg.Charsets)
+	assert.Equal(t, map[string][]string{"env": {"dev", "prod"}}, cfg.Cycles)
+}
+
+func TestMarshalConfigDefaults(t *testing.T) {
+	data, err := fastrand.NewEngine().MarshalConfig()
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "mail_providers", "the built-in provider list is left out")
+
+	var fields map[string]any
+	require.NoError(t, json.Unmarshal(data, &fields))
+	assert.EqualValues(t, 16, fields["default_length"])
+	assert.Equal(t, []any{"url", "html"}, fields["input_encodings"])
+}
+
+func TestNewEngineFromConfig(t *testing.T) {
+	engine, err := fastrand.NewEngineFromConfig([]byte(`{"default_length": 4, "disabled_keywords": ["uuid"]}`),
+		fastrand.WithCustomKeyword("PING", func(int) []byte { return []byte("pong") }))
+	require.NoError(t, err)
+	assert.Regexp(t, `^[0-9]{4}$`, engine.RandomizerString("{RAND;DIGIT}"))
+	assert.Regexp(t, `^[0-9]{1,3}$`, engine.RandomizerString("{RAND;1-3;DIGIT}"), "omitted fields keep their defaults")
+	assert.Len(t, engine.RandomizerString("{RAND;UUID}"), 4, "UUID is disabled")
+	assert.Equal(t, "pong", engine.RandomizerString("{RAND;PING}"))
+}
+
+func TestNewEngineFromConfigErrors(t *testing.T) {
+	cases := map[string]string{
+		`{"default_length": `:                  "invalid engine config",
+		`{"input_encodings": ["rot13"]}`:       `unknown encoding "rot13"`,
+		`{"length_distribution": "pareto"}`:    `unknown length distribution "pareto"`,
+		`{"min_length": 50, "max_length": 10}`: "min length 50 exceeds max length 10",
+		`{"charsets": {"HEX": "ab"}}`:          "is a keyword",
+	}
+	for data, want := range cases {
+		engine, err := fastrand.NewEngineFromConfig([]byte(data))
+		assert.Nil(t, engine, data)
+		assert.ErrorContains(t, err, want, data)
+	}
+
+	engine := fastrand.NewEngine(fastrand.WithCustomCharset("BIN", []byte{0xff, 0xfe}))
+	_, err := engine.MarshalConfig()
+	assert.ErrorContains(t, err, "not valid UTF-8")
+}
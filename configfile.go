@@ -0,0 +1,425 @@
+package fastrand
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// LoadEngine builds an engine from the configuration file at path, applying
+// opts afterwards. Files ending in .json hold MarshalConfig output; any
+// other file uses the sectioned format read by ParseEngineConfig:
+//
+//	# fastrand.conf
+//	[limits]
+//	default_length = 12
+//	max_length = 64
+//
+//	[keywords]
+//	disabled = ["EMAIL", "IPV6"]
+//	length_distribution = "zipf"
+//
+//	[encoding]
+//	input = ["url", "html"]
+//	output = ["json"]
+//
+//	[providers]
+//	mail = ["corp.example", "test.example"]
+//	user_agents = ["scanner/1.0", "curl/8.7.1"]
+//
+//	[names]
+//	first = ["Alex", "Sam"]
+//	last = ["Rivera", "Chen"]
+//
+//	[charsets]
+//	VOWEL = "aeiou"
+//
+//	[custom_charsets]
+//	ABL = 'xyz'
+//
+//	[excluded_chars]
+//	ABR = "l1IO0"
+//
+//	[cycles]
+//	env = ["dev", "staging", "prod"]
+//
+//	[xml]
+//	element_names = ["item", "row"]
+//
+//	[dialects]
+//	shell = ["${RAND", "}", ":"]
+func LoadEngine(path string, opts ...Option) (*FastEngine, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return NewEngineFromConfig(data, opts...)
+	}
+	c, err := ParseEngineConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w in %s", err, path)
+	}
+	return c.NewEngine(opts...)
+}
+
+// ParseEngineConfig reads an engine configuration in the sectioned format
+// shown at LoadEngine, a small subset of TOML: [section] headers, key =
+// value lines and # comments. Values are integers, true or false,
+// double-quoted strings with Go escapes, single-quoted raw strings, or
+// arrays of strings, which may span lines. [charsets] registers new
+// charsets, [custom_charsets] overrides the charsets of built-in keywords,
+// [excluded_chars] removes characters from charsets as WithExcludedChars
+// does and [cycles] defines CYCLE lists. [dialects] adds tag dialects in file
+// order, each as a labeled [start, end, separator] array; the labels are
+// only for readers. Settings that are not given keep their NewEngine
+// defaults; unknown sections and keys are errors.
+func ParseEngineConfig(data []byte) (EngineConfig, error) {
+	c := NewEngine().Config()
+	p := configParser{rest: data}
+	section := ""
+	for p.more() {
+		line, lineNo := p.line()
+		if len(line) == 0 {
+			continue
+		}
+		if line[0] == '[' {
+			if line[len(line)-1] != ']' {
+				return c, configError(lineNo, "unterminated section header")
+			}
+			section = string(bytes.TrimSpace(line[1 : len(line)-1]))
+			if _, ok := configKeys[section]; !ok && !isConfigMapSection(section) {
+				return c, configError(lineNo, fmt.Sprintf("unknown section [%s]", section))
+			}
+			continue
+		}
+		eq := bytes.IndexByte(line, '=')
+		if eq == -1 {
+			return c, configError(lineNo, "expected key = value")
+		}
+		key := string(bytes.TrimSpace(line[:eq]))
+		raw := bytes.TrimSpace(line[eq+1:])
+		if len(raw) > 0 && raw[0] == '[' {
+			raw = p.continueArray(raw)
+		}
+		v, err := parseConfigValue(raw)
+		if err != nil {
+			return c, configError(lineNo, err.Error())
+		}
+		if err := setConfigValue(&c, section, key, v); err != nil {
+			return c, configError(lineNo, err.Error())
+		}
+	}
+	return c, nil
+}
+
+func configError(line int, reason string) error {
+	return fmt.Errorf("fastrand: config line %d: %s", line, reason)
+}
+
+// configKeys maps the keys of each fixed section to the EngineConfig field
+// they set.
+var configKeys = map[string]map[string]func(c *EngineConfig, v configValue) error{
+	"limits": {
+		"default_length":      func(c *EngineConfig, v configValue) error { return v.asInt(&c.DefaultLength) },
+		"min_length":          func(c *EngineConfig, v configValue) error { return v.asInt(&c.MinLength) },
+		"max_length":          func(c *EngineConfig, v configValue) error { return v.asInt(&c.MaxLength) },
+		"max_expansion_depth": func(c *EngineConfig, v configValue) error { return v.asInt(&c.MaxExpansionDepth) },
+		"max_output_size":     func(c *EngineConfig, v configValue) error { return v.asInt(&c.MaxOutputSize) },
+		"max_tags":            func(c *EngineConfig, v configValue) error { return v.asInt(&c.MaxTags) },
+		"max_choices_per_tag": func(c *EngineConfig, v configValue) error { return v.asInt(&c.MaxChoicesPerTag) },
+	},
+	"keywords": {
+		"disabled":            func(c *EngineConfig, v configValue) error { return v.asStrings(&c.DisabledKeywords) },
+		"ranges":              func(c *EngineConfig, v configValue) error { return v.asBool(&c.Ranges) },
+		"keyword_choices":     func(c *EngineConfig, v configValue) error { return v.asBool(&c.KeywordChoices) },
+		"length_choices":      func(c *EngineConfig, v configValue) error { return v.asBool(&c.LengthChoices) },
+		"length_distribution": func(c *EngineConfig, v configValue) error { return v.asString(&c.LengthDistribution) },
+		"strict_parsing":      func(c *EngineConfig, v configValue) error { return v.asBool(&c.StrictParsing) },
+		"require_keyword":     func(c *EngineConfig, v configValue) error { return v.asBool(&c.RequireKeyword) },
+		"unknown_keywords":    func(c *EngineConfig, v configValue) error { return v.asString(&c.UnknownKeywords) },
+	},
+	"encoding": {
+		"input":  func(c *EngineConfig, v configValue) error { return v.asStrings(&c.InputEncodings) },
+		"output": func(c *EngineConfig, v configValue) error { return v.asStrings(&c.OutputEncodings) },
+	},
+	"providers": {
+		"mail":        func(c *EngineConfig, v configValue) error { return v.asStrings(&c.MailProviders) },
+		"user_agents": func(c *EngineConfig, v configValue) error { return v.asStrings(&c.UserAgents) },
+	},
+	"names": {
+		"first": func(c *EngineConfig, v configValue) error { return v.asStrings(&c.FirstNames) },
+		"last":  func(c *EngineConfig, v configValue) error { return v.asStrings(&c.LastNames) },
+	},
+	"xml": {
+		"element_names": func(c *EngineConfig, v configValue) error { return v.asStrings(&c.XMLElementNames) },
+	},
+}
+
+// isConfigMapSection reports whether section holds arbitrary names rather
+// than fixed keys.
+func isConfigMapSection(section string) bool {
+	switch section {
+	case "charsets", "custom_charsets", "excluded_chars", "cycles", "dialects":
+		return true
+	}
+	return false
+}
+
+func setConfigValue(c *EngineConfig, section, key string, v configValue) error {
+	var s string
+	switch section {
+	case "charsets":
+		if err := v.asString(&s); err != nil {
+			return err
+		}
+		if c.Charsets == nil {
+			c.Charsets = make(map[string]string)
+		}
+		c.Charsets[key] = s
+		return nil
+	case "custom_charsets":
+		if err := v.asString(&s); err != nil {
+			return err
+		}
+		if c.CustomCharsets == nil {
+			c.CustomCharsets = make(map[string]string)
+		}
+		c.CustomCharsets[key] = s
+		return nil
+	case "excluded_chars":
+		if err := v.asString(&s); err != nil {
+			return err
+		}
+		if c.ExcludedChars == nil {
+			c.ExcludedChars = make(map[string]string)
+		}
+		c.ExcludedChars[key] = s
+		return nil
+	case "cycles":
+		var items []string
+		if err := v.asStrings(&items); err != nil {
+			return err
+		}
+		if c.Cycles == nil {
+			c.Cycles = make(map[string][]string)
+		}
+		c.Cycles[key] = items
+		return nil
+	case "dialects":
+		var parts []string
+		if err := v.asStrings(&parts); err != nil {
+			return err
+		}
+		if len(parts) != 3 {
+			return fmt.Errorf("expected [start, end, separator] for dialect %q", key)
+		}
+		c.Dialects = append(c.Dialects, Dialect{Start: parts[0], End: parts[1], Sep: parts[2]})
+		return nil
+	}
+	set, ok := configKeys[section][key]
+	if !ok {
+		if section == "" {
+			return fmt.Errorf("key %q outside a section", key)
+		}
+		return fmt.Errorf("unknown key %q in [%s]", key, section)
+	}
+	return set(c, v)
+}
+
+// configParser splits a configuration file into lines with comments and
+// surrounding space removed.
+type configParser struct {
+	rest   []byte
+	lineNo int
+}
+
+func (p *configParser) more() bool {
+	return len(p.rest) > 0
+}
+
+func (p *configParser) line() ([]byte, int) {
+	var line []byte
+	if i := bytes.IndexByte(p.rest, '\n'); i != -1 {
+		line, p.rest = p.rest[:i], p.rest[i+1:]
+	} else {
+		line, p.rest = p.rest, nil
+	}
+	p.lineNo++
+	return bytes.TrimSpace(stripConfigComment(line)), p.lineNo
+}
+
+// continueArray appends following lines to an array value until its
+// closing bracket.
+func (p *configParser) continueArray(raw []byte) []byte {
+	for !arrayClosed(raw) && p.more() {
+		next, _ := p.line()
+		raw = append(append(raw[:len(raw):len(raw)], ' '), next...)
+	}
+	return raw
+}
+
+// stripConfigComment removes a # comment that is not inside a string.
+func stripConfigComment(line []byte) []byte {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+func arrayClosed(raw []byte) bool {
+	var quote byte
+	for i := 0; i < len(raw); i++ {
+		switch c := raw[i]; {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == ']':
+			return true
+		}
+	}
+	return false
+}
+
+// configValue is a parsed value: a string, an integer, a bool or a list of
+// strings.
+type configValue struct {
+	kind  byte // 's', 'i', 'b' or 'a'
+	str   string
+	num   int
+	items []string
+}
+
+func (v configValue) asInt(dst *int) error {
+	if v.kind != 'i' {
+		return fmt.Errorf("expected an integer")
+	}
+	*dst = v.num
+	return nil
+}
+
+func (v configValue) asBool(dst *bool) error {
+	if v.kind != 'b' {
+		return fmt.Errorf("expected true or false")
+	}
+	*dst = v.num != 0
+	return nil
+}
+
+func (v configValue) asString(dst *string) error {
+	if v.kind != 's' {
+		return fmt.Errorf("expected a string")
+	}
+	*dst = v.str
+	return nil
+}
+
+func (v configValue) asStrings(dst *[]string) error {
+	if v.kind != 'a' {
+		return fmt.Errorf("expected an array of strings")
+	}
+	*dst = v.items
+	return nil
+}
+
+func parseConfigValue(raw []byte) (configValue, error) {
+	switch {
+	case len(raw) == 0:
+		return configValue{}, fmt.Errorf("missing value")
+	case raw[0] == '[':
+		return parseConfigArray(raw)
+	case raw[0] == '"' || raw[0] == '\'':
+		s, rest, err := parseConfigString(raw)
+		if err != nil {
+			return configValue{}, err
+		}
+		if len(bytes.TrimSpace(rest)) > 0 {
+			return configValue{}, fmt.Errorf("unexpected %q after string", rest)
+		}
+		return configValue{kind: 's', str: s}, nil
+	case string(raw) == "true":
+		return configValue{kind: 'b', num: 1}, nil
+	case string(raw) == "false":
+		return configValue{kind: 'b'}, nil
+	}
+	n, err := strconv.Atoi(string(raw))
+	if err != nil {
+		return configValue{}, fmt.Errorf("invalid value %q", raw)
+	}
+	return configValue{kind: 'i', num: n}, nil
+}
+
+func parseConfigArray(raw []byte) (configValue, error) {
+	v := configValue{kind: 'a', items: []string{}}
+	rest := bytes.TrimSpace(raw[1:])
+	for {
+		rest = bytes.TrimLeft(rest, " \t\r")
+		switch {
+		case len(rest) == 0:
+			return configValue{}, fmt.Errorf("unterminated array")
+		case rest[0] == ']':
+			if len(bytes.TrimSpace(rest[1:])) > 0 {
+				return configValue{}, fmt.Errorf("unexpected %q after array", rest[1:])
+			}
+			return v, nil
+		}
+		s, after, err := parseConfigString(rest)
+		if err != nil {
+			return configValue{}, err
+		}
+		v.items = append(v.items, s)
+		rest = bytes.TrimLeft(after, " \t\r")
+		if len(rest) > 0 && rest[0] == ',' {
+			rest = rest[1:]
+		} else if len(rest) > 0 && rest[0] != ']' {
+			return configValue{}, fmt.Errorf("expected ',' or ']' in array")
+		}
+	}
+}
+
+// parseConfigString parses the quoted string at the start of raw and
+// returns it with the remaining input.
+func parseConfigString(raw []byte) (string, []byte, error) {
+	if len(raw) == 0 || raw[0] != '"' && raw[0] != '\'' {
+		return "", nil, fmt.Errorf("expected a quoted string")
+	}
+	quote := raw[0]
+	for i := 1; i < len(raw); i++ {
+		switch raw[i] {
+		case '\\':
+			if quote == '"' {
+				i++
+			}
+		case quote:
+			if quote == '\'' {
+				return string(raw[1:i]), raw[i+1:], nil
+			}
+			s, err := strconv.Unquote(string(raw[:i+1]))
+			if err != nil {
+				return "", nil, fmt.Errorf("invalid string %s", raw[:i+1])
+			}
+			return s, raw[i+1:], nil
+		}
+	}
+	return "", nil, fmt.Errorf("unterminated string")
+}
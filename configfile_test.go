@@ -0,0 +1,109 @@
+package fastrand_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const engineConfigFile = `# fastrand.conf
+[limits]
+default_length = 6   # short by default
+max_length = 64
+
+[keywords]
+disabled = ["EMAIL", "IPV6"]
+length_distribution = "zipf"
+ranges = false
+
+[encoding]
+input = ["url"]
+output = []
+
+[providers]
+mail = [
+	"corp.example",  # primary
+	"test.example",
+]
+
+[charsets]
+VOWEL = "aeiou"
+
+[custom_charsets]
+ABL = 'x#z'
+
+[cycles]
+env = ["dev", "prod"]
+
+[xml]
+element_names = ["item"]
+`
+
+func TestParseEngineConfig(t *testing.T) {
+	c, err := fastrand.ParseEngineConfig([]byte(engineConfigFile))
+	require.NoError(t, err)
+	assert.Equal(t, 6, c.DefaultLength)
+	assert.Equal(t, 1, c.MinLength, "unset values keep their defaults")
+	assert.Equal(t, 64, c.MaxLength)
+	assert.Equal(t, []string{"EMAIL", "IPV6"}, c.DisabledKeywords)
+	assert.Equal(t, "zipf", c.LengthDistribution)
+	assert.False(t, c.Ranges)
+	assert.True(t, c.KeywordChoices)
+	assert.Equal(t, []string{"url"}, c.InputEncodings)
+	assert.Empty(t, c.OutputEncodings)
+	assert.Equal(t, []string{"corp.example", "test.example"}, c.MailProviders)
+	assert.Equal(t, map[string]string{"VOWEL": "aeiou"}, c.Charsets)
+	assert.Equal(t, map[string]string{"ABL": "x#z"}, c.CustomCharsets)
+	assert.Equal(t, map[string][]string{"env": {"dev", "prod"}}, c.Cycles)
+	assert.Equal(t, []string{"item"}, c.XMLElementNames)
+}
+
+func TestLoadEngine(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "fastrand.conf")
+	require.NoError(t, os.WriteFile(path, []byte(engineConfigFile), 0o600))
+
+	engine, err := fastrand.LoadEngine(path, fastrand.WithCustomKeyword("PING", func(int) []byte { return []byte("pong") }))
+	require.NoError(t, err)
+	assert.Regexp(t, `^[aeiou]{6}$`, engine.RandomizerString("{RAND;VOWEL}"))
+	assert.Regexp(t, `^[x#z]{6}$`, engine.RandomizerString("{RAND;ABL}"))
+	assert.Len(t, engine.RandomizerString("{RAND;EMAIL}"), 6, "EMAIL is disabled")
+	assert.Equal(t, []string{"corp.example", "test.example"}, engine.MailProviders())
+	assert.Equal(t, "dev prod pong", engine.RandomizerString("{RAND;CYCLE:env} {RAND;CYCLE:env} {RAND;PING}"))
+
+	jsonPath := filepath.Join(dir, "engine.json")
+	data, err := engine.MarshalConfig()
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(jsonPath, data, 0o600))
+	fromJSON, err := fastrand.LoadEngine(jsonPath)
+	require.NoError(t, err)
+	assert.Equal(t, engine.Config(), fromJSON.Config())
+
+	_, err = fastrand.LoadEngine(filepath.Join(dir, "missing.conf"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestParseEngineConfigErrors(t *testing.T) {
+	cases := map[string]string{
+		"[limits":                          "line 1: unterminated section header",
+		"[colors]":                         "line 1: unknown section [colors]",
+		"default_length = 4":               `key "default_length" outside a section`,
+		"[limits]\nmin = 4":                `line 2: unknown key "min" in [limits]`,
+		"[limits]\ndefault_length":         "line 2: expected key = value",
+		"[limits]\ndefault_length = \"4\"": "expected an integer",
+		"[keywords]\nranges = yes":         `invalid value "yes"`,
+		"[keywords]\ndisabled = [\"A\"":    "unterminated array",
+		"[keywords]\ndisabled = [A]":       "expected a quoted string",
+		"[charsets]\nX = \"abc":            "unterminated string",
+		"[charsets]\nX = \"a\" b":          "after string",
+		"[cycles]\nenv = \"dev\"":          "expected an array of strings",
+	}
+	for data, want := range cases {
+		_, err := fastrand.ParseEngineConfig([]byte(data))
+		assert.ErrorContains(t, err, want, data)
+	}
+}
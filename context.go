@@ -0,0 +1,40 @@
+package fastrand
+
+import "context"
+
+// RandomizerCtx expands payload with the default engine, stopping early
+// when ctx is cancelled. See FastEngine.RandomizerCtx.
+func RandomizerCtx(ctx context.Context, payload []byte) ([]byte, error) {
+	return defaultEngine.Load().RandomizerCtx(ctx, payload)
+}
+
+// RandomizerCtx is like Randomizer but checks ctx between tags, including
+// every pass through a repeat block, so the expansion of a very large
+// payload can be abandoned when, for example, the HTTP request that asked
+// for it is cancelled. It returns ctx.Err() once ctx is done; a custom
+// keyword generator that is already running is not interrupted. It also
+// reports malformed tags, unknown keywords and exceeded budgets as
+// RandomizerErr does.
+func (e *FastEngine) RandomizerCtx(ctx context.Context, payload []byte) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if ctx.Done() == nil && !e.strictParsing && e.unknownKeywords != UnknownKeywordError {
+		return e.Randomizer(payload), nil
+	}
+	normalized, scratch := e.normalized(payload)
+	defer e.release(scratch)
+	if err := e.checkPayload(normalized); err != nil {
+		return nil, err
+	}
+	x := expansion{done: ctx.Done()}
+	buf := make([]byte, 0, len(normalized)+512)
+	err := e.expandInto(normalized, &buf, &x)
+	if x.cancelled {
+		return nil, ctx.Err()
+	}
+	if err != nil && e.strictParsing {
+		return nil, err
+	}
+	return buf, nil
+}
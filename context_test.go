@@ -0,0 +1,71 @@
+package fastrand_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRandomizerCtx(t *testing.T) {
+	out, err := fastrand.RandomizerCtx(context.Background(), []byte("id={RAND;8;DIGIT}"))
+	require.NoError(t, err)
+	assert.Len(t, out, 11)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	out, err = fastrand.NewEngine().RandomizerCtx(ctx, []byte("plain text"))
+	require.NoError(t, err)
+	assert.Equal(t, "plain text", string(out))
+}
+
+func TestRandomizerCtx_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	out, err := fastrand.RandomizerCtx(ctx, []byte("{RAND;HEX}"))
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, out)
+}
+
+func TestRandomizerCtx_CancelledBetweenTags(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	engine := fastrand.NewEngine(fastrand.WithCustomKeyword("SLOW", func(int) []byte {
+		if calls++; calls == 3 {
+			cancel()
+		}
+		return []byte("x")
+	}))
+
+	out, err := engine.RandomizerCtx(ctx, []byte("{RAND-REPEAT;10000}{RAND;SLOW}{/RAND-REPEAT}"))
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, out)
+	assert.Equal(t, 3, calls, "no tag is expanded after cancellation")
+}
+
+func TestRandomizerCtx_Deadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	engine := fastrand.NewEngine(fastrand.WithCustomKeyword("SLOW", func(int) []byte {
+		time.Sleep(time.Millisecond)
+		return []byte("x")
+	}))
+
+	_, err := engine.RandomizerCtx(ctx, []byte("{RAND-REPEAT;10000}{RAND;SLOW}{/RAND-REPEAT}"))
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+func TestRandomizerCtx_Strict(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithStrictParsing(true), fastrand.WithMaxTags(2))
+
+	var tagErr *fastrand.TagError
+	_, err := engine.RandomizerCtx(context.Background(), []byte("{RAND;8;NOPE}"))
+	assert.ErrorAs(t, err, &tagErr)
+
+	_, err = engine.RandomizerCtx(context.Background(), []byte("{RAND-REPEAT;2}{RAND;HEX}{/RAND-REPEAT}"))
+	assert.ErrorIs(t, err, fastrand.ErrTooManyTags)
+}
@@ -0,0 +1,201 @@
+package fastrand
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"time"
+)
+
+const secondsPerDay = 24 * 60 * 60
+
+// defaultDateMin and defaultDateMax bound DATE without a range: the years
+// 32-bit Unix time covers.
+var (
+	defaultDateMin = time.Date(1970, time.January, 1, 0, 0, 0, 0, time.UTC)
+	defaultDateMax = time.Date(2037, time.December, 31, 0, 0, 0, 0, time.UTC)
+)
+
+// positionalParam returns the first field of a parameter list that is not
+// a name=value pair, such as the range in DATE(2000-2030).
+func positionalParam(params []byte) ([]byte, bool) {
+	for len(params) > 0 {
+		var field []byte
+		field, params, _ = bytes.Cut(params, []byte{','})
+		if bytes.IndexByte(field, '=') == -1 {
+			return bytes.TrimSpace(field), true
+		}
+	}
+	return nil, false
+}
+
+// takesPositional reports whether kw accepts a range without a name in its
+// parameter list.
+func (k *keywordSpec) takesPositional() bool {
+	if k.custom != nil || k.charset != nil || k.fallback {
+		return false
+	}
+	switch k.upper() {
+	case "DATE", "TIME":
+		return true
+	}
+	return false
+}
+
+// parseIntRange parses "a-b" or "a" into an inclusive range within
+// [lo, hi].
+func parseIntRange(v []byte, lo, hi int) (int, int, bool) {
+	first, last, isRange := bytes.Cut(v, []byte{'-'})
+	a, ok := parseLengthFast(first)
+	if !ok {
+		return 0, 0, false
+	}
+	b := a
+	if isRange {
+		if b, ok = parseLengthFast(last); !ok {
+			return 0, 0, false
+		}
+	}
+	return a, b, a >= lo && b <= hi && a <= b
+}
+
+// parseClock parses "15:04" or "15:04:05" into seconds since midnight.
+func parseClock(v []byte) (int, bool) {
+	h, rest, ok := bytes.Cut(v, []byte{':'})
+	if !ok {
+		return 0, false
+	}
+	m, sec, hasSec := bytes.Cut(rest, []byte{':'})
+	hh, okH := parseLengthFast(h)
+	mm, okM := parseLengthFast(m)
+	ss, okS := 0, true
+	if hasSec {
+		ss, okS = parseLengthFast(sec)
+	}
+	if !okH || !okM || !okS || hh > 23 || mm > 59 || ss > 59 {
+		return 0, false
+	}
+	return hh*3600 + mm*60 + ss, true
+}
+
+// dateBounds returns the days a DATE keyword draws from, from its year
+// range and its min and max dates, and why they are invalid, or "".
+func dateBounds(params []byte) (lo, hi time.Time, reason string) {
+	lo, hi = defaultDateMin, defaultDateMax
+	if v, ok := positionalParam(params); ok {
+		first, last, ok := parseIntRange(v, 1, 9999)
+		if !ok {
+			return defaultDateMin, defaultDateMax, fmt.Sprintf("invalid year range %q: want 2000-2030 within 1-9999", v)
+		}
+		lo = time.Date(first, time.January, 1, 0, 0, 0, 0, time.UTC)
+		hi = time.Date(last, time.December, 31, 0, 0, 0, 0, time.UTC)
+	}
+	if lo, reason = dateParam(params, "min", lo); reason != "" {
+		return defaultDateMin, defaultDateMax, reason
+	}
+	if hi, reason = dateParam(params, "max", hi); reason != "" {
+		return defaultDateMin, defaultDateMax, reason
+	}
+	if hi.Before(lo) {
+		return defaultDateMin, defaultDateMax, "min is after max"
+	}
+	return lo, hi, ""
+}
+
+// clockBounds returns the seconds since midnight a TIME keyword draws from,
+// from its hour range and its min and max times, and why they are invalid,
+// or "".
+func clockBounds(params []byte) (lo, hi int, reason string) {
+	lo, hi = 0, secondsPerDay-1
+	if v, ok := positionalParam(params); ok {
+		first, last, ok := parseIntRange(v, 0, 23)
+		if !ok {
+			return 0, secondsPerDay - 1, fmt.Sprintf("invalid hour range %q: want 9-17 within 0-23", v)
+		}
+		lo, hi = first*3600, last*3600+3599
+	}
+	if lo, reason = clockParam(params, "min", lo); reason != "" {
+		return 0, secondsPerDay - 1, reason
+	}
+	if hi, reason = clockParam(params, "max", hi); reason != "" {
+		return 0, secondsPerDay - 1, reason
+	}
+	if hi < lo {
+		return 0, secondsPerDay - 1, "min is after max"
+	}
+	return lo, hi, ""
+}
+
+// dateParam returns the date in the named parameter, or def when there is
+// none, and why it is invalid, or "".
+func dateParam(params []byte, name string, def time.Time) (time.Time, string) {
+	v, ok := keywordParam(params, name)
+	if !ok {
+		return def, ""
+	}
+	t, err := time.Parse(time.DateOnly, unsafeString(v))
+	if err != nil {
+		return def, fmt.Sprintf("invalid %s %q: want 2006-01-02", name, v)
+	}
+	return t, ""
+}
+
+// clockParam returns the time of day in the named parameter, in seconds
+// since midnight, or def when there is none, and why it is invalid, or "".
+func clockParam(params []byte, name string, def int) (int, string) {
+	v, ok := keywordParam(params, name)
+	if !ok {
+		return def, ""
+	}
+	s, ok := parseClock(v)
+	if !ok {
+		return def, fmt.Sprintf("invalid %s %q: want 15:04 or 15:04:05", name, v)
+	}
+	return s, ""
+}
+
+// separator returns a DATE or TIME keyword's sep parameter, or def.
+func separator(params []byte, def string) []byte {
+	if v, ok := keywordParam(params, "sep"); ok {
+		return v
+	}
+	return s2b(def)
+}
+
+// appendDate appends a calendar date such as 2021-07-14 drawn uniformly
+// from the range of a DATE keyword. An invalid range falls back to the
+// default one.
+func (e *FastEngine) appendDate(out *[]byte, params []byte) {
+	lo, hi, _ := dateBounds(params)
+	days := uint64(hi.Sub(lo) / (secondsPerDay * time.Second))
+	y, m, d := lo.AddDate(0, 0, int(uint64N(e.next, days+1))).Date()
+	sep := separator(params, "-")
+	*out = appendPadded(*out, uint64(y), 4)
+	*out = append(*out, sep...)
+	*out = appendPadded(*out, uint64(m), 2)
+	*out = append(*out, sep...)
+	*out = appendPadded(*out, uint64(d), 2)
+}
+
+// appendTime appends a clock time such as 09:26:53 drawn uniformly from the
+// range of a TIME keyword. An invalid range falls back to the whole day.
+func (e *FastEngine) appendTime(out *[]byte, params []byte) {
+	lo, hi, _ := clockBounds(params)
+	s := uint64(lo) + uint64N(e.next, uint64(hi-lo+1))
+	sep := separator(params, ":")
+	*out = appendPadded(*out, s/3600, 2)
+	*out = append(*out, sep...)
+	*out = appendPadded(*out, s/60%60, 2)
+	*out = append(*out, sep...)
+	*out = appendPadded(*out, s%60, 2)
+}
+
+// dateTimeEntropy returns the bits of entropy of a DATE or TIME keyword.
+func dateTimeEntropy(kw *keywordSpec) float64 {
+	if kw.upper() == "DATE" {
+		lo, hi, _ := dateBounds(kw.params)
+		return math.Log2(float64(hi.Sub(lo)/(secondsPerDay*time.Second)) + 1)
+	}
+	lo, hi, _ := clockBounds(kw.params)
+	return math.Log2(float64(hi-lo) + 1)
+}
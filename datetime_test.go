@@ -0,0 +1,88 @@
+package fastrand_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDate(t *testing.T) {
+	for i := 0; i < 200; i++ {
+		out := fastrand.RandomizerString("{RAND;DATE(2000-2030)}")
+		d, err := time.Parse(time.DateOnly, out)
+		require.NoError(t, err, out)
+		assert.GreaterOrEqual(t, d.Year(), 2000)
+		assert.LessOrEqual(t, d.Year(), 2030)
+
+		d, err = time.Parse(time.DateOnly, fastrand.RandomizerString("{RAND;date}"))
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, d.Year(), 1970)
+		assert.LessOrEqual(t, d.Year(), 2037)
+	}
+	assert.Regexp(t, `^2024/\d\d/\d\d$`, fastrand.RandomizerString("{RAND;DATE(2024,sep=/)}"))
+	assert.Regexp(t, `^2024\d{4}$`, fastrand.RandomizerString("{RAND;DATE(2024,sep=)}"))
+	assert.Equal(t, "2024.02.29", fastrand.RandomizerString("{RAND;DATE(min=2024-02-29,max=2024-02-29,sep=.)}"))
+	assert.Contains(t, []string{"2023-12-31", "2024-01-01"}, fastrand.RandomizerString("{RAND;DATE(min=2023-12-31,max=2024-01-01)}"))
+}
+
+func TestTime(t *testing.T) {
+	for i := 0; i < 200; i++ {
+		out := fastrand.RandomizerString("{RAND;TIME(9-17)}")
+		c, err := time.Parse(time.TimeOnly, out)
+		require.NoError(t, err, out)
+		assert.GreaterOrEqual(t, c.Hour(), 9)
+		assert.LessOrEqual(t, c.Hour(), 17)
+
+		_, err = time.Parse(time.TimeOnly, fastrand.RandomizerString("{RAND;TIME}"))
+		require.NoError(t, err)
+	}
+	assert.Regexp(t, `^12\.\d\d\.\d\d$`, fastrand.RandomizerString("{RAND;TIME(12,sep=.)}"))
+	assert.Equal(t, "08:30:00", fastrand.RandomizerString("{RAND;TIME(min=08:30,max=08:30)}"))
+	assert.Equal(t, "235959", fastrand.RandomizerString("{RAND;TIME(min=23:59:59,sep=)}"))
+}
+
+func TestDateTimeInvalid(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithStrictParsing(true))
+	for payload, reason := range map[string]string{
+		"{RAND;DATE(2030-2000)}":                     `invalid year range "2030-2000"`,
+		"{RAND;DATE(min=2024-13-01)}":                `invalid min "2024-13-01"`,
+		"{RAND;DATE(min=2024-02-01,max=2024-01-01)}": "min is after max",
+		"{RAND;TIME(9-24)}":                          `invalid hour range "9-24"`,
+		"{RAND;TIME(max=25:00)}":                     `invalid max "25:00"`,
+		"{RAND;TIME(format=x)}":                      `unknown parameter "format"`,
+		"{RAND;HEX(9-17)}":                           `malformed parameter "9-17"`,
+	} {
+		_, err := engine.RandomizerErr([]byte(payload))
+		assert.ErrorContains(t, err, reason, payload)
+	}
+	_, err := engine.RandomizerErr([]byte("{RAND;DATE(2000-2030,sep=/)}{RAND;TIME(min=09:00,max=17:30:15)}"))
+	assert.NoError(t, err)
+
+	d, err := time.Parse(time.DateOnly, fastrand.RandomizerString("{RAND;DATE(2030-2000)}"))
+	require.NoError(t, err, "an invalid range falls back to the default")
+	assert.GreaterOrEqual(t, d.Year(), 1970)
+}
+
+func TestDateTimeEntropy(t *testing.T) {
+	bits, err := fastrand.TagEntropy("{RAND;DATE(min=2024-01-01,max=2024-01-08)}")
+	require.NoError(t, err)
+	assert.InDelta(t, 3, bits, 1e-9)
+
+	bits, err = fastrand.TagEntropy("{RAND;TIME}")
+	require.NoError(t, err)
+	assert.InDelta(t, 16.4, bits, 0.01)
+}
+
+func TestAllocsDateTime(t *testing.T) {
+	engine := fastrand.NewEngine()
+	payload := []byte("{RAND;DATE(2000-2030)}T{RAND;TIME(9-17)}")
+	dst := make([]byte, 0, 64)
+	dst = engine.RandomizerAppend(dst, payload)
+	allocs := testing.AllocsPerRun(100, func() {
+		dst = engine.RandomizerAppend(dst[:0], payload)
+	})
+	assert.Zero(t, allocs)
+}
@@ -0,0 +1,29 @@
+package fastrand_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetDefaultEngine(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithDisabledKeywords("EMAIL"), fastrand.WithDefaultLength(4))
+	prev := fastrand.SetDefaultEngine(engine)
+	t.Cleanup(func() { fastrand.SetDefaultEngine(prev) })
+
+	assert.Same(t, engine, fastrand.DefaultEngine())
+	assert.Regexp(t, `^[0-9]{4}$`, fastrand.RandomizerString("{RAND;DIGIT}"))
+	assert.Regexp(t, `^[0-9]{4}$`, string(fastrand.Randomizer([]byte("{RAND;DIGIT}"))))
+	assert.Len(t, fastrand.RandomizerString("{RAND;8;EMAIL}"), 8, "EMAIL is disabled")
+
+	var buf bytes.Buffer
+	_, err := fastrand.RandomizerTo(&buf, []byte("{RAND;HEX}"))
+	assert.NoError(t, err)
+	assert.Len(t, buf.String(), 8)
+
+	assert.Same(t, engine, fastrand.SetDefaultEngine(nil))
+	assert.NotSame(t, engine, fastrand.DefaultEngine())
+	assert.Regexp(t, `^[0-9]{16}$`, fastrand.RandomizerString("{RAND;DIGIT}"), "nil installs a default engine")
+}
@@ -0,0 +1,134 @@
+package fastrand
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"slices"
+)
+
+var sourcePrefix = []byte("FROM=")
+
+// maxK8sNameLength is the longest valid DNS-1123 label.
+const maxK8sNameLength = 63
+
+// splitSource splits a trailing "FROM=name" segment off a tag body and
+// returns the remaining body and the variable name, which is nil when there
+// is no such segment.
+func splitSource(body []byte) ([]byte, []byte) {
+	return splitNamed(body, sourcePrefix)
+}
+
+// derivable reports whether kw can derive its value from a variable with
+// FROM=.
+func (k *keywordSpec) derivable() bool {
+	if k.custom != nil || k.charset != nil || k.fallback {
+		return false
+	}
+	switch k.upper() {
+	case "EMAIL", "IDENT", "K8SNAME", "USERNAME":
+		return true
+	}
+	return false
+}
+
+// checkSource returns why the FROM= segment of spec is invalid given the
+// variables defined before it, or "" if it is valid.
+func checkSource(spec *tagSpec, defined [][]byte) string {
+	if !slices.ContainsFunc(defined, func(d []byte) bool { return bytes.Equal(d, spec.source) }) {
+		return fmt.Sprintf("undefined variable %q", spec.source)
+	}
+	for i := range spec.keywords {
+		if k := &spec.keywords[i]; !k.derivable() {
+			return fmt.Sprintf("FROM= needs EMAIL, IDENT, K8SNAME or USERNAME, not %q", k.text)
+		}
+	}
+	return ""
+}
+
+// deriveKeyword appends the value of kw derived from the variable source,
+// such as john.smith@gmail.com for an EMAIL from "John Smith". It reports
+// false, so the keyword is expanded as usual, when the variable is unset or
+// holds no letters or digits.
+func (e *FastEngine) deriveKeyword(out *[]byte, kw *keywordSpec, source []byte, x *expansion) bool {
+	value, ok := x.lookupVar(source)
+	if !ok || !kw.derivable() {
+		return false
+	}
+	start := len(*out)
+	switch kw.upper() {
+	case "USERNAME":
+		return e.deriveUsername(out, value)
+	case "EMAIL":
+		if !appendSlug(out, value, '.') {
+			return false
+		}
+		provider, _ := keywordParam(kw.params, "provider")
+		if len(provider) == 0 {
+			provider = e.randomMailProvider()
+		}
+		*out = append(*out, '@')
+		*out = append(*out, provider...)
+	case "IDENT":
+		if !appendSlug(out, value, '_') {
+			return false
+		}
+		if c := (*out)[start]; c >= '0' && c <= '9' {
+			*out = slices.Insert(*out, start, 'x')
+		}
+		var key [5]byte
+		if b := (*out)[start:]; len(b) <= len(key) {
+			n := upperASCIIInto(key[:], b)
+			if sqlReserved[unsafeString(key[:n])] {
+				*out = append(*out, '_')
+			}
+		}
+	case "K8SNAME":
+		if !appendSlug(out, value, '-') {
+			return false
+		}
+		if len(*out)-start > maxK8sNameLength {
+			*out = (*out)[:start+maxK8sNameLength]
+			for (*out)[len(*out)-1] == '-' {
+				*out = (*out)[:len(*out)-1]
+			}
+		}
+	}
+	return true
+}
+
+// derivedEntropy returns the bits of entropy kw adds to the variable it
+// derives from.
+func (e *FastEngine) derivedEntropy(kw *keywordSpec) float64 {
+	if _, fixed := keywordParam(kw.params, "provider"); kw.upper() == "EMAIL" && !fixed && len(e.mailProviders) > 0 {
+		return math.Log2(float64(len(e.mailProviders)))
+	}
+	if kw.upper() == "USERNAME" {
+		return math.Log2(usernameStyles)
+	}
+	return 0
+}
+
+// appendSlug appends the ASCII letters and digits of value in lower case,
+// with every run of other bytes between them replaced by one sep. It
+// reports false, appending nothing, when value has no letters or digits.
+func appendSlug(out *[]byte, value []byte, sep byte) bool {
+	start := len(*out)
+	pending := false
+	for _, c := range value {
+		switch {
+		case 'A' <= c && c <= 'Z':
+			c += 'a' - 'A'
+		case 'a' <= c && c <= 'z', '0' <= c && c <= '9':
+		default:
+			pending = len(*out) > start
+			continue
+		}
+		if pending {
+			*out = append(*out, sep)
+			pending = false
+		}
+		*out = append(*out, c)
+	}
+	return len(*out) > start
+}
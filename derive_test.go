@@ -0,0 +1,113 @@
+package fastrand_test
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var namePattern = regexp.MustCompile(`^[A-Z][a-z]+ [A-Z][a-z]+$`)
+
+func TestNameKeyword(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		assert.Regexp(t, namePattern, fastrand.RandomizerString("{RAND;NAME}"))
+	}
+	bits, err := fastrand.TagEntropy("{RAND;NAME}")
+	require.NoError(t, err)
+	assert.Greater(t, bits, 12.0)
+}
+
+func TestDerivedEmail(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		out := fastrand.RandomizerString("{RAND;NAME;VAR=n} <{RAND;EMAIL;FROM=n}>")
+		name, email, ok := strings.Cut(out, " <")
+		require.True(t, ok, out)
+		local, _, ok := strings.Cut(strings.TrimSuffix(email, ">"), "@")
+		require.True(t, ok, out)
+		assert.Equal(t, strings.ToLower(strings.ReplaceAll(name, " ", ".")), local)
+	}
+}
+
+func TestDerivedKeywords(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithCustomKeyword("FULLNAME", func(int) []byte { return []byte("  Grace M. Hopper!") }))
+
+	assert.Equal(t, "grace.m.hopper@example.com", engine.RandomizerString("{RAND;FULLNAME;VAR=n}{RAND;EMAIL(provider=example.com);FROM=n}")[len("  Grace M. Hopper!"):])
+	assert.Equal(t, "grace_m_hopper", engine.RandomizerString("{RAND;FULLNAME;VAR=n}{RAND;IDENT;FROM=n}")[len("  Grace M. Hopper!"):])
+	assert.Equal(t, "GRACE-M-HOPPER", engine.RandomizerString("{RAND;FULLNAME;VAR=n}{RAND;K8SNAME;FROM=n;upper}")[len("  Grace M. Hopper!"):])
+
+	digits := fastrand.NewEngine(fastrand.WithCustomKeyword("ID", func(int) []byte { return []byte("42") }))
+	assert.Equal(t, "x42", digits.RandomizerString("{RAND;ID;VAR=n}{RAND;IDENT;FROM=n}")[2:])
+
+	long := fastrand.NewEngine(fastrand.WithCustomKeyword("LONG", func(int) []byte { return []byte(strings.Repeat("ab ", 40)) }))
+	name := long.RandomizerString("{RAND;LONG;VAR=n}{RAND;K8SNAME;FROM=n}")[120:]
+	assert.LessOrEqual(t, len(name), 63)
+	assert.Regexp(t, `^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`, name)
+}
+
+func TestDerivedFallback(t *testing.T) {
+	out := fastrand.RandomizerString("{RAND;6;EMAIL(provider=example.com);FROM=missing}")
+	assert.Regexp(t, `^[a-z]{6}@example\.com$`, out, "an unset variable expands the keyword as usual")
+
+	out = fastrand.RandomizerString("{RAND?0;NAME;VAR=n}{RAND;6;EMAIL(provider=example.com);FROM=n}")
+	assert.Regexp(t, `^[a-z]{6}@example\.com$`, out, "a skipped variable expands the keyword as usual")
+
+	out = fastrand.RandomizerString("{RAND;NAME;VAR=n}{RAND;8;DIGIT;FROM=n}")
+	assert.Regexp(t, `[0-9]{8}$`, out, "keywords that cannot derive ignore FROM=")
+}
+
+func TestDerivedStrict(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithStrictParsing(true))
+
+	for payload, reason := range map[string]string{
+		"{RAND;EMAIL;FROM=n}":                    `undefined variable "n"`,
+		"{RAND;EMAIL;FROM=n}{RAND;NAME;VAR=n}":   `undefined variable "n"`,
+		"{RAND;NAME;VAR=n}{RAND;8;DIGIT;FROM=n}": `FROM= needs EMAIL, IDENT, K8SNAME or USERNAME, not "DIGIT"`,
+		"{RAND;NAME;VAR=n}{RAND;EMAIL;FROM=}":    `empty variable name after "FROM="`,
+	} {
+		_, err := engine.RandomizerErr([]byte(payload))
+		var tagErr *fastrand.TagError
+		if assert.True(t, errors.As(err, &tagErr), payload) {
+			assert.Equal(t, reason, tagErr.Reason, payload)
+		}
+	}
+
+	out, err := engine.RandomizerErr([]byte("{RAND;NAME;VAR=n}|{RAND;EMAIL,IDENT;FROM=n;VAR=e}|{REF;e}"))
+	require.NoError(t, err)
+	parts := strings.Split(string(out), "|")
+	require.Len(t, parts, 3)
+	assert.Equal(t, parts[1], parts[2])
+}
+
+func TestDerivedTemplate(t *testing.T) {
+	tmpl, err := fastrand.Compile([]byte("{RAND;NAME;VAR=n},{RAND;IDENT;FROM=n}"))
+	require.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		name, ident, ok := strings.Cut(tmpl.ExecuteString(), ",")
+		require.True(t, ok)
+		assert.Equal(t, strings.ToLower(strings.ReplaceAll(name, " ", "_")), ident)
+	}
+}
+
+func TestDerivedInspectAndEntropy(t *testing.T) {
+	specs, err := fastrand.Inspect([]byte("{RAND;NAME;VAR=n}{RAND;EMAIL;FROM=n}"))
+	require.NoError(t, err)
+	require.Len(t, specs, 2)
+	assert.Equal(t, "n", specs[0].Variable)
+	assert.Equal(t, "n", specs[1].Source)
+	assert.Equal(t, "EMAIL", specs[1].Keywords[0].Name)
+
+	bits, err := fastrand.TagEntropy("{RAND;IDENT;FROM=n}")
+	require.NoError(t, err)
+	assert.Zero(t, bits)
+	bits, err = fastrand.TagEntropy("{RAND;EMAIL(provider=example.com);FROM=n}")
+	require.NoError(t, err)
+	assert.Zero(t, bits)
+	bits, err = fastrand.TagEntropy("{RAND;EMAIL;FROM=n}")
+	require.NoError(t, err)
+	assert.Greater(t, bits, 0.0)
+}
@@ -0,0 +1,161 @@
+package fastrand
+
+import (
+	"bytes"
+	"strings"
+)
+
+// Dialect is an alternative tag syntax, such as ${RAND:8:DIGIT} or
+// __RAND_8_DIGIT__, that an engine accepts besides {RAND;8;DIGIT}. Dialect
+// tags are rewritten to the standard syntax before they are expanded, so
+// every tag feature works in them.
+type Dialect struct {
+	// Start opens a tag and ends in RAND, such as "${RAND". What comes
+	// before RAND also opens {REF;name} and {/RAND-REPEAT} tags, as in
+	// ${REF:name}.
+	Start string `json:"start"`
+	// End closes a tag, such as "}". Tags end at its first occurrence.
+	End string `json:"end"`
+	// Sep separates the parts of a tag, such as ":". Every occurrence in a
+	// tag becomes ';', so it should not otherwise appear in the tag: with
+	// ":" keyword arguments such as SEQ:name are not available.
+	Sep string `json:"sep"`
+}
+
+// dialectNames are the tag names that may follow a dialect's opening
+// delimiter.
+var dialectNames = [...][]byte{[]byte("RAND"), []byte("REF"), repeatClose[1 : len(repeatClose)-1]}
+
+// WithAdditionalDialect makes the engine also recognize tags written as
+// start, sep-separated parts and end, such as ${RAND:8:DIGIT} with
+// WithAdditionalDialect("${RAND", "}", ":"), so templates written for other
+// tools can be expanded by the same engine. It may be given several times;
+// where dialects overlap the leftmost tag wins, then the dialect added
+// first. Dialects whose start does not end in RAND after an opening
+// delimiter, or whose end or separator is empty, are ignored.
+func WithAdditionalDialect(start, end, sep string) Option {
+	return func(e *FastEngine) {
+		if d := (Dialect{Start: start, End: end, Sep: sep}); d.valid() {
+			e.dialects = append(e.dialects, d)
+		}
+	}
+}
+
+func (d Dialect) valid() bool {
+	return len(d.Start) > len("RAND") && strings.HasSuffix(d.Start, "RAND") && d.End != "" && d.Sep != ""
+}
+
+// open returns the delimiter that precedes the tag name.
+func (d *Dialect) open() []byte {
+	return s2b(d.Start[:len(d.Start)-len("RAND")])
+}
+
+// hasDialectTags reports whether payload may contain a tag in one of the
+// engine's dialects.
+func (e *FastEngine) hasDialectTags(payload []byte) bool {
+	for i := range e.dialects {
+		if bytes.Contains(payload, e.dialects[i].open()) {
+			return true
+		}
+	}
+	return false
+}
+
+// nextDialectTag returns the offset of the first dialect tag in payload at
+// or after cursor, its dialect and its tag name, or -1 if there is none.
+func (e *FastEngine) nextDialectTag(payload []byte, cursor int) (int, *Dialect, []byte) {
+	first, dialect, name := -1, (*Dialect)(nil), []byte(nil)
+	for i := range e.dialects {
+		d := &e.dialects[i]
+		open := d.open()
+		limit := len(payload)
+		if first != -1 {
+			limit = min(limit, first+len(open)-1)
+		}
+		for from := cursor; from < limit; {
+			j := bytes.Index(payload[from:limit], open)
+			if j == -1 {
+				break
+			}
+			j += from
+			if n := dialectName(payload[j+len(open):]); n != nil {
+				first, dialect, name = j, d, n
+				break
+			}
+			from = j + 1
+		}
+	}
+	return first, dialect, name
+}
+
+func dialectName(b []byte) []byte {
+	for _, name := range dialectNames {
+		if bytes.HasPrefix(b, name) {
+			return name
+		}
+	}
+	return nil
+}
+
+// rewriteDialects appends payload to dst with every complete dialect tag
+// rewritten to the standard syntax. Text from an unterminated dialect tag
+// on is copied unchanged.
+func (e *FastEngine) rewriteDialects(dst, payload []byte) []byte {
+	cursor := 0
+	for {
+		i, d, name := e.nextDialectTag(payload, cursor)
+		if i == -1 {
+			break
+		}
+		bodyStart := i + len(d.open()) + len(name)
+		bodyLen := bytes.Index(payload[bodyStart:], s2b(d.End))
+		if bodyLen == -1 {
+			break
+		}
+		dst = append(dst, payload[cursor:i]...)
+		dst = append(dst, startTag[0])
+		dst = append(dst, name...)
+		for body, sep := payload[bodyStart:bodyStart+bodyLen], s2b(d.Sep); ; {
+			part, rest, found := bytes.Cut(body, sep)
+			dst = append(dst, part...)
+			if !found {
+				break
+			}
+			dst = append(dst, sepTag)
+			body = rest
+		}
+		dst = append(dst, endTag)
+		cursor = bodyStart + bodyLen + len(d.End)
+	}
+	return append(dst, payload[cursor:]...)
+}
+
+// partialDialectSuffix returns the length of the longest suffix of b that
+// could belong to a dialect tag whose end has not been seen yet. Like
+// standard tags, dialect tags longer than maxStreamTag are not waited for.
+func (e *FastEngine) partialDialectSuffix(b []byte) int {
+	cursor := 0
+	for {
+		i, d, name := e.nextDialectTag(b, cursor)
+		if i == -1 {
+			break
+		}
+		bodyStart := i + len(d.open()) + len(name)
+		bodyLen := bytes.Index(b[bodyStart:], s2b(d.End))
+		if bodyLen == -1 {
+			if len(b)-i <= maxStreamTag {
+				return len(b) - i
+			}
+			break
+		}
+		cursor = bodyStart + bodyLen + len(d.End)
+	}
+	longest := 0
+	for i := range e.dialects {
+		open := e.dialects[i].open()
+		for _, name := range dialectNames {
+			longest = max(longest, partialPrefix(b[cursor:], append(open[:len(open):len(open)], name...)))
+		}
+	}
+	return longest
+}
@@ -0,0 +1,109 @@
+package fastrand_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDialectEngine(opts ...fastrand.Option) *fastrand.FastEngine {
+	return fastrand.NewEngine(append([]fastrand.Option{
+		fastrand.WithAdditionalDialect("${RAND", "}", ":"),
+		fastrand.WithAdditionalDialect("__RAND", "__", "_"),
+	}, opts...)...)
+}
+
+func TestAdditionalDialect(t *testing.T) {
+	engine := newDialectEngine()
+
+	assert.Regexp(t, `^a=[0-9]{8}&b=[a-z]{4}&c=[A-Z]{6}$`, engine.RandomizerString("a={RAND;8;DIGIT}&b=${RAND:4:ABL}&c=__RAND_6_ABU__"))
+	assert.Len(t, engine.RandomizerString("${RAND}"), 16)
+	assert.Regexp(t, `^[0-9]{3}$`, engine.RandomizerString("${RANDOM:3:DIGIT}"))
+
+	out := engine.RandomizerString("${RAND:8:HEX:VAR=id}|${REF:id}|__REF_id__")
+	parts := strings.Split(out, "|")
+	require.Len(t, parts, 3)
+	assert.Equal(t, parts[0], parts[1])
+	assert.Equal(t, parts[0], parts[2])
+
+	assert.Equal(t, "xxx", engine.RandomizerString("${RAND-REPEAT:3}x${/RAND-REPEAT}"))
+
+	for _, literal := range []string{"${HOME}", "$RAND:8}", "${RAND:8:DIGIT", "__init__", "plain text"} {
+		assert.Equal(t, literal, engine.RandomizerString(literal))
+	}
+	assert.Regexp(t, `^\$\{HOME\}[0-9]{2}$`, engine.RandomizerString("${HOME}${RAND:2:DIGIT}"))
+
+	assert.Equal(t, "${RAND:4:DIGIT}", fastrand.RandomizerString("${RAND:4:DIGIT}"), "the default engine has no dialects")
+}
+
+func TestAdditionalDialectInvalid(t *testing.T) {
+	for _, d := range [][3]string{{"RAND", "}", ":"}, {"${RAN", "}", ":"}, {"${RAND", "", ":"}, {"${RAND", "}", ""}} {
+		engine := fastrand.NewEngine(fastrand.WithAdditionalDialect(d[0], d[1], d[2]))
+		assert.Empty(t, engine.Config().Dialects, "%q", d)
+	}
+}
+
+func TestAdditionalDialectPaths(t *testing.T) {
+	engine := newDialectEngine(fastrand.WithStrictParsing(true))
+	const payload = "id=${RAND:6:DIGIT} name=__RAND_5_ABL__;"
+	const pattern = `^id=[0-9]{6} name=[a-z]{5};$`
+
+	tmpl, err := engine.Compile([]byte(payload))
+	require.NoError(t, err)
+	assert.Regexp(t, pattern, tmpl.ExecuteString())
+
+	out, err := engine.RandomizerErr([]byte(payload))
+	require.NoError(t, err)
+	assert.Regexp(t, pattern, string(out))
+
+	_, err = engine.RandomizerErr([]byte("${RAND:8:NOPE}"))
+	assert.ErrorContains(t, err, `unknown keyword "NOPE"`)
+
+	specs, err := engine.Inspect([]byte(payload))
+	require.NoError(t, err)
+	require.Len(t, specs, 2)
+	assert.Equal(t, "{RAND;6;DIGIT}", specs[0].Text)
+
+	assert.Regexp(t, pattern, string(engine.RandomizerBatch([][]byte{[]byte(payload)}, 1)[0]))
+
+	for name, wrap := range map[string]func(io.Reader) io.Reader{
+		"whole":   func(r io.Reader) io.Reader { return r },
+		"onebyte": iotest.OneByteReader,
+	} {
+		b, err := io.ReadAll(engine.RandomizerReader(wrap(strings.NewReader(strings.Repeat(payload+"\n", 3)))))
+		require.NoError(t, err, name)
+		for _, line := range strings.Split(strings.TrimSuffix(string(b), "\n"), "\n") {
+			assert.Regexp(t, pattern, line, name)
+		}
+	}
+}
+
+func TestAdditionalDialectConfig(t *testing.T) {
+	engine := newDialectEngine()
+	c := engine.Config()
+	assert.Equal(t, []fastrand.Dialect{{Start: "${RAND", End: "}", Sep: ":"}, {Start: "__RAND", End: "__", Sep: "_"}}, c.Dialects)
+
+	data, err := engine.MarshalConfig()
+	require.NoError(t, err)
+	restored, err := fastrand.NewEngineFromConfig(data)
+	require.NoError(t, err)
+	assert.Equal(t, c, restored.Config())
+	assert.Regexp(t, `^[0-9]{4}$`, restored.RandomizerString("${RAND:4:DIGIT}"))
+
+	_, err = fastrand.NewEngineFromConfig([]byte(`{"dialects": [{"start": "${", "end": "}", "sep": ":"}]}`))
+	assert.ErrorContains(t, err, "invalid dialect")
+
+	parsed, err := fastrand.ParseEngineConfig([]byte("[dialects]\nshell = [\"${RAND\", \"}\", \":\"]\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []fastrand.Dialect{{Start: "${RAND", End: "}", Sep: ":"}}, parsed.Dialects)
+	_, err = fastrand.ParseEngineConfig([]byte("[dialects]\nshell = [\"${RAND\", \"}\"]\n"))
+	assert.Error(t, err)
+
+	engine.ResetDefaultsOnly()
+	assert.Empty(t, engine.Config().Dialects)
+}
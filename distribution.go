@@ -0,0 +1,138 @@
+package fastrand
+
+import (
+	"bytes"
+	"math"
+	"strconv"
+)
+
+// LengthDistribution selects how a length is drawn from a range tag such as
+// {RAND;10-999;BYTES}. A tag can override the engine default with a suffix:
+// {RAND;10-999:zipf;BYTES}.
+type LengthDistribution int
+
+const (
+	// LengthUniform gives every length in the range the same probability.
+	LengthUniform LengthDistribution = iota
+	// LengthZipf favors short lengths with a heavy tail: length min+k-1 is
+	// drawn with probability roughly proportional to 1/k, like real
+	// payload, key and file sizes.
+	LengthZipf
+	// LengthGeometric makes every length a little less likely than the one
+	// before it, with a mean about a quarter of the way into the range, like
+	// the sizes of retries or queued messages.
+	LengthGeometric
+	// LengthNormal centers lengths on the engine's default length, or the
+	// middle of the range when the default lies outside it, with a standard
+	// deviation of a sixth of the range, clamped to the range.
+	LengthNormal
+)
+
+// lengthDistributionNames are the tag suffixes and configuration names of
+// the distributions, indexed by distribution.
+var lengthDistributionNames = [...]string{
+	LengthUniform:   "uniform",
+	LengthZipf:      "zipf",
+	LengthGeometric: "geometric",
+	LengthNormal:    "normal",
+}
+
+func (d LengthDistribution) String() string {
+	if d < 0 || int(d) >= len(lengthDistributionNames) {
+		return "LengthDistribution(" + strconv.Itoa(int(d)) + ")"
+	}
+	return lengthDistributionNames[d]
+}
+
+// parseLengthDistribution maps a case-insensitive tag suffix to a
+// distribution.
+func parseLengthDistribution(name []byte) (LengthDistribution, bool) {
+	for d, n := range lengthDistributionNames {
+		if bytes.EqualFold(name, s2b(n)) {
+			return LengthDistribution(d), true
+		}
+	}
+	return 0, false
+}
+
+// pick returns a length in [min, max]. center is the engine's default
+// length, around which LengthNormal draws.
+func (d LengthDistribution) pick(next func() uint64, min, max, center int) int {
+	n := max - min + 1
+	switch d {
+	case LengthZipf:
+		// Inverting the continuous 1/x density over [1, n+1) and flooring
+		// gives P(k) = ln((k+1)/k) / ln(n+1), close to Zipf with s = 1.
+		k := int(math.Pow(float64(n+1), float64From(next)))
+		if k > n {
+			k = n
+		}
+		return min + k - 1
+	case LengthGeometric:
+		// Inverting the CDF of a geometric distribution truncated to n
+		// values, with success probability p, so no draw is rejected.
+		p := 4 / float64(n+3)
+		if p >= 1 {
+			return min
+		}
+		lq := math.Log1p(-p)
+		k := int(math.Log1p(float64From(next)*math.Expm1(float64(n)*lq)) / lq)
+		if k > n-1 {
+			k = n - 1
+		}
+		return min + k
+	case LengthNormal:
+		if center < min || center > max {
+			center = min + (n-1)/2
+		}
+		return normalLength(next, center, n/6, min, max)
+	default:
+		return min + int(uint64N(next, uint64(n)))
+	}
+}
+
+// parseNormalLength parses a "~mean±stddev" length spec; "+-" may be used in
+// place of "±".
+func parseNormalLength(b []byte) (mean, stddev int, ok bool) {
+	if len(b) < 2 || b[0] != '~' {
+		return 0, 0, false
+	}
+	b = b[1:]
+	i := bytes.Index(b, plusMinus)
+	sepLen := len(plusMinus)
+	if i == -1 {
+		i = bytes.Index(b, asciiPlusMinus)
+		sepLen = len(asciiPlusMinus)
+	}
+	if i == -1 {
+		return 0, 0, false
+	}
+	mean, ok1 := parseLengthFast(b[:i])
+	stddev, ok2 := parseLengthFast(b[i+sepLen:])
+	return mean, stddev, ok1 && ok2
+}
+
+var (
+	plusMinus      = []byte("±")
+	asciiPlusMinus = []byte("+-")
+)
+
+// normalLength draws a length from a normal distribution with the given mean
+// and standard deviation, rounded and clamped to [min, max].
+func normalLength(next func() uint64, mean, stddev, min, max int) int {
+	l := int(math.Round(float64(mean) + float64(stddev)*normFloat64(next)))
+	if l < min {
+		return min
+	}
+	if l > max {
+		return max
+	}
+	return l
+}
+
+// normFloat64 returns a standard normal sample using the Box-Muller transform.
+func normFloat64(next func() uint64) float64 {
+	u1 := 1 - float64From(next) // (0, 1], so the log is finite
+	u2 := float64From(next)
+	return math.Sqrt(-2*math.Log(u1)) * math.Cos(2*math.Pi*u2)
+}
@@ -0,0 +1,143 @@
+package fastrand_test
+
+import (
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRangeZipfDistribution(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithMaxLength(999))
+	counts := map[int]int{}
+	const n = 20000
+	for i := 0; i < n; i++ {
+		l := len(engine.Randomizer([]byte("{RAND;10-999:zipf;BYTES}")))
+		assert.GreaterOrEqual(t, l, 10)
+		assert.LessOrEqual(t, l, 999)
+		counts[l]++
+	}
+	assert.Greater(t, counts[10], counts[11], "the minimum length should be the most likely")
+	assert.Greater(t, counts[10], n/20)
+
+	short := 0
+	for l, c := range counts {
+		if l < 100 {
+			short += c
+		}
+	}
+	assert.Greater(t, short, n/2, "most lengths should be near the minimum")
+	assert.Less(t, short, n*9/10, "the tail should not be empty")
+}
+
+func TestWithLengthDistribution(t *testing.T) {
+	zipf := fastrand.NewEngine(fastrand.WithLengthDistribution(fastrand.LengthZipf))
+	short := 0
+	for i := 0; i < 2000; i++ {
+		if len(zipf.RandomizerString("{RAND;1-90;DIGIT}")) <= 10 {
+			short++
+		}
+	}
+	assert.Greater(t, short, 800, "zipf default should favor short lengths")
+
+	short = 0
+	for i := 0; i < 2000; i++ {
+		if len(zipf.RandomizerString("{RAND;1-90:uniform;DIGIT}")) <= 10 {
+			short++
+		}
+	}
+	assert.Less(t, short, 400, "a tag suffix overrides the engine default")
+}
+
+func TestRangeDistributionMalformed(t *testing.T) {
+	engine := fastrand.NewEngine()
+	assert.Len(t, engine.RandomizerString("{RAND;5-10:bogus;DIGIT}"), 16, "unknown distributions fall back to the default length")
+}
+
+func TestNormalLength(t *testing.T) {
+	engine := fastrand.NewEngine()
+	const n = 5000
+	sum, within := 0, 0
+	for i := 0; i < n; i++ {
+		l := len(engine.RandomizerString("{RAND;~64±16;ABL}"))
+		assert.GreaterOrEqual(t, l, 1)
+		assert.LessOrEqual(t, l, 99)
+		sum += l
+		if l >= 48 && l <= 80 {
+			within++
+		}
+	}
+	mean := float64(sum) / n
+	assert.InDelta(t, 64, mean, 2)
+	assert.InDelta(t, 0.68, float64(within)/n, 0.05, "about 68% should fall within one standard deviation")
+
+	for i := 0; i < 200; i++ {
+		l := len(engine.RandomizerString("{RAND;~95+-40;DIGIT}"))
+		assert.LessOrEqual(t, l, 99, "samples are clamped to the engine bounds")
+	}
+	assert.Len(t, engine.RandomizerString("{RAND;~8±0;DIGIT}"), 8)
+}
+
+func TestNormalLengthMalformed(t *testing.T) {
+	engine := fastrand.NewEngine()
+	assert.Len(t, engine.RandomizerString("{RAND;~64;DIGIT}"), 16)
+	assert.Len(t, engine.RandomizerString("{RAND;~500±5;DIGIT}"), 16, "a mean outside the engine bounds falls back to the default")
+}
+
+func TestRangeGeometricDistribution(t *testing.T) {
+	engine := fastrand.NewEngine()
+	const n = 20000
+	counts := map[int]int{}
+	sum := 0
+	for i := 0; i < n; i++ {
+		l := len(engine.RandomizerString("{RAND;1-97:geometric;DIGIT}"))
+		assert.GreaterOrEqual(t, l, 1)
+		assert.LessOrEqual(t, l, 97)
+		counts[l]++
+		sum += l
+	}
+	assert.Greater(t, counts[1], counts[10], "shorter lengths should be more likely")
+	assert.Greater(t, counts[10], counts[40])
+	assert.InDelta(t, 25, float64(sum)/n, 3, "the mean should be about a quarter into the range")
+
+	assert.Len(t, engine.RandomizerString("{RAND;7-7:geometric;DIGIT}"), 7)
+}
+
+func TestRangeNormalDistribution(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithDefaultLength(40))
+	const n = 10000
+	sum := 0
+	for i := 0; i < n; i++ {
+		l := len(engine.RandomizerString("{RAND;10-70:normal;DIGIT}"))
+		assert.GreaterOrEqual(t, l, 10)
+		assert.LessOrEqual(t, l, 70)
+		sum += l
+	}
+	assert.InDelta(t, 40, float64(sum)/n, 1, "lengths should center on the default length")
+
+	sum = 0
+	for i := 0; i < n; i++ {
+		l := len(engine.RandomizerString("{RAND;50-90:normal;DIGIT}"))
+		assert.GreaterOrEqual(t, l, 50)
+		assert.LessOrEqual(t, l, 90)
+		sum += l
+	}
+	assert.InDelta(t, 70, float64(sum)/n, 1, "a default outside the range centers on its middle")
+}
+
+func TestLengthDistributionConfig(t *testing.T) {
+	for _, d := range []fastrand.LengthDistribution{fastrand.LengthUniform, fastrand.LengthZipf, fastrand.LengthGeometric, fastrand.LengthNormal} {
+		engine := fastrand.NewEngine(fastrand.WithLengthDistribution(d))
+		c := engine.Config()
+		assert.Equal(t, d.String(), c.LengthDistribution)
+		restored, err := c.NewEngine()
+		if assert.NoError(t, err) {
+			assert.Equal(t, c, restored.Config())
+		}
+	}
+
+	tags, err := fastrand.Inspect([]byte("{RAND;1-9:GEOMETRIC;DIGIT}"))
+	if assert.NoError(t, err) && assert.Len(t, tags, 1) {
+		assert.Equal(t, "geometric", tags[0].Length.Distribution)
+	}
+}
@@ -0,0 +1,70 @@
+package fastrand
+
+import (
+	_ "embed"
+	"fmt"
+)
+
+//go:embed tlds.txt
+var tldsList string
+
+var tlds = parseLines(tldsList)
+
+// maxDomainDepth bounds the depth parameter of DOMAIN, keeping names well
+// under the 253-byte limit of a hostname.
+const maxDomainDepth = 8
+
+// Domain returns a random hostname such as "xk3f.brave-otter.io": zero or
+// one random subdomain labels, a registrable name built from words and a
+// common top-level domain.
+func Domain() string {
+	var out []byte
+	appendHostname(fastUint64, &out, 0, 1, "")
+	return unsafeString(out)
+}
+
+// appendHostname appends a hostname with between minDepth and maxDepth
+// random subdomain labels below a word-based name in tld, or in a random
+// top-level domain when tld is empty.
+func appendHostname(next func() uint64, out *[]byte, minDepth, maxDepth int, tld string) {
+	depth := minDepth + int(uint64N(next, uint64(maxDepth-minDepth+1)))
+	for i := 0; i < depth; i++ {
+		appendHostLabel(next, out, 2+int(uint64N(next, 7)))
+		*out = append(*out, '.')
+	}
+	if next()&1 == 0 {
+		*out = append(*out, pickWord(next, adjectives)...)
+		*out = append(*out, '-')
+	}
+	*out = append(*out, pickWord(next, nouns)...)
+	*out = append(*out, '.')
+	if tld == "" {
+		tld = pickWord(next, tlds)
+	}
+	*out = append(*out, tld...)
+}
+
+// domainParams returns the subdomain depth range and the top-level domain
+// of a DOMAIN keyword, or why its depth parameter is invalid. The depth
+// defaults to 0-1.
+func domainParams(params []byte) (minDepth, maxDepth int, tld []byte, reason string) {
+	minDepth, maxDepth = 0, 1
+	if v, ok := keywordParam(params, "depth"); ok {
+		var valid bool
+		if minDepth, maxDepth, valid = parseIntRange(v, 0, maxDomainDepth); !valid {
+			return 0, 1, nil, fmt.Sprintf("invalid depth %q: want N or MIN-MAX within [0, %d]", v, maxDomainDepth)
+		}
+	}
+	tld, _ = keywordParam(params, "tld")
+	if len(tld) > 0 && tld[0] == '.' {
+		tld = tld[1:]
+	}
+	return minDepth, maxDepth, tld, ""
+}
+
+// appendDomain appends a hostname shaped by the depth and tld parameters
+// of a DOMAIN keyword.
+func (e *FastEngine) appendDomain(out *[]byte, kw *keywordSpec) {
+	minDepth, maxDepth, tld, _ := domainParams(kw.params)
+	appendHostname(e.next, out, minDepth, maxDepth, unsafeString(tld))
+}
@@ -0,0 +1,68 @@
+package fastrand_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const hostnamePattern = `^([a-z][a-z0-9]*\.){0,9}[a-z]+(-[a-z]+)?\.[a-z]+(\.[a-z]+)?$`
+
+func TestDomain(t *testing.T) {
+	depths := map[int]int{}
+	for i := 0; i < 500; i++ {
+		name := fastrand.Domain()
+		require.Regexp(t, hostnamePattern, name)
+		require.LessOrEqual(t, len(name), 253)
+		depths[strings.Count(name, ".")]++
+	}
+	assert.NotZero(t, depths[1]+depths[2], "names without a subdomain")
+	assert.NotZero(t, depths[2]+depths[3], "names with one subdomain")
+}
+
+func TestDomainKeyword(t *testing.T) {
+	for i := 0; i < 200; i++ {
+		out := fastrand.RandomizerString("{RAND;DOMAIN}")
+		require.Regexp(t, hostnamePattern, out)
+
+		out = fastrand.RandomizerString("{RAND;DOMAIN(depth=3,tld=.test)}")
+		require.Regexp(t, `^([a-z][a-z0-9]+\.){3}[a-z]+(-[a-z]+)?\.test$`, out)
+
+		out = fastrand.RandomizerString("{RAND;DOMAIN(depth=0,tld=example)}")
+		require.Regexp(t, `^[a-z]+(-[a-z]+)?\.example$`, out)
+
+		out = fastrand.RandomizerString("{RAND;DOMAIN(depth=1-2,tld=com)}")
+		dots := strings.Count(out, ".")
+		require.True(t, dots == 2 || dots == 3, out)
+	}
+	assert.NotEqual(t, fastrand.RandomizerString("{RAND;DOMAIN}"), fastrand.RandomizerString("{RAND;DOMAIN}"))
+}
+
+func TestDomainKeywordStrict(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithStrictParsing(true))
+	for _, payload := range []string{"{RAND;DOMAIN(depth=9)}", "{RAND;DOMAIN(depth=2-1)}", "{RAND;DOMAIN(depth=x)}"} {
+		_, err := engine.RandomizerErr([]byte(payload))
+		assert.ErrorContains(t, err, "invalid depth", payload)
+	}
+	_, err := engine.RandomizerErr([]byte("{RAND;DOMAIN(tls=com)}"))
+	assert.ErrorContains(t, err, "unknown parameter")
+	_, err = engine.RandomizerErr([]byte("{RAND;DOMAIN(depth=0-8,tld=internal)}"))
+	assert.NoError(t, err)
+
+	_, err = fastrand.TagEntropy("{RAND;DOMAIN}")
+	assert.ErrorIs(t, err, fastrand.ErrUnknownEntropy)
+}
+
+func TestAllocsDomainKeyword(t *testing.T) {
+	engine := fastrand.NewEngine()
+	payload := []byte("Host: {RAND;DOMAIN(depth=2)}")
+	dst := make([]byte, 0, 256)
+	dst = engine.RandomizerAppend(dst, payload)
+	allocs := testing.AllocsPerRun(100, func() {
+		dst = engine.RandomizerAppend(dst[:0], payload)
+	})
+	assert.Zero(t, allocs)
+}
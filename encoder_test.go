@@ -0,0 +1,157 @@
+package fastrand_test
+
+import (
+	"bytes"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func upperEncoder(dst *[]byte, src []byte) {
+	*dst = append(*dst, bytes.ToUpper(src)...)
+}
+
+func TestOutputEncoder(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithOutputEncoder(upperEncoder))
+
+	assert.Equal(t, "PLAIN TEXT", engine.RandomizerString("plain text"))
+	assert.Equal(t, "PLAIN TEXT", string(engine.Randomizer([]byte("plain text"))))
+	assert.Regexp(t, `^ID=[a-z]{8}&X$`, engine.RandomizerString("id={RAND;8;ABL}&x"))
+	assert.Regexp(t, `^ID=[a-z]{8}$`, string(engine.RandomizerAppend(nil, []byte("id={RAND;8;ABL}"))))
+
+	tmpl, err := engine.Compile([]byte("id={RAND;8;ABL}"))
+	require.NoError(t, err)
+	assert.Regexp(t, `^ID=[a-z]{8}$`, tmpl.ExecuteString())
+
+	out, err := io.ReadAll(engine.RandomizerReader(strings.NewReader("id={RAND;8;ABL}")))
+	require.NoError(t, err)
+	assert.Regexp(t, `^ID=[a-z]{8}$`, string(out))
+}
+
+func TestOutputEncoderOverridesBuiltin(t *testing.T) {
+	engine := fastrand.NewEngine(
+		fastrand.WithOutputEncoding(fastrand.RandomizerEncodingURL|fastrand.RandomizerEncodingJSON),
+		fastrand.WithOutputEncoder(upperEncoder),
+		fastrand.WithCustomKeyword("Q", func(int) []byte { return []byte(`"q"`) }),
+	)
+	assert.Equal(t, `A B=\"q\"`, engine.RandomizerString("a b={RAND;Q}"))
+
+	engine.Reset()
+	assert.Equal(t, "a b", engine.RandomizerString("a b"))
+}
+
+func TestOutputEncodings(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithOutputEncodings(fastrand.RandomizerEncodingURL, fastrand.RandomizerEncodingBase64))
+	// "a b&" -> "a+b%26" -> base64
+	assert.Equal(t, "YStiJTI2", engine.RandomizerString("a b&"))
+
+	engine = fastrand.NewEngine(fastrand.WithOutputEncodings(fastrand.RandomizerEncodingURL, fastrand.RandomizerEncodingURL))
+	assert.Regexp(t, `^q%253D[0-9]{4}%2526$`, engine.RandomizerString("q={RAND;4;DIGIT}&"))
+
+	engine = fastrand.NewEngine(fastrand.WithOutputEncodings(fastrand.RandomizerEncodingBase64))
+	assert.Equal(t, "aGk=", engine.RandomizerString("hi"))
+}
+
+func TestOutputEncodingURLDouble(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithOutputEncoding(fastrand.RandomizerEncodingURLDouble))
+	assert.Equal(t, "a%2520b%253Cx%253E%2525_~", engine.RandomizerString("a b<x>%_~"))
+	assert.Regexp(t, `^q%253D[0-9]{4}%2526$`, engine.RandomizerString("q={RAND;4;DIGIT}&"))
+
+	once, err := url.PathUnescape(engine.RandomizerString("<a href='x'> é"))
+	require.NoError(t, err)
+	twice, err := url.PathUnescape(once)
+	require.NoError(t, err)
+	assert.Equal(t, "<a href='x'> é", twice)
+}
+
+func TestOutputEncodingsJSON(t *testing.T) {
+	engine := fastrand.NewEngine(
+		fastrand.WithOutputEncodings(fastrand.RandomizerEncodingHTML, fastrand.RandomizerEncodingJSON),
+		fastrand.WithCustomKeyword("Q", func(int) []byte { return []byte(`"`) }),
+	)
+	assert.Equal(t, `&lt;\"`, engine.RandomizerString("<{RAND;Q}"))
+}
+
+func TestOutputEncodingsAllocs(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithOutputEncodings(fastrand.RandomizerEncodingHTML, fastrand.RandomizerEncodingURL))
+	payload := "<a href='{RAND;8;ABL}'>"
+	dst := engine.RandomizerAppendString(make([]byte, 0, 256), payload)
+	allocs := testing.AllocsPerRun(100, func() {
+		dst = engine.RandomizerAppendString(dst[:0], payload)
+	})
+	assert.Zero(t, allocs)
+}
+
+func TestInputEncodingUnicode(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithInputEncoding(fastrand.RandomizerEncodingUnicode))
+	cases := map[string]string{
+		`id=\u007BRAND\u003B8\u003BDIGIT\u007D`: `^id=[0-9]{8}$`,
+		`id=\u007bRAND;8;DIGIT\u007d`:           `^id=[0-9]{8}$`,
+		`id=\x7bRAND\x3b8\x3bABL\x7d!`:          `^id=[a-z]{8}!$`,
+		`id=\x7BRAND;4;HEX\x7D`:                 `^id=[0-9a-f]{8}$`,
+		`{"a":"\u007BRAND;UUID\u007D"}`:         `^\{"a":"[0-9a-f-]{36}"\}$`,
+	}
+	for payload, pattern := range cases {
+		assert.Regexp(t, pattern, engine.RandomizerString(payload), payload)
+	}
+
+	assert.Equal(t, `{x} \x41 \`, engine.RandomizerString(`{x} \x41 \`))
+	assert.Equal(t, `\u007BRAND;8;DIGIT}`, fastrand.RandomizerString(`\u007BRAND;8;DIGIT}`), "off by default")
+}
+
+func TestInputEncodingUnicodeTemplateAndStream(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithInputEncoding(fastrand.RandomizerEncodingUnicode | fastrand.RandomizerEncodingURL))
+	payload := `a=\u007BRAND\u003b6\u003BDIGIT\u007d&b=%7BRAND%3B3%3BABL%7D`
+	pattern := `^a=[0-9]{6}&b=[a-z]{3}$`
+
+	tmpl, err := engine.Compile([]byte(payload))
+	require.NoError(t, err)
+	assert.Regexp(t, pattern, tmpl.ExecuteString())
+
+	out, err := io.ReadAll(engine.RandomizerReader(iotest.OneByteReader(strings.NewReader(payload))))
+	require.NoError(t, err)
+	assert.Regexp(t, pattern, string(out))
+}
+
+func bracketNormalizer(b []byte) []byte {
+	b = bytes.ReplaceAll(b, []byte("[["), []byte("{RAND;"))
+	return bytes.ReplaceAll(b, []byte("]]"), []byte("}"))
+}
+
+func TestInputNormalizer(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithInputNormalizer(bracketNormalizer))
+	payload := "id=[[8;DIGIT]]&k=%7BRAND%3B3%3BABL%7D"
+	pattern := `^id=[0-9]{8}&k=[a-z]{3}$`
+
+	assert.Regexp(t, pattern, engine.RandomizerString(payload))
+	assert.Regexp(t, pattern, string(engine.Randomizer([]byte(payload))))
+	assert.Regexp(t, pattern, string(engine.RandomizerAppendString(nil, payload)))
+
+	tmpl, err := engine.Compile([]byte(payload))
+	require.NoError(t, err)
+	assert.Regexp(t, pattern, tmpl.ExecuteString())
+
+	out, err := io.ReadAll(engine.RandomizerReader(strings.NewReader(payload)))
+	require.NoError(t, err)
+	assert.Regexp(t, pattern, string(out))
+}
+
+func TestInputNormalizerAlone(t *testing.T) {
+	engine := fastrand.NewEngine(
+		fastrand.WithInputNormalizer(bracketNormalizer),
+		fastrand.WithInputEncoding(fastrand.RandomizerEncodingNone),
+	)
+	assert.Regexp(t, `^[0-9]{4} %7BRAND%7D$`, engine.RandomizerString("[[4;DIGIT]] %7BRAND%7D"))
+
+	engine = fastrand.NewEngine(fastrand.WithInputNormalizer(bytes.ToLower))
+	assert.Equal(t, "no tags", engine.RandomizerString("NO TAGS"), "runs on payloads without tags")
+
+	engine.Reset()
+	assert.Equal(t, "NO TAGS", engine.RandomizerString("NO TAGS"))
+}
@@ -0,0 +1,19 @@
+package fastrand
+
+// chainEncoder returns an OutputEncoder applying each encoding of chain in
+// turn, with intermediate results in pooled buffers.
+func (e *FastEngine) chainEncoder(chain []RandomizerEncoding) OutputEncoder {
+	return func(dst *[]byte, src []byte) {
+		bufs := [2]*[]byte{e.bufferPool.Get(2 * len(src)), e.bufferPool.Get(2 * len(src))}
+		cur := src
+		for i, enc := range chain[:len(chain)-1] {
+			buf := bufs[i%2]
+			*buf = (*buf)[:0]
+			appendEncoded(buf, cur, enc)
+			cur = *buf
+		}
+		appendEncoded(dst, cur, chain[len(chain)-1])
+		e.bufferPool.Put(bufs[0])
+		e.bufferPool.Put(bufs[1])
+	}
+}
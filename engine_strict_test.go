@@ -0,0 +1,63 @@
+package fastrand_test
+
+import (
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEngineStrict(t *testing.T) {
+	engine, err := fastrand.NewEngineStrict(
+		fastrand.WithMinLength(4),
+		fastrand.WithMaxLength(32),
+		fastrand.WithDefaultLength(8),
+		fastrand.WithCustomCharset("ABL", []byte("xyz")),
+		fastrand.WithCustomKeyword("PING", func(int) []byte { return []byte("pong") }),
+	)
+	require.NoError(t, err)
+	assert.Regexp(t, `^[xyz]{8}$`, engine.RandomizerString("{RAND;ABL}"))
+
+	_, err = fastrand.NewEngineStrict()
+	assert.NoError(t, err, "the defaults are consistent")
+}
+
+func TestNewEngineStrictRejects(t *testing.T) {
+	cases := map[string]struct {
+		opts []fastrand.Option
+		want string
+	}{
+		"MinAboveMax": {
+			[]fastrand.Option{fastrand.WithMinLength(50), fastrand.WithMaxLength(10), fastrand.WithDefaultLength(10)},
+			"min length 50 exceeds max length 10",
+		},
+		"DefaultAboveMax": {
+			[]fastrand.Option{fastrand.WithMaxLength(8)},
+			"default length 16 is outside [1, 8]",
+		},
+		"DefaultBelowMin": {
+			[]fastrand.Option{fastrand.WithMinLength(20)},
+			"default length 16 is outside [20, 99]",
+		},
+		"EmptyCharset": {
+			[]fastrand.Option{fastrand.WithCustomCharset("hex", nil)},
+			`custom charset "HEX" is empty`,
+		},
+		"NilGenerator": {
+			[]fastrand.Option{fastrand.WithCustomKeyword("ping", nil)},
+			`custom keyword "PING" has a nil generator`,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			engine, err := fastrand.NewEngineStrict(tc.opts...)
+			assert.Nil(t, engine)
+			assert.ErrorContains(t, err, tc.want)
+		})
+	}
+
+	_, err := fastrand.NewEngineStrict(fastrand.WithMaxLength(8), fastrand.WithCustomCharset("ABL", []byte{}))
+	assert.ErrorContains(t, err, "default length")
+	assert.ErrorContains(t, err, "custom charset", "every problem is reported")
+}
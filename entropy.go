@@ -0,0 +1,218 @@
+package fastrand
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"slices"
+)
+
+// ErrUnknownEntropy is returned by TagEntropy for keywords whose output has
+// no well-defined entropy, such as XML, FORM, TIMESTAMP, the protocol
+// keywords and custom keywords.
+var ErrUnknownEntropy = errors.New("fastrand: entropy of keyword is not well defined")
+
+// allBytes holds every byte value once, the charset of BYTES.
+var allBytes = func() (b [256]byte) {
+	for i := range b {
+		b[i] = byte(i)
+	}
+	return b
+}()
+
+// CharsetEntropy returns the bits of entropy in a string of length
+// characters drawn uniformly from charset. Repeated characters in charset
+// are counted with their extra weight, so they lower the result.
+func CharsetEntropy(charset CharsList, length int) float64 {
+	if len(charset) == 0 || length <= 0 {
+		return 0
+	}
+	var counts [256]int
+	for _, c := range charset {
+		counts[c]++
+	}
+	total := float64(len(charset))
+	perChar := 0.0
+	for _, n := range counts {
+		if n > 0 {
+			p := float64(n) / total
+			perChar -= p * math.Log2(p)
+		}
+	}
+	return perChar * float64(length)
+}
+
+// TagEntropy reports the bits of entropy one expansion of tag produces with
+// the default engine. See FastEngine.TagEntropy.
+func TagEntropy(tag string) (float64, error) {
+	return defaultEngine.Load().TagEntropy(tag)
+}
+
+// TagEntropy reports the bits of entropy one expansion of a single tag such
+// as "{RAND;32;ABR}" produces with this engine's charsets and settings. When
+// the tag allows several lengths or keywords the weakest case is reported,
+// so the result is a lower bound suitable for security reviews. SEQ and
+// registered CYCLE values are predictable, and optional tags may produce
+// nothing, so they report zero. Case modifiers count each letter's two
+// cases as one character, and values derived with FROM= count only what
+// they add to the variable, such as an EMAIL's mail provider.
+func (e *FastEngine) TagEntropy(tag string) (float64, error) {
+	b := s2b(tag)
+	if !bytes.HasPrefix(b, startTag) || b[len(b)-1] != endTag {
+		return 0, errors.New("fastrand: not a tag")
+	}
+	spec, ok := e.parseTag(b[:len(b)-1], nil, nil)
+	if !ok {
+		return 0, errors.New("fastrand: not a tag")
+	}
+
+	if spec.chance.optional && spec.chance.percent < 100 {
+		return 0, nil
+	}
+
+	length := spec.length
+	switch spec.lengthKind {
+	case lengthChoice:
+		length = slices.MinFunc(spec.lengths, func(a, b weightedLength) int { return a.length - b.length }).length
+	case lengthNormal:
+		length = e.minLength
+	}
+
+	weakest := math.Inf(1)
+	for i := range spec.keywords {
+		kw := &spec.keywords[i]
+		if spec.source != nil && kw.derivable() {
+			weakest = min(weakest, e.derivedEntropy(kw))
+			continue
+		}
+		bits, err := e.keywordEntropy(kw, e.paramLength(kw, length), spec.caseMode)
+		if err != nil {
+			return 0, err
+		}
+		weakest = min(weakest, bits)
+	}
+	return weakest, nil
+}
+
+func (e *FastEngine) keywordEntropy(kw *keywordSpec, length int, mode caseMode) (float64, error) {
+	if kw.custom != nil {
+		return 0, ErrUnknownEntropy
+	}
+	if kw.charset != nil {
+		return CharsetEntropy(mode.folded(kw.charset), length), nil
+	}
+	if kw.fallback {
+		return CharsetEntropy(mode.folded(e.getCharset(kwABR, CharsAll)), length), nil
+	}
+
+	switch kw.upper() {
+	case "ABL":
+		return CharsetEntropy(mode.folded(e.getCharset(kwABL, CharsAlphabetLower)), length), nil
+	case "ABU":
+		return CharsetEntropy(mode.folded(e.getCharset(kwABU, CharsAlphabetUpper)), length), nil
+	case "ABR":
+		return CharsetEntropy(mode.folded(e.getCharset(kwABR, CharsAlphabet)), length), nil
+	case "DIGIT":
+		return CharsetEntropy(mode.folded(e.getCharset(kwDIGIT, CharsDigits)), length), nil
+	case "NULL":
+		return CharsetEntropy(mode.folded(e.getCharset(kwNULL, CharsNull)), length), nil
+	case "SPACE", "SEQ":
+		return 0, nil
+	case "UUID":
+		return 122, nil
+	case "ULID":
+		return 80, nil
+	case "UUIDV7":
+		return 74, nil
+	case "MAC":
+		return macEntropy(kw), nil
+	case "BYTES":
+		if mode != caseNone {
+			return CharsetEntropy(mode.folded(allBytes[:]), length), nil
+		}
+		return 8 * float64(length), nil
+	case "HEX", "BASE32":
+		if length <= 0 {
+			length = e.defaultLength
+		}
+		return 8 * float64(length), nil
+	case "IPV4":
+		return ipv4Entropy(kw.arg), nil
+	case "IPV6":
+		return ipv6Entropy(kw.arg), nil
+	case "EMAIL":
+		bits := CharsetEntropy(CharsAlphabetLower, length)
+		if _, fixed := keywordParam(kw.params, "provider"); !fixed && len(e.mailProviders) > 0 {
+			bits += math.Log2(float64(len(e.mailProviders)))
+		}
+		return bits, nil
+	case "CYCLE":
+		if _, ok := e.cycles[string(kw.arg)]; ok {
+			return 0, nil
+		}
+		return CharsetEntropy(mode.folded(e.getCharset(kwABR, CharsAll)), length), nil
+	case "IDENT":
+		return CharsetEntropy(mode.folded(CharsAlphabet), 1) + CharsetEntropy(mode.folded(identifierChars), length-1), nil
+	case "K8SNAME":
+		return math.Log2(float64(len(adjectives))) + math.Log2(float64(len(nouns))) + CharsetEntropy(k8sSuffixChars, 5), nil
+	case "ADJ":
+		return math.Log2(float64(len(adjectives))), nil
+	case "NOUN":
+		return math.Log2(float64(len(nouns))), nil
+	case "VERB":
+		return math.Log2(float64(len(verbs))), nil
+	case "CC":
+		return cardEntropy(kw.arg), nil
+	case "CCEXP":
+		return math.Log2(cardExpiryMonths), nil
+	case "CCCVV":
+		return float64(cvvLength(kw.arg)) * math.Log2(10), nil
+	case "PHONE":
+		return phoneEntropy(kw.arg), nil
+	case "UA":
+		return math.Log2(float64(len(e.userAgents))), nil
+	case "NAME":
+		return math.Log2(float64(len(e.firstNames))) + math.Log2(float64(len(e.lastNames))), nil
+	case "HTTPMETHOD":
+		methods, _ := httpMethodSet(kw.arg)
+		return math.Log2(float64(len(methods))), nil
+	case "HEADERVAL":
+		return headerValueEntropy(kw.arg, length), nil
+	case "HOSTNAME":
+		return hostnameEntropy(kw.arg, length)
+	case "COUNTRY":
+		return math.Log2(float64(len(countryAlpha2))), nil
+	case "LOCALE":
+		return math.Log2(float64(len(locales))), nil
+	case "CURRENCY":
+		return math.Log2(float64(len(currencies))), nil
+	case "TZ":
+		return math.Log2(float64(len(timeZones))), nil
+	case "MIME":
+		return mimeEntropy(kw.arg), nil
+	case "COLOR":
+		return colorEntropy(kw.arg), nil
+	case "PASSWORD":
+		return e.passwordEntropy(kw, length), nil
+	case "WORD":
+		return math.Log2(float64(len(fillerWords))), nil
+	case "WORDS", "SENTENCE":
+		return float64(length) * math.Log2(float64(len(fillerWords))), nil
+	case "FIRSTNAME":
+		return math.Log2(float64(len(e.firstNames))), nil
+	case "LASTNAME":
+		return math.Log2(float64(len(e.lastNames))), nil
+	case "DATE", "TIME":
+		return dateTimeEntropy(kw), nil
+	case "FLOAT":
+		r, _ := parseFloatArg(kw.arg)
+		return math.Log2(float64(r.hi-r.lo) + 1), nil
+	case "PICK":
+		return pickEntropy(kw.arg), nil
+	case "XML", "FORM", "DNSQ", "HTTPREQ", "SMTP", "TIMESTAMP", "DOMAIN", "URL", "USERNAME", "JWT", "GEO",
+		"FILENAME", "PATH":
+		return 0, ErrUnknownEntropy
+	default:
+		return CharsetEntropy(mode.folded(e.getCharset(kwABR, CharsAll)), length), nil
+	}
+}
@@ -0,0 +1,68 @@
+package fastrand_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCharsetEntropy(t *testing.T) {
+	assert.InDelta(t, 6*math.Log2(10), fastrand.CharsetEntropy(fastrand.CharsDigits, 6), 1e-9)
+	assert.Equal(t, 0.0, fastrand.CharsetEntropy(fastrand.CharsDigits, 0))
+	assert.Equal(t, 0.0, fastrand.CharsetEntropy(nil, 8))
+	assert.InDelta(t, 0.9183, fastrand.CharsetEntropy(fastrand.CharsList("aab"), 1), 1e-4, "duplicates reduce entropy")
+}
+
+func TestTagEntropy(t *testing.T) {
+	cases := map[string]float64{
+		"{RAND;32;HEX}":         256,
+		"{RAND;UUID}":           122,
+		"{RAND;16;BYTES}":       128,
+		"{RAND;8;DIGIT}":        8 * math.Log2(10),
+		"{RAND;8-12;ABR}":       8 * math.Log2(52),
+		"{RAND;12,6,9;ABL}":     6 * math.Log2(26),
+		"{RANDOM;4;ABU}":        4 * math.Log2(26),
+		"{RAND;8;DIGIT,HEX}":    8 * math.Log2(10),
+		"{RAND;IPV4}":           32,
+		"{RAND;10;SPACE}":       0,
+		"{RAND;SEQ:orders}":     0,
+		"{RAND;5;NOSUCHKWD}":    5 * math.Log2(float64(len(fastrand.CharsAll))),
+		"{RAND;~40±5;ABL}":      math.Log2(26),
+		"{RAND;10-99:zipf;ABL}": 10 * math.Log2(26),
+		"{RAND;8;ABR;upper}":    8 * math.Log2(26),
+		"{RAND;UUID;upper}":     122,
+		"{RAND;16;BYTES;lower}": 16 * (204*8 + 26*2*7) / 256.0,
+	}
+	for tag, want := range cases {
+		got, err := fastrand.TagEntropy(tag)
+		if assert.NoError(t, err, tag) {
+			assert.InDelta(t, want, got, 1e-9, tag)
+		}
+	}
+
+	_, err := fastrand.TagEntropy("{RAND;XML}")
+	assert.ErrorIs(t, err, fastrand.ErrUnknownEntropy)
+	_, err = fastrand.TagEntropy("not a tag")
+	assert.Error(t, err)
+}
+
+func TestEngineTagEntropy(t *testing.T) {
+	engine := fastrand.NewEngine(
+		fastrand.WithCustomCharset("DIGIT", []byte("01")),
+		fastrand.WithCustomKeyword("TOKEN", func(int) []byte { return []byte("x") }),
+		fastrand.WithCycle("env", "dev", "prod"),
+	)
+	bits, err := engine.TagEntropy("{RAND;16;DIGIT}")
+	require.NoError(t, err)
+	assert.InDelta(t, 16, bits, 1e-9, "custom charsets are taken into account")
+
+	bits, err = engine.TagEntropy("{RAND;CYCLE:env}")
+	require.NoError(t, err)
+	assert.Zero(t, bits)
+
+	_, err = engine.TagEntropy("{RAND;TOKEN}")
+	assert.ErrorIs(t, err, fastrand.ErrUnknownEntropy)
+}
@@ -0,0 +1,135 @@
+package fastrand
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+)
+
+// exclusionCache holds charsets with characters removed, keyed by the
+// charset and the removed characters. Like classCache it is bounded by
+// maxCachedClasses.
+var exclusionCache struct {
+	sync.RWMutex
+	m map[[2]string]CharsList
+}
+
+// WithExcludedChars removes chars from the charset of keyword wherever it
+// is used, so generated values avoid visually ambiguous or syntactically
+// dangerous characters, as in WithExcludedChars("ABR", "l1O0"). It applies
+// to the charset keywords ABL, ABU, ABR, DIGIT and NULL, including their
+// custom charsets, and to registered charsets. Empty chars removes an
+// earlier exclusion.
+func WithExcludedChars(keyword, chars string) Option {
+	return func(e *FastEngine) {
+		if chars == "" {
+			delete(e.keywords.Load().excluded, strings.ToUpper(keyword))
+			return
+		}
+		e.keywords.Load().excluded[strings.ToUpper(keyword)] = chars
+	}
+}
+
+// splitExclusion splits a trailing "!chars" exclusion off a keyword, as in
+// ABR!l1O0 or [a-z]!lo, and returns the keyword and the excluded
+// characters, which are nil when there is no exclusion. For character
+// classes it starts after the first "]!".
+func splitExclusion(keyword []byte) ([]byte, []byte) {
+	from := 0
+	if len(keyword) > 0 && keyword[0] == '[' {
+		i := bytes.Index(keyword, []byte("]!"))
+		if i == -1 {
+			return keyword, nil
+		}
+		from = i + 1
+	}
+	i := bytes.IndexByte(keyword[from:], '!')
+	if i == -1 {
+		return keyword, nil
+	}
+	i += from
+	return keyword[:i], keyword[i+1:]
+}
+
+// builtinCharset returns the charset of the built-in charset keyword
+// upper, with any custom charset applied.
+func (e *FastEngine) builtinCharset(upper string) (CharsList, bool) {
+	var builtin CharsList
+	switch upper {
+	case "ABL":
+		builtin = CharsAlphabetLower
+	case "ABU":
+		builtin = CharsAlphabetUpper
+	case "ABR":
+		builtin = CharsAlphabet
+	case "DIGIT":
+		builtin = CharsDigits
+	case "NULL":
+		builtin = CharsNull
+	default:
+		return nil, false
+	}
+	return e.getCharset(s2b(upper), builtin), true
+}
+
+// excludeChars removes from k's charset the characters excluded from its
+// keyword with WithExcludedChars and those in inline, and reports whether
+// k draws from a charset at all. A keyword left without characters is
+// marked excluded, which makes its tag invalid rather than widening it to
+// CharsAll.
+func (e *FastEngine) excludeChars(k *keywordSpec, inline []byte) bool {
+	cs := k.charset
+	if cs == nil && !k.fallback && k.custom == nil {
+		cs, _ = e.builtinCharset(k.upper())
+	}
+	if cs == nil {
+		return false
+	}
+	var excluded string
+	if k.n > 0 {
+		excluded = e.keywords.Load().excluded[k.upper()]
+	}
+	if excluded == "" && len(inline) == 0 {
+		return true
+	}
+	if cs = withoutChars(withoutChars(cs, s2b(excluded)), inline); len(cs) == 0 {
+		k.charset, k.excluded = nil, true
+	} else {
+		k.charset = cs
+	}
+	return true
+}
+
+// withoutChars returns cs without the characters in excluded, caching the
+// result.
+func withoutChars(cs CharsList, excluded []byte) CharsList {
+	if len(excluded) == 0 {
+		return cs
+	}
+	key := [2]string{unsafeString(cs), unsafeString(excluded)}
+	exclusionCache.RLock()
+	filtered, ok := exclusionCache.m[key]
+	exclusionCache.RUnlock()
+	if ok {
+		return filtered
+	}
+	var drop [256]bool
+	for _, c := range excluded {
+		drop[c] = true
+	}
+	filtered = make(CharsList, 0, len(cs))
+	for _, c := range cs {
+		if !drop[c] {
+			filtered = append(filtered, c)
+		}
+	}
+	exclusionCache.Lock()
+	if exclusionCache.m == nil {
+		exclusionCache.m = make(map[[2]string]CharsList)
+	}
+	if len(exclusionCache.m) < maxCachedClasses {
+		exclusionCache.m[[2]string{string(cs), string(excluded)}] = filtered
+	}
+	exclusionCache.Unlock()
+	return filtered
+}
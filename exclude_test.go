@@ -0,0 +1,116 @@
+package fastrand_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInlineExclusion(t *testing.T) {
+	cases := map[string]string{
+		"{RAND;99;ABR!l1O0}":         "lO",
+		"{RAND;99;DIGIT!0123456}":    "0123456",
+		"{RAND;99;[a-e]!ae}":         "ae",
+		"{RAND;99;abl!aeiou}":        "aeiou",
+		"{RAND;99;ABU!XYZ(len=50)}":  "XYZ",
+		"{RAND;99;ABL!abc,DIGIT!09}": "abc09",
+	}
+	for payload, excluded := range cases {
+		for i := 0; i < 20; i++ {
+			out := fastrand.RandomizerString(payload)
+			require.NotEmpty(t, out, payload)
+			require.False(t, strings.ContainsAny(out, excluded), "%s gave %q", payload, out)
+		}
+	}
+	assert.Regexp(t, `^[789]{30}$`, fastrand.RandomizerString("{RAND;30;DIGIT!0123456}"))
+	assert.Len(t, fastrand.RandomizerString("{RAND;ABU!XYZ(len=50)}"), 50)
+}
+
+func TestInlineExclusionOnlyOnCharsets(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithCycle("a!b", "x", "y"))
+	assert.Equal(t, "x", engine.RandomizerString("{RAND;CYCLE:a!b}"), "other keywords keep their '!'")
+
+	strict := fastrand.NewEngine(fastrand.WithStrictParsing(true))
+	_, err := strict.RandomizerErr([]byte("{RAND;8;DIGIT!0123456789}"))
+	assert.ErrorContains(t, err, "leaves no characters")
+	_, err = strict.RandomizerErr([]byte("{RAND;8;HEX!0}"))
+	assert.ErrorContains(t, err, "unknown keyword")
+	_, err = strict.RandomizerErr([]byte("{RAND;8;ABR!l1O0}"))
+	assert.NoError(t, err)
+}
+
+func TestExclusionLeavingNoCharacters(t *testing.T) {
+	for _, payload := range []string{
+		"{RAND;8;ABL!abcdefghijklmnopqrstuvwxyz}",
+		"{RAND;8;[a-c]!abc}",
+		"{RAND;8;DIGIT!0123456789|ABL}",
+	} {
+		assert.Equal(t, "x"+payload, fastrand.RandomizerString("x"+payload), "the tag is kept, not widened to CharsAll")
+		tmpl, err := fastrand.Compile([]byte(payload))
+		require.NoError(t, err)
+		assert.Equal(t, payload, tmpl.ExecuteString())
+	}
+
+	engine := fastrand.NewEngine(fastrand.WithExcludedChars("DIGIT", "0123456789"))
+	assert.Equal(t, "{RAND;8;DIGIT}", engine.RandomizerString("{RAND;8;DIGIT}"))
+	_, err := fastrand.NewEngine(fastrand.WithStrictParsing(true)).RandomizerErr([]byte("{RAND;8;ABL!abcdefghijklmnopqrstuvwxyz}"))
+	assert.ErrorContains(t, err, "leaves no characters")
+}
+
+func TestWithExcludedChars(t *testing.T) {
+	engine := fastrand.NewEngine(
+		fastrand.WithExcludedChars("abr", "l1O0"),
+		fastrand.WithExcludedChars("DIGIT", "0123"),
+		fastrand.WithCustomCharset("ABL", []byte("xyz")),
+		fastrand.WithExcludedChars("ABL", "x"),
+	)
+	require.NoError(t, engine.RegisterCharset("HEXLOWER", fastrand.CharsList("0123456789abcdef")))
+	for i := 0; i < 20; i++ {
+		assert.NotContains(t, engine.RandomizerString("{RAND;99;ABR}"), "l")
+		assert.Regexp(t, `^[4-9]{40}$`, engine.RandomizerString("{RAND;40;DIGIT!}"))
+		assert.Regexp(t, `^[5-9]{40}$`, engine.RandomizerString("{RAND;40;DIGIT!4}"), "inline adds to the engine's exclusions")
+		assert.Regexp(t, `^[yz]{40}$`, engine.RandomizerString("{RAND;40;ABL}"))
+	}
+
+	_, err := fastrand.NewEngineStrict(fastrand.WithExcludedChars("DIGIT", "0123456789"))
+	assert.ErrorContains(t, err, `leave keyword "DIGIT" no characters`)
+	_, err = fastrand.NewEngineStrict(fastrand.WithExcludedChars("DIGIT", "0"), fastrand.WithExcludedChars("DIGIT", ""))
+	assert.NoError(t, err)
+}
+
+func TestExcludedCharsConfig(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithExcludedChars("ABR", "l1O0"))
+	data, err := engine.MarshalConfig()
+	require.NoError(t, err)
+	restored, err := fastrand.NewEngineFromConfig(data)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"ABR": "l1O0"}, restored.Config().ExcludedChars)
+
+	c, err := fastrand.ParseEngineConfig([]byte("[excluded_chars]\nDIGIT = \"01\"\n"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"DIGIT": "01"}, c.ExcludedChars)
+
+	engine.Reset()
+	assert.Nil(t, engine.Config().ExcludedChars)
+}
+
+func TestInspectExclusion(t *testing.T) {
+	specs, err := fastrand.Inspect([]byte("{RAND;8;ABR!l1O0}"))
+	require.NoError(t, err)
+	require.Len(t, specs, 1)
+	assert.Equal(t, []fastrand.TagKeyword{{Name: "ABR", Excluded: "l1O0", Known: true}}, specs[0].Keywords)
+}
+
+func TestAllocsInlineExclusion(t *testing.T) {
+	engine := fastrand.NewEngine()
+	payload := []byte("id={RAND;16;ABR!l1O0}")
+	dst := make([]byte, 0, 64)
+	dst = engine.RandomizerAppend(dst, payload)
+	allocs := testing.AllocsPerRun(100, func() {
+		dst = engine.RandomizerAppend(dst[:0], payload)
+	})
+	assert.Zero(t, allocs)
+}
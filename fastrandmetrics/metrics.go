@@ -0,0 +1,101 @@
+// Package fastrandmetrics exports the counters of a fastrand engine built
+// with fastrand.WithStats(true), so long-running generators can be
+// monitored. Counters are published through expvar and served in the
+// Prometheus text exposition format, which Prometheus scrapes directly.
+//
+// The package does not implement prometheus.Collector: WritePrometheus
+// writes the exposition format by hand so that the module does not depend
+// on the Prometheus client library (client_golang). Programs that already
+// register collectors can serve Handler on its own path or scrape it as a
+// separate target.
+package fastrandmetrics
+
+import (
+	"bytes"
+	"expvar"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/obeliskdev/fastrand"
+)
+
+// Var returns an expvar.Var that reports e's counters as a JSON object
+// with payloads, bytes, tags, fallbacks and keywords fields.
+func Var(e *fastrand.FastEngine) expvar.Var {
+	return expvar.Func(func() any {
+		st := e.Stats()
+		keywords := st.Keywords
+		if keywords == nil {
+			keywords = map[string]uint64{}
+		}
+		return map[string]any{
+			"payloads":  st.Payloads,
+			"bytes":     st.Bytes,
+			"tags":      st.Tags,
+			"fallbacks": st.Fallbacks,
+			"keywords":  keywords,
+		}
+	})
+}
+
+// Publish publishes e's counters as the expvar variable name, so they
+// appear under /debug/vars. Like expvar.Publish it panics if name is
+// already in use.
+func Publish(name string, e *fastrand.FastEngine) {
+	expvar.Publish(name, Var(e))
+}
+
+// metrics are the engine-wide counters, in exposition order.
+var metrics = []struct {
+	name, help string
+	value      func(st *fastrand.EngineStats) uint64
+}{
+	{"fastrand_payloads_total", "Payloads expanded.", func(st *fastrand.EngineStats) uint64 { return st.Payloads }},
+	{"fastrand_bytes_total", "Bytes generated by expansions.", func(st *fastrand.EngineStats) uint64 { return st.Bytes }},
+	{"fastrand_tags_total", "Tags expanded.", func(st *fastrand.EngineStats) uint64 { return st.Tags }},
+	{"fastrand_fallbacks_total", "Tags that fell back to CharsAll characters.", func(st *fastrand.EngineStats) uint64 { return st.Fallbacks }},
+}
+
+// WritePrometheus writes e's counters to w in the Prometheus text
+// exposition format. Expansions per keyword are reported as
+// fastrand_keyword_expansions_total with a keyword label.
+func WritePrometheus(w io.Writer, e *fastrand.FastEngine) error {
+	st := e.Stats()
+	var b strings.Builder
+	for _, m := range metrics {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s counter\n%s %d\n", m.name, m.help, m.name, m.name, m.value(&st))
+	}
+	b.WriteString("# HELP fastrand_keyword_expansions_total Tags expanded per keyword.\n")
+	b.WriteString("# TYPE fastrand_keyword_expansions_total counter\n")
+	for _, kw := range slices.Sorted(maps.Keys(st.Keywords)) {
+		fmt.Fprintf(&b, "fastrand_keyword_expansions_total{keyword=\"%s\"} %d\n", labelEscaper.Replace(kw), st.Keywords[kw])
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// labelEscaper escapes label values as the exposition format requires.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// Handler returns an http.Handler that serves e's counters for Prometheus
+// to scrape. The counters are rendered before anything is sent, so a
+// failure yields a 500 response; a failed write to the client, which by
+// then has the status line, is logged with the standard logger.
+func Handler(e *fastrand.FastEngine) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var buf bytes.Buffer
+		if err := WritePrometheus(&buf, e); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			log.Printf("fastrandmetrics: writing metrics: %v", err)
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package fastrandmetrics_test
+
+import (
+	"encoding/json"
+	"errors"
+	"expvar"
+	"log"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/obeliskdev/fastrand/fastrandmetrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublish(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithStats(true))
+	fastrandmetrics.Publish("fastrand_test_engine", engine)
+	engine.RandomizerString("{RAND;4;DIGIT}{RAND;8;DIGIT}{RAND;3;ZZZ}")
+
+	var got struct {
+		Payloads  uint64            `json:"payloads"`
+		Bytes     uint64            `json:"bytes"`
+		Tags      uint64            `json:"tags"`
+		Fallbacks uint64            `json:"fallbacks"`
+		Keywords  map[string]uint64 `json:"keywords"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(expvar.Get("fastrand_test_engine").String()), &got))
+	assert.Equal(t, uint64(1), got.Payloads)
+	assert.Equal(t, uint64(15), got.Bytes)
+	assert.Equal(t, uint64(3), got.Tags)
+	assert.Equal(t, uint64(1), got.Fallbacks)
+	assert.Equal(t, map[string]uint64{"DIGIT": 2}, got.Keywords)
+}
+
+func TestWritePrometheus(t *testing.T) {
+	engine := fastrand.NewEngine(
+		fastrand.WithStats(true),
+		fastrand.WithCustomKeyword(`A"B`, func(int) []byte { return []byte("x") }),
+	)
+	engine.RandomizerString(`{RAND;4;HEX}{RAND;2;DIGIT}{RAND;A"B}`)
+
+	var b strings.Builder
+	require.NoError(t, fastrandmetrics.WritePrometheus(&b, engine))
+	out := b.String()
+	assert.Contains(t, out, "# TYPE fastrand_payloads_total counter\nfastrand_payloads_total 1\n")
+	assert.Contains(t, out, "fastrand_bytes_total 11\n")
+	assert.Contains(t, out, "fastrand_tags_total 3\n")
+	assert.Contains(t, out, "fastrand_fallbacks_total 0\n")
+	assert.Contains(t, out, "fastrand_keyword_expansions_total{keyword=\"DIGIT\"} 1\n"+
+		"fastrand_keyword_expansions_total{keyword=\"HEX\"} 1\n")
+	assert.Contains(t, out, `fastrand_keyword_expansions_total{keyword="A\"B"} 1`)
+}
+
+func TestHandler(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithStats(true))
+	engine.RandomizerString("{RAND;HEX}")
+
+	rec := httptest.NewRecorder()
+	fastrandmetrics.Handler(engine).ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, 200, rec.Code)
+	assert.True(t, strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain"))
+	assert.Contains(t, rec.Body.String(), "fastrand_payloads_total 1\n")
+}
+
+// brokenWriter is a ResponseWriter whose client has gone away.
+type brokenWriter struct {
+	*httptest.ResponseRecorder
+}
+
+func (brokenWriter) Write([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func TestHandlerWriteError(t *testing.T) {
+	defer log.SetOutput(log.Writer())
+	var logged strings.Builder
+	log.SetOutput(&logged)
+	engine := fastrand.NewEngine(fastrand.WithStats(true))
+	fastrandmetrics.Handler(engine).ServeHTTP(brokenWriter{httptest.NewRecorder()}, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Contains(t, logged.String(), "fastrandmetrics: writing metrics: connection reset")
+}
@@ -0,0 +1,26 @@
+// Package fastrandtest provides test helpers for code that uses fastrand
+// generators and templates.
+package fastrandtest
+
+import (
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+)
+
+// Check evaluates prop against n values drawn from gen. The first value for
+// which prop returns false is minimized with shrink, and the test fails
+// reporting both the original and the minimal counterexample.
+func Check[T any](tb testing.TB, n int, gen func() T, shrink fastrand.Shrinker[T], prop func(T) bool) {
+	tb.Helper()
+	fails := func(v T) bool { return !prop(v) }
+	for i := 0; i < n; i++ {
+		v := gen()
+		if !fails(v) {
+			continue
+		}
+		minimal := fastrand.Minimize(v, shrink, fails)
+		tb.Errorf("fastrandtest: property failed after %d runs\n  original: %#v\n  minimal:  %#v", i+1, v, minimal)
+		return
+	}
+}
@@ -0,0 +1,58 @@
+package fastrandtest_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/obeliskdev/fastrand/fastrandtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Logf(string, ...any) {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestCheck_Passing(t *testing.T) {
+	rec := &recorder{TB: t}
+	fastrandtest.Check(rec, 200, func() int { return fastrand.IntN(100) }, fastrand.ShrinkInt[int],
+		func(v int) bool { return v < 100 })
+	assert.Empty(t, rec.errors)
+}
+
+func TestCheck_MinimizesFailure(t *testing.T) {
+	rec := &recorder{TB: t}
+	fastrandtest.Check(rec, 1000, func() int { return fastrand.Int(500, 100000) }, fastrand.ShrinkInt[int],
+		func(v int) bool { return v < 300 })
+	require.Len(t, rec.errors, 1)
+	assert.Contains(t, rec.errors[0], "minimal:  300")
+}
+
+func TestCheck_MinimizesString(t *testing.T) {
+	rec := &recorder{TB: t}
+	gen := func() string { return fastrand.String(32, fastrand.CharsAlphabetLower) + "Z" }
+	fastrandtest.Check(rec, 10, gen, fastrand.ShrinkString, func(s string) bool {
+		for i := 0; i < len(s); i++ {
+			if s[i] == 'Z' {
+				return false
+			}
+		}
+		return true
+	})
+	require.Len(t, rec.errors, 1)
+	assert.Contains(t, rec.errors[0], `minimal:  "Z"`)
+}
@@ -0,0 +1,218 @@
+package fastrandtest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+)
+
+// UpdateEnv names the environment variable that, when non-empty, makes
+// Golden rewrite golden files instead of comparing against them.
+const UpdateEnv = "FASTRAND_UPDATE_GOLDEN"
+
+var (
+	tagStarts   = [][]byte{[]byte("{RAND"), []byte("{REF;"), []byte("{/RAND-REPEAT")}
+	repeatOpen  = []byte("{RAND-REPEAT")
+	repeatClose = []byte("{/RAND-REPEAT}")
+	tagEnd      = byte('}')
+)
+
+type segment struct {
+	src   string
+	isTag bool
+	out   []byte
+}
+
+// Golden expands tmpl with an engine seeded by seed and compares the result
+// with the golden file at path. A missing golden file is created. On a
+// mismatch the test fails with a report of which tags changed output.
+func Golden(tb testing.TB, path, tmpl string, seed uint64, opts ...fastrand.Option) {
+	tb.Helper()
+
+	engine := fastrand.NewEngine(append([]fastrand.Option{fastrand.WithSeed(seed)}, opts...)...)
+	got := engine.RandomizerAppend(nil, []byte(tmpl))
+
+	if os.Getenv(UpdateEnv) != "" {
+		writeGolden(tb, path, got)
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		writeGolden(tb, path, got)
+		tb.Logf("fastrandtest: created golden file %s", path)
+		return
+	}
+	if err != nil {
+		tb.Fatalf("fastrandtest: reading golden file: %v", err)
+		return
+	}
+
+	if bytes.Equal(got, want) {
+		return
+	}
+	tb.Errorf("fastrandtest: output does not match %s (set %s=1 to update):\n%s", path, UpdateEnv, diffSegments(alignSegments(splitSegments(tmpl), got), want))
+}
+
+func writeGolden(tb testing.TB, path string, data []byte) {
+	tb.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		tb.Fatalf("fastrandtest: creating golden dir: %v", err)
+		return
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		tb.Fatalf("fastrandtest: writing golden file: %v", err)
+	}
+}
+
+// splitSegments splits tmpl into literal and tag segments. References and
+// whole repeat blocks count as tags, since their output is not the text
+// they are written with. Adjacent tags are kept together as one segment
+// since nothing separates their output.
+func splitSegments(tmpl string) []segment {
+	var segs []segment
+	src := []byte(tmpl)
+	cursor := 0
+	for cursor < len(src) {
+		start := indexTag(src[cursor:])
+		if start == -1 {
+			segs = appendSegment(segs, string(src[cursor:]), false)
+			break
+		}
+		start += cursor
+		end := bytes.IndexByte(src[start:], tagEnd)
+		if end == -1 {
+			segs = appendSegment(segs, string(src[cursor:]), false)
+			break
+		}
+		end += start + 1
+		if bytes.HasPrefix(src[start:], repeatOpen) {
+			end = repeatEnd(src, end)
+		}
+		if start > cursor {
+			segs = appendSegment(segs, string(src[cursor:start]), false)
+		}
+		segs = appendSegment(segs, string(src[start:end]), true)
+		cursor = end
+	}
+	return segs
+}
+
+// indexTag returns the index of the first tag, reference or block end in
+// src, or -1.
+func indexTag(src []byte) int {
+	first := -1
+	for _, start := range tagStarts {
+		if i := bytes.Index(src, start); i != -1 && (first == -1 || i < first) {
+			first = i
+		}
+	}
+	return first
+}
+
+// repeatEnd returns the index just past the {/RAND-REPEAT} that closes the
+// block whose opening tag ends at i, or i when the block is unclosed.
+func repeatEnd(src []byte, i int) int {
+	depth := 1
+	for j := i; j < len(src); j++ {
+		switch {
+		case bytes.HasPrefix(src[j:], repeatClose):
+			if depth--; depth == 0 {
+				return j + len(repeatClose)
+			}
+		case bytes.HasPrefix(src[j:], repeatOpen):
+			depth++
+		}
+	}
+	return i
+}
+
+func appendSegment(segs []segment, src string, isTag bool) []segment {
+	if n := len(segs); n > 0 && segs[n-1].isTag == isTag {
+		segs[n-1].src += src
+		return segs
+	}
+	return append(segs, segment{src: src, isTag: isTag})
+}
+
+// alignSegments attributes the bytes of got, one expansion of the whole
+// template, to its segments by finding each literal segment in it. A
+// literal that does not appear where expected, such as text an output
+// encoding rewrote, is merged into the neighbouring tags.
+func alignSegments(segs []segment, got []byte) []segment {
+	var out []segment
+	pending := -1
+	pos := 0
+	for _, s := range segs {
+		if s.isTag {
+			if pending == -1 {
+				out = append(out, segment{isTag: true})
+				pending = len(out) - 1
+			}
+			out[pending].src += s.src
+			continue
+		}
+		idx := bytes.Index(got[pos:], []byte(s.src))
+		if idx == -1 || (pending == -1 && idx != 0) {
+			if pending == -1 {
+				out = append(out, segment{isTag: true})
+				pending = len(out) - 1
+			}
+			out[pending].src += s.src
+			continue
+		}
+		if pending != -1 {
+			out[pending].out = got[pos : pos+idx]
+			pending = -1
+		}
+		pos += idx
+		out = append(out, segment{src: s.src, out: got[pos : pos+len(s.src)]})
+		pos += len(s.src)
+	}
+	if pending != -1 {
+		out[pending].out = got[pos:]
+	}
+	return out
+}
+
+// diffSegments aligns want against the literal segments of the template and
+// reports every tag segment whose output differs.
+func diffSegments(segs []segment, want []byte) string {
+	var sb strings.Builder
+	pos := 0
+	for i, s := range segs {
+		if !s.isTag {
+			if !bytes.HasPrefix(want[pos:], s.out) {
+				fmt.Fprintf(&sb, "  template text changed at output offset %d: got %q\n", pos, s.out)
+				return sb.String()
+			}
+			pos += len(s.out)
+			continue
+		}
+
+		end := len(want)
+		if i+1 < len(segs) {
+			idx := bytes.Index(want[pos:], segs[i+1].out)
+			if idx == -1 {
+				fmt.Fprintf(&sb, "  %s at output offset %d: cannot align with golden output\n", s.src, pos)
+				return sb.String()
+			}
+			end = pos + idx
+		}
+		if !bytes.Equal(want[pos:end], s.out) {
+			fmt.Fprintf(&sb, "  %s at output offset %d:\n    got:  %q\n    want: %q\n", s.src, pos, s.out, want[pos:end])
+		}
+		pos = end
+	}
+	if pos < len(want) {
+		fmt.Fprintf(&sb, "  golden output has %d extra trailing bytes\n", len(want)-pos)
+	}
+	return sb.String()
+}
@@ -0,0 +1,100 @@
+package fastrandtest_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/obeliskdev/fastrand/fastrandtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const goldenTemplate = "user={RAND;8;ABL}&pin={RAND;6;DIGIT}&id={RAND;UUID}{RAND;IPV4}&mail={RAND;8;EMAIL}"
+
+func TestGolden_CreatesAndMatches(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "testdata", "fixture.golden")
+
+	rec := &recorder{TB: t}
+	fastrandtest.Golden(rec, path, goldenTemplate, 42)
+	require.Empty(t, rec.errors)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err, "golden file should be created")
+	assert.True(t, strings.HasPrefix(string(data), "user="))
+
+	rec = &recorder{TB: t}
+	fastrandtest.Golden(rec, path, goldenTemplate, 42)
+	assert.Empty(t, rec.errors, "same seed should reproduce the golden output")
+}
+
+func TestGolden_ReportsChangedTags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixture.golden")
+
+	rec := &recorder{TB: t}
+	fastrandtest.Golden(rec, path, goldenTemplate, 1)
+	require.Empty(t, rec.errors)
+
+	rec = &recorder{TB: t}
+	fastrandtest.Golden(rec, path, goldenTemplate, 2)
+	require.Len(t, rec.errors, 1, "different seed should fail the comparison")
+	report := rec.errors[0]
+	assert.Contains(t, report, "{RAND;8;ABL}")
+	assert.Contains(t, report, "{RAND;6;DIGIT}")
+	assert.Contains(t, report, "{RAND;UUID}{RAND;IPV4}", "adjacent tags are reported together")
+	assert.Contains(t, report, "want:")
+}
+
+func TestGolden_MatchesEngine(t *testing.T) {
+	const tmpl = "a={RAND;8;HEX;VAR=s} b={REF;s} {RAND-REPEAT;2}x{RAND;2;DIGIT}{/RAND-REPEAT}"
+	path := filepath.Join(t.TempDir(), "fixture.golden")
+
+	rec := &recorder{TB: t}
+	fastrandtest.Golden(rec, path, tmpl, 5)
+	require.Empty(t, rec.errors)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, fastrand.NewEngine(fastrand.WithSeed(5)).RandomizerString(tmpl), string(data),
+		"the golden file holds one expansion of the whole template")
+	assert.Regexp(t, `^a=([0-9a-f]{16}) b=([0-9a-f]{16}) x[0-9]{2}x[0-9]{2}$`, string(data))
+	assert.Equal(t, string(data[2:18]), string(data[21:37]), "references see earlier variables")
+
+	rec = &recorder{TB: t}
+	fastrandtest.Golden(rec, path, tmpl, 6)
+	require.Len(t, rec.errors, 1)
+	report := rec.errors[0]
+	assert.Contains(t, report, "{RAND;8;HEX;VAR=s}")
+	assert.Contains(t, report, "{REF;s}")
+	assert.Contains(t, report, "{RAND-REPEAT;2}x{RAND;2;DIGIT}{/RAND-REPEAT}", "repeat blocks are reported whole")
+	assert.NotContains(t, report, "template text changed")
+}
+
+func TestGolden_ReportsTemplateTextChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixture.golden")
+
+	rec := &recorder{TB: t}
+	fastrandtest.Golden(rec, path, "a={RAND;4;HEX}", 7)
+	require.Empty(t, rec.errors)
+
+	rec = &recorder{TB: t}
+	fastrandtest.Golden(rec, path, "b={RAND;4;HEX}", 7)
+	require.Len(t, rec.errors, 1)
+	assert.Contains(t, rec.errors[0], "template text changed")
+}
+
+func TestGolden_UpdateEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixture.golden")
+	require.NoError(t, os.WriteFile(path, []byte("stale"), 0o644))
+
+	t.Setenv(fastrandtest.UpdateEnv, "1")
+	rec := &recorder{TB: t}
+	fastrandtest.Golden(rec, path, "{RAND;4;HEX}", 3, fastrand.WithOutputEncoding(fastrand.RandomizerEncodingNone))
+	require.Empty(t, rec.errors)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Len(t, data, 8, "update mode should rewrite the golden file")
+}
@@ -0,0 +1,155 @@
+package fastrand
+
+import (
+	"bytes"
+	"fmt"
+)
+
+var (
+	fileExtensions = []string{
+		"txt", "pdf", "png", "jpg", "gif", "csv", "json", "xml", "html", "md",
+		"log", "conf", "docx", "xlsx", "zip", "tar.gz", "sh", "py", "go", "bak",
+	}
+	posixRoots   = []string{"home", "var", "etc", "opt", "srv", "tmp", "usr"}
+	windowsRoots = []string{"Users", "ProgramData", "Windows", "Program Files", "inetpub", "Temp"}
+)
+
+// maxPathDepth bounds the depth parameter of PATH.
+const maxPathDepth = 16
+
+// Filename returns a plausible file name such as "quiet_river_7.csv".
+func Filename() string {
+	var out []byte
+	appendFilename(fastUint64, &out, "")
+	return unsafeString(out)
+}
+
+// FilePath returns a POSIX path with depth directories below the root and
+// a file name, such as "/var/report/quiet_river_7.csv".
+func FilePath(depth int) string {
+	var out []byte
+	appendPath(fastUint64, &out, pathShape{minDepth: depth, maxDepth: depth})
+	return unsafeString(out)
+}
+
+// appendFilename appends a file name made of words, an optional number and
+// ext, or a random extension when ext is empty.
+func appendFilename(next func() uint64, out *[]byte, ext string) {
+	if next()&1 == 0 {
+		*out = append(*out, pickWord(next, adjectives)...)
+		*out = append(*out, '_')
+	}
+	*out = append(*out, pickWord(next, nouns)...)
+	if next()&3 == 0 {
+		*out = append(*out, '_')
+		*out = strconvAppendUint(*out, uint64N(next, 100), 10)
+	}
+	if ext == "" {
+		ext = pickWord(next, fileExtensions)
+	}
+	*out = append(*out, '.')
+	*out = append(*out, ext...)
+}
+
+// pathShape is the shape of the paths a PATH keyword generates.
+type pathShape struct {
+	minDepth, maxDepth int
+	windows            bool
+	traversal          int
+	ext                string
+}
+
+var defaultPathShape = pathShape{minDepth: 1, maxDepth: 4}
+
+// appendPath appends a path with a file name. Without traversal it is
+// absolute, below a typical root such as /var or C:\Users; with it, the
+// path is relative and starts with that many parent-directory steps.
+func appendPath(next func() uint64, out *[]byte, shape pathShape) {
+	sep, roots := byte('/'), posixRoots
+	if shape.windows {
+		sep, roots = '\\', windowsRoots
+	}
+	depth := shape.minDepth + int(uint64N(next, uint64(shape.maxDepth-shape.minDepth+1)))
+	if shape.traversal > 0 {
+		for i := 0; i < shape.traversal; i++ {
+			*out = append(*out, '.', '.', sep)
+		}
+	} else {
+		if shape.windows {
+			*out = append(*out, "C:"...)
+		}
+		*out = append(*out, sep)
+		if depth > 0 {
+			*out = append(*out, pickWord(next, roots)...)
+			*out = append(*out, sep)
+			depth--
+		}
+	}
+	for i := 0; i < depth; i++ {
+		*out = append(*out, pickWord(next, nouns)...)
+		*out = append(*out, sep)
+	}
+	appendFilename(next, out, shape.ext)
+}
+
+// filenameExt returns the extension the ext parameter of a FILENAME or
+// PATH keyword asks for, or why it is invalid.
+func filenameExt(params []byte) (string, string) {
+	ext, ok := keywordParam(params, "ext")
+	if !ok {
+		return "", ""
+	}
+	ext = bytes.TrimPrefix(ext, []byte{'.'})
+	if len(ext) == 0 || bytes.ContainsAny(ext, `/\`) {
+		return "", fmt.Sprintf("invalid extension %q", ext)
+	}
+	return unsafeString(ext), ""
+}
+
+// pathParams returns the path shape the depth, style, traversal and ext
+// parameters of a PATH keyword ask for, or why one is invalid.
+func pathParams(params []byte) (pathShape, string) {
+	shape := defaultPathShape
+	if params == nil {
+		return shape, ""
+	}
+	if v, ok := keywordParam(params, "depth"); ok {
+		var valid bool
+		if shape.minDepth, shape.maxDepth, valid = parseIntRange(v, 0, maxPathDepth); !valid {
+			return defaultPathShape, fmt.Sprintf("invalid depth %q: want N or MIN-MAX within [0, %d]", v, maxPathDepth)
+		}
+	}
+	if v, ok := keywordParam(params, "style"); ok {
+		switch {
+		case bytes.EqualFold(v, []byte("posix")):
+		case bytes.EqualFold(v, []byte("windows")):
+			shape.windows = true
+		default:
+			return defaultPathShape, fmt.Sprintf("invalid style %q: want posix or windows", v)
+		}
+	}
+	if v, ok := keywordParam(params, "traversal"); ok {
+		var valid bool
+		if shape.traversal, valid = parseLengthFast(v); !valid || shape.traversal > maxPathDepth {
+			return defaultPathShape, fmt.Sprintf("invalid traversal %q: want 0 to %d", v, maxPathDepth)
+		}
+	}
+	var reason string
+	if shape.ext, reason = filenameExt(params); reason != "" {
+		return defaultPathShape, reason
+	}
+	return shape, ""
+}
+
+// appendFilename appends a file name with the ext parameter of a FILENAME
+// keyword.
+func (e *FastEngine) appendFilename(out *[]byte, kw *keywordSpec) {
+	ext, _ := filenameExt(kw.params)
+	appendFilename(e.next, out, ext)
+}
+
+// appendPath appends a path shaped by the parameters of a PATH keyword.
+func (e *FastEngine) appendPath(out *[]byte, kw *keywordSpec) {
+	shape, _ := pathParams(kw.params)
+	appendPath(e.next, out, shape)
+}
@@ -0,0 +1,71 @@
+package fastrand_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const filenamePattern = `[a-z]+(_[a-z]+)?(_[0-9]{1,2})?\.[a-z]+(\.gz)?`
+
+func TestFilename(t *testing.T) {
+	for i := 0; i < 300; i++ {
+		require.Regexp(t, `^`+filenamePattern+`$`, fastrand.Filename())
+		path := fastrand.FilePath(3)
+		require.Regexp(t, `^/[a-z]+/[a-z]+/[a-z]+/`+filenamePattern+`$`, path)
+	}
+	assert.Regexp(t, `^/`+filenamePattern+`$`, fastrand.FilePath(0))
+}
+
+func TestFilenameKeywords(t *testing.T) {
+	for i := 0; i < 300; i++ {
+		require.Regexp(t, `^`+filenamePattern+`$`, fastrand.RandomizerString("{RAND;FILENAME}"))
+		require.Regexp(t, `^[a-z_0-9]+\.pdf$`, fastrand.RandomizerString("{RAND;FILENAME(ext=.pdf)}"))
+
+		out := fastrand.RandomizerString("{RAND;PATH}")
+		require.Regexp(t, `^(/[a-z]+){1,4}/`+filenamePattern+`$`, out)
+
+		out = fastrand.RandomizerString("{RAND;PATH(style=windows,depth=2,ext=ini)}")
+		require.Regexp(t, `^C:\\[A-Za-z ]+\\[a-z]+\\[a-z_0-9]+\.ini$`, out)
+
+		out = fastrand.RandomizerString("{RAND;PATH(traversal=3,depth=0-1)}")
+		require.True(t, strings.HasPrefix(out, "../../../"), out)
+		require.NotContains(t, out[9:], "..", out)
+		require.LessOrEqual(t, strings.Count(out, "/"), 4, out)
+
+		out = fastrand.RandomizerString("{RAND;PATH(traversal=2,style=WINDOWS,depth=0)}")
+		require.Regexp(t, `^\.\.\\\.\.\\`+filenamePattern+`$`, out)
+	}
+}
+
+func TestFilenameKeywordsStrict(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithStrictParsing(true))
+	cases := map[string]string{
+		"{RAND;FILENAME(ext=)}":       "invalid extension",
+		"{RAND;PATH(ext=a/b)}":        "invalid extension",
+		"{RAND;PATH(depth=17)}":       "invalid depth",
+		"{RAND;PATH(style=dos)}":      "invalid style",
+		"{RAND;PATH(traversal=many)}": "invalid traversal",
+		"{RAND;FILENAME(depth=1)}":    "unknown parameter",
+	}
+	for payload, want := range cases {
+		_, err := engine.RandomizerErr([]byte(payload))
+		assert.ErrorContains(t, err, want, payload)
+	}
+	_, err := engine.RandomizerErr([]byte("{RAND;FILENAME}{RAND;PATH(depth=0-16,style=posix,traversal=16,ext=tar.gz)}"))
+	assert.NoError(t, err)
+}
+
+func TestAllocsFilenameKeywords(t *testing.T) {
+	engine := fastrand.NewEngine()
+	payload := []byte("GET /download?file={RAND;PATH(traversal=4,depth=1)}&name={RAND;FILENAME}")
+	dst := make([]byte, 0, 256)
+	dst = engine.RandomizerAppend(dst, payload)
+	allocs := testing.AllocsPerRun(100, func() {
+		dst = engine.RandomizerAppend(dst[:0], payload)
+	})
+	assert.Zero(t, allocs)
+}
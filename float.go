@@ -0,0 +1,100 @@
+package fastrand
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+const (
+	// defaultFloatPrecision is the number of fraction digits of FLOAT
+	// without a precision.
+	defaultFloatPrecision = 2
+	// maxFloatPrecision bounds FLOAT's fraction digits.
+	maxFloatPrecision = 15
+	// maxFloatUnits bounds the range of FLOAT in units of its last digit,
+	// so the scaled bounds fit an int64.
+	maxFloatUnits = 1 << 62
+)
+
+// floatRange is the range of a FLOAT keyword in units of its last fraction
+// digit: FLOAT:0.5-2:2 draws from [50, 200] and writes two digits.
+type floatRange struct {
+	lo, hi    int64
+	precision int
+}
+
+// defaultFloatRange is FLOAT without an argument: [0, 1] in hundredths.
+var defaultFloatRange = floatRange{lo: 0, hi: 100, precision: defaultFloatPrecision}
+
+// parseFloatArg parses FLOAT's argument "min-max" or "min-max:precision",
+// such as 0.0-100.0:2, and returns why it is invalid, or "". Without an
+// argument it returns defaultFloatRange.
+func parseFloatArg(arg []byte) (floatRange, string) {
+	if len(arg) == 0 {
+		return defaultFloatRange, ""
+	}
+	bounds, digits, hasPrecision := bytes.Cut(arg, []byte{':'})
+	r := floatRange{precision: defaultFloatPrecision}
+	if hasPrecision {
+		p, ok := parseLengthFast(digits)
+		if !ok || p > maxFloatPrecision {
+			return defaultFloatRange, fmt.Sprintf("invalid precision %q: want 0 to %d fraction digits", digits, maxFloatPrecision)
+		}
+		r.precision = p
+	}
+	sep := -1
+	for i := 1; i < len(bounds); i++ {
+		if bounds[i] == '-' && bounds[i-1] != 'e' && bounds[i-1] != 'E' {
+			sep = i
+			break
+		}
+	}
+	if sep == -1 {
+		return defaultFloatRange, fmt.Sprintf("invalid range %q: want min-max such as 0.0-100.0", bounds)
+	}
+	lo, errLo := strconv.ParseFloat(unsafeString(bounds[:sep]), 64)
+	hi, errHi := strconv.ParseFloat(unsafeString(bounds[sep+1:]), 64)
+	if errLo != nil || errHi != nil || math.IsNaN(lo) || math.IsNaN(hi) {
+		return defaultFloatRange, fmt.Sprintf("invalid range %q: want min-max such as 0.0-100.0", bounds)
+	}
+	scale := math.Pow10(r.precision)
+	scaledLo, scaledHi := roundToUnit(lo*scale, math.Ceil), roundToUnit(hi*scale, math.Floor)
+	if math.Abs(scaledLo) > maxFloatUnits || math.Abs(scaledHi) > maxFloatUnits {
+		return defaultFloatRange, fmt.Sprintf("range %q is too wide for %d fraction digits", bounds, r.precision)
+	}
+	r.lo, r.hi = int64(scaledLo), int64(scaledHi)
+	if r.lo > r.hi {
+		return defaultFloatRange, fmt.Sprintf("range %q holds no value with %d fraction digits", bounds, r.precision)
+	}
+	return r, ""
+}
+
+// roundToUnit rounds a scaled bound to the nearest integer when it is one
+// up to floating-point error, as 0.1*10 is, and with round otherwise.
+func roundToUnit(x float64, round func(float64) float64) float64 {
+	if r := math.Round(x); math.Abs(x-r) <= 1e-9*max(1, math.Abs(x)) {
+		return r
+	}
+	return round(x)
+}
+
+// appendFloat appends a decimal drawn uniformly from the values of FLOAT's
+// range with its number of fraction digits. An invalid argument falls back
+// to defaultFloatRange.
+func (e *FastEngine) appendFloat(out *[]byte, arg []byte) {
+	r, _ := parseFloatArg(arg)
+	v := r.lo + int64(uint64N(e.next, uint64(r.hi-r.lo)+1))
+	abs := uint64(v)
+	if v < 0 {
+		*out = append(*out, '-')
+		abs = uint64(-v)
+	}
+	scale := uint64(math.Pow10(r.precision))
+	*out = strconv.AppendUint(*out, abs/scale, 10)
+	if r.precision > 0 {
+		*out = append(*out, '.')
+		*out = appendPadded(*out, abs%scale, r.precision)
+	}
+}
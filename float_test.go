@@ -0,0 +1,65 @@
+package fastrand_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFloat(t *testing.T) {
+	for i := 0; i < 500; i++ {
+		out := fastrand.RandomizerString("{RAND;FLOAT:0.0-100.0:2}")
+		require.Regexp(t, `^\d{1,3}\.\d\d$`, out)
+		v, err := strconv.ParseFloat(out, 64)
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, v, 0.0)
+		assert.LessOrEqual(t, v, 100.0)
+
+		out = fastrand.RandomizerString("{RAND;FLOAT:-1.5-1.5:3}")
+		require.Regexp(t, `^-?\d\.\d{3}$`, out)
+		v, _ = strconv.ParseFloat(out, 64)
+		assert.GreaterOrEqual(t, v, -1.5)
+		assert.LessOrEqual(t, v, 1.5)
+
+		assert.Regexp(t, `^[01]\.\d\d$`, fastrand.RandomizerString("{RAND;float}"))
+		assert.Regexp(t, `^-?[0-5]$`, fastrand.RandomizerString("{RAND;FLOAT:-5-5:0}"))
+	}
+	assert.Equal(t, "0.30", fastrand.RandomizerString("{RAND;FLOAT:0.3-0.3}"), "0.3 scales to an exact unit")
+	assert.Equal(t, "-0.002", fastrand.RandomizerString("{RAND;FLOAT:-0.002--0.002:3}"))
+	assert.Equal(t, "12.5", fastrand.RandomizerString("{RAND;FLOAT:12.46-12.54:1}"))
+}
+
+func TestFloatInvalid(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithStrictParsing(true))
+	for payload, reason := range map[string]string{
+		"{RAND;FLOAT:abc}":         `invalid range "abc"`,
+		"{RAND;FLOAT:1-x}":         `invalid range "1-x"`,
+		"{RAND;FLOAT:0-1:99}":      `invalid precision "99"`,
+		"{RAND;FLOAT:0.11-0.19:1}": "holds no value with 1 fraction digits",
+		"{RAND;FLOAT:0-1e300:2}":   "too wide",
+	} {
+		_, err := engine.RandomizerErr([]byte(payload))
+		assert.ErrorContains(t, err, reason, payload)
+	}
+	_, err := engine.RandomizerErr([]byte("{RAND;FLOAT:-1e3-1e3:4}"))
+	assert.NoError(t, err)
+	assert.Regexp(t, `^[01]\.\d\d$`, fastrand.RandomizerString("{RAND;FLOAT:abc}"), "an invalid range falls back to the default")
+
+	bits, err := fastrand.TagEntropy("{RAND;FLOAT:0-1:2}")
+	require.NoError(t, err)
+	assert.InDelta(t, 6.658, bits, 0.001)
+}
+
+func TestAllocsFloat(t *testing.T) {
+	engine := fastrand.NewEngine()
+	payload := []byte(`{"price":{RAND;FLOAT:0.0-100.0:2}}`)
+	dst := make([]byte, 0, 64)
+	dst = engine.RandomizerAppend(dst, payload)
+	allocs := testing.AllocsPerRun(100, func() {
+		dst = engine.RandomizerAppend(dst[:0], payload)
+	})
+	assert.Zero(t, allocs)
+}
@@ -0,0 +1,100 @@
+package fastrand_test
+
+import (
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	formColorRegex = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+	formTelRegex   = regexp.MustCompile(`^[+0-9()\- .a-z]+$`)
+	formTimeRegex  = regexp.MustCompile(`^\d{2}:\d{2}(:\d{2}(\.\d{3})?)?$`)
+	formWeekRegex  = regexp.MustCompile(`^\d{4}-W\d{2}$`)
+	formMonthRegex = regexp.MustCompile(`^\d{4}-\d{2}$`)
+)
+
+func checkFormValue(tb testing.TB, inputType, v string) {
+	tb.Helper()
+	switch inputType {
+	case "email":
+		_, err := mail.ParseAddress(v)
+		assert.NoError(tb, err, "email %q should parse", v)
+	case "number":
+		_, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			assert.ErrorIs(tb, err, strconv.ErrRange, "number %q should be numeric", v)
+		}
+	case "date":
+		_, err := time.Parse("2006-01-02", v)
+		assert.NoError(tb, err, "date %q should be a valid calendar date", v)
+	case "time":
+		assert.Regexp(tb, formTimeRegex, v)
+	case "month":
+		assert.Regexp(tb, formMonthRegex, v)
+	case "week":
+		assert.Regexp(tb, formWeekRegex, v)
+	case "tel":
+		assert.Regexp(tb, formTelRegex, v)
+	case "url":
+		u, err := url.Parse(v)
+		if assert.NoError(tb, err, "url %q should parse", v) {
+			assert.NotEmpty(tb, u.Scheme)
+			assert.NotEmpty(tb, u.Host)
+		}
+	case "color":
+		assert.Regexp(tb, formColorRegex, v)
+	}
+}
+
+func TestFormValue(t *testing.T) {
+	types := []string{"email", "number", "date", "time", "month", "week", "tel", "url", "color", "text"}
+	for _, typ := range types {
+		t.Run(typ, func(t *testing.T) {
+			seen := map[string]bool{}
+			for i := 0; i < 300; i++ {
+				v := fastrand.FormValue(typ)
+				checkFormValue(t, typ, v)
+				seen[v] = true
+			}
+			assert.Greater(t, len(seen), 5, "values should vary")
+		})
+	}
+}
+
+func TestFormValueAliases(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		checkFormValue(t, "number", fastrand.FormValue("range"))
+		checkFormValue(t, "email", fastrand.FormValue("EMAIL"))
+		_ = fastrand.FormValue("no-such-type")
+	}
+}
+
+func TestFormValueBoundaries(t *testing.T) {
+	seen := map[string]bool{}
+	for i := 0; i < 2000; i++ {
+		seen[fastrand.FormValue("date")] = true
+	}
+	assert.True(t, seen["2024-02-29"] || seen["2000-02-29"], "leap days should be among the boundary values")
+}
+
+func TestRandomizerFormKeyword(t *testing.T) {
+	for i := 0; i < 200; i++ {
+		result := fastrand.RandomizerString(`{"mail":"{RAND;FORM:email}","color":"{RAND;FORM:Color}"}`)
+		parts := strings.Split(result, `"`)
+		checkFormValue(t, "email", parts[3])
+		checkFormValue(t, "color", parts[7])
+	}
+
+	engine := fastrand.NewEngine(fastrand.WithSeed(8))
+	other := fastrand.NewEngine(fastrand.WithSeed(8))
+	tmpl := "{RAND;FORM:url}|{RAND;FORM:tel}|{RAND;FORM:date}"
+	assert.Equal(t, engine.RandomizerString(tmpl), other.RandomizerString(tmpl))
+}
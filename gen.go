@@ -0,0 +1,170 @@
+package fastrand
+
+import (
+	"sort"
+	"strings"
+)
+
+// Gen produces a fresh random value of type T on every call. Generators are
+// composed with Map, Filter, OneOf, SliceOf and Weighted.
+type Gen[T any] func() T
+
+// Any returns the next value of g as an interface value.
+func (g Gen[T]) Any() any {
+	return g()
+}
+
+// maxFilterAttempts bounds Filter so an unsatisfiable predicate panics
+// instead of spinning forever.
+const maxFilterAttempts = 1000
+
+// Const returns a generator that always yields v.
+func Const[T any](v T) Gen[T] {
+	return func() T { return v }
+}
+
+// UUIDs returns a generator of random RFC 4122 v4 UUID strings.
+func UUIDs() Gen[string] {
+	return func() string {
+		var raw [16]byte
+		FillBytes(raw[:])
+		raw[6] = (raw[6] & 0x0f) | 0x40
+		raw[8] = (raw[8] & 0x3f) | 0x80
+		b := make([]byte, 0, 36)
+		appendUUIDText(&b, &raw)
+		return unsafeString(b)
+	}
+}
+
+// FromTemplate returns a generator that expands tmpl with the default
+// engine on every call. The template is compiled once. It panics if the
+// default engine rejects tmpl, as a strict engine does malformed tags.
+func FromTemplate(tmpl string) Gen[string] {
+	t, err := Compile(s2b(tmpl))
+	if err != nil {
+		panic("fastrand: FromTemplate: " + strings.TrimPrefix(err.Error(), "fastrand: "))
+	}
+	return t.ExecuteString
+}
+
+// IntRange returns a generator of integers in the inclusive range [min, max].
+func IntRange(min, max int) Gen[int] {
+	if min > max {
+		panic("fastrand: IntRange min must not exceed max")
+	}
+	return func() int { return Int(min, max) }
+}
+
+// Float64Range returns a generator of floats in [min, max).
+func Float64Range(min, max float64) Gen[float64] {
+	if min > max {
+		panic("fastrand: Float64Range min must not exceed max")
+	}
+	return func() float64 { return min + Float64()*(max-min) }
+}
+
+// StringOf returns a generator of strings of the given length drawn from
+// charset.
+func StringOf(length int, charset CharsList) Gen[string] {
+	if length <= 0 {
+		panic("fastrand: length must be positive")
+	}
+	if len(charset) == 0 {
+		panic("fastrand: charset must not be empty")
+	}
+	return func() string { return String(length, charset) }
+}
+
+// Elements returns a generator that picks uniformly from items.
+func Elements[T any](items ...T) Gen[T] {
+	if len(items) == 0 {
+		panic("fastrand: Elements requires at least one item")
+	}
+	return func() T { return Choice(items) }
+}
+
+// Map returns a generator that applies f to every value produced by g.
+func Map[T, U any](g Gen[T], f func(T) U) Gen[U] {
+	return func() U { return f(g()) }
+}
+
+// Filter returns a generator that draws from g until keep accepts a value.
+// It panics if keep rejects too many consecutive values.
+func Filter[T any](g Gen[T], keep func(T) bool) Gen[T] {
+	return func() T {
+		for i := 0; i < maxFilterAttempts; i++ {
+			if v := g(); keep(v) {
+				return v
+			}
+		}
+		panic("fastrand: Filter rejected too many consecutive values")
+	}
+}
+
+// OneOf returns a generator that delegates to one of gens chosen uniformly
+// on every call.
+func OneOf[T any](gens ...Gen[T]) Gen[T] {
+	if len(gens) == 0 {
+		panic("fastrand: OneOf requires at least one generator")
+	}
+	return func() T { return Choice(gens)() }
+}
+
+// SliceOf returns a generator of slices whose length is in [minLen, maxLen]
+// and whose elements come from g.
+func SliceOf[T any](g Gen[T], minLen, maxLen int) Gen[[]T] {
+	if minLen < 0 || minLen > maxLen {
+		panic("fastrand: invalid SliceOf length range")
+	}
+	return func() []T {
+		s := make([]T, Int(minLen, maxLen))
+		for i := range s {
+			s[i] = g()
+		}
+		return s
+	}
+}
+
+// WeightedGen pairs a generator with its relative selection weight.
+type WeightedGen[T any] struct {
+	Weight int
+	Gen    Gen[T]
+}
+
+// Weighted returns a generator that delegates to one of choices with
+// probability proportional to its weight. Choices with a non-positive
+// weight are never selected.
+func Weighted[T any](choices ...WeightedGen[T]) Gen[T] {
+	weights := make([]int, len(choices))
+	for i, c := range choices {
+		weights[i] = c.Weight
+	}
+	cum := cumulativeWeights(weights)
+	if len(cum) == 0 || cum[len(cum)-1] == 0 {
+		panic("fastrand: Weighted requires a positive total weight")
+	}
+	return func() T {
+		return choices[weightedIndex(fastUint64, cum)].Gen()
+	}
+}
+
+// cumulativeWeights returns the running totals of weights, treating
+// non-positive weights as zero.
+func cumulativeWeights(weights []int) []uint64 {
+	cum := make([]uint64, len(weights))
+	var total uint64
+	for i, w := range weights {
+		if w > 0 {
+			total += uint64(w)
+		}
+		cum[i] = total
+	}
+	return cum
+}
+
+// weightedIndex picks an index with probability proportional to the
+// difference between consecutive cumulative weights.
+func weightedIndex(next func() uint64, cum []uint64) int {
+	r := uint64N(next, cum[len(cum)-1])
+	return sort.Search(len(cum), func(i int) bool { return cum[i] > r })
+}
@@ -0,0 +1,136 @@
+package fastrand_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenConst(t *testing.T) {
+	g := fastrand.Const("fixed")
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, "fixed", g())
+	}
+}
+
+func TestGenSources(t *testing.T) {
+	ints := fastrand.IntRange(18, 99)
+	floats := fastrand.Float64Range(1.5, 2.5)
+	strs := fastrand.StringOf(6, fastrand.CharsDigits)
+	elems := fastrand.Elements("a", "b")
+	for i := 0; i < 500; i++ {
+		v := ints()
+		assert.GreaterOrEqual(t, v, 18)
+		assert.LessOrEqual(t, v, 99)
+		f := floats()
+		assert.GreaterOrEqual(t, f, 1.5)
+		assert.Less(t, f, 2.5)
+		s := strs()
+		assert.Len(t, s, 6)
+		checkCharset(t, []byte(s), fastrand.CharsDigits)
+		assert.Contains(t, []string{"a", "b"}, elems())
+	}
+
+	assert.Panics(t, func() { fastrand.IntRange(5, 1) })
+	assert.Panics(t, func() { fastrand.Elements[int]() })
+}
+
+func TestGenMap(t *testing.T) {
+	g := fastrand.Map(fastrand.IntRange(1, 9), func(v int) string { return strings.Repeat("x", v) })
+	for i := 0; i < 100; i++ {
+		s := g()
+		assert.GreaterOrEqual(t, len(s), 1)
+		assert.LessOrEqual(t, len(s), 9)
+	}
+}
+
+func TestGenFilter(t *testing.T) {
+	even := fastrand.Filter(fastrand.IntRange(0, 100), func(v int) bool { return v%2 == 0 })
+	for i := 0; i < 200; i++ {
+		assert.Equal(t, 0, even()%2)
+	}
+
+	never := fastrand.Filter(fastrand.IntRange(0, 10), func(int) bool { return false })
+	assert.Panics(t, func() { never() })
+}
+
+func TestGenOneOf(t *testing.T) {
+	g := fastrand.OneOf(fastrand.Const(1), fastrand.Const(2), fastrand.Const(3))
+	seen := map[int]bool{}
+	for i := 0; i < 300; i++ {
+		seen[g()] = true
+	}
+	assert.Len(t, seen, 3)
+	assert.Panics(t, func() { fastrand.OneOf[int]() })
+}
+
+func TestGenSliceOf(t *testing.T) {
+	g := fastrand.SliceOf(fastrand.IntRange(0, 5), 2, 4)
+	for i := 0; i < 200; i++ {
+		s := g()
+		assert.GreaterOrEqual(t, len(s), 2)
+		assert.LessOrEqual(t, len(s), 4)
+		for _, v := range s {
+			assert.LessOrEqual(t, v, 5)
+		}
+	}
+
+	nested := fastrand.SliceOf(fastrand.SliceOf(fastrand.Const("x"), 1, 1), 3, 3)
+	assert.Equal(t, [][]string{{"x"}, {"x"}, {"x"}}, nested())
+	assert.Panics(t, func() { fastrand.SliceOf(fastrand.Const(0), 3, 1) })
+}
+
+func TestGenWeighted(t *testing.T) {
+	g := fastrand.Weighted(
+		fastrand.WeightedGen[string]{Weight: 9, Gen: fastrand.Const("common")},
+		fastrand.WeightedGen[string]{Weight: 1, Gen: fastrand.Const("rare")},
+		fastrand.WeightedGen[string]{Weight: 0, Gen: fastrand.Const("never")},
+	)
+	counts := map[string]int{}
+	for i := 0; i < 10000; i++ {
+		counts[g()]++
+	}
+	assert.Zero(t, counts["never"])
+	assert.InDelta(t, 9000, counts["common"], 500)
+	assert.InDelta(t, 1000, counts["rare"], 500)
+
+	assert.Panics(t, func() {
+		fastrand.Weighted(fastrand.WeightedGen[int]{Weight: 0, Gen: fastrand.Const(1)})
+	})
+}
+
+func TestGenComposition(t *testing.T) {
+	type user struct {
+		Name string
+		Age  int
+		Tags []string
+	}
+	names := fastrand.StringOf(8, fastrand.CharsAlphabetLower)
+	ages := fastrand.IntRange(18, 99)
+	tags := fastrand.SliceOf(fastrand.Elements("admin", "dev", "ops"), 0, 3)
+	users := fastrand.Map(fastrand.Const(struct{}{}), func(struct{}) user {
+		return user{Name: names(), Age: ages(), Tags: tags()}
+	})
+
+	for i := 0; i < 50; i++ {
+		u := users()
+		assert.Len(t, u.Name, 8)
+		assert.GreaterOrEqual(t, u.Age, 18)
+		assert.LessOrEqual(t, len(u.Tags), 3)
+	}
+}
+
+func TestFromTemplate(t *testing.T) {
+	g := fastrand.FromTemplate("id={RAND;8;DIGIT}")
+	for i := 0; i < 20; i++ {
+		assert.Regexp(t, `^id=[0-9]{8}$`, g())
+	}
+
+	prev := fastrand.SetDefaultEngine(fastrand.NewEngine(fastrand.WithStrictParsing(true)))
+	t.Cleanup(func() { fastrand.SetDefaultEngine(prev) })
+	assert.PanicsWithValue(t, `fastrand: FromTemplate: tag at offset 0: unknown keyword "NOPE"`, func() {
+		fastrand.FromTemplate("{RAND;8;NOPE}")
+	})
+}
@@ -0,0 +1,120 @@
+package fastrand
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+const (
+	// defaultGeoPrecision is the number of decimal places GEO writes by
+	// default, about 0.1 m at the equator.
+	defaultGeoPrecision = 6
+	maxGeoPrecision     = 10
+)
+
+// geoBox is a latitude/longitude bounding box in degrees. A box whose
+// minLon is above its maxLon crosses the antimeridian.
+type geoBox struct {
+	minLat, maxLat, minLon, maxLon float64
+}
+
+var wholeGlobe = geoBox{minLat: -90, maxLat: 90, minLon: -180, maxLon: 180}
+
+// GeoOption configures the coordinates LatLon generates.
+type GeoOption func(*geoBox)
+
+// WithBoundingBox confines coordinates to the box from (minLat, minLon) to
+// (maxLat, maxLon). A minLon above maxLon gives a box that crosses the
+// antimeridian. Bounds outside [-90, 90] and [-180, 180] are clamped.
+func WithBoundingBox(minLat, minLon, maxLat, maxLon float64) GeoOption {
+	return func(b *geoBox) {
+		b.minLat, b.maxLat = clampFloat(min(minLat, maxLat), -90, 90), clampFloat(max(minLat, maxLat), -90, 90)
+		b.minLon, b.maxLon = clampFloat(minLon, -180, 180), clampFloat(maxLon, -180, 180)
+	}
+}
+
+func clampFloat(v, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(hi, v))
+}
+
+// LatLon returns a random point on the globe in degrees, or within the
+// bounding box an option sets. Points are spread evenly by area, so the
+// poles are not over-represented.
+func LatLon(opts ...GeoOption) (lat, lon float64) {
+	b := wholeGlobe
+	for _, opt := range opts {
+		opt(&b)
+	}
+	return latLon(fastUint64, b)
+}
+
+func latLon(next func() uint64, b geoBox) (lat, lon float64) {
+	lo, hi := math.Sin(b.minLat*math.Pi/180), math.Sin(b.maxLat*math.Pi/180)
+	lat = math.Asin(lo+float64From(next)*(hi-lo)) * 180 / math.Pi
+	span := b.maxLon - b.minLon
+	if span < 0 {
+		span += 360
+	}
+	if lon = b.minLon + float64From(next)*span; lon > 180 {
+		lon -= 360
+	}
+	return lat, lon
+}
+
+// geoParams returns the bounding box and precision the minlat, maxlat,
+// minlon, maxlon and precision parameters of a GEO keyword ask for, or why
+// one is invalid.
+func geoParams(params []byte) (b geoBox, precision int, reason string) {
+	b, precision = wholeGlobe, defaultGeoPrecision
+	if params == nil {
+		return b, precision, ""
+	}
+	if reason = geoBound(params, "minlat", -90, 90, &b.minLat); reason != "" {
+		return wholeGlobe, defaultGeoPrecision, reason
+	}
+	if reason = geoBound(params, "maxlat", -90, 90, &b.maxLat); reason != "" {
+		return wholeGlobe, defaultGeoPrecision, reason
+	}
+	if reason = geoBound(params, "minlon", -180, 180, &b.minLon); reason != "" {
+		return wholeGlobe, defaultGeoPrecision, reason
+	}
+	if reason = geoBound(params, "maxlon", -180, 180, &b.maxLon); reason != "" {
+		return wholeGlobe, defaultGeoPrecision, reason
+	}
+	if b.minLat > b.maxLat {
+		return wholeGlobe, defaultGeoPrecision, fmt.Sprintf("minlat %g is above maxlat %g", b.minLat, b.maxLat)
+	}
+	if v, ok := keywordParam(params, "precision"); ok {
+		var valid bool
+		if precision, valid = parseLengthFast(v); !valid || precision > maxGeoPrecision {
+			return wholeGlobe, defaultGeoPrecision, fmt.Sprintf("invalid precision %q: want 0 to %d", v, maxGeoPrecision)
+		}
+	}
+	return b, precision, ""
+}
+
+// geoBound sets *dst to the named parameter of a GEO keyword when it is
+// present, or returns why it is not a number within [lo, hi].
+func geoBound(params []byte, name string, lo, hi float64, dst *float64) string {
+	v, ok := keywordParam(params, name)
+	if !ok {
+		return ""
+	}
+	f, err := strconv.ParseFloat(unsafeString(v), 64)
+	if err != nil || f < lo || f > hi {
+		return fmt.Sprintf("invalid %s %q: want a number within [%g, %g]", name, v, lo, hi)
+	}
+	*dst = f
+	return ""
+}
+
+// appendGeo appends a point as "lat,lon" with the bounding box and
+// precision of a GEO keyword, such as 37.774929,-122.419416.
+func (e *FastEngine) appendGeo(out *[]byte, kw *keywordSpec) {
+	b, precision, _ := geoParams(kw.params)
+	lat, lon := latLon(e.next, b)
+	*out = strconv.AppendFloat(*out, lat, 'f', precision, 64)
+	*out = append(*out, ',')
+	*out = strconv.AppendFloat(*out, lon, 'f', precision, 64)
+}
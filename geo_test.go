@@ -0,0 +1,76 @@
+package fastrand_test
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatLon(t *testing.T) {
+	north := 0
+	for i := 0; i < 2000; i++ {
+		lat, lon := fastrand.LatLon()
+		require.True(t, lat >= -90 && lat <= 90 && lon >= -180 && lon <= 180, "%g,%g", lat, lon)
+		if math.Abs(lat) > 60 {
+			north++
+		}
+	}
+	assert.Less(t, north, 300, "points are spread by area, so few lie beyond 60°")
+
+	for i := 0; i < 500; i++ {
+		lat, lon := fastrand.LatLon(fastrand.WithBoundingBox(37.7, -122.5, 37.8, -122.3))
+		require.True(t, lat >= 37.7 && lat <= 37.8 && lon >= -122.5 && lon <= -122.3, "%g,%g", lat, lon)
+
+		lat, lon = fastrand.LatLon(fastrand.WithBoundingBox(-20, 170, -10, -170))
+		require.True(t, lat >= -20 && lat <= -10, lat)
+		require.True(t, lon >= 170 || lon <= -170, "the box crosses the antimeridian: %g", lon)
+	}
+}
+
+func TestGeoKeyword(t *testing.T) {
+	for i := 0; i < 300; i++ {
+		out := fastrand.RandomizerString("{RAND;GEO}")
+		require.Regexp(t, `^-?[0-9]{1,2}\.[0-9]{6},-?[0-9]{1,3}\.[0-9]{6}$`, out)
+
+		out = fastrand.RandomizerString("{RAND;GEO(minlat=51.28,maxlat=51.69,minlon=-0.51,maxlon=0.33,precision=3)}")
+		require.Regexp(t, `^51\.[0-9]{3},-?0\.[0-9]{3}$`, out)
+		lat, lon, _ := strings.Cut(out, ",")
+		la, _ := strconv.ParseFloat(lat, 64)
+		lo, _ := strconv.ParseFloat(lon, 64)
+		require.True(t, la >= 51.28 && la <= 51.69 && lo >= -0.51 && lo <= 0.33, out)
+	}
+	assert.Regexp(t, `^-?[0-9]+,-?[0-9]+$`, fastrand.RandomizerString("{RAND;GEO(precision=0)}"))
+}
+
+func TestGeoKeywordStrict(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithStrictParsing(true))
+	cases := map[string]string{
+		"{RAND;GEO(minlat=-91)}":         "invalid minlat",
+		"{RAND;GEO(maxlon=east)}":        "invalid maxlon",
+		"{RAND;GEO(minlat=10,maxlat=5)}": "minlat 10 is above maxlat 5",
+		"{RAND;GEO(precision=11)}":       "invalid precision",
+		"{RAND;GEO(lat=1)}":              "unknown parameter",
+	}
+	for payload, want := range cases {
+		_, err := engine.RandomizerErr([]byte(payload))
+		assert.ErrorContains(t, err, want, payload)
+	}
+	_, err := engine.RandomizerErr([]byte("{RAND;GEO}{RAND;GEO(minlon=170,maxlon=-170,precision=2)}"))
+	assert.NoError(t, err)
+}
+
+func TestAllocsGeoKeyword(t *testing.T) {
+	engine := fastrand.NewEngine()
+	payload := []byte(`{"loc":"{RAND;GEO(minlat=40.5,maxlat=40.9,minlon=-74.3,maxlon=-73.7,precision=5)}"}`)
+	dst := make([]byte, 0, 128)
+	dst = engine.RandomizerAppend(dst, payload)
+	allocs := testing.AllocsPerRun(100, func() {
+		dst = engine.RandomizerAppend(dst[:0], payload)
+	})
+	assert.Zero(t, allocs)
+}
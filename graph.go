@@ -0,0 +1,174 @@
+package fastrand
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Tree returns a random rooted tree with the given number of nodes as an
+// adjacency list of children: tree[i] lists the children of node i. Node 0
+// is the root (depth 0) and no node is deeper than maxDepth.
+func Tree(nodes, maxDepth int) [][]int {
+	if nodes <= 0 {
+		panic("fastrand: Tree requires a positive node count")
+	}
+	if maxDepth <= 0 && nodes > 1 {
+		panic("fastrand: Tree maxDepth must be positive for more than one node")
+	}
+	children := make([][]int, nodes)
+	depth := make([]int, nodes)
+	parents := []int{0}
+	for i := 1; i < nodes; i++ {
+		p := parents[int(fastUint64N(uint64(len(parents))))]
+		children[p] = append(children[p], i)
+		depth[i] = depth[p] + 1
+		if depth[i] < maxDepth {
+			parents = append(parents, i)
+		}
+	}
+	return children
+}
+
+type graphConfig struct {
+	directed  bool
+	dag       bool
+	connected bool
+}
+
+// GraphOption configures Graph.
+type GraphOption func(*graphConfig)
+
+// Directed makes Graph produce directed edges.
+func Directed() GraphOption {
+	return func(c *graphConfig) {
+		c.directed = true
+	}
+}
+
+// DAG makes Graph produce a directed acyclic graph.
+func DAG() GraphOption {
+	return func(c *graphConfig) {
+		c.directed = true
+		c.dag = true
+	}
+}
+
+// Connected makes Graph produce a connected graph; directed graphs are
+// weakly connected.
+func Connected() GraphOption {
+	return func(c *graphConfig) {
+		c.connected = true
+	}
+}
+
+// Graph returns a random simple graph with the given number of nodes and
+// edges as sorted adjacency lists: graph[u] lists the nodes v with an edge
+// u->v. Undirected edges appear in both lists. It panics if the edge count
+// cannot be satisfied.
+func Graph(nodes, edges int, opts ...GraphOption) [][]int {
+	var cfg graphConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if nodes <= 0 {
+		panic("fastrand: Graph requires a positive node count")
+	}
+	maxEdges := nodes * (nodes - 1) / 2
+	if cfg.directed && !cfg.dag {
+		maxEdges *= 2
+	}
+	if edges < 0 || edges > maxEdges {
+		panic(fmt.Sprintf("fastrand: Graph cannot place %d edges on %d nodes", edges, nodes))
+	}
+	if cfg.connected && edges < nodes-1 {
+		panic(fmt.Sprintf("fastrand: connected Graph on %d nodes needs at least %d edges", nodes, nodes-1))
+	}
+
+	g := &graphBuilder{
+		cfg:  cfg,
+		adj:  make([][]int, nodes),
+		seen: make(map[[2]int]bool, edges),
+		rank: Perm(nodes),
+	}
+
+	if cfg.connected {
+		order := Perm(nodes)
+		for i := 1; i < nodes; i++ {
+			g.add(order[i], order[int(fastUint64N(uint64(i)))])
+		}
+	}
+
+	if remaining := edges - len(g.seen); remaining*2 <= maxEdges {
+		for len(g.seen) < edges {
+			u := int(fastUint64N(uint64(nodes)))
+			v := int(fastUint64N(uint64(nodes)))
+			if u != v {
+				g.add(u, v)
+			}
+		}
+	} else {
+		var candidates [][2]int
+		for u := 0; u < nodes; u++ {
+			for v := 0; v < nodes; v++ {
+				if u == v || (!g.ordered() && u > v) {
+					continue
+				}
+				if !g.seen[g.key(u, v)] {
+					candidates = append(candidates, [2]int{u, v})
+				}
+			}
+		}
+		Shuffle(len(candidates), func(i, j int) {
+			candidates[i], candidates[j] = candidates[j], candidates[i]
+		})
+		for _, c := range candidates[:edges-len(g.seen)] {
+			g.add(c[0], c[1])
+		}
+	}
+
+	for _, list := range g.adj {
+		sort.Ints(list)
+	}
+	return g.adj
+}
+
+type graphBuilder struct {
+	cfg  graphConfig
+	adj  [][]int
+	seen map[[2]int]bool
+	rank []int
+}
+
+// ordered reports whether (u, v) and (v, u) are distinct edges.
+func (g *graphBuilder) ordered() bool {
+	return g.cfg.directed && !g.cfg.dag
+}
+
+func (g *graphBuilder) key(u, v int) [2]int {
+	if !g.ordered() && u > v {
+		u, v = v, u
+	}
+	return [2]int{u, v}
+}
+
+// add inserts the edge between u and v unless it already exists. DAG edges
+// are oriented along a random topological order so no cycle can form.
+func (g *graphBuilder) add(u, v int) {
+	k := g.key(u, v)
+	if g.seen[k] {
+		return
+	}
+	g.seen[k] = true
+	switch {
+	case g.cfg.dag:
+		if g.rank[u] > g.rank[v] {
+			u, v = v, u
+		}
+		g.adj[u] = append(g.adj[u], v)
+	case g.cfg.directed:
+		g.adj[u] = append(g.adj[u], v)
+	default:
+		g.adj[u] = append(g.adj[u], v)
+		g.adj[v] = append(g.adj[v], u)
+	}
+}
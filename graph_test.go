@@ -0,0 +1,144 @@
+package fastrand_test
+
+import (
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func countEdges(adj [][]int) int {
+	n := 0
+	for _, list := range adj {
+		n += len(list)
+	}
+	return n
+}
+
+func weaklyConnected(adj [][]int) bool {
+	n := len(adj)
+	und := make([][]int, n)
+	for u, list := range adj {
+		for _, v := range list {
+			und[u] = append(und[u], v)
+			und[v] = append(und[v], u)
+		}
+	}
+	seen := make([]bool, n)
+	stack := []int{0}
+	seen[0] = true
+	count := 1
+	for len(stack) > 0 {
+		u := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, v := range und[u] {
+			if !seen[v] {
+				seen[v] = true
+				count++
+				stack = append(stack, v)
+			}
+		}
+	}
+	return count == n
+}
+
+func acyclic(adj [][]int) bool {
+	state := make([]int, len(adj))
+	var visit func(u int) bool
+	visit = func(u int) bool {
+		state[u] = 1
+		for _, v := range adj[u] {
+			if state[v] == 1 || (state[v] == 0 && !visit(v)) {
+				return false
+			}
+		}
+		state[u] = 2
+		return true
+	}
+	for u := range adj {
+		if state[u] == 0 && !visit(u) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestTree(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		tree := fastrand.Tree(50, 4)
+		require.Len(t, tree, 50)
+		assert.Equal(t, 49, countEdges(tree), "a tree has nodes-1 edges")
+		assert.True(t, weaklyConnected(tree))
+
+		depth := make([]int, 50)
+		parents := make([]int, 50)
+		for u, kids := range tree {
+			for _, v := range kids {
+				parents[v]++
+				depth[v] = depth[u] + 1
+				assert.LessOrEqual(t, depth[v], 4, "no node may exceed maxDepth")
+			}
+		}
+		assert.Zero(t, parents[0], "root has no parent")
+		for v := 1; v < 50; v++ {
+			assert.Equal(t, 1, parents[v], "every non-root node has exactly one parent")
+		}
+	}
+
+	assert.Equal(t, [][]int{nil}, fastrand.Tree(1, 0))
+	chain := fastrand.Tree(4, 1)
+	assert.ElementsMatch(t, []int{1, 2, 3}, chain[0], "maxDepth 1 produces a star")
+	assert.Panics(t, func() { fastrand.Tree(0, 3) })
+	assert.Panics(t, func() { fastrand.Tree(3, 0) })
+}
+
+func TestGraphUndirected(t *testing.T) {
+	g := fastrand.Graph(20, 30)
+	require.Len(t, g, 20)
+	assert.Equal(t, 60, countEdges(g), "undirected edges are listed from both ends")
+	for u, list := range g {
+		prev := -1
+		for _, v := range list {
+			assert.NotEqual(t, u, v, "no self loops")
+			assert.Greater(t, v, prev, "lists are sorted without duplicates")
+			assert.Contains(t, g[v], u, "undirected edges are symmetric")
+			prev = v
+		}
+	}
+}
+
+func TestGraphDirected(t *testing.T) {
+	g := fastrand.Graph(6, 30, fastrand.Directed())
+	assert.Equal(t, 30, countEdges(g), "a complete directed graph has n*(n-1) edges")
+}
+
+func TestGraphDAG(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		g := fastrand.Graph(15, 60, fastrand.DAG())
+		assert.Equal(t, 60, countEdges(g))
+		assert.True(t, acyclic(g))
+	}
+	complete := fastrand.Graph(8, 28, fastrand.DAG())
+	assert.True(t, acyclic(complete))
+}
+
+func TestGraphConnected(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		g := fastrand.Graph(30, 29, fastrand.Connected())
+		assert.True(t, weaklyConnected(g), "minimum edge count should yield a spanning tree")
+
+		d := fastrand.Graph(30, 40, fastrand.DAG(), fastrand.Connected())
+		assert.True(t, weaklyConnected(d))
+		assert.True(t, acyclic(d))
+		assert.Equal(t, 40, countEdges(d))
+	}
+}
+
+func TestGraphInvalid(t *testing.T) {
+	assert.Panics(t, func() { fastrand.Graph(0, 0) })
+	assert.Panics(t, func() { fastrand.Graph(4, 7) })
+	assert.Panics(t, func() { fastrand.Graph(4, 7, fastrand.DAG()) })
+	assert.Panics(t, func() { fastrand.Graph(10, 5, fastrand.Connected()) })
+	assert.NotPanics(t, func() { fastrand.Graph(4, 12, fastrand.Directed()) })
+}
@@ -0,0 +1,119 @@
+package fastrand
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+)
+
+// RFC 1123 limits on a hostname label and on a whole name without its
+// trailing dot.
+const (
+	maxLabelLength    = 63
+	maxHostnameLength = 253
+)
+
+// Hostname returns a random RFC 1123 host label of length characters,
+// clamped to [1, 63]: lowercase letters, digits and single hyphens,
+// starting with a letter and never ending in a hyphen.
+func Hostname(length int) string {
+	var out []byte
+	appendLabel(fastUint64, &out, min(max(length, 1), maxLabelLength))
+	return unsafeString(out)
+}
+
+// FQDN returns a random fully qualified domain name of length characters,
+// clamped to [4, 253], made of valid labels and a top-level domain of two
+// to six letters. The trailing root dot is left out.
+func FQDN(length int) string {
+	var out []byte
+	appendFQDN(fastUint64, &out, length)
+	return unsafeString(out)
+}
+
+// appendLabel appends a host label of n characters. It never holds two
+// hyphens in a row, so it cannot be mistaken for an IDNA "xn--" label.
+func appendLabel(next func() uint64, out *[]byte, n int) {
+	start := len(*out)
+	ensureCap(out, start+n)
+	*out = (*out)[:start+n]
+	b := (*out)[start:]
+	b[0] = CharsAlphabetLower[uint64N(next, uint64(len(CharsAlphabetLower)))]
+	for i := 1; i < n; i++ {
+		if i == n-1 || b[i-1] == '-' {
+			b[i] = hostLabelChars[uint64N(next, uint64(len(hostLabelChars)))]
+			continue
+		}
+		// One draw in len(hostLabelChars)+1 is a hyphen.
+		if c := uint64N(next, uint64(len(hostLabelChars)+1)); c < uint64(len(hostLabelChars)) {
+			b[i] = hostLabelChars[c]
+		} else {
+			b[i] = '-'
+		}
+	}
+}
+
+// appendFQDN appends a name of length characters, clamped to [4, 253],
+// split into labels of three to fifteen characters below an alphabetic
+// top-level domain.
+func appendFQDN(next func() uint64, out *[]byte, length int) {
+	length = min(max(length, 4), maxHostnameLength)
+	tld := 2 + int(uint64N(next, uint64(min(6, length-2)-1)))
+	for rest := length - tld - 1; rest > 0; {
+		n := min(rest, 3+int(uint64N(next, 13)))
+		if rest-n == 1 {
+			// Leave room for the dot and a label of at least one character.
+			n--
+		}
+		appendLabel(next, out, n)
+		*out = append(*out, '.')
+		rest -= n + 1
+	}
+	start := len(*out)
+	ensureCap(out, start+tld)
+	*out = (*out)[:start+tld]
+	fillStringInto(next, (*out)[start:], CharsAlphabetLower, len(CharsAlphabetLower))
+}
+
+// hostnameForm reports whether the argument of a HOSTNAME keyword asks for
+// a fully qualified name rather than a single label, and whether it is
+// label, fqdn or empty.
+func hostnameForm(arg []byte) (fqdn, ok bool) {
+	switch {
+	case len(arg) == 0, bytes.EqualFold(arg, []byte("label")):
+		return false, true
+	case bytes.EqualFold(arg, []byte("fqdn")):
+		return true, true
+	}
+	return false, false
+}
+
+// appendHostnameKeyword appends a label or, for HOSTNAME:fqdn, a fully
+// qualified name of length characters.
+func (e *FastEngine) appendHostnameKeyword(out *[]byte, length int, arg []byte) {
+	if fqdn, _ := hostnameForm(arg); fqdn {
+		appendFQDN(e.next, out, length)
+		return
+	}
+	appendLabel(e.next, out, min(max(length, 1), maxLabelLength))
+}
+
+// hostnameEntropy returns a lower bound on the bits of entropy of a
+// HOSTNAME label, counting every character after the first as one of the
+// 36 letters and digits. The entropy of an FQDN is not well defined.
+func hostnameEntropy(arg []byte, length int) (float64, error) {
+	if fqdn, _ := hostnameForm(arg); fqdn {
+		return 0, ErrUnknownEntropy
+	}
+	n := min(max(length, 1), maxLabelLength)
+	return math.Log2(float64(len(CharsAlphabetLower))) + float64(n-1)*math.Log2(float64(len(hostLabelChars))), nil
+}
+
+// checkHostnameArg returns why the argument of a HOSTNAME keyword is not a
+// known form, or "".
+func checkHostnameArg(arg []byte) string {
+	if _, ok := hostnameForm(arg); !ok {
+		return fmt.Sprintf("invalid hostname form %q: want label or fqdn", arg)
+	}
+	return ""
+}
@@ -0,0 +1,89 @@
+package fastrand_test
+
+import (
+	"math"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var hostLabel = regexp.MustCompile(`^[a-z]([a-z0-9-]*[a-z0-9])?$`)
+
+func requireFQDN(t *testing.T, name string, length int) {
+	t.Helper()
+	require.Len(t, name, length, name)
+	labels := strings.Split(name, ".")
+	require.GreaterOrEqual(t, len(labels), 2, name)
+	for _, label := range labels[:len(labels)-1] {
+		require.Regexp(t, hostLabel, label, name)
+		require.LessOrEqual(t, len(label), 63, name)
+		require.NotContains(t, label, "--", name)
+	}
+	require.Regexp(t, `^[a-z]{2,6}$`, labels[len(labels)-1], name)
+}
+
+func TestHostname(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 12, 63} {
+		for i := 0; i < 200; i++ {
+			label := fastrand.Hostname(n)
+			require.Len(t, label, n)
+			require.Regexp(t, hostLabel, label)
+			require.NotContains(t, label, "--")
+		}
+	}
+	assert.Len(t, fastrand.Hostname(0), 1)
+	assert.Len(t, fastrand.Hostname(500), 63)
+
+	hyphens := 0
+	for i := 0; i < 200; i++ {
+		hyphens += strings.Count(fastrand.Hostname(40), "-")
+	}
+	assert.Positive(t, hyphens, "labels use hyphens")
+}
+
+func TestFQDN(t *testing.T) {
+	for _, n := range []int{4, 5, 6, 20, 64, 253} {
+		for i := 0; i < 200; i++ {
+			requireFQDN(t, fastrand.FQDN(n), n)
+		}
+	}
+	assert.Len(t, fastrand.FQDN(1), 4)
+	assert.Len(t, fastrand.FQDN(1000), 253)
+}
+
+func TestHostnameKeyword(t *testing.T) {
+	for i := 0; i < 200; i++ {
+		label := fastrand.RandomizerString("{RAND;16;HOSTNAME}")
+		require.Len(t, label, 16)
+		require.Regexp(t, hostLabel, label)
+		require.Regexp(t, hostLabel, fastrand.RandomizerString("{RAND;8;HOSTNAME:label}"))
+		requireFQDN(t, fastrand.RandomizerString("{RAND;30;HOSTNAME:FQDN}"), 30)
+	}
+
+	bits, err := fastrand.TagEntropy("{RAND;10;HOSTNAME}")
+	require.NoError(t, err)
+	assert.InDelta(t, math.Log2(26)+9*math.Log2(36), bits, 1e-9)
+	_, err = fastrand.TagEntropy("{RAND;10;HOSTNAME:fqdn}")
+	assert.ErrorIs(t, err, fastrand.ErrUnknownEntropy)
+
+	engine := fastrand.NewEngine(fastrand.WithStrictParsing(true))
+	_, err = engine.RandomizerErr([]byte("{RAND;HOSTNAME:idn}"))
+	assert.ErrorContains(t, err, "invalid hostname form")
+	_, err = engine.RandomizerErr([]byte("{RAND;HOSTNAME}{RAND;HOSTNAME:label}{RAND;HOSTNAME:fqdn}"))
+	assert.NoError(t, err)
+}
+
+func TestAllocsHostname(t *testing.T) {
+	engine := fastrand.NewEngine()
+	payload := []byte("host={RAND;12;HOSTNAME}&fqdn={RAND;40;HOSTNAME:fqdn}")
+	dst := make([]byte, 0, 128)
+	dst = engine.RandomizerAppend(dst, payload)
+	allocs := testing.AllocsPerRun(100, func() {
+		dst = engine.RandomizerAppend(dst[:0], payload)
+	})
+	assert.Zero(t, allocs)
+}
@@ -0,0 +1,114 @@
+package fastrand
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+)
+
+var (
+	standardHTTPMethods = []string{"GET", "HEAD", "POST", "PUT", "DELETE", "CONNECT", "OPTIONS", "TRACE", "PATCH"}
+	webDAVMethods       = []string{"PROPFIND", "PROPPATCH", "MKCOL", "COPY", "MOVE", "LOCK", "UNLOCK", "SEARCH", "REPORT"}
+	allHTTPMethods      = append(append([]string{}, standardHTTPMethods...), webDAVMethods...)
+
+	// headerTokenChars are the tchar characters of RFC 9110, of which a
+	// header token is made.
+	headerTokenChars = CharsList("!#$%&'*+-.^_`|~0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz")
+	// headerQuotedChars are the printable qdtext characters of RFC 9110,
+	// which a quoted-string holds unescaped: everything but '"' and '\'.
+	headerQuotedChars = CharsList(" !#$%&'()*+,-./0123456789:;<=>?@ABCDEFGHIJKLMNOPQRSTUVWXYZ[]^_`abcdefghijklmnopqrstuvwxyz{|}~")
+)
+
+// httpMethodSet returns the methods the argument of an HTTPMETHOD keyword
+// names: standard, webdav or, by default, both.
+func httpMethodSet(arg []byte) ([]string, bool) {
+	switch {
+	case len(arg) == 0:
+		return allHTTPMethods, true
+	case bytes.EqualFold(arg, []byte("standard")):
+		return standardHTTPMethods, true
+	case bytes.EqualFold(arg, []byte("webdav")):
+		return webDAVMethods, true
+	}
+	return allHTTPMethods, false
+}
+
+func (e *FastEngine) appendHTTPMethod(out *[]byte, arg []byte) {
+	methods, _ := httpMethodSet(arg)
+	*out = append(*out, pickWord(e.next, methods)...)
+}
+
+// checkHTTPMethodArg returns why the argument of an HTTPMETHOD keyword is
+// not a method set, or "".
+func checkHTTPMethodArg(arg []byte) string {
+	if _, ok := httpMethodSet(arg); !ok {
+		return fmt.Sprintf("invalid method set %q: want standard or webdav", arg)
+	}
+	return ""
+}
+
+// headerValueForm returns the form the argument of a HEADERVAL keyword
+// names: "token", "quoted" or, by default, "" for either.
+func headerValueForm(arg []byte) (string, bool) {
+	switch {
+	case len(arg) == 0:
+		return "", true
+	case bytes.EqualFold(arg, []byte("token")):
+		return "token", true
+	case bytes.EqualFold(arg, []byte("quoted")):
+		return "quoted", true
+	}
+	return "", false
+}
+
+// appendHeaderValue appends a header field value of length characters in
+// the form the argument of a HEADERVAL keyword names: an RFC 9110 token,
+// or a quoted-string whose content characters are drawn evenly from the
+// qdtext characters and the escaped '\"' and '\\'.
+func (e *FastEngine) appendHeaderValue(out *[]byte, length int, arg []byte) {
+	form, _ := headerValueForm(arg)
+	if form == "" {
+		form = "token"
+		if e.next()&1 == 0 {
+			form = "quoted"
+		}
+	}
+	if form == "token" {
+		e.appendString(out, max(length, 1), headerTokenChars)
+		return
+	}
+	*out = append(*out, '"')
+	for i := 0; i < length; i++ {
+		r := int(uint64N(e.next, uint64(len(headerQuotedChars)+2)))
+		if r < len(headerQuotedChars) {
+			*out = append(*out, headerQuotedChars[r])
+		} else {
+			*out = append(*out, '\\', "\"\\"[r-len(headerQuotedChars)])
+		}
+	}
+	*out = append(*out, '"')
+}
+
+// headerValueEntropy returns the bits of entropy of a HEADERVAL keyword,
+// that of the weaker form when it may draw either.
+func headerValueEntropy(arg []byte, length int) float64 {
+	form, _ := headerValueForm(arg)
+	token := CharsetEntropy(headerTokenChars, max(length, 1))
+	quoted := float64(length) * math.Log2(float64(len(headerQuotedChars)+2))
+	switch form {
+	case "token":
+		return token
+	case "quoted":
+		return quoted
+	}
+	return math.Min(token, quoted) + 1
+}
+
+// checkHeaderValueArg returns why the argument of a HEADERVAL keyword is
+// not a form, or "".
+func checkHeaderValueArg(arg []byte) string {
+	if _, ok := headerValueForm(arg); !ok {
+		return fmt.Sprintf("invalid header value form %q: want token or quoted", arg)
+	}
+	return ""
+}
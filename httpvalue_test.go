@@ -0,0 +1,85 @@
+package fastrand_test
+
+import (
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	headerToken        = regexp.MustCompile("^[!#$%&'*+\\-.^_`|~0-9A-Za-z]+$")
+	headerQuotedString = regexp.MustCompile(`^"([\t !#-\[\]-~]|\\[\t -~])*"$`)
+	standardMethods    = []string{"GET", "HEAD", "POST", "PUT", "DELETE", "CONNECT", "OPTIONS", "TRACE", "PATCH"}
+	webDAVMethods      = []string{"PROPFIND", "PROPPATCH", "MKCOL", "COPY", "MOVE", "LOCK", "UNLOCK", "SEARCH", "REPORT"}
+)
+
+func TestHTTPMethodKeyword(t *testing.T) {
+	seen := map[string]bool{}
+	for i := 0; i < 500; i++ {
+		m := fastrand.RandomizerString("{RAND;HTTPMETHOD}")
+		require.Contains(t, append(standardMethods, webDAVMethods...), m)
+		seen[m] = true
+		require.Contains(t, standardMethods, fastrand.RandomizerString("{RAND;HTTPMETHOD:standard}"))
+		require.Contains(t, webDAVMethods, fastrand.RandomizerString("{RAND;HTTPMETHOD:WebDAV}"))
+	}
+	assert.Len(t, seen, 18)
+
+	bits, err := fastrand.TagEntropy("{RAND;HTTPMETHOD:webdav}")
+	require.NoError(t, err)
+	assert.InDelta(t, 3.17, bits, 0.01)
+}
+
+func TestHeaderValueKeyword(t *testing.T) {
+	quoted := 0
+	for i := 0; i < 500; i++ {
+		v := fastrand.RandomizerString("{RAND;12;HEADERVAL}")
+		require.True(t, headerToken.MatchString(v) || headerQuotedString.MatchString(v), v)
+		if v[0] == '"' {
+			quoted++
+		}
+
+		v = fastrand.RandomizerString("{RAND;8;HEADERVAL:token}")
+		require.Regexp(t, headerToken, v)
+		require.Len(t, v, 8)
+
+		v = fastrand.RandomizerString("{RAND;20;HEADERVAL:quoted}")
+		require.Regexp(t, headerQuotedString, v)
+
+		req, err := http.NewRequest(http.MethodGet, "http://example.com/", nil)
+		require.NoError(t, err)
+		req.Header.Set("X-Test", v)
+		assert.Equal(t, v, req.Header.Get("X-Test"))
+	}
+
+	assert.Greater(t, quoted, 100, "both forms are drawn")
+	assert.Less(t, quoted, 400)
+
+	bits, err := fastrand.TagEntropy("{RAND;4;HEADERVAL:quoted}")
+	require.NoError(t, err)
+	assert.InDelta(t, 4*6.5699, bits, 0.001)
+}
+
+func TestHTTPValueKeywordsStrict(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithStrictParsing(true))
+	_, err := engine.RandomizerErr([]byte("{RAND;HTTPMETHOD:custom}"))
+	assert.ErrorContains(t, err, "invalid method set")
+	_, err = engine.RandomizerErr([]byte("{RAND;HEADERVAL:comment}"))
+	assert.ErrorContains(t, err, "invalid header value form")
+	_, err = engine.RandomizerErr([]byte("{RAND;HTTPMETHOD}{RAND;HTTPMETHOD:STANDARD}{RAND;HEADERVAL}{RAND;HEADERVAL:Quoted}"))
+	assert.NoError(t, err)
+}
+
+func TestAllocsHTTPValueKeywords(t *testing.T) {
+	engine := fastrand.NewEngine()
+	payload := []byte("{RAND;HTTPMETHOD} / HTTP/1.1\r\nX-Custom: {RAND;16;HEADERVAL}\r\n")
+	dst := make([]byte, 0, 128)
+	dst = engine.RandomizerAppend(dst, payload)
+	allocs := testing.AllocsPerRun(100, func() {
+		dst = engine.RandomizerAppend(dst[:0], payload)
+	})
+	assert.Zero(t, allocs)
+}
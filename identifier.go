@@ -0,0 +1,57 @@
+package fastrand
+
+var identifierChars = append(append(CharsList{}, CharsAlphabetDigits...), '_')
+
+// sqlReserved lists short reserved words that a random identifier could
+// collide with and that parsers would reject unquoted.
+var sqlReserved = map[string]bool{
+	"AS": true, "AT": true, "BY": true, "DO": true, "GO": true, "IF": true, "IN": true, "IS": true,
+	"NO": true, "OF": true, "ON": true, "OR": true, "TO": true,
+	"ADD": true, "ALL": true, "AND": true, "ANY": true, "ASC": true, "END": true, "FOR": true,
+	"KEY": true, "NOT": true, "SET": true, "TOP": true, "USE": true,
+	"CASE": true, "CAST": true, "DESC": true, "DROP": true, "ELSE": true, "EXEC": true, "FROM": true,
+	"FULL": true, "INTO": true, "JOIN": true, "LEFT": true, "LIKE": true, "NULL": true, "OPEN": true,
+	"OVER": true, "PLAN": true, "READ": true, "THEN": true, "TRAN": true, "USER": true, "VIEW": true,
+	"WHEN": true, "WITH": true,
+	"ALTER": true, "BEGIN": true, "CHECK": true, "CROSS": true, "FETCH": true, "GRANT": true,
+	"GROUP": true, "INDEX": true, "INNER": true, "LIMIT": true, "MERGE": true, "ORDER": true,
+	"OUTER": true, "RIGHT": true, "TABLE": true, "UNION": true, "WHERE": true,
+}
+
+// Identifier returns a random identifier of the given length that starts
+// with a letter, contains only [A-Za-z0-9_] and is not a common SQL reserved
+// word, so it can be used as a table, column or variable name unquoted.
+func Identifier(length int) string {
+	if length <= 0 {
+		panic("fastrand: length must be positive")
+	}
+	var out []byte
+	appendIdentifier(fastUint64, &out, length)
+	return unsafeString(out)
+}
+
+func appendIdentifier(next func() uint64, out *[]byte, length int) {
+	if length <= 0 {
+		return
+	}
+	start := len(*out)
+	ensureCap(out, start+length)
+	*out = (*out)[:start+length]
+	b := (*out)[start:]
+	for {
+		fillStringInto(next, b[:1], CharsAlphabet, len(CharsAlphabet))
+		fillStringInto(next, b[1:], identifierChars, len(identifierChars))
+		if length > 5 {
+			return
+		}
+		var key [5]byte
+		n := upperASCIIInto(key[:], b)
+		if !sqlReserved[unsafeString(key[:n])] {
+			return
+		}
+	}
+}
+
+func (e *FastEngine) appendIdentifier(out *[]byte, length int) {
+	appendIdentifier(e.next, out, length)
+}
@@ -0,0 +1,43 @@
+package fastrand_test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+)
+
+var identifierRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+func TestIdentifier(t *testing.T) {
+	for _, length := range []int{1, 2, 3, 8, 64} {
+		for i := 0; i < 500; i++ {
+			id := fastrand.Identifier(length)
+			assert.Len(t, id, length)
+			assert.Regexp(t, identifierRegex, id)
+		}
+	}
+	assert.Panics(t, func() { fastrand.Identifier(0) })
+}
+
+func TestIdentifierAvoidsReservedWords(t *testing.T) {
+	reserved := map[string]bool{"AS": true, "BY": true, "IN": true, "IS": true, "ON": true, "OR": true, "TO": true, "IF": true}
+	for i := 0; i < 20000; i++ {
+		id := fastrand.Identifier(2)
+		assert.False(t, reserved[strings.ToUpper(id)], "identifier %q is a reserved word", id)
+	}
+}
+
+func TestRandomizerIdentKeyword(t *testing.T) {
+	for i := 0; i < 200; i++ {
+		result := fastrand.RandomizerString("CREATE TABLE {RAND;12;IDENT} ({RAND;6;ident} INT)")
+		fields := strings.Fields(result)
+		assert.Len(t, fields[2], 12)
+		assert.Regexp(t, identifierRegex, fields[2])
+		col := strings.TrimPrefix(fields[3], "(")
+		assert.Len(t, col, 6)
+		assert.Regexp(t, identifierRegex, col)
+	}
+}
@@ -0,0 +1,207 @@
+package fastrand
+
+import (
+	"bytes"
+	"strings"
+)
+
+// TagKind identifies what a TagSpec describes.
+type TagKind uint8
+
+const (
+	// TagRand is a {RAND;...} tag.
+	TagRand TagKind = iota
+	// TagRef is a {REF;name} reference to a variable.
+	TagRef
+	// TagRepeat is the opening tag of a {RAND-REPEAT} block.
+	TagRepeat
+)
+
+// TagSpec describes a tag found by Inspect.
+type TagSpec struct {
+	Offset int    // byte offset in the payload after input decoding
+	Text   string // the tag as written, braces included
+	Kind   TagKind
+	// Length is the length a TagRand tag asks for, or the repetition
+	// count of a TagRepeat block.
+	Length TagLength
+	// Keywords lists the keyword of a TagRand tag, or its valid keyword
+	// choices. It is empty when the tag names no keyword, as in {RAND;8},
+	// which yields CharsAll characters.
+	Keywords []TagKeyword
+	// Variable is the VAR= name a TagRand tag stores its value under, or
+	// the variable a TagRef reads.
+	Variable string
+	// Source is the FROM= variable a TagRand tag derives its value from,
+	// or "" for none.
+	Source string
+	// Probability is the percent chance that a TagRand tag or TagRepeat
+	// block is expanded at all: 100 unless a ?percent modifier is given.
+	Probability int
+	// Case is the case modifier of a TagRand tag: "upper", "lower",
+	// "title" or "" for none.
+	Case string
+}
+
+// TagLength describes the lengths a tag can draw.
+type TagLength struct {
+	Min, Max int // bounds of the length; equal for a fixed length
+	// Choices lists the candidates of a length choice list such as 8,16,32
+	// and Weights their weights, which are nil when choices are uniform.
+	Choices []int
+	Weights []int
+	// Distribution is "uniform", "zipf", "geometric" or "normal" for ranges
+	// and "normal" for ~mean±stddev lengths, whose Mean and StdDev are set
+	// and whose draws are clamped to [Min, Max].
+	Distribution string
+	Mean, StdDev int
+	// Default reports that the tag gives no valid length, so the engine
+	// default is used.
+	Default bool
+}
+
+// TagKeyword is one keyword of a tag.
+type TagKeyword struct {
+	// Name is the upper-cased keyword, such as HEX or CYCLE, or an inline
+	// character class as written, such as [a-f0-9].
+	Name   string
+	Arg    string // the argument after ':', as in CYCLE:env
+	Params string // the parameter list, as in HEX(upper=true)
+	Weight int    // choice weight, 0 when choices are uniform
+	// Excluded lists the characters a "!chars" exclusion removes, as in
+	// ABR!l1O0.
+	Excluded string
+	// Known is false for unknown or disabled keywords and invalid
+	// character classes, which expand to CharsAll characters.
+	Known bool
+}
+
+// Inspect parses payload with the default engine. See FastEngine.Inspect.
+func Inspect(payload []byte) ([]TagSpec, error) {
+	return defaultEngine.Load().Inspect(payload)
+}
+
+// Inspect returns the tags in payload in order, without expanding them, so
+// templates can be linted and their placeholders listed. Tags inside repeat
+// blocks are listed after the block's opening tag. Text that Randomizer
+// would copy literally is skipped. The error reports the malformed tags as
+// WithStrictParsing would, or is nil; the tags that do parse are returned
+// either way.
+func (e *FastEngine) Inspect(payload []byte) ([]TagSpec, error) {
+	payload, scratch := e.normalized(payload)
+	defer e.release(scratch)
+	err := e.checkTags(payload)
+
+	var specs []TagSpec
+	cursor, refIndex := 0, -1
+	for {
+		startIndex, isRef := nextTag(payload, cursor, true, &refIndex)
+		if startIndex == -1 {
+			break
+		}
+		endIndex := bytes.IndexByte(payload[startIndex:], endTag)
+		if endIndex == -1 {
+			break
+		}
+		endIndex += startIndex
+		cursor = endIndex + 1
+		tag := payload[startIndex:endIndex]
+		ts := TagSpec{Offset: startIndex, Text: string(payload[startIndex:cursor]), Probability: 100}
+
+		switch {
+		case isRef:
+			name, ok := refName(tag)
+			if !ok {
+				continue
+			}
+			ts.Kind, ts.Variable = TagRef, string(name)
+		case bytes.HasPrefix(tag, repeatOpen):
+			r, ok := parseRepeat(tag)
+			if !ok || findRepeatEnd(payload[cursor:]) == -1 {
+				continue
+			}
+			ts.Kind = TagRepeat
+			ts.Length = TagLength{Min: r.min, Max: r.max}
+			ts.Probability = r.chance.probability()
+		default:
+			spec, ok := e.parseTag(tag, nil, nil)
+			if !ok {
+				continue
+			}
+			ts.Kind = TagRand
+			ts.Length = e.tagLength(tag, &spec)
+			ts.Keywords = tagKeywords(&spec)
+			ts.Variable = string(spec.variable)
+			ts.Source = string(spec.source)
+			ts.Probability = spec.chance.probability()
+			ts.Case = spec.caseMode.String()
+		}
+		specs = append(specs, ts)
+	}
+	return specs, err
+}
+
+// tagLength describes the length part of spec, parsed from tag.
+func (e *FastEngine) tagLength(tag []byte, spec *tagSpec) TagLength {
+	l := TagLength{Min: spec.length, Max: spec.length}
+	switch spec.lengthKind {
+	case lengthChoice:
+		l.Min, l.Max = spec.lengths[0].length, spec.lengths[0].length
+		for _, c := range spec.lengths {
+			l.Min, l.Max = min(l.Min, c.length), max(l.Max, c.length)
+			l.Choices = append(l.Choices, c.length)
+			if spec.weight > 0 {
+				l.Weights = append(l.Weights, c.weight)
+			}
+		}
+	case lengthNormal:
+		l.Min, l.Max = e.minLength, e.maxLength
+		l.Distribution = "normal"
+		l.Mean, l.StdDev = spec.length, spec.lengthMax
+	case lengthRange:
+		l.Max = spec.lengthMax
+		l.Distribution = spec.dist.String()
+	default:
+		_, valid := e.parseLength(tagLengthPart(tag), nil)
+		l.Default = !valid
+	}
+	return l
+}
+
+// tagLengthPart returns the part of a {RAND;...} tag where a length may be
+// given, or nil for a bare {RAND}.
+func tagLengthPart(tag []byte) []byte {
+	_, body, _ := parseChance(bytes.TrimPrefix(tag[len(startTag):], startTagOpt))
+	if len(body) == 0 {
+		return nil
+	}
+	body, _ = splitVariable(body[1:])
+	lenPart, _, _ := bytes.Cut(body, []byte{sepTag})
+	return lenPart
+}
+
+func tagKeywords(spec *tagSpec) []TagKeyword {
+	var keywords []TagKeyword
+	for i := range spec.keywords {
+		k := &spec.keywords[i]
+		if k.text == nil {
+			continue
+		}
+		name, params := splitKeywordParams(k.text)
+		tk := TagKeyword{Params: string(params), Known: !k.fallback}
+		if base, excluded := splitExclusion(name); excluded != nil && k.charset != nil {
+			name, tk.Excluded = base, string(excluded)
+		}
+		if spec.keywordWeight > 0 {
+			tk.Weight = k.weight
+		}
+		if isCharClass(name) {
+			tk.Name = string(name)
+		} else {
+			name, arg := splitKeywordArg(name)
+			tk.Name, tk.Arg = strings.ToUpper(string(name)), string(arg)
+		}
+		keywords = append(keywords, tk)
+	}
+	return keywords
+}
@@ -0,0 +1,83 @@
+package fastrand_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspect(t *testing.T) {
+	payload := "id={RAND;8;HEX;VAR=id}&u={RAND;UUID:3,IPV4:1}&r={RAND;5-10:zipf;[a-f]}&{REF;id}" +
+		"{RAND-REPEAT?50;2-4}x={RAND;4,8;CYCLE:env}{/RAND-REPEAT}{RAND}"
+	specs, err := fastrand.Inspect([]byte(payload))
+	require.NoError(t, err)
+	require.Len(t, specs, 7)
+
+	assert.Equal(t, fastrand.TagSpec{
+		Offset:      3,
+		Text:        "{RAND;8;HEX;VAR=id}",
+		Kind:        fastrand.TagRand,
+		Length:      fastrand.TagLength{Min: 8, Max: 8},
+		Keywords:    []fastrand.TagKeyword{{Name: "HEX", Known: true}},
+		Variable:    "id",
+		Probability: 100,
+	}, specs[0])
+
+	assert.Equal(t, fastrand.TagLength{Min: 16, Max: 16, Default: true}, specs[1].Length)
+	assert.Equal(t, []fastrand.TagKeyword{
+		{Name: "UUID", Weight: 3, Known: true},
+		{Name: "IPV4", Weight: 1, Known: true},
+	}, specs[1].Keywords)
+
+	assert.Equal(t, fastrand.TagLength{Min: 5, Max: 10, Distribution: "zipf"}, specs[2].Length)
+	assert.Equal(t, []fastrand.TagKeyword{{Name: "[a-f]", Known: true}}, specs[2].Keywords)
+
+	assert.Equal(t, fastrand.TagRef, specs[3].Kind)
+	assert.Equal(t, "id", specs[3].Variable)
+	assert.Equal(t, "{REF;id}", payload[specs[3].Offset:specs[3].Offset+len(specs[3].Text)])
+
+	assert.Equal(t, fastrand.TagRepeat, specs[4].Kind)
+	assert.Equal(t, fastrand.TagLength{Min: 2, Max: 4}, specs[4].Length)
+	assert.Equal(t, 50, specs[4].Probability)
+
+	assert.Equal(t, fastrand.TagLength{Min: 4, Max: 8, Choices: []int{4, 8}}, specs[5].Length)
+	assert.Equal(t, []fastrand.TagKeyword{{Name: "CYCLE", Arg: "env", Known: true}}, specs[5].Keywords)
+
+	assert.Equal(t, "{RAND}", specs[6].Text)
+	assert.Empty(t, specs[6].Keywords)
+	assert.True(t, specs[6].Length.Default)
+}
+
+func TestInspectNormalAndParams(t *testing.T) {
+	specs, err := fastrand.Inspect([]byte("{RAND?25;~12±3;EMAIL(provider=corp.com)}"))
+	require.NoError(t, err)
+	require.Len(t, specs, 1)
+	assert.Equal(t, 25, specs[0].Probability)
+	assert.Equal(t, fastrand.TagLength{Min: 1, Max: 99, Distribution: "normal", Mean: 12, StdDev: 3}, specs[0].Length)
+	assert.Equal(t, []fastrand.TagKeyword{{Name: "EMAIL", Params: "provider=corp.com", Known: true}}, specs[0].Keywords)
+}
+
+func TestInspectMalformed(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithDisabledKeywords("EMAIL"))
+	specs, err := engine.Inspect([]byte("a={RAND;8;EMAIL}&b={RAND;4;DIGIT}&c={RAND;4;DIGIT"))
+
+	var tagErr *fastrand.TagError
+	require.True(t, errors.As(err, &tagErr))
+	assert.Equal(t, 2, tagErr.Offset)
+	assert.Contains(t, tagErr.Reason, "disabled")
+
+	require.Len(t, specs, 2, "tags that parse are still listed")
+	assert.Equal(t, []fastrand.TagKeyword{{Name: "EMAIL"}}, specs[0].Keywords, "disabled keywords are not known")
+	assert.Equal(t, "{RAND;4;DIGIT}", specs[1].Text)
+}
+
+func TestInspectEncodedInput(t *testing.T) {
+	specs, err := fastrand.Inspect([]byte("q=%7BRAND%3B6%3BDIGIT%7D"))
+	require.NoError(t, err)
+	require.Len(t, specs, 1)
+	assert.Equal(t, "{RAND;6;DIGIT}", specs[0].Text)
+	assert.Equal(t, 2, specs[0].Offset, "offsets refer to the decoded payload")
+}
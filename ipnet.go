@@ -0,0 +1,243 @@
+package fastrand
+
+import (
+	"fmt"
+	"math"
+	"net/netip"
+)
+
+// ipv4Prefix is an IPv4 network: a 32-bit address with its host bits
+// cleared and a prefix length.
+type ipv4Prefix struct {
+	addr uint32
+	bits uint8
+}
+
+func v4Prefix(a, b, c, d byte, bits uint8) ipv4Prefix {
+	return ipv4Prefix{addr: uint32(a)<<24 | uint32(b)<<16 | uint32(c)<<8 | uint32(d), bits: bits}
+}
+
+// size returns the number of addresses in p.
+func (p ipv4Prefix) size() uint64 {
+	return 1 << (32 - p.bits)
+}
+
+func (p ipv4Prefix) contains(addr uint32) bool {
+	return (addr^p.addr)>>(32-p.bits) == 0
+}
+
+// ipv4Reserved lists the special-purpose IPv4 blocks of RFC 6890 and its
+// updates. PUBLIC addresses avoid all of them.
+var ipv4Reserved = []ipv4Prefix{
+	v4Prefix(0, 0, 0, 0, 8),
+	v4Prefix(10, 0, 0, 0, 8),
+	v4Prefix(100, 64, 0, 0, 10),
+	v4Prefix(127, 0, 0, 0, 8),
+	v4Prefix(169, 254, 0, 0, 16),
+	v4Prefix(172, 16, 0, 0, 12),
+	v4Prefix(192, 0, 0, 0, 24),
+	v4Prefix(192, 0, 2, 0, 24),
+	v4Prefix(192, 88, 99, 0, 24),
+	v4Prefix(192, 168, 0, 0, 16),
+	v4Prefix(198, 18, 0, 0, 15),
+	v4Prefix(198, 51, 100, 0, 24),
+	v4Prefix(203, 0, 113, 0, 24),
+	v4Prefix(224, 0, 0, 0, 4),
+	v4Prefix(240, 0, 0, 0, 4),
+}
+
+// ipv4Classes maps the class names the IPV4 keyword accepts to their
+// networks. PUBLIC, every address outside ipv4Reserved, is handled apart.
+var ipv4Classes = map[string][]ipv4Prefix{
+	"PRIVATE":       {v4Prefix(10, 0, 0, 0, 8), v4Prefix(172, 16, 0, 0, 12), v4Prefix(192, 168, 0, 0, 16)},
+	"LOOPBACK":      {v4Prefix(127, 0, 0, 0, 8)},
+	"LINKLOCAL":     {v4Prefix(169, 254, 0, 0, 16)},
+	"CGNAT":         {v4Prefix(100, 64, 0, 0, 10)},
+	"MULTICAST":     {v4Prefix(224, 0, 0, 0, 4)},
+	"DOCUMENTATION": {v4Prefix(192, 0, 2, 0, 24), v4Prefix(198, 51, 100, 0, 24), v4Prefix(203, 0, 113, 0, 24)},
+}
+
+// ipv4Ranges resolves the argument of an IPV4 keyword, a class name such
+// as PRIVATE or a CIDR prefix such as 10.0.0.0/8, to the networks it draws
+// from, using single to hold a parsed prefix. public reports the PUBLIC
+// class, and ok is false for an argument that is neither.
+func ipv4Ranges(arg []byte, single *[1]ipv4Prefix) (ranges []ipv4Prefix, public, ok bool) {
+	var key [16]byte
+	if len(arg) <= len(key) {
+		n := upperASCIIInto(key[:], arg)
+		if unsafeString(key[:n]) == "PUBLIC" {
+			return nil, true, true
+		}
+		if ranges, ok := ipv4Classes[unsafeString(key[:n])]; ok {
+			return ranges, false, true
+		}
+	}
+	p, err := netip.ParsePrefix(unsafeString(arg))
+	if err != nil || !p.Addr().Is4() {
+		return nil, false, false
+	}
+	a := p.Masked().Addr().As4()
+	single[0] = v4Prefix(a[0], a[1], a[2], a[3], uint8(p.Bits()))
+	return single[:], false, true
+}
+
+// pickIPv4 draws an address uniformly from the union of ranges, or from
+// the public addresses.
+func pickIPv4(next func() uint64, ranges []ipv4Prefix, public bool) uint32 {
+	if public {
+	draw:
+		for {
+			addr := uint32(next())
+			for _, p := range ipv4Reserved {
+				if p.contains(addr) {
+					continue draw
+				}
+			}
+			return addr
+		}
+	}
+	var total uint64
+	for _, p := range ranges {
+		total += p.size()
+	}
+	r := uint64N(next, total)
+	for _, p := range ranges[:len(ranges)-1] {
+		if r < p.size() {
+			return p.addr | uint32(r)
+		}
+		r -= p.size()
+	}
+	return ranges[len(ranges)-1].addr | uint32(r)
+}
+
+// appendIPv4Prefixed appends an address drawn from the networks the
+// argument of an IPV4 keyword names. An invalid argument draws from all
+// addresses.
+func (e *FastEngine) appendIPv4Prefixed(out *[]byte, arg []byte) {
+	var single [1]ipv4Prefix
+	ranges, public, ok := ipv4Ranges(arg, &single)
+	if !ok {
+		e.appendIPv4(out)
+		return
+	}
+	appendIPv4Addr(out, pickIPv4(e.next, ranges, public))
+}
+
+// appendIPv4Addr appends addr in dotted-decimal form.
+func appendIPv4Addr(out *[]byte, addr uint32) {
+	appendUintByte(out, byte(addr>>24))
+	*out = append(*out, '.')
+	appendUintByte(out, byte(addr>>16))
+	*out = append(*out, '.')
+	appendUintByte(out, byte(addr>>8))
+	*out = append(*out, '.')
+	appendUintByte(out, byte(addr))
+}
+
+// ipv4Entropy returns the bits of entropy of an IPV4 keyword.
+func ipv4Entropy(arg []byte) float64 {
+	var single [1]ipv4Prefix
+	ranges, public, ok := ipv4Ranges(arg, &single)
+	if !ok {
+		return 32
+	}
+	var total uint64
+	if public {
+		total = 1 << 32
+		for _, p := range ipv4Reserved {
+			total -= p.size()
+		}
+	}
+	for _, p := range ranges {
+		total += p.size()
+	}
+	return math.Log2(float64(total))
+}
+
+// checkIPv4Arg returns why the argument of an IPV4 keyword is neither a
+// class nor a prefix, or "".
+func checkIPv4Arg(arg []byte) string {
+	var single [1]ipv4Prefix
+	if _, _, ok := ipv4Ranges(arg, &single); !ok {
+		return fmt.Sprintf("invalid network %q: want a CIDR prefix such as 10.0.0.0/8 or PRIVATE, PUBLIC, LOOPBACK, LINKLOCAL, CGNAT, MULTICAST or DOCUMENTATION", arg)
+	}
+	return ""
+}
+
+// ipv6Prefix is an IPv6 network: a 128-bit address with its host bits
+// cleared and a prefix length.
+type ipv6Prefix struct {
+	addr [16]byte
+	bits uint8
+}
+
+func v6Prefix(cidr string) ipv6Prefix {
+	p := netip.MustParsePrefix(cidr)
+	return ipv6Prefix{addr: p.Addr().As16(), bits: uint8(p.Bits())}
+}
+
+// ipv6Classes maps the class names the IPV6 keyword accepts to their
+// networks. LINKLOCAL and ULA use the parts of fe80::/10 and fc00::/7 that
+// are assigned in practice.
+var ipv6Classes = map[string]ipv6Prefix{
+	"GLOBAL":        v6Prefix("2000::/3"),
+	"LINKLOCAL":     v6Prefix("fe80::/64"),
+	"ULA":           v6Prefix("fd00::/8"),
+	"MULTICAST":     v6Prefix("ff00::/8"),
+	"DOCUMENTATION": v6Prefix("2001:db8::/32"),
+}
+
+// ipv6Range resolves the argument of an IPV6 keyword, a class name such as
+// ULA or a CIDR prefix such as 2001:db8::/32, to the network it draws from.
+// ok is false for an argument that is neither.
+func ipv6Range(arg []byte) (ipv6Prefix, bool) {
+	var key [16]byte
+	if len(arg) <= len(key) {
+		n := upperASCIIInto(key[:], arg)
+		if p, ok := ipv6Classes[unsafeString(key[:n])]; ok {
+			return p, true
+		}
+	}
+	p, err := netip.ParsePrefix(unsafeString(arg))
+	if err != nil || !p.Addr().Is6() {
+		return ipv6Prefix{}, false
+	}
+	return ipv6Prefix{addr: p.Masked().Addr().As16(), bits: uint8(p.Bits())}, true
+}
+
+// appendIPv6Prefixed appends an address drawn from the network the
+// argument of an IPV6 keyword names. An invalid argument draws from all
+// addresses.
+func (e *FastEngine) appendIPv6Prefixed(out *[]byte, arg []byte) {
+	p, ok := ipv6Range(arg)
+	if !ok {
+		e.appendIPv6(out)
+		return
+	}
+	var raw [16]byte
+	fillBytes(e.next, raw[:])
+	full := int(p.bits / 8)
+	copy(raw[:full], p.addr[:full])
+	if rem := p.bits % 8; rem != 0 {
+		mask := byte(0xff) << (8 - rem)
+		raw[full] = p.addr[full] | raw[full]&^mask
+	}
+	appendIPv6Addr(out, &raw)
+}
+
+// ipv6Entropy returns the bits of entropy of an IPV6 keyword.
+func ipv6Entropy(arg []byte) float64 {
+	if p, ok := ipv6Range(arg); ok {
+		return float64(128 - int(p.bits))
+	}
+	return 128
+}
+
+// checkIPv6Arg returns why the argument of an IPV6 keyword is neither a
+// class nor a prefix, or "".
+func checkIPv6Arg(arg []byte) string {
+	if _, ok := ipv6Range(arg); !ok {
+		return fmt.Sprintf("invalid network %q: want a CIDR prefix such as 2001:db8::/32 or GLOBAL, LINKLOCAL, ULA, MULTICAST or DOCUMENTATION", arg)
+	}
+	return ""
+}
@@ -0,0 +1,177 @@
+package fastrand_test
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parsePrefixes(tb testing.TB, cidrs ...string) []netip.Prefix {
+	tb.Helper()
+	prefixes := make([]netip.Prefix, len(cidrs))
+	for i, c := range cidrs {
+		prefixes[i] = netip.MustParsePrefix(c)
+	}
+	return prefixes
+}
+
+func inAny(addr netip.Addr, prefixes []netip.Prefix) bool {
+	for _, p := range prefixes {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestIPv4Prefix(t *testing.T) {
+	cases := map[string][]netip.Prefix{
+		"10.0.0.0/8":      parsePrefixes(t, "10.0.0.0/8"),
+		"192.168.1.77/24": parsePrefixes(t, "192.168.1.0/24"),
+		"203.0.113.9/32":  parsePrefixes(t, "203.0.113.9/32"),
+		"private":         parsePrefixes(t, "10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16"),
+		"LOOPBACK":        parsePrefixes(t, "127.0.0.0/8"),
+		"LINKLOCAL":       parsePrefixes(t, "169.254.0.0/16"),
+		"CGNAT":           parsePrefixes(t, "100.64.0.0/10"),
+		"MULTICAST":       parsePrefixes(t, "224.0.0.0/4"),
+		"DOCUMENTATION":   parsePrefixes(t, "192.0.2.0/24", "198.51.100.0/24", "203.0.113.0/24"),
+		"0.0.0.0/0":       parsePrefixes(t, "0.0.0.0/0"),
+	}
+	for arg, prefixes := range cases {
+		for i := 0; i < 200; i++ {
+			out := fastrand.RandomizerString("{RAND;IPV4:" + arg + "}")
+			addr, err := netip.ParseAddr(out)
+			require.NoError(t, err, out)
+			require.True(t, addr.Is4(), out)
+			require.True(t, inAny(addr, prefixes), "%s gave %s", arg, out)
+		}
+	}
+}
+
+func TestIPv4Public(t *testing.T) {
+	reserved := parsePrefixes(t, "0.0.0.0/8", "10.0.0.0/8", "100.64.0.0/10", "127.0.0.0/8", "169.254.0.0/16",
+		"172.16.0.0/12", "192.0.0.0/24", "192.0.2.0/24", "192.168.0.0/16", "198.18.0.0/15", "198.51.100.0/24",
+		"203.0.113.0/24", "224.0.0.0/4", "240.0.0.0/4")
+	for i := 0; i < 2000; i++ {
+		out := fastrand.RandomizerString("{RAND;IPV4:PUBLIC}")
+		addr, err := netip.ParseAddr(out)
+		require.NoError(t, err, out)
+		require.False(t, inAny(addr, reserved), out)
+		require.True(t, addr.IsGlobalUnicast(), out)
+	}
+}
+
+func TestIPv4PrefixSpread(t *testing.T) {
+	private := parsePrefixes(t, "10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16")
+	counts := make([]int, len(private))
+	for i := 0; i < 3000; i++ {
+		addr := netip.MustParseAddr(fastrand.RandomizerString("{RAND;IPV4:PRIVATE}"))
+		for j, p := range private {
+			if p.Contains(addr) {
+				counts[j]++
+			}
+		}
+	}
+	assert.Greater(t, counts[0], counts[1], "ranges are weighted by size")
+	assert.Greater(t, counts[1], counts[2])
+}
+
+func TestIPv4PrefixStrict(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithStrictParsing(true))
+	for _, arg := range []string{"10.0.0.0/33", "2001:db8::/32", "INTRANET", "10.0.0.0"} {
+		_, err := engine.RandomizerErr([]byte("{RAND;IPV4:" + arg + "}"))
+		assert.ErrorContains(t, err, "invalid network", arg)
+	}
+	_, err := engine.RandomizerErr([]byte("{RAND;IPV4:10.0.0.0/8}{RAND;IPV4:public}{RAND;IPV4}"))
+	assert.NoError(t, err)
+
+	addr, err := netip.ParseAddr(fastrand.RandomizerString("{RAND;IPV4:bogus}"))
+	require.NoError(t, err, "an invalid network draws from all addresses")
+	assert.True(t, addr.Is4())
+
+	bits, err := fastrand.TagEntropy("{RAND;IPV4:10.0.0.0/8}")
+	require.NoError(t, err)
+	assert.Equal(t, 24.0, bits)
+	bits, err = fastrand.TagEntropy("{RAND;IPV4:PUBLIC}")
+	require.NoError(t, err)
+	assert.InDelta(t, 31.8, bits, 0.05)
+}
+
+func TestAllocsIPv4Prefix(t *testing.T) {
+	engine := fastrand.NewEngine()
+	payload := []byte("src={RAND;IPV4:10.0.0.0/8}&dst={RAND;IPV4:PUBLIC}")
+	dst := make([]byte, 0, 64)
+	dst = engine.RandomizerAppend(dst, payload)
+	allocs := testing.AllocsPerRun(100, func() {
+		dst = engine.RandomizerAppend(dst[:0], payload)
+	})
+	assert.Zero(t, allocs)
+}
+
+func TestIPv6Prefix(t *testing.T) {
+	cases := map[string]netip.Prefix{
+		"2001:db8::/32":         netip.MustParsePrefix("2001:db8::/32"),
+		"2001:db8:abcd:12::/61": netip.MustParsePrefix("2001:db8:abcd:10::/61"),
+		"fd12:3456::1/128":      netip.MustParsePrefix("fd12:3456::1/128"),
+		"global":                netip.MustParsePrefix("2000::/3"),
+		"LINKLOCAL":             netip.MustParsePrefix("fe80::/64"),
+		"ULA":                   netip.MustParsePrefix("fd00::/8"),
+		"MULTICAST":             netip.MustParsePrefix("ff00::/8"),
+		"DOCUMENTATION":         netip.MustParsePrefix("2001:db8::/32"),
+	}
+	for arg, prefix := range cases {
+		for i := 0; i < 200; i++ {
+			out := fastrand.RandomizerString("{RAND;IPV6:" + arg + "}")
+			addr, err := netip.ParseAddr(out)
+			require.NoError(t, err, out)
+			require.True(t, addr.Is6(), out)
+			require.True(t, prefix.Contains(addr), "%s gave %s", arg, out)
+		}
+	}
+	for i := 0; i < 200; i++ {
+		addr := netip.MustParseAddr(fastrand.RandomizerString("{RAND;IPV6:LINKLOCAL}"))
+		assert.True(t, addr.IsLinkLocalUnicast())
+		addr = netip.MustParseAddr(fastrand.RandomizerString("{RAND;IPV6:ULA}"))
+		assert.True(t, addr.IsPrivate())
+		addr = netip.MustParseAddr(fastrand.RandomizerString("{RAND;IPV6:GLOBAL}"))
+		assert.True(t, addr.IsGlobalUnicast())
+	}
+}
+
+func TestIPv6PrefixStrict(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithStrictParsing(true))
+	for _, arg := range []string{"2001:db8::/129", "10.0.0.0/8", "SITELOCAL", "2001:db8::"} {
+		_, err := engine.RandomizerErr([]byte("{RAND;IPV6:" + arg + "}"))
+		assert.ErrorContains(t, err, "invalid network", arg)
+	}
+	_, err := engine.RandomizerErr([]byte("{RAND;IPV6:2001:db8::/32}{RAND;IPV6:ula}{RAND;IPV6}"))
+	assert.NoError(t, err)
+
+	addr, err := netip.ParseAddr(fastrand.RandomizerString("{RAND;IPV6:bogus}"))
+	require.NoError(t, err, "an invalid network draws from all addresses")
+	assert.True(t, addr.Is6())
+
+	bits, err := fastrand.TagEntropy("{RAND;IPV6:2001:db8::/32}")
+	require.NoError(t, err)
+	assert.Equal(t, 96.0, bits)
+	bits, err = fastrand.TagEntropy("{RAND;IPV6:LINKLOCAL}")
+	require.NoError(t, err)
+	assert.Equal(t, 64.0, bits)
+	bits, err = fastrand.TagEntropy("{RAND;IPV6}")
+	require.NoError(t, err)
+	assert.Equal(t, 128.0, bits)
+}
+
+func TestAllocsIPv6Prefix(t *testing.T) {
+	engine := fastrand.NewEngine()
+	payload := []byte("src={RAND;IPV6:2001:db8::/32}&dst={RAND;IPV6:ULA}")
+	dst := make([]byte, 0, 128)
+	dst = engine.RandomizerAppend(dst, payload)
+	allocs := testing.AllocsPerRun(100, func() {
+		dst = engine.RandomizerAppend(dst[:0], payload)
+	})
+	assert.Zero(t, allocs)
+}
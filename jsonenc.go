@@ -0,0 +1,55 @@
+package fastrand
+
+import "unicode/utf8"
+
+// jsonEscapes maps the ASCII bytes that need escaping in a JSON string to
+// their short escape, or 'u' for a \u00XX escape.
+var jsonEscapes = [utf8.RuneSelf]byte{
+	'"': '"', '\\': '\\', '\b': 'b', '\f': 'f', '\n': 'n', '\r': 'r', '\t': 't',
+	0x00: 'u', 0x01: 'u', 0x02: 'u', 0x03: 'u', 0x04: 'u', 0x05: 'u', 0x06: 'u', 0x07: 'u',
+	0x0b: 'u', 0x0e: 'u', 0x0f: 'u', 0x10: 'u', 0x11: 'u', 0x12: 'u', 0x13: 'u', 0x14: 'u',
+	0x15: 'u', 0x16: 'u', 0x17: 'u', 0x18: 'u', 0x19: 'u', 0x1a: 'u', 0x1b: 'u', 0x1c: 'u',
+	0x1d: 'u', 0x1e: 'u', 0x1f: 'u',
+}
+
+// jsonSafe reports whether b can be placed in a JSON string unchanged.
+func jsonSafe(b []byte) bool {
+	ascii := true
+	for _, c := range b {
+		if c >= utf8.RuneSelf {
+			ascii = false
+		} else if jsonEscapes[c] != 0 {
+			return false
+		}
+	}
+	return ascii || utf8.Valid(b)
+}
+
+// appendJSONEscaped appends src escaped for use inside a JSON string
+// literal. Quotes, backslashes and control characters are escaped, valid
+// UTF-8 is kept, and bytes that are not valid UTF-8 become \u00XX so the
+// document stays well formed.
+func appendJSONEscaped(out *[]byte, src []byte) {
+	for i := 0; i < len(src); {
+		c := src[i]
+		if c < utf8.RuneSelf {
+			switch esc := jsonEscapes[c]; esc {
+			case 0:
+				*out = append(*out, c)
+			case 'u':
+				*out = append(*out, '\\', 'u', '0', '0', strconvDigits[c>>4], strconvDigits[c&0xf])
+			default:
+				*out = append(*out, '\\', esc)
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRune(src[i:])
+		if r == utf8.RuneError && size == 1 {
+			*out = append(*out, '\\', 'u', '0', '0', strconvDigits[c>>4], strconvDigits[c&0xf])
+		} else {
+			*out = append(*out, src[i:i+size]...)
+		}
+		i += size
+	}
+}
@@ -0,0 +1,55 @@
+package fastrand_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputEncodingJSON(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithOutputEncoding(fastrand.RandomizerEncodingJSON))
+	payload := []byte(`{"raw":"{RAND;64;BYTES}","null":"{RAND;32;NULL}","all":"{RAND;64;ABR,XML}","id":"{RAND;UUID}"}`)
+	for i := 0; i < 200; i++ {
+		out := engine.Randomizer(payload)
+		var doc map[string]string
+		require.NoError(t, json.Unmarshal(out, &doc), "%q", out)
+		assert.Len(t, doc["id"], 36)
+	}
+}
+
+func TestOutputEncodingJSONEscapes(t *testing.T) {
+	engine := fastrand.NewEngine(
+		fastrand.WithOutputEncoding(fastrand.RandomizerEncodingJSON),
+		fastrand.WithCustomKeyword("Q", func(int) []byte { return []byte("a\"b\\c\n\x01é\xff") }),
+	)
+	assert.Equal(t, `"x":"a\"b\\c\n\u0001é\u00ff"`, engine.RandomizerString(`"x":"{RAND;Q}"`))
+}
+
+func TestOutputEncodingJSONVariables(t *testing.T) {
+	engine := fastrand.NewEngine(
+		fastrand.WithOutputEncoding(fastrand.RandomizerEncodingJSON),
+		fastrand.WithCustomKeyword("Q", func(int) []byte { return []byte(`"`) }),
+	)
+	assert.Equal(t, `\" \"`, engine.RandomizerString("{RAND;Q;VAR=q} {REF;q}"))
+}
+
+func TestOutputEncodingJSONNested(t *testing.T) {
+	engine := fastrand.NewEngine(
+		fastrand.WithOutputEncoding(fastrand.RandomizerEncodingJSON),
+		fastrand.WithMaxExpansionDepth(1),
+		fastrand.WithCustomKeyword("OUTER", func(int) []byte { return []byte(`"{RAND;INNER}"`) }),
+		fastrand.WithCustomKeyword("INNER", func(int) []byte { return []byte(`\`) }),
+	)
+	assert.Equal(t, `\"\\\"`, engine.RandomizerString("{RAND;OUTER}"))
+}
+
+func TestOutputEncodingJSONWithURL(t *testing.T) {
+	engine := fastrand.NewEngine(
+		fastrand.WithOutputEncoding(fastrand.RandomizerEncodingJSON|fastrand.RandomizerEncodingURL),
+		fastrand.WithCustomKeyword("Q", func(int) []byte { return []byte(`"`) }),
+	)
+	assert.Equal(t, "a+b%3D\\\"", engine.RandomizerString("a b={RAND;Q}"))
+}
@@ -0,0 +1,92 @@
+package fastrand
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"time"
+)
+
+// jwtLifetime is how long after issue the tokens JWT generates expire.
+const jwtLifetime = time.Hour
+
+// JWT returns a structurally valid JSON Web Token with random sub and jti
+// claims, issued now and expiring in an hour. With a key the token is
+// HS256-signed, so a server sharing the key accepts it; without one the
+// signature is 32 random bytes that only pass superficial parsing.
+func JWT(key []byte) string {
+	var out []byte
+	appendJWT(fastUint64, &out, time.Now(), key, false)
+	return unsafeString(out)
+}
+
+// appendJWT appends a token with random claims. none makes an unsigned
+// token with alg "none" and an empty signature.
+func appendJWT(next func() uint64, out *[]byte, now time.Time, key []byte, none bool) {
+	start := len(*out)
+	header := `{"alg":"HS256","typ":"JWT"}`
+	if none {
+		header = `{"alg":"none","typ":"JWT"}`
+	}
+	*out = base64.RawURLEncoding.AppendEncode(*out, s2b(header))
+	*out = append(*out, '.')
+
+	var buf [128]byte
+	claims := append(buf[:0], `{"sub":"`...)
+	claims = strconvAppendUint(claims, 1_000_000+uint64N(next, 9_000_000), 10)
+	claims = append(claims, `","iat":`...)
+	claims = strconvAppendUint(claims, uint64(now.Unix()), 10)
+	claims = append(claims, `,"exp":`...)
+	claims = strconvAppendUint(claims, uint64(now.Add(jwtLifetime).Unix()), 10)
+	claims = append(claims, `,"jti":"`...)
+	var jti [8]byte
+	fillBytes(next, jti[:])
+	for _, b := range jti {
+		claims = append(claims, strconvDigits[b>>4], strconvDigits[b&0x0f])
+	}
+	claims = append(claims, `"}`...)
+	*out = base64.RawURLEncoding.AppendEncode(*out, claims)
+	*out = append(*out, '.')
+
+	if none {
+		return
+	}
+	var sig [sha256.Size]byte
+	if len(key) > 0 {
+		sig = signHS256(key, (*out)[start:len(*out)-1])
+	} else {
+		fillBytes(next, sig[:])
+	}
+	*out = base64.RawURLEncoding.AppendEncode(*out, sig[:])
+}
+
+// signHS256 returns the HMAC-SHA256 of msg under key.
+func signHS256(key, msg []byte) [sha256.Size]byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(msg)
+	return [sha256.Size]byte(mac.Sum(nil))
+}
+
+// jwtParams returns the signing key and whether the token is unsigned from
+// the key and alg parameters of a JWT keyword, or why alg is invalid.
+func jwtParams(params []byte) (key []byte, none bool, reason string) {
+	key, _ = keywordParam(params, "key")
+	if alg, ok := keywordParam(params, "alg"); ok {
+		switch {
+		case bytes.EqualFold(alg, []byte("none")):
+			none = true
+		case !bytes.EqualFold(alg, []byte("HS256")):
+			return nil, false, fmt.Sprintf("invalid alg %q: want HS256 or none", alg)
+		}
+	}
+	return key, none, ""
+}
+
+// appendJWT appends a token shaped by the key and alg parameters of a JWT
+// keyword, issued at the engine clock's current time.
+func (e *FastEngine) appendJWT(out *[]byte, kw *keywordSpec) {
+	key, none, _ := jwtParams(kw.params)
+	appendJWT(e.next, out, e.now(), key, none)
+}
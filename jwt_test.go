@@ -0,0 +1,107 @@
+package fastrand_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// parseJWT splits a token and decodes its header and claims.
+func parseJWT(tb testing.TB, token string) (header, claims map[string]any, sig []byte) {
+	tb.Helper()
+	parts := strings.Split(token, ".")
+	require.Len(tb, parts, 3, token)
+	for i, dst := range []*map[string]any{&header, &claims} {
+		raw, err := base64.RawURLEncoding.DecodeString(parts[i])
+		require.NoError(tb, err, token)
+		require.NoError(tb, json.Unmarshal(raw, dst), string(raw))
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	require.NoError(tb, err, token)
+	return header, claims, sig
+}
+
+func TestJWT(t *testing.T) {
+	before := time.Now().Unix()
+	token := fastrand.JWT(nil)
+	header, claims, sig := parseJWT(t, token)
+	assert.Equal(t, map[string]any{"alg": "HS256", "typ": "JWT"}, header)
+	assert.Regexp(t, `^[0-9]{7}$`, claims["sub"])
+	assert.Regexp(t, `^[0-9a-f]{16}$`, claims["jti"])
+	iat := int64(claims["iat"].(float64))
+	assert.GreaterOrEqual(t, iat, before)
+	assert.Equal(t, float64(iat+3600), claims["exp"])
+	assert.Len(t, sig, 32)
+	assert.NotEqual(t, token, fastrand.JWT(nil))
+
+	key := []byte("s3cret")
+	token = fastrand.JWT(key)
+	_, _, sig = parseJWT(t, token)
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(token[:strings.LastIndexByte(token, '.')]))
+	assert.Equal(t, mac.Sum(nil), sig, "the signature verifies with the key")
+}
+
+func TestJWTKeyword(t *testing.T) {
+	token := fastrand.RandomizerString("{RAND;JWT(key=s3cret)}")
+	_, _, sig := parseJWT(t, token)
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write([]byte(token[:strings.LastIndexByte(token, '.')]))
+	assert.Equal(t, mac.Sum(nil), sig)
+
+	token = fastrand.RandomizerString("{RAND;JWT(alg=none)}")
+	header, _, sig := parseJWT(t, token)
+	assert.Equal(t, "none", header["alg"])
+	assert.Empty(t, sig)
+	assert.True(t, strings.HasSuffix(token, "."))
+
+	out := fastrand.RandomizerString("Authorization: Bearer {RAND;JWT}")
+	parseJWT(t, strings.TrimPrefix(out, "Authorization: Bearer "))
+}
+
+func TestJWTKeywordClock(t *testing.T) {
+	at := time.Date(2030, time.June, 15, 12, 0, 0, 0, time.UTC)
+	engine := fastrand.NewEngine(fastrand.WithClock(func() time.Time { return at }))
+	_, claims, _ := parseJWT(t, engine.RandomizerString("{RAND;JWT}"))
+	assert.Equal(t, float64(at.Unix()), claims["iat"])
+	assert.Equal(t, float64(at.Unix()+3600), claims["exp"])
+
+	a := fastrand.NewEngine(fastrand.WithSeed(5))
+	b := fastrand.NewEngine(fastrand.WithSeed(5))
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, a.RandomizerString("{RAND;JWT(key=s3cret)}"), b.RandomizerString("{RAND;JWT(key=s3cret)}"),
+			"seeded engines issue identical tokens")
+	}
+}
+
+func TestJWTKeywordStrict(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithStrictParsing(true))
+	_, err := engine.RandomizerErr([]byte("{RAND;JWT(alg=RS256)}"))
+	assert.ErrorContains(t, err, "invalid alg")
+	_, err = engine.RandomizerErr([]byte("{RAND;JWT(kid=1)}"))
+	assert.ErrorContains(t, err, "unknown parameter")
+	_, err = engine.RandomizerErr([]byte("{RAND;JWT}{RAND;JWT(alg=hs256,key=k)}{RAND;JWT(alg=None)}"))
+	assert.NoError(t, err)
+
+	_, err = fastrand.TagEntropy("{RAND;JWT}")
+	assert.ErrorIs(t, err, fastrand.ErrUnknownEntropy)
+}
+
+func TestAllocsJWTKeyword(t *testing.T) {
+	engine := fastrand.NewEngine()
+	payload := []byte("Authorization: Bearer {RAND;JWT}")
+	dst := make([]byte, 0, 512)
+	dst = engine.RandomizerAppend(dst, payload)
+	allocs := testing.AllocsPerRun(100, func() {
+		dst = engine.RandomizerAppend(dst[:0], payload)
+	})
+	assert.Zero(t, allocs)
+}
@@ -0,0 +1,157 @@
+package fastrand
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CustomKeywordGeneratorV2 generates the value of a custom keyword from
+// the tag that names it, so keywords can take arguments and parameters and
+// draw from the engine's charsets and random source. See KeywordContext.
+type CustomKeywordGeneratorV2 func(ctx KeywordContext) []byte
+
+// KeywordContext describes one expansion of a custom keyword. It is only
+// valid during the generator call.
+type KeywordContext struct {
+	// Length is the length the tag asks for, after any len= parameter.
+	Length int
+	// Arg is the text after ':' in the keyword, as "prod" in {RAND;ENV:prod},
+	// or nil.
+	Arg []byte
+	// Params is the raw parameter list, as "min=1,max=9" in
+	// {RAND;NUM(min=1,max=9)}, or nil. Param looks up single parameters.
+	Params []byte
+
+	engine *FastEngine
+}
+
+// Param returns the value of the named parameter, with surrounding spaces
+// removed. Names are case-insensitive.
+func (c KeywordContext) Param(name string) (string, bool) {
+	v, ok := keywordParam(c.Params, name)
+	return string(v), ok
+}
+
+// Charset returns the engine's charset named name: a charset registered
+// with RegisterCharset, or the charset of a built-in charset keyword (ABL,
+// ABU, ABR, DIGIT or NULL) with any custom charset applied.
+func (c KeywordContext) Charset(name string) (CharsList, bool) {
+	upper := strings.ToUpper(name)
+	if cs, ok := c.engine.registeredCharset(upper); ok {
+		return cs, true
+	}
+	return c.engine.builtinCharset(upper)
+}
+
+// Uint64 returns a random number from the engine's random source, which
+// WithSeed makes reproducible.
+func (c KeywordContext) Uint64() uint64 {
+	return c.engine.next()
+}
+
+// Uint64N returns a random number in [0, n) from the engine's random
+// source. It panics if n is 0.
+func (c KeywordContext) Uint64N(n uint64) uint64 {
+	if n == 0 {
+		panic("fastrand: invalid argument to Uint64N")
+	}
+	return uint64N(c.engine.next, n)
+}
+
+// AppendString appends length characters drawn from charset with the
+// engine's random source to dst and returns the extended buffer.
+func (c KeywordContext) AppendString(dst []byte, length int, charset CharsList) []byte {
+	c.engine.appendString(&dst, length, charset)
+	return dst
+}
+
+// CustomKeywordWriter appends the value of a custom keyword of the given
+// length to dst, the engine's output buffer, and returns the extended
+// slice, like strconv.AppendInt, so frequently used keywords need not
+// allocate a slice per expansion. It must not modify dst[:len(dst)].
+type CustomKeywordWriter func(dst []byte, length int) []byte
+
+// customKeyword is the form every kind of custom keyword generator is
+// stored in: it appends one value to dst.
+type customKeyword func(dst []byte, ctx KeywordContext) []byte
+
+// custom adapts gen to a customKeyword. A nil gen stays nil.
+func (gen CustomKeywordGenerator) custom() customKeyword {
+	if gen == nil {
+		return nil
+	}
+	return func(dst []byte, ctx KeywordContext) []byte { return append(dst, gen(ctx.Length)...) }
+}
+
+// custom adapts gen to a customKeyword. A nil gen stays nil.
+func (gen CustomKeywordGeneratorV2) custom() customKeyword {
+	if gen == nil {
+		return nil
+	}
+	return func(dst []byte, ctx KeywordContext) []byte { return append(dst, gen(ctx)...) }
+}
+
+// custom adapts w to a customKeyword. A nil w stays nil.
+func (w CustomKeywordWriter) custom() customKeyword {
+	if w == nil {
+		return nil
+	}
+	return func(dst []byte, ctx KeywordContext) []byte { return w(dst, ctx.Length) }
+}
+
+// WithCustomKeywordV2 is like WithCustomKeyword for a generator that
+// receives the whole KeywordContext.
+func WithCustomKeywordV2(keyword string, generator CustomKeywordGeneratorV2) Option {
+	return func(e *FastEngine) {
+		e.keywords.Load().custom[strings.ToUpper(keyword)] = generator.custom()
+	}
+}
+
+// WithCustomKeywordWriter is like WithCustomKeyword for a generator that
+// appends its value to the engine's output buffer.
+func WithCustomKeywordWriter(keyword string, writer CustomKeywordWriter) Option {
+	return func(e *FastEngine) {
+		e.keywords.Load().custom[strings.ToUpper(keyword)] = writer.custom()
+	}
+}
+
+// RegisterKeywordV2 registers a custom keyword with the default engine.
+// See FastEngine.RegisterKeywordV2.
+func RegisterKeywordV2(name string, gen CustomKeywordGeneratorV2) error {
+	return defaultEngine.Load().RegisterKeywordV2(name, gen)
+}
+
+// RegisterKeywordWriter registers a buffer-writing custom keyword with the
+// default engine. See FastEngine.RegisterKeywordWriter.
+func RegisterKeywordWriter(name string, w CustomKeywordWriter) error {
+	return defaultEngine.Load().RegisterKeywordWriter(name, w)
+}
+
+// RegisterKeywordV2 is like RegisterKeyword for a generator that receives
+// the whole KeywordContext.
+func (e *FastEngine) RegisterKeywordV2(name string, gen CustomKeywordGeneratorV2) error {
+	return e.registerKeyword(name, gen.custom())
+}
+
+// RegisterKeywordWriter is like RegisterKeyword for a generator that
+// appends its value to the engine's output buffer.
+func (e *FastEngine) RegisterKeywordWriter(name string, w CustomKeywordWriter) error {
+	return e.registerKeyword(name, w.custom())
+}
+
+func (e *FastEngine) registerKeyword(name string, gen customKeyword) error {
+	upper := strings.ToUpper(name)
+	switch {
+	case !validCharsetName(upper):
+		return fmt.Errorf("fastrand: invalid keyword name %q", name)
+	case gen == nil:
+		return fmt.Errorf("fastrand: keyword %q has a nil generator", name)
+	}
+	return e.updateKeywords(func(t *keywordTable) error {
+		if _, isCharset := e.registeredCharset(upper); isCharset {
+			return fmt.Errorf("fastrand: keyword name %q is a registered charset", name)
+		}
+		t.custom[upper] = gen
+		return nil
+	})
+}
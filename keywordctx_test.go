@@ -0,0 +1,127 @@
+package fastrand_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// numGen is a parameterized keyword: NUM(min=a,max=b) yields a number in
+// [a, b].
+func numGen(ctx fastrand.KeywordContext) []byte {
+	lo, hi := 0, 9
+	if v, ok := ctx.Param("min"); ok {
+		lo, _ = strconv.Atoi(v)
+	}
+	if v, ok := ctx.Param("max"); ok {
+		hi, _ = strconv.Atoi(v)
+	}
+	return strconv.AppendInt(nil, int64(lo)+int64(ctx.Uint64N(uint64(hi-lo+1))), 10)
+}
+
+func TestCustomKeywordV2(t *testing.T) {
+	engine := fastrand.NewEngine(
+		fastrand.WithCustomKeywordV2("NUM", numGen),
+		fastrand.WithCustomKeywordV2("ECHO", func(ctx fastrand.KeywordContext) []byte {
+			return []byte(strconv.Itoa(ctx.Length) + "|" + string(ctx.Arg) + "|" + string(ctx.Params))
+		}),
+	)
+	for i := 0; i < 100; i++ {
+		n, err := strconv.Atoi(engine.RandomizerString("{RAND;NUM(min=10,max=12)}"))
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, n, 10)
+		assert.LessOrEqual(t, n, 12)
+	}
+	assert.Equal(t, "5|prod|", engine.RandomizerString("{RAND;5;ECHO:prod}"))
+	assert.Equal(t, "7||len=7, x=1", engine.RandomizerString("{RAND;ECHO(len=7, x=1)}"))
+	assert.Equal(t, "16||", engine.RandomizerString("{RAND;ECHO}"))
+
+	strict := fastrand.NewEngine(fastrand.WithStrictParsing(true), fastrand.WithCustomKeywordV2("NUM", numGen))
+	_, err := strict.RandomizerErr([]byte("{RAND;NUM(min=1,max=3)}"))
+	assert.NoError(t, err, "custom keywords accept any parameter")
+}
+
+func TestKeywordContextCharsetAndSource(t *testing.T) {
+	gen := func(ctx fastrand.KeywordContext) []byte {
+		cs, ok := ctx.Charset(string(ctx.Arg))
+		if !ok {
+			return []byte("none")
+		}
+		return ctx.AppendString(nil, ctx.Length, cs)
+	}
+	engine := fastrand.NewEngine(
+		fastrand.WithCustomKeywordV2("FROMCS", gen),
+		fastrand.WithCustomCharset("DIGIT", []byte("7")),
+		fastrand.WithSeed(42),
+	)
+	require.NoError(t, engine.RegisterCharset("VOWEL", fastrand.CharsList("aeiou")))
+
+	assert.Equal(t, "7777", engine.RandomizerString("{RAND;4;FROMCS:digit}"), "custom charsets apply")
+	assert.Regexp(t, `^[aeiou]{6}$`, engine.RandomizerString("{RAND;6;FROMCS:VOWEL}"))
+	assert.Regexp(t, `^[a-z]{3}$`, engine.RandomizerString("{RAND;3;FROMCS:ABL}"))
+	assert.Equal(t, "none", engine.RandomizerString("{RAND;3;FROMCS:EMAIL}"))
+
+	seeded := func() string {
+		e := fastrand.NewEngine(fastrand.WithSeed(7), fastrand.WithCustomKeywordV2("R", func(ctx fastrand.KeywordContext) []byte {
+			return strconv.AppendUint(nil, ctx.Uint64(), 10)
+		}))
+		return e.RandomizerString("{RAND;R}")
+	}
+	assert.Equal(t, seeded(), seeded(), "the engine's random source is seeded")
+}
+
+func TestRegisterKeywordV2(t *testing.T) {
+	engine := fastrand.NewEngine()
+	require.NoError(t, engine.RegisterKeywordV2("NUM", numGen))
+	assert.Len(t, engine.RandomizerString("{RAND;NUM(min=100,max=100)}"), 3)
+	assert.Error(t, engine.RegisterKeywordV2("NUM", nil))
+	assert.Error(t, engine.RegisterKeywordV2("bad name", numGen))
+
+	require.NoError(t, engine.RegisterKeyword("OLD", func(n int) []byte { return []byte(strings.Repeat("o", n)) }))
+	assert.Equal(t, "ooo", engine.RandomizerString("{RAND;3;OLD}"), "length-only generators keep working")
+	assert.True(t, engine.UnregisterKeyword("NUM"))
+}
+
+func digitWriter(dst []byte, length int) []byte {
+	for i := 0; i < length; i++ {
+		dst = append(dst, '0'+byte(i%10))
+	}
+	return dst
+}
+
+func TestCustomKeywordWriter(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithCustomKeywordWriter("SEQDIGITS", digitWriter))
+	assert.Equal(t, "a-01234-b", engine.RandomizerString("a-{RAND;5;SEQDIGITS}-b"))
+	assert.Equal(t, "0123,012", engine.RandomizerString("{RAND;4;seqdigits},{RAND;3;SEQDIGITS}"))
+
+	require.NoError(t, engine.RegisterKeywordWriter("TAGGED", func(dst []byte, length int) []byte {
+		return append(dst, "<{RAND;2;SEQDIGITS}>"...)
+	}))
+	assert.Equal(t, "<{RAND;2;SEQDIGITS}>", engine.RandomizerString("{RAND;TAGGED}"))
+	assert.Error(t, engine.RegisterKeywordWriter("NIL", nil))
+
+	nested := fastrand.NewEngine(
+		fastrand.WithMaxExpansionDepth(1),
+		fastrand.WithCustomKeywordWriter("SEQDIGITS", digitWriter),
+		fastrand.WithCustomKeywordWriter("TAGGED", func(dst []byte, length int) []byte {
+			return append(dst, "<{RAND;2;SEQDIGITS}>"...)
+		}),
+	)
+	assert.Equal(t, "x<01>", nested.RandomizerString("x{RAND;TAGGED}"), "written tags are re-expanded")
+}
+
+func TestAllocsCustomKeywordWriter(t *testing.T) {
+	tmpl, err := fastrand.NewEngine(fastrand.WithCustomKeywordWriter("SEQDIGITS", digitWriter)).
+		Compile([]byte("id={RAND;12;SEQDIGITS}"))
+	require.NoError(t, err)
+	dst := make([]byte, 0, 64)
+	allocs := testing.AllocsPerRun(100, func() {
+		dst = tmpl.Append(dst[:0])
+	})
+	assert.Zero(t, allocs)
+	assert.Equal(t, "id=012345678901", string(dst))
+}
@@ -0,0 +1,141 @@
+package fastrand
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"strings"
+)
+
+// keywordTable holds which built-in keywords are enabled, the custom
+// keyword generators, the custom charsets of built-in keywords and the
+// characters excluded from charsets. Only
+// options and Reset, which must not run concurrently with the engine,
+// modify the table in place; runtime changes publish an updated copy, so
+// concurrent expansions see either the old or the new configuration.
+type keywordTable struct {
+	enabled  map[string]bool
+	custom   map[string]customKeyword
+	charsets map[string][]byte
+	excluded map[string]string // characters removed with WithExcludedChars
+}
+
+func newKeywordTable() *keywordTable {
+	t := &keywordTable{
+		enabled:  make(map[string]bool, len(allKeywords)),
+		custom:   make(map[string]customKeyword),
+		charsets: make(map[string][]byte),
+		excluded: make(map[string]string),
+	}
+	for _, kw := range allKeywords {
+		t.enabled[kw] = true
+	}
+	return t
+}
+
+func (t *keywordTable) clone() *keywordTable {
+	return &keywordTable{
+		enabled:  maps.Clone(t.enabled),
+		custom:   maps.Clone(t.custom),
+		charsets: maps.Clone(t.charsets),
+		excluded: maps.Clone(t.excluded),
+	}
+}
+
+// updateKeywords publishes a copy of the keyword table modified by update,
+// unless update returns an error.
+func (e *FastEngine) updateKeywords(update func(t *keywordTable) error) error {
+	e.registryMu.Lock()
+	defer e.registryMu.Unlock()
+	next := e.keywords.Load().clone()
+	if err := update(next); err != nil {
+		return err
+	}
+	e.keywords.Store(next)
+	return nil
+}
+
+// RegisterKeyword registers a custom keyword with the default engine. See
+// FastEngine.RegisterKeyword.
+func RegisterKeyword(name string, gen CustomKeywordGenerator) error {
+	return defaultEngine.Load().RegisterKeyword(name, gen)
+}
+
+// UnregisterKeyword removes a custom keyword from the default engine. See
+// FastEngine.UnregisterKeyword.
+func UnregisterKeyword(name string) bool {
+	return defaultEngine.Load().UnregisterKeyword(name)
+}
+
+// SetKeywordEnabled enables or disables a built-in keyword of the default
+// engine. See FastEngine.SetKeywordEnabled.
+func SetKeywordEnabled(name string, enabled bool) error {
+	return defaultEngine.Load().SetKeywordEnabled(name, enabled)
+}
+
+// SetCustomCharset overrides the charset of a built-in keyword of the
+// default engine. See FastEngine.SetCustomCharset.
+func SetCustomCharset(keyword string, charset []byte) error {
+	return defaultEngine.Load().SetCustomCharset(keyword, charset)
+}
+
+// RegisterKeyword makes gen available to templates as the keyword name,
+// like WithCustomKeyword but after construction. Names are
+// case-insensitive, at most 16 letters, digits or underscores, and may not
+// shadow a registered charset; a built-in keyword of the same name is
+// overridden. Registering a name again replaces its generator. It is safe
+// to call while the engine is in use; compiled templates keep the
+// generators they were compiled with.
+func (e *FastEngine) RegisterKeyword(name string, gen CustomKeywordGenerator) error {
+	return e.registerKeyword(name, gen.custom())
+}
+
+// UnregisterKeyword removes the custom keyword name, whether it was added
+// with RegisterKeyword or WithCustomKeyword, and reports whether it
+// existed. A built-in keyword it overrode becomes visible again. It is safe
+// to call while the engine is in use.
+func (e *FastEngine) UnregisterKeyword(name string) bool {
+	upper := strings.ToUpper(name)
+	existed := false
+	_ = e.updateKeywords(func(t *keywordTable) error {
+		_, existed = t.custom[upper]
+		delete(t.custom, upper)
+		return nil
+	})
+	return existed
+}
+
+// SetKeywordEnabled enables or disables the built-in keyword name, like
+// WithDisabledKeywords but after construction. Disabled keywords expand
+// like unknown ones. It returns an error for names that are not built-in
+// keywords and is safe to call while the engine is in use.
+func (e *FastEngine) SetKeywordEnabled(name string, enabled bool) error {
+	upper := strings.ToUpper(name)
+	return e.updateKeywords(func(t *keywordTable) error {
+		if _, builtin := t.enabled[upper]; !builtin {
+			return fmt.Errorf("fastrand: unknown keyword %q", name)
+		}
+		t.enabled[upper] = enabled
+		return nil
+	})
+}
+
+// SetCustomCharset makes the built-in keyword draw from charset, like
+// WithCustomCharset but after construction. An empty charset restores the
+// keyword's own. It returns an error for names that are not built-in
+// keywords and is safe to call while the engine is in use; the charset is
+// copied.
+func (e *FastEngine) SetCustomCharset(keyword string, charset []byte) error {
+	upper := strings.ToUpper(keyword)
+	return e.updateKeywords(func(t *keywordTable) error {
+		if _, builtin := t.enabled[upper]; !builtin {
+			return fmt.Errorf("fastrand: unknown keyword %q", keyword)
+		}
+		if len(charset) == 0 {
+			delete(t.charsets, upper)
+		} else {
+			t.charsets[upper] = bytes.Clone(charset)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,106 @@
+package fastrand_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterKeyword(t *testing.T) {
+	engine := fastrand.NewEngine()
+	require.NoError(t, engine.RegisterKeyword("tenant", func(int) []byte { return []byte("acme") }))
+	assert.Equal(t, "id=acme", engine.RandomizerString("id={RAND;TENANT}"))
+
+	require.NoError(t, engine.RegisterKeyword("TENANT", func(int) []byte { return []byte("globex") }))
+	assert.Equal(t, "id=globex", engine.RandomizerString("id={RAND;tenant}"), "registering again replaces")
+
+	assert.True(t, engine.UnregisterKeyword("Tenant"))
+	assert.False(t, engine.UnregisterKeyword("TENANT"))
+	assert.NotContains(t, engine.RandomizerString("{RAND;TENANT}"), "globex")
+
+	require.NoError(t, engine.RegisterKeyword("UUID", func(int) []byte { return []byte("fixed") }))
+	assert.Equal(t, "fixed", engine.RandomizerString("{RAND;UUID}"), "built-ins can be overridden")
+	engine.UnregisterKeyword("UUID")
+	assert.Regexp(t, `^[0-9a-f-]{36}$`, engine.RandomizerString("{RAND;UUID}"))
+}
+
+func TestRegisterKeywordErrors(t *testing.T) {
+	engine := fastrand.NewEngine()
+	gen := func(int) []byte { return nil }
+	require.NoError(t, engine.RegisterCharset("GREEK", fastrand.CharsList("αβγ")))
+
+	assert.Error(t, engine.RegisterKeyword("", gen))
+	assert.Error(t, engine.RegisterKeyword("BAD NAME", gen))
+	assert.Error(t, engine.RegisterKeyword("OK", nil))
+	assert.Error(t, engine.RegisterKeyword("greek", gen), "charset names are taken")
+
+	require.NoError(t, engine.RegisterKeyword("ROLL", gen))
+	assert.Error(t, engine.RegisterCharset("ROLL", fastrand.CharsDigits), "keyword names are taken")
+}
+
+func TestSetKeywordEnabled(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithStrictParsing(true))
+	require.NoError(t, engine.SetKeywordEnabled("email", false))
+	_, err := engine.RandomizerErr([]byte("{RAND;EMAIL}"))
+	assert.ErrorContains(t, err, "disabled")
+
+	require.NoError(t, engine.SetKeywordEnabled("EMAIL", true))
+	out, err := engine.RandomizerErr([]byte("{RAND;8;EMAIL}"))
+	require.NoError(t, err)
+	assert.Contains(t, string(out), "@")
+
+	assert.Error(t, engine.SetKeywordEnabled("NOPE", false))
+
+	engine = fastrand.NewEngine(fastrand.WithDisabledKeywords("HEX"))
+	require.NoError(t, engine.SetKeywordEnabled("hex", true))
+	assert.Regexp(t, `^[0-9a-f]{8}$`, engine.RandomizerString("{RAND;4;HEX}"))
+}
+
+func TestSetCustomCharset(t *testing.T) {
+	engine := fastrand.NewEngine()
+	require.NoError(t, engine.SetCustomCharset("digit", []byte("7")))
+	assert.Equal(t, "7777", engine.RandomizerString("{RAND;4;DIGIT}"))
+	assert.Equal(t, map[string]string{"DIGIT": "7"}, engine.Config().CustomCharsets)
+
+	require.NoError(t, engine.SetCustomCharset("DIGIT", nil))
+	assert.Regexp(t, "^[0-9]{32}$", engine.RandomizerString("{RAND;32;DIGIT}"), "an empty charset restores the built-in one")
+
+	assert.Error(t, engine.SetCustomCharset("NOPE", []byte("x")))
+
+	charset := []byte("ab")
+	require.NoError(t, engine.SetCustomCharset("DIGIT", charset))
+	charset[0], charset[1] = 'z', 'z'
+	assert.Regexp(t, "^[ab]{16}$", engine.RandomizerString("{RAND;16;DIGIT}"), "the charset is copied")
+}
+
+func TestRegisterKeywordConcurrent(t *testing.T) {
+	engine := fastrand.NewEngine()
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for range 200 {
+				out := engine.RandomizerString("{RAND;4;LIVE}-{RAND;4;HEX}")
+				assert.Contains(t, []int{9, 13}, len(out), "HEX yields 8 characters, or 4 when disabled")
+				assert.Regexp(t, "^[0-9]{4}$", engine.RandomizerString("{RAND;4;DIGIT}"))
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := range 200 {
+				if (i+j)%2 == 0 {
+					assert.NoError(t, engine.RegisterKeyword("LIVE", func(int) []byte { return []byte("live") }))
+				} else {
+					engine.UnregisterKeyword("LIVE")
+				}
+				assert.NoError(t, engine.SetKeywordEnabled("HEX", j%3 != 0))
+				assert.NoError(t, engine.SetCustomCharset("DIGIT", []byte("0123456789"[:j%10+1])))
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,122 @@
+package fastrand_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithMaxOutputSize(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithMaxOutputSize(10))
+
+	assert.Len(t, engine.Randomizer([]byte("{RAND;8;DIGIT}")), 8)
+	assert.Len(t, engine.Randomizer([]byte("{RAND;8;DIGIT}{RAND;8;DIGIT}")), 10)
+	assert.Equal(t, "0123456789", engine.RandomizerString("0123456789abcdef"), "literal text counts too")
+
+	out := engine.RandomizerAppend([]byte("prefix:"), []byte("{RAND;20;DIGIT}"))
+	assert.Len(t, out, len("prefix:")+10, "the budget covers the appended output only")
+
+	assert.Len(t, fastrand.NewEngine().Randomizer([]byte("{RAND;20;DIGIT}")), 20, "unlimited by default")
+	assert.Equal(t, 10, engine.Config().MaxOutputSize)
+}
+
+func TestWithMaxOutputSize_Repeat(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithMaxOutputSize(1000))
+	payload := "{RAND-REPEAT;10000}{RAND-REPEAT;10000}{RAND;99;DIGIT}{/RAND-REPEAT}{/RAND-REPEAT}"
+
+	assert.Len(t, engine.RandomizerString(payload), 1000)
+
+	tmpl, err := engine.Compile([]byte(payload))
+	require.NoError(t, err)
+	assert.Len(t, tmpl.Execute(), 1000)
+}
+
+func TestWithMaxOutputSize_Strict(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithMaxOutputSize(16), fastrand.WithStrictParsing(true))
+
+	out, err := engine.RandomizerErr([]byte("{RAND;8;DIGIT}{RAND;8;DIGIT}"))
+	require.NoError(t, err, "output of exactly the budget is allowed")
+	assert.Len(t, out, 16)
+
+	out, err = engine.RandomizerErr([]byte("{RAND;8;DIGIT}{RAND;8;DIGIT}!"))
+	assert.ErrorIs(t, err, fastrand.ErrOutputTooLarge)
+	assert.Nil(t, out)
+}
+
+func TestWithMaxOutputSize_Reader(t *testing.T) {
+	payload := strings.Repeat("{RAND;50;DIGIT}\n", 10000)
+
+	engine := fastrand.NewEngine(fastrand.WithMaxOutputSize(5000))
+	out, err := io.ReadAll(engine.RandomizerReader(strings.NewReader(payload)))
+	require.NoError(t, err)
+	assert.Len(t, out, 5000)
+
+	strict := fastrand.NewEngine(fastrand.WithMaxOutputSize(5000), fastrand.WithStrictParsing(true))
+	out, err = io.ReadAll(strict.RandomizerReader(strings.NewReader(payload)))
+	assert.ErrorIs(t, err, fastrand.ErrOutputTooLarge)
+	assert.Len(t, out, 5000)
+}
+
+func TestWithMaxTags(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithMaxTags(3))
+
+	out := engine.RandomizerString("a{RAND;2;DIGIT}b{RAND;2;DIGIT}c{RAND;2;DIGIT}d")
+	assert.Len(t, out, 10, "three tags are within the limit")
+
+	out = engine.RandomizerString("a{RAND;2;DIGIT}b{RAND;2;DIGIT}c{RAND;2;DIGIT}d{RAND;2;DIGIT}e")
+	assert.Len(t, out, 10, "expansion stops at the fourth tag")
+	assert.True(t, strings.HasSuffix(out, "d"))
+
+	out = engine.RandomizerString("{RAND-REPEAT;10000}{RAND-REPEAT;10000}x{RAND;1;DIGIT}{/RAND-REPEAT}{/RAND-REPEAT}")
+	assert.Len(t, out, 3, "repeat blocks and every pass through them count")
+
+	tmpl, err := engine.Compile([]byte("{RAND-REPEAT;10000}{RAND;1;DIGIT}{/RAND-REPEAT}"))
+	require.NoError(t, err)
+	assert.Len(t, tmpl.Execute(), 2)
+
+	out2, err := io.ReadAll(engine.RandomizerReader(strings.NewReader(strings.Repeat("{RAND;1;DIGIT}", 100))))
+	require.NoError(t, err)
+	assert.Len(t, out2, 3)
+}
+
+func TestWithMaxTags_Strict(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithMaxTags(2), fastrand.WithStrictParsing(true))
+
+	_, err := engine.RandomizerErr([]byte("{RAND;HEX}{RAND;HEX}"))
+	require.NoError(t, err)
+
+	_, err = engine.RandomizerErr([]byte("{RAND-REPEAT;3}{RAND;HEX}{/RAND-REPEAT}"))
+	assert.ErrorIs(t, err, fastrand.ErrTooManyTags)
+
+	err = engine.Validate([]byte("{RAND;HEX}-{RAND;HEX}-{RAND;HEX}"))
+	var tagErr *fastrand.TagError
+	require.ErrorAs(t, err, &tagErr)
+	assert.Equal(t, 22, tagErr.Offset)
+	assert.Contains(t, tagErr.Reason, "too many tags")
+
+	_, err = io.ReadAll(engine.RandomizerReader(strings.NewReader("{RAND;HEX}{RAND;HEX}{RAND;HEX}")))
+	assert.ErrorIs(t, err, fastrand.ErrTooManyTags)
+}
+
+func TestWithMaxChoicesPerTag(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithMaxChoicesPerTag(3))
+
+	assert.NotContains(t, engine.RandomizerString("{RAND;4,5,6;DIGIT,HEX,ABL}"), "{RAND", "three choices are within the limit")
+
+	tooMany := "{RAND;8;DIGIT,HEX,ABL,ABR}"
+	assert.Equal(t, tooMany, engine.RandomizerString(tooMany), "malformed tags are copied literally")
+	assert.Equal(t, "{RAND;1,2,3,4;DIGIT}", engine.RandomizerString("{RAND;1,2,3,4;DIGIT}"))
+	assert.Equal(t, "{RAND;DIGIT,HEX,ABL,ABR}", engine.RandomizerString("{RAND;DIGIT,HEX,ABL,ABR}"))
+	assert.Len(t, fastrand.NewEngine(fastrand.WithMaxChoicesPerTag(1)).RandomizerString("{RAND;8;DIGIT(min=1,max=2)}"), 8,
+		"commas inside parameters are not choices")
+
+	var tagErr *fastrand.TagError
+	require.ErrorAs(t, engine.Validate([]byte("ok {RAND;8;DIGIT,HEX,ABL,ABR}")), &tagErr)
+	assert.Equal(t, 3, tagErr.Offset)
+	assert.Contains(t, tagErr.Reason, "too many choices")
+	assert.NoError(t, engine.Validate([]byte("{RAND;8;DIGIT,HEX,ABL}")))
+}
@@ -0,0 +1,143 @@
+package fastrand
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Warning describes a construct Lint finds suspicious: it is valid, so
+// lenient expansion goes ahead, but probably not what the template author
+// meant. Offset and Length locate the tag as in TagError.
+type Warning struct {
+	Offset int
+	Length int
+	Reason string
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("fastrand: tag at offset %d: %s", w.Offset, w.Reason)
+}
+
+// Lint lints payload with the default engine. See FastEngine.Lint.
+func Lint(payload []byte) []Warning {
+	return defaultEngine.Load().Lint(payload)
+}
+
+// Lint returns warnings, in payload order, for tags that expand but likely
+// not as intended with this engine: lengths outside [min, max] that fall
+// back to the default, ~mean±stddev lengths whose draws are often clamped,
+// ranges whose bounds are equal, len= parameters that are ignored,
+// disabled keywords, and keyword choices that are never drawn or all
+// invalid. Tags too malformed to parse are left to Validate.
+func (e *FastEngine) Lint(payload []byte) []Warning {
+	payload, scratch := e.normalized(payload)
+	defer e.release(scratch)
+
+	var warnings []Warning
+	cursor, refIndex := 0, -1
+	for {
+		startIndex, isRef := nextTag(payload, cursor, true, &refIndex)
+		if startIndex == -1 {
+			return warnings
+		}
+		endIndex := bytes.IndexByte(payload[startIndex:], endTag)
+		if endIndex == -1 {
+			return warnings
+		}
+		endIndex += startIndex
+		tag := payload[startIndex:endIndex]
+		cursor = endIndex + 1
+		if isRef || bytes.HasPrefix(tag, repeatOpen) || bytes.HasPrefix(tag, base64Open) {
+			continue
+		}
+		for _, reason := range e.lintTag(tag) {
+			warnings = append(warnings, Warning{Offset: startIndex, Length: cursor - startIndex, Reason: reason})
+		}
+	}
+}
+
+// lintTag returns the warnings for tag, which starts with "{RAND" and
+// excludes the closing brace.
+func (e *FastEngine) lintTag(tag []byte) []string {
+	spec, ok := e.parseTag(tag, nil, nil)
+	if !ok {
+		return nil
+	}
+	var reasons []string
+	lenPart, typeKeyword := tagParts(tag)
+	switch spec.lengthKind {
+	case lengthRange:
+		if spec.length == spec.lengthMax {
+			reasons = append(reasons, fmt.Sprintf("range %q always yields length %d", lenPart, spec.length))
+		}
+	case lengthNormal:
+		if spec.length-3*spec.lengthMax < e.minLength || spec.length+3*spec.lengthMax > e.maxLength {
+			reasons = append(reasons, fmt.Sprintf("lengths drawn from %q are clamped to [%d, %d]", lenPart, e.minLength, e.maxLength))
+		}
+	default:
+		if _, valid := e.parseLength(lenPart, nil); !valid {
+			if len(lenPart) > 0 && (lenPart[0] == '~' || lenPart[0] >= '0' && lenPart[0] <= '9') {
+				reasons = append(reasons, fmt.Sprintf("length %q is outside [%d, %d]; length %d is used instead", lenPart, e.minLength, e.maxLength, spec.length))
+			} else if typeKeyword == nil {
+				typeKeyword = lenPart
+			}
+		}
+	}
+
+	if e.keywordChoicesEnabled && indexChoiceSep(typeKeyword) != -1 {
+		var skipped []string
+		for list := typeKeyword; len(list) > 0; {
+			choice := list
+			if i := indexChoiceSep(list); i != -1 {
+				choice, list = list[:i], list[i+1:]
+			} else {
+				list = nil
+			}
+			if name, weight, _ := splitWeight(choice); len(name) > 0 && !e.isKeywordValid(name) {
+				skipped = append(skipped, fmt.Sprintf("keyword choice %q is unknown or disabled and never drawn", name))
+			} else if weight == 0 {
+				skipped = append(skipped, fmt.Sprintf("keyword choice %q has weight 0 and is never drawn", name))
+			}
+		}
+		if !spec.keywordChoice {
+			return append(reasons, fmt.Sprintf("every keyword choice in %q is unknown or disabled", typeKeyword))
+		}
+		reasons = append(reasons, skipped...)
+	} else if len(typeKeyword) > 0 && spec.keywords[0].fallback && e.isDisabled(&spec.keywords[0]) {
+		reasons = append(reasons, fmt.Sprintf("keyword %q is disabled on this engine", typeKeyword))
+	}
+
+	for i := range spec.keywords {
+		kw := &spec.keywords[i]
+		if v, ok := keywordParam(kw.params, "len"); ok {
+			if l, ok := parseLengthFast(v); !ok || l < e.minLength || l > e.maxLength {
+				reasons = append(reasons, fmt.Sprintf("len=%s in %q is outside [%d, %d] and ignored", v, kw.text, e.minLength, e.maxLength))
+			}
+		}
+	}
+	return reasons
+}
+
+// isDisabled reports whether kw names a built-in keyword disabled on the
+// engine.
+func (e *FastEngine) isDisabled(kw *keywordSpec) bool {
+	enabled, builtin := e.keywords.Load().enabled[kw.upper()]
+	return builtin && !enabled
+}
+
+// tagParts returns the length and keyword parts of a {RAND;...} tag, with
+// the chance, VAR=, case modifier and FROM= segments removed. The keyword
+// part is nil when the tag has a single part.
+func tagParts(tag []byte) (lenPart, typeKeyword []byte) {
+	_, body, _ := parseChance(bytes.TrimPrefix(tag[len(startTag):], startTagOpt))
+	if len(body) == 0 {
+		return nil, nil
+	}
+	body, _ = splitVariable(body[1:])
+	body, _ = splitCaseModifier(body)
+	body, _ = splitSource(body)
+	if i := bytes.IndexByte(body, sepTag); i != -1 {
+		return body[:i], body[i+1:]
+	}
+	return body, nil
+}
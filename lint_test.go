@@ -0,0 +1,53 @@
+package fastrand_test
+
+import (
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	engine := fastrand.NewEngine(
+		fastrand.WithDisabledKeywords("EMAIL"),
+		fastrand.WithMaxLength(64),
+	)
+	cases := map[string]string{
+		"{RAND;5-5;HEX}":            `range "5-5" always yields length 5`,
+		"{RAND;500;HEX}":            `length "500" is outside [1, 64]; length 16 is used instead`,
+		"{RAND;~40±10;DIGIT}":       `lengths drawn from "~40±10" are clamped to [1, 64]`,
+		"{RAND;EMAIL}":              `keyword "EMAIL" is disabled on this engine`,
+		"{RAND;8;IPV4,BOGUS}":       `keyword choice "BOGUS" is unknown or disabled and never drawn`,
+		"{RAND;8;IPV4:1,IPV6:0}":    `keyword choice "IPV6" has weight 0 and is never drawn`,
+		"{RAND;8;EMAIL,NOPE}":       `every keyword choice in "EMAIL,NOPE" is unknown or disabled`,
+		"{RAND;8;ABU(len=100)}":     `len=100 in "ABU(len=100)" is outside [1, 64] and ignored`,
+		"{RAND?50;5-5;DIGIT;VAR=x}": `range "5-5" always yields length 5`,
+	}
+	for payload, reason := range cases {
+		warnings := engine.Lint([]byte("ab" + payload))
+		require.Len(t, warnings, 1, payload)
+		assert.Equal(t, fastrand.Warning{Offset: 2, Length: len(payload), Reason: reason}, warnings[0], payload)
+	}
+}
+
+func TestLintClean(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithDisabledKeywords("EMAIL"))
+	for _, payload := range []string{
+		"plain text",
+		"{RAND;8;HEX}{RAND;4-9;ABR}{RAND;~20±2;DIGIT}{RAND;UUID,IPV4}",
+		"{RAND;8;HEX;VAR=id}{RAND:id}{RAND;HEX}",
+		"{RAND;NOPE}",
+		"{RAND;8",
+	} {
+		assert.Empty(t, engine.Lint([]byte(payload)), payload)
+	}
+}
+
+func TestLintMultiple(t *testing.T) {
+	warnings := fastrand.Lint([]byte("{RAND;3-3;HEX} and {RAND;3-3;DIGIT}"))
+	require.Len(t, warnings, 2)
+	assert.Equal(t, 0, warnings[0].Offset)
+	assert.Equal(t, 19, warnings[1].Offset)
+	assert.Equal(t, `fastrand: tag at offset 19: range "3-3" always yields length 3`, warnings[1].String())
+}
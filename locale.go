@@ -0,0 +1,70 @@
+package fastrand
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"strings"
+)
+
+var (
+	//go:embed countries.txt
+	countriesList string
+	//go:embed locales.txt
+	localesList string
+	//go:embed currencies.txt
+	currenciesList string
+	//go:embed timezones.txt
+	timeZonesList string
+)
+
+var (
+	// countryAlpha2 and countryAlpha3 are the ISO 3166-1 codes of the
+	// COUNTRY keyword, in the same order; each line of countries.txt holds
+	// both codes of one country.
+	countryAlpha2, countryAlpha3 = splitCountries(parseLines(countriesList))
+
+	// locales are BCP 47 language tags with a region, such as pt-BR.
+	locales = parseLines(localesList)
+	// currencies are the ISO 4217 codes of circulating currencies, without
+	// the codes for funds, precious metals and testing.
+	currencies = parseLines(currenciesList)
+	// timeZones are the IANA time zone names of zone.tab, one or more per
+	// country, such as Europe/Lisbon.
+	timeZones = parseLines(timeZonesList)
+)
+
+func splitCountries(lines []string) (alpha2, alpha3 []string) {
+	alpha2 = make([]string, len(lines))
+	alpha3 = make([]string, len(lines))
+	for i, line := range lines {
+		alpha2[i], alpha3[i], _ = strings.Cut(line, " ")
+	}
+	return alpha2, alpha3
+}
+
+// countryCodes returns the codes the argument of a COUNTRY keyword names:
+// alpha2 (the default) or alpha3, and whether it is one of them.
+func countryCodes(arg []byte) ([]string, bool) {
+	switch {
+	case len(arg) == 0, bytes.EqualFold(arg, []byte("alpha2")):
+		return countryAlpha2, true
+	case bytes.EqualFold(arg, []byte("alpha3")):
+		return countryAlpha3, true
+	}
+	return countryAlpha2, false
+}
+
+func (e *FastEngine) appendCountry(out *[]byte, arg []byte) {
+	codes, _ := countryCodes(arg)
+	e.appendWord(out, codes)
+}
+
+// checkCountryArg returns why the argument of a COUNTRY keyword is not a
+// code form, or "".
+func checkCountryArg(arg []byte) string {
+	if _, ok := countryCodes(arg); !ok {
+		return fmt.Sprintf("invalid country code form %q: want alpha2 or alpha3", arg)
+	}
+	return ""
+}
@@ -0,0 +1,64 @@
+package fastrand_test
+
+import (
+	"math"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountryKeyword(t *testing.T) {
+	seen := map[string]bool{}
+	for i := 0; i < 2000; i++ {
+		code := fastrand.RandomizerString("{RAND;COUNTRY}")
+		require.Regexp(t, `^[A-Z]{2}$`, code)
+		seen[code] = true
+		require.Regexp(t, `^[A-Z]{2}$`, fastrand.RandomizerString("{RAND;COUNTRY:alpha2}"))
+		require.Regexp(t, `^[A-Z]{3}$`, fastrand.RandomizerString("{RAND;COUNTRY:ALPHA3}"))
+	}
+	assert.Greater(t, len(seen), 200)
+
+	bits, err := fastrand.TagEntropy("{RAND;COUNTRY:alpha3}")
+	require.NoError(t, err)
+	assert.InDelta(t, math.Log2(249), bits, 1e-9)
+
+	engine := fastrand.NewEngine(fastrand.WithStrictParsing(true))
+	_, err = engine.RandomizerErr([]byte("{RAND;COUNTRY:numeric}"))
+	assert.ErrorContains(t, err, "invalid country code form")
+	_, err = engine.RandomizerErr([]byte("{RAND;COUNTRY}{RAND;COUNTRY:alpha3}{RAND;LOCALE}{RAND;CURRENCY}{RAND;TZ}"))
+	assert.NoError(t, err)
+}
+
+func TestCodeKeywords(t *testing.T) {
+	for i := 0; i < 1000; i++ {
+		require.Regexp(t, `^[a-z]{2,3}-[A-Z]{2}$`, fastrand.RandomizerString("{RAND;LOCALE}"))
+		require.Regexp(t, `^[A-Z]{3}$`, fastrand.RandomizerString("{RAND;CURRENCY}"))
+		require.NotEqual(t, "XXX", fastrand.RandomizerString("{RAND;CURRENCY}"))
+
+		name := fastrand.RandomizerString("{RAND;TZ}")
+		loc, err := time.LoadLocation(name)
+		require.NoError(t, err, name)
+		require.Equal(t, name, loc.String())
+	}
+
+	for tag, n := range map[string]float64{"{RAND;LOCALE}": 72, "{RAND;CURRENCY}": 159, "{RAND;TZ}": 418} {
+		bits, err := fastrand.TagEntropy(tag)
+		require.NoError(t, err)
+		assert.InDelta(t, math.Log2(n), bits, 1e-9, tag)
+	}
+}
+
+func TestAllocsCodeKeywords(t *testing.T) {
+	engine := fastrand.NewEngine()
+	payload := []byte("{RAND;COUNTRY:alpha3} {RAND;LOCALE} {RAND;CURRENCY} {RAND;TZ}")
+	dst := make([]byte, 0, 128)
+	dst = engine.RandomizerAppend(dst, payload)
+	allocs := testing.AllocsPerRun(100, func() {
+		dst = engine.RandomizerAppend(dst[:0], payload)
+	})
+	assert.Zero(t, allocs)
+}
@@ -0,0 +1,144 @@
+package fastrand
+
+import (
+	"fmt"
+	"net"
+)
+
+const (
+	// macLocal is the locally administered bit of a MAC address's first
+	// octet; macMulticast is its group bit.
+	macLocal     = 0x02
+	macMulticast = 0x01
+)
+
+// macConfig is the shape of the addresses MAC generates.
+type macConfig struct {
+	prefix    []byte
+	universal bool
+	multicast bool
+}
+
+// MACOption configures the addresses MAC generates.
+type MACOption func(*macConfig)
+
+// WithMACPrefix fixes the leading octets of the address, such as a
+// vendor's three-octet OUI. The prefix's own bits then decide whether the
+// address is universal or multicast; at most six octets are used.
+func WithMACPrefix(prefix []byte) MACOption {
+	return func(c *macConfig) {
+		c.prefix = prefix[:min(len(prefix), 6)]
+	}
+}
+
+// WithMACUniversal clears the locally administered bit, so the address
+// looks vendor-assigned.
+func WithMACUniversal() MACOption {
+	return func(c *macConfig) { c.universal = true }
+}
+
+// WithMACMulticast sets the group bit, making a multicast address.
+func WithMACMulticast() MACOption {
+	return func(c *macConfig) { c.multicast = true }
+}
+
+// MAC returns a random 48-bit MAC address. By default it is a locally
+// administered unicast address, which cannot collide with vendor-assigned
+// hardware; options change those bits or fix a prefix.
+func MAC(opts ...MACOption) net.HardwareAddr {
+	var c macConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+	var raw [6]byte
+	fillMAC(fastUint64, &raw, c)
+	return net.HardwareAddr(raw[:])
+}
+
+// fillMAC fills raw with a random address shaped by c.
+func fillMAC(next func() uint64, raw *[6]byte, c macConfig) {
+	fillBytes(next, raw[:])
+	if copy(raw[:], c.prefix) > 0 {
+		return
+	}
+	raw[0] &^= macLocal | macMulticast
+	if !c.universal {
+		raw[0] |= macLocal
+	}
+	if c.multicast {
+		raw[0] |= macMulticast
+	}
+}
+
+// parseMACPrefix parses the prefix argument of a MAC keyword: one to six
+// hex octets separated by ':' or '-', such as 00:1A:2B.
+func parseMACPrefix(arg []byte, prefix *[6]byte) (int, bool) {
+	n := 0
+	for i := 0; i < len(arg); {
+		if n == len(prefix) || i+2 > len(arg) {
+			return 0, false
+		}
+		hi, okHi := fromHexChar(arg[i])
+		lo, okLo := fromHexChar(arg[i+1])
+		if !okHi || !okLo {
+			return 0, false
+		}
+		prefix[n] = hi<<4 | lo
+		n++
+		i += 2
+		if i < len(arg) {
+			if arg[i] != ':' && arg[i] != '-' || i+1 == len(arg) {
+				return 0, false
+			}
+			i++
+		}
+	}
+	return n, true
+}
+
+// macKeywordConfig returns the address shape of a MAC keyword from its
+// prefix argument and its universal and multicast parameters. An invalid
+// prefix is ignored.
+func macKeywordConfig(kw *keywordSpec, prefix *[6]byte) macConfig {
+	var c macConfig
+	if n, ok := parseMACPrefix(kw.arg, prefix); ok {
+		c.prefix = prefix[:n]
+	}
+	v, _ := keywordParam(kw.params, "universal")
+	c.universal = paramBool(v)
+	v, _ = keywordParam(kw.params, "multicast")
+	c.multicast = paramBool(v)
+	return c
+}
+
+// appendMAC appends a MAC address in the colon-separated lower-case form,
+// such as 02:1a:2b:3c:4d:5e.
+func (e *FastEngine) appendMAC(out *[]byte, kw *keywordSpec) {
+	var prefix, raw [6]byte
+	fillMAC(e.next, &raw, macKeywordConfig(kw, &prefix))
+	for i, b := range raw {
+		if i > 0 {
+			*out = append(*out, ':')
+		}
+		*out = append(*out, strconvDigits[b>>4], strconvDigits[b&0x0f])
+	}
+}
+
+// macEntropy returns the random bits of a MAC keyword's address.
+func macEntropy(kw *keywordSpec) float64 {
+	var prefix [6]byte
+	if c := macKeywordConfig(kw, &prefix); len(c.prefix) > 0 {
+		return float64(8 * (6 - len(c.prefix)))
+	}
+	return 46
+}
+
+// checkMACArg returns why the prefix argument of a MAC keyword is invalid,
+// or "".
+func checkMACArg(arg []byte) string {
+	var prefix [6]byte
+	if _, ok := parseMACPrefix(arg, &prefix); !ok {
+		return fmt.Sprintf("invalid prefix %q: want hex octets such as 00:1A:2B", arg)
+	}
+	return ""
+}
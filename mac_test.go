@@ -0,0 +1,79 @@
+package fastrand_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMAC(t *testing.T) {
+	for i := 0; i < 200; i++ {
+		mac := fastrand.MAC()
+		require.Len(t, mac, 6)
+		assert.Equal(t, byte(0x02), mac[0]&0x03, "locally administered unicast by default")
+
+		mac = fastrand.MAC(fastrand.WithMACUniversal())
+		assert.Equal(t, byte(0x00), mac[0]&0x03)
+
+		mac = fastrand.MAC(fastrand.WithMACMulticast())
+		assert.Equal(t, byte(0x03), mac[0]&0x03)
+
+		mac = fastrand.MAC(fastrand.WithMACUniversal(), fastrand.WithMACMulticast())
+		assert.Equal(t, byte(0x01), mac[0]&0x03)
+	}
+
+	oui := []byte{0x00, 0x1a, 0x2b}
+	mac := fastrand.MAC(fastrand.WithMACPrefix(oui))
+	assert.Equal(t, net.HardwareAddr{0x00, 0x1a, 0x2b}, mac[:3])
+	assert.NotEqual(t, fastrand.MAC(), fastrand.MAC())
+}
+
+func TestMACKeyword(t *testing.T) {
+	for i := 0; i < 200; i++ {
+		out := fastrand.RandomizerString("{RAND;MAC}")
+		mac, err := net.ParseMAC(out)
+		require.NoError(t, err, out)
+		require.Regexp(t, `^([0-9a-f]{2}:){5}[0-9a-f]{2}$`, out)
+		assert.Equal(t, byte(0x02), mac[0]&0x03)
+
+		mac, err = net.ParseMAC(fastrand.RandomizerString("{RAND;MAC(universal=true,multicast=true)}"))
+		require.NoError(t, err)
+		assert.Equal(t, byte(0x01), mac[0]&0x03)
+	}
+	assert.Regexp(t, `^00:1a:2b(:[0-9a-f]{2}){3}$`, fastrand.RandomizerString("{RAND;MAC:00:1A:2B}"))
+	assert.Regexp(t, `^00:1a:2b(:[0-9a-f]{2}){3}$`, fastrand.RandomizerString("{RAND;MAC:00-1a-2b}"))
+	assert.Regexp(t, `^([0-9A-F]{2}:){5}[0-9A-F]{2}$`, fastrand.RandomizerString("{RAND;MAC;upper}"))
+}
+
+func TestMACKeywordStrict(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithStrictParsing(true))
+	for _, payload := range []string{"{RAND;MAC:00:1}", "{RAND;MAC:zz}", "{RAND;MAC:00:11:22:33:44:55:66}", "{RAND;MAC:00:}"} {
+		_, err := engine.RandomizerErr([]byte(payload))
+		assert.ErrorContains(t, err, "invalid prefix", payload)
+	}
+	_, err := engine.RandomizerErr([]byte("{RAND;MAC:001A2B}"))
+	assert.ErrorContains(t, err, "invalid prefix", "octets must be separated")
+	_, err = engine.RandomizerErr([]byte("{RAND;MAC:00:1A:2B(multicast=yes)}"))
+	assert.NoError(t, err)
+
+	bits, err := fastrand.TagEntropy("{RAND;MAC}")
+	require.NoError(t, err)
+	assert.Equal(t, 46.0, bits)
+	bits, err = fastrand.TagEntropy("{RAND;MAC:00:1A:2B}")
+	require.NoError(t, err)
+	assert.Equal(t, 24.0, bits)
+}
+
+func TestAllocsMACKeyword(t *testing.T) {
+	engine := fastrand.NewEngine()
+	payload := []byte("hwaddr={RAND;MAC:00:1A:2B}")
+	dst := make([]byte, 0, 64)
+	dst = engine.RandomizerAppend(dst, payload)
+	allocs := testing.AllocsPerRun(100, func() {
+		dst = engine.RandomizerAppend(dst[:0], payload)
+	})
+	assert.Zero(t, allocs)
+}
@@ -0,0 +1,91 @@
+package fastrand
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"math"
+	"strings"
+)
+
+//go:embed mime_types.txt
+var mimeTypesList string
+
+// mimeTypes are the real media types of the MIME keyword, one per line of
+// mime_types.txt.
+var mimeTypes = parseLines(mimeTypesList)
+
+// mimeClassSep separates the classes of a MIME keyword.
+const mimeClassSep = '|'
+
+// mimeMatches reports whether media type t belongs to one of the
+// '|'-separated classes, such as image|video, matched case-insensitively.
+// Every type matches an empty class list.
+func mimeMatches(t string, classes []byte) bool {
+	if len(classes) == 0 {
+		return true
+	}
+	class, _, _ := strings.Cut(t, "/")
+	for len(classes) > 0 {
+		var c []byte
+		c, classes, _ = bytes.Cut(classes, []byte{mimeClassSep})
+		if bytes.EqualFold(c, s2b(class)) {
+			return true
+		}
+	}
+	return false
+}
+
+// mimeCount returns how many media types belong to the classes.
+func mimeCount(classes []byte) int {
+	n := 0
+	for _, t := range mimeTypes {
+		if mimeMatches(t, classes) {
+			n++
+		}
+	}
+	return n
+}
+
+// appendMIME appends a media type from the classes the argument of a MIME
+// keyword names, such as image, or from any class when it names none
+// known.
+func (e *FastEngine) appendMIME(out *[]byte, classes []byte) {
+	n := mimeCount(classes)
+	if n == 0 {
+		classes, n = nil, len(mimeTypes)
+	}
+	r := int(uint64N(e.next, uint64(n)))
+	for _, t := range mimeTypes {
+		if !mimeMatches(t, classes) {
+			continue
+		}
+		if r == 0 {
+			*out = append(*out, t...)
+			return
+		}
+		r--
+	}
+}
+
+// mimeEntropy returns the bits of entropy of a MIME keyword.
+func mimeEntropy(classes []byte) float64 {
+	n := mimeCount(classes)
+	if n == 0 {
+		n = len(mimeTypes)
+	}
+	return math.Log2(float64(n))
+}
+
+// checkMIMEArg returns why the argument of a MIME keyword names a class
+// with no media types, or "".
+func checkMIMEArg(classes []byte) string {
+	for len(classes) > 0 {
+		var c []byte
+		c, classes, _ = bytes.Cut(classes, []byte{mimeClassSep})
+		if len(c) == 0 || mimeCount(c) == 0 {
+			return fmt.Sprintf("unknown media type class %q: want application, audio, font, image, message, model, multipart, text or video", c)
+		}
+	}
+	return ""
+}
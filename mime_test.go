@@ -0,0 +1,54 @@
+package fastrand_test
+
+import (
+	"mime"
+	"strings"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMIMEKeyword(t *testing.T) {
+	classes := map[string]bool{}
+	for i := 0; i < 500; i++ {
+		out := fastrand.RandomizerString("{RAND;MIME}")
+		media, _, err := mime.ParseMediaType(out)
+		require.NoError(t, err, out)
+		require.Equal(t, out, media)
+		class, _, _ := strings.Cut(out, "/")
+		classes[class] = true
+
+		require.True(t, strings.HasPrefix(fastrand.RandomizerString("{RAND;MIME:image}"), "image/"))
+		out = fastrand.RandomizerString("{RAND;MIME:Audio|video}")
+		require.True(t, strings.HasPrefix(out, "audio/") || strings.HasPrefix(out, "video/"), out)
+	}
+	assert.GreaterOrEqual(t, len(classes), 8)
+	assert.Regexp(t, `^[a-z]+/`, fastrand.RandomizerString("{RAND;MIME:bogus}"), "an unknown class draws from every type")
+}
+
+func TestMIMEKeywordStrict(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithStrictParsing(true))
+	for _, payload := range []string{"{RAND;MIME:images}", "{RAND;MIME:|image}", "{RAND;MIME:image/png}"} {
+		_, err := engine.RandomizerErr([]byte(payload))
+		assert.ErrorContains(t, err, "unknown media type class", payload)
+	}
+	_, err := engine.RandomizerErr([]byte("{RAND;MIME}{RAND;MIME:TEXT|application}"))
+	assert.NoError(t, err)
+
+	bits, err := fastrand.TagEntropy("{RAND;MIME:font}")
+	require.NoError(t, err)
+	assert.Equal(t, 2.0, bits)
+}
+
+func TestAllocsMIMEKeyword(t *testing.T) {
+	engine := fastrand.NewEngine()
+	payload := []byte("Content-Type: {RAND;MIME:image|text}\r\nAccept: {RAND;MIME}")
+	dst := make([]byte, 0, 128)
+	dst = engine.RandomizerAppend(dst, payload)
+	allocs := testing.AllocsPerRun(100, func() {
+		dst = engine.RandomizerAppend(dst[:0], payload)
+	})
+	assert.Zero(t, allocs)
+}
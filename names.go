@@ -0,0 +1,139 @@
+package fastrand
+
+import (
+	"bytes"
+	"slices"
+)
+
+// SafeFirstNames and SafeLastNames are the default name lists of the NAME,
+// FIRSTNAME, LASTNAME and USERNAME keywords, capitalized.
+var (
+	SafeFirstNames = titleWords(firstNames)
+	SafeLastNames  = titleWords(surnames)
+)
+
+func titleWords(words []string) []string {
+	titled := make([]string, len(words))
+	for i, w := range words {
+		b := []byte(w)
+		caseTitle.apply(b)
+		titled[i] = string(b)
+	}
+	return titled
+}
+
+// NameLists returns the first names and last names the name keywords draw
+// from.
+func (e *FastEngine) NameLists() (first, last []string) {
+	return e.firstNames, e.lastNames
+}
+
+// WithNameLists replaces the first names and last names the NAME,
+// FIRSTNAME, LASTNAME and USERNAME keywords draw from. Names are used as
+// given; empty strings are dropped, and a list with none left keeps its
+// default.
+func WithNameLists(first, last []string) Option {
+	return func(e *FastEngine) {
+		if first = nonEmpty(first); len(first) > 0 {
+			e.firstNames = first
+		}
+		if last = nonEmpty(last); len(last) > 0 {
+			e.lastNames = last
+		}
+	}
+}
+
+// nonEmpty returns a copy of list without its empty strings.
+func nonEmpty(list []string) []string {
+	return slices.DeleteFunc(slices.Clone(list), func(s string) bool { return s == "" })
+}
+
+// appendName appends a person's name such as "Ada Lovelace": a first name
+// and a last name.
+func (e *FastEngine) appendName(out *[]byte) {
+	*out = append(*out, pickWord(e.next, e.firstNames)...)
+	*out = append(*out, ' ')
+	*out = append(*out, pickWord(e.next, e.lastNames)...)
+}
+
+// usernameStyles is the number of ways USERNAME joins a first and a last
+// name.
+const usernameStyles = 5
+
+// appendUsername appends a lower-case handle built from a first and a last
+// name, such as "ada.lovelace", "alovelace", "ada_l" or "ada1815".
+func (e *FastEngine) appendUsername(out *[]byte) {
+	e.appendHandle(out, s2b(pickWord(e.next, e.firstNames)), s2b(pickWord(e.next, e.lastNames)))
+}
+
+// deriveUsername appends a handle for the name in value, as USERNAME with
+// FROM= does: its first word is the first name and its last word the last
+// name. A single word, such as a FIRSTNAME, is paired with a last name from
+// the engine's list. It reports false, appending nothing, when value has
+// no letters or digits.
+func (e *FastEngine) deriveUsername(out *[]byte, value []byte) bool {
+	var first, last []byte
+	for rest := value; len(rest) > 0; {
+		var word []byte
+		word, rest, _ = bytes.Cut(rest, []byte{' '})
+		if bytes.ContainsFunc(word, isSlugRune) {
+			if first == nil {
+				first = word
+			} else {
+				last = word
+			}
+		}
+	}
+	if first == nil {
+		return false
+	}
+	if last == nil {
+		last = s2b(pickWord(e.next, e.lastNames))
+	}
+	e.appendHandle(out, first, last)
+	return true
+}
+
+// isSlugRune reports whether appendSlug keeps r: an ASCII letter or digit.
+func isSlugRune(r rune) bool {
+	return 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9'
+}
+
+// appendHandle appends a username built from first and last in one of the
+// styles USERNAME uses.
+func (e *FastEngine) appendHandle(out *[]byte, first, last []byte) {
+	switch e.next() % usernameStyles {
+	case 0:
+		appendHandlePart(out, first)
+		*out = append(*out, '.')
+		appendHandlePart(out, last)
+	case 1:
+		start := len(*out)
+		appendHandlePart(out, first)
+		*out = (*out)[:start+1]
+		appendHandlePart(out, last)
+	case 2:
+		appendHandlePart(out, first)
+		*out = append(*out, '_')
+		start := len(*out)
+		appendHandlePart(out, last)
+		*out = (*out)[:start+1]
+	case 3:
+		appendHandlePart(out, first)
+		*out = strconvAppendUint(*out, 1950+uint64N(e.next, 60), 10)
+	default:
+		appendHandlePart(out, first)
+		*out = append(*out, '_')
+		appendHandlePart(out, last)
+		*out = strconvAppendUint(*out, uint64N(e.next, 100), 10)
+	}
+}
+
+// appendHandlePart appends name as part of a username: its letters and
+// digits in lower case with other runs turned into '-', or "user" when it
+// has none.
+func appendHandlePart(out *[]byte, name []byte) {
+	if !appendSlug(out, name, '-') {
+		*out = append(*out, "user"...)
+	}
+}
@@ -0,0 +1,107 @@
+package fastrand_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNameKeywords(t *testing.T) {
+	require.NotEmpty(t, fastrand.SafeFirstNames)
+	require.NotEmpty(t, fastrand.SafeLastNames)
+	for i := 0; i < 200; i++ {
+		first := fastrand.RandomizerString("{RAND;FIRSTNAME}")
+		assert.Contains(t, fastrand.SafeFirstNames, first)
+		assert.Regexp(t, `^[A-Z][a-z]+`, first)
+		assert.Contains(t, fastrand.SafeLastNames, fastrand.RandomizerString("{RAND;LASTNAME}"))
+		assert.Regexp(t, `^[A-Z][a-z]+ [A-Z][a-z]+`, fastrand.RandomizerString("{RAND;NAME}"))
+		assert.Regexp(t, `^[a-z0-9-]+([._][a-z0-9-]+|[0-9]{4}|_[a-z])?[0-9]*$`, fastrand.RandomizerString("{RAND;USERNAME}"))
+	}
+}
+
+func TestWithNameLists(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithNameLists([]string{"Mary Jane", ""}, []string{"O'Brien"}))
+	first, last := engine.NameLists()
+	assert.Equal(t, []string{"Mary Jane"}, first)
+	assert.Equal(t, []string{"O'Brien"}, last)
+	assert.Equal(t, "Mary Jane O'Brien", engine.RandomizerString("{RAND;NAME}"))
+	assert.Equal(t, "O'Brien", engine.RandomizerString("{RAND;LASTNAME}"))
+
+	seen := map[string]bool{}
+	for i := 0; i < 500; i++ {
+		seen[engine.RandomizerString("{RAND;USERNAME}")] = true
+	}
+	assert.True(t, seen["mary-jane.o-brien"])
+	assert.True(t, seen["mo-brien"])
+	assert.True(t, seen["mary-jane_o"])
+
+	bits, err := engine.TagEntropy("{RAND;FIRSTNAME}")
+	require.NoError(t, err)
+	assert.Zero(t, bits)
+
+	partial := fastrand.NewEngine(fastrand.WithNameLists(nil, []string{"Rivera"}))
+	first, last = partial.NameLists()
+	assert.Equal(t, fastrand.SafeFirstNames, first)
+	assert.Equal(t, []string{"Rivera"}, last)
+
+	engine.Reset()
+	first, _ = engine.NameLists()
+	assert.Equal(t, fastrand.SafeFirstNames, first)
+}
+
+func TestDerivedUsername(t *testing.T) {
+	engine := fastrand.NewEngine(
+		fastrand.WithNameLists([]string{"Ada"}, []string{"Lovelace"}),
+		fastrand.WithCustomKeyword("WHO", func(int) []byte { return []byte("Grace M. Hopper") }),
+		fastrand.WithStrictParsing(true),
+	)
+	seen := map[string]bool{}
+	for i := 0; i < 300; i++ {
+		_, handle, _ := strings.Cut(engine.RandomizerString("{RAND;WHO;VAR=n}|{RAND;USERNAME;FROM=n}"), "|")
+		assert.Regexp(t, `^(grace\.hopper|ghopper|grace_h|grace[0-9]{4}|grace_hopper[0-9]{1,2})$`, handle)
+		seen[handle] = true
+
+		_, handle, _ = strings.Cut(engine.RandomizerString("{RAND;FIRSTNAME;VAR=f}|{RAND;USERNAME;FROM=f}"), "|")
+		assert.Regexp(t, `^(ada\.lovelace|alovelace|ada_l|ada[0-9]{4}|ada_lovelace[0-9]{1,2})$`, handle,
+			"a first name alone takes a last name from the engine's list")
+	}
+	assert.True(t, seen["grace.hopper"])
+	assert.True(t, seen["ghopper"])
+	assert.True(t, seen["grace_h"])
+
+	_, err := engine.RandomizerErr([]byte("{RAND;NAME;VAR=n}{RAND;USERNAME;FROM=n}"))
+	assert.NoError(t, err)
+	bits, err := engine.TagEntropy("{RAND;USERNAME;FROM=n}")
+	require.NoError(t, err)
+	assert.InDelta(t, 2.3219, bits, 0.001, "only the choice of style adds to the variable")
+}
+
+func TestNameListsConfig(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithNameLists([]string{"Alex"}, []string{"Chen"}))
+	data, err := engine.MarshalConfig()
+	require.NoError(t, err)
+	restored, err := fastrand.NewEngineFromConfig(data)
+	require.NoError(t, err)
+	first, last := restored.NameLists()
+	assert.Equal(t, []string{"Alex"}, first)
+	assert.Equal(t, []string{"Chen"}, last)
+
+	c, err := fastrand.ParseEngineConfig([]byte("[names]\nfirst = [\"Sam\"]\nlast = [\"Rivera\"]\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Sam"}, c.FirstNames)
+	assert.Equal(t, []string{"Rivera"}, c.LastNames)
+}
+
+func TestAllocsNameKeywords(t *testing.T) {
+	engine := fastrand.NewEngine()
+	payload := []byte("{RAND;FIRSTNAME} {RAND;LASTNAME} <{RAND;USERNAME}>")
+	dst := make([]byte, 0, 64)
+	dst = engine.RandomizerAppend(dst, payload)
+	allocs := testing.AllocsPerRun(100, func() {
+		dst = engine.RandomizerAppend(dst[:0], payload)
+	})
+	assert.Zero(t, allocs)
+}
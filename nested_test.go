@@ -0,0 +1,59 @@
+package fastrand_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNestedExpansion(t *testing.T) {
+	user := func(int) []byte { return []byte("user-{RAND;4;DIGIT}") }
+
+	engine := fastrand.NewEngine(
+		fastrand.WithCustomKeyword("USER", user),
+		fastrand.WithMaxExpansionDepth(1),
+	)
+	out := engine.RandomizerString("id={RAND;USER}")
+	assert.Regexp(t, regexp.MustCompile(`^id=user-[0-9]{4}$`), out)
+
+	flat := fastrand.NewEngine(fastrand.WithCustomKeyword("USER", user))
+	assert.Equal(t, "id=user-{RAND;4;DIGIT}", flat.RandomizerString("id={RAND;USER}"))
+}
+
+func TestNestedExpansionDepthLimit(t *testing.T) {
+	engine := fastrand.NewEngine(
+		fastrand.WithCustomKeyword("LOOP", func(int) []byte { return []byte("<{RAND;LOOP}>") }),
+		fastrand.WithMaxExpansionDepth(3),
+	)
+	assert.Equal(t, "<<<<{RAND;LOOP}>>>>", engine.RandomizerString("{RAND;LOOP}"))
+}
+
+func TestNestedExpansionCycle(t *testing.T) {
+	engine := fastrand.NewEngine(
+		fastrand.WithCycle("env", "dev-{RAND;3;DIGIT}", "prod"),
+		fastrand.WithMaxExpansionDepth(2),
+	)
+	assert.Regexp(t, `^dev-[0-9]{3}$`, engine.RandomizerString("{RAND;CYCLE:env}"))
+	assert.Equal(t, "prod", engine.RandomizerString("{RAND;CYCLE:env}"))
+}
+
+func TestNestedExpansionNotEncoded(t *testing.T) {
+	engine := fastrand.NewEngine(
+		fastrand.WithCustomKeyword("Q", func(int) []byte { return []byte("a&b {RAND;2;DIGIT}") }),
+		fastrand.WithMaxExpansionDepth(1),
+		fastrand.WithOutputEncoding(fastrand.RandomizerEncodingHTML),
+	)
+	assert.Regexp(t, `^x&lt;a&b [0-9]{2}$`, engine.RandomizerString("x<{RAND;Q}"))
+}
+
+func TestNestedExpansionTemplate(t *testing.T) {
+	engine := fastrand.NewEngine(
+		fastrand.WithCustomKeyword("USER", func(int) []byte { return []byte("u{RAND;2;DIGIT}") }),
+		fastrand.WithMaxExpansionDepth(1),
+	)
+	tmpl, err := engine.Compile([]byte("[{RAND;USER}]"))
+	assert.NoError(t, err)
+	assert.Regexp(t, `^\[u[0-9]{2}\]$`, tmpl.ExecuteString())
+}
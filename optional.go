@@ -0,0 +1,40 @@
+package fastrand
+
+import "bytes"
+
+// chanceSpec is the "?percent" modifier of an optional tag or block such as
+// {RAND?50;16;HEX}, which is emitted only that percentage of the time.
+type chanceSpec struct {
+	optional bool
+	percent  int
+}
+
+// parseChance parses a leading "?percent" modifier off body and returns the
+// rest. It reports false for a malformed percentage.
+func parseChance(body []byte) (chanceSpec, []byte, bool) {
+	if len(body) == 0 || body[0] != '?' {
+		return chanceSpec{}, body, true
+	}
+	end := bytes.IndexByte(body, sepTag)
+	if end == -1 {
+		end = len(body)
+	}
+	percent, ok := parseLengthFast(body[1:end])
+	if !ok || percent > 100 {
+		return chanceSpec{}, body, false
+	}
+	return chanceSpec{optional: true, percent: percent}, body[end:], true
+}
+
+// skip draws whether an optional tag or block is left out this time.
+func (c chanceSpec) skip(next func() uint64) bool {
+	return c.optional && int(uint64N(next, 100)) >= c.percent
+}
+
+// probability returns the percent chance that the tag or block is emitted.
+func (c chanceSpec) probability() int {
+	if !c.optional {
+		return 100
+	}
+	return c.percent
+}
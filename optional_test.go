@@ -0,0 +1,72 @@
+package fastrand_test
+
+import (
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptionalTag(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithSeed(3))
+	const n = 2000
+	present := 0
+	for i := 0; i < n; i++ {
+		out := engine.RandomizerString("a{RAND?50;16;HEX}b")
+		switch len(out) {
+		case 2:
+		case 34:
+			present++
+		default:
+			t.Fatalf("unexpected output %q", out)
+		}
+	}
+	assert.InDelta(t, 0.5, float64(present)/n, 0.05)
+
+	for i := 0; i < 20; i++ {
+		require.Equal(t, "ab", fastrand.RandomizerString("a{RAND?0;8;ABL}b"))
+		require.Len(t, fastrand.RandomizerString("{RAND?100;8;ABL}"), 8)
+		require.Len(t, fastrand.RandomizerString("{RAND?100}"), 16)
+	}
+}
+
+func TestOptionalTagMalformed(t *testing.T) {
+	for _, payload := range []string{"{RAND?;8;ABL}", "{RAND?101;8;ABL}", "{RAND?x;8;ABL}"} {
+		assert.Equal(t, payload, fastrand.RandomizerString(payload))
+	}
+	_, err := fastrand.NewEngine(fastrand.WithStrictParsing(true)).RandomizerErr([]byte("{RAND?150;8;ABL}"))
+	assert.ErrorContains(t, err, "invalid probability")
+}
+
+func TestOptionalTagVariable(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, "[]", fastrand.RandomizerString("{RAND?0;8;ABL;VAR=v}[{REF;v}]"))
+	}
+}
+
+func TestOptionalBlock(t *testing.T) {
+	counts := map[string]int{}
+	for i := 0; i < 400; i++ {
+		counts[fastrand.RandomizerString("{RAND-REPEAT?50;2}x{/RAND-REPEAT}")]++
+	}
+	require.Len(t, counts, 2)
+	assert.Positive(t, counts[""])
+	assert.Positive(t, counts["xx"])
+}
+
+func TestOptionalTagTemplateSeeded(t *testing.T) {
+	payload := []byte("{RAND?30;4;DIGIT}-{RAND-REPEAT?70;1-3}{RAND;2;ABL}{/RAND-REPEAT}")
+	tmpl, err := fastrand.NewEngine(fastrand.WithSeed(9)).Compile(payload)
+	require.NoError(t, err)
+	direct := fastrand.NewEngine(fastrand.WithSeed(9))
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, string(direct.Randomizer(payload)), tmpl.ExecuteString())
+	}
+}
+
+func TestOptionalTagEntropy(t *testing.T) {
+	bits, err := fastrand.TagEntropy("{RAND?50;16;HEX}")
+	require.NoError(t, err)
+	assert.Zero(t, bits)
+}
@@ -0,0 +1,208 @@
+package fastrand
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// keywordParams lists the parameters each built-in keyword accepts besides
+// "len", which every keyword takes. Custom keywords accept any parameter.
+var keywordParams = map[string][]string{
+	"HEX":       {"upper"},
+	"UUID":      {"upper"},
+	"UUIDV7":    {"upper"},
+	"EMAIL":     {"provider"},
+	"TIMESTAMP": {"layout", "min", "max"},
+	"DATE":      {"min", "max", "sep"},
+	"TIME":      {"min", "max", "sep"},
+	"SEQ":       {"start", "pad"},
+	"BASE32":    {"alphabet", "pad"},
+	"MAC":       {"universal", "multicast"},
+	"DOMAIN":    {"depth", "tld"},
+	"URL":       {"scheme", "host", "path", "query"},
+	"PASSWORD":  {"lower", "upper", "digit", "symbol", "symbols"},
+	"JWT":       {"key", "alg"},
+	"GEO":       {"minlat", "maxlat", "minlon", "maxlon", "precision"},
+	"FILENAME":  {"ext"},
+	"PATH":      {"depth", "style", "traversal", "ext"},
+}
+
+// splitKeywordParams splits a keyword such as "HEX(len=32,upper=true)" into
+// its name and the text between the parentheses, which is nil when the
+// keyword has no parameter list.
+func splitKeywordParams(keyword []byte) ([]byte, []byte) {
+	if len(keyword) == 0 || keyword[len(keyword)-1] != ')' {
+		return keyword, nil
+	}
+	i := bytes.IndexByte(keyword, '(')
+	if i == -1 {
+		return keyword, nil
+	}
+	return keyword[:i], keyword[i+1 : len(keyword)-1]
+}
+
+// indexChoiceSep returns the index of the first ',' in a keyword list that
+// is not inside a parameter list, or -1.
+func indexChoiceSep(list []byte) int {
+	depth := 0
+	for i, c := range list {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			if depth > 0 {
+				depth--
+			}
+		case ',':
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// keywordParam returns the value of the named parameter in a parameter
+// list like "len=32,upper=true". Names are case-insensitive.
+func keywordParam(params []byte, name string) ([]byte, bool) {
+	for len(params) > 0 {
+		var field []byte
+		field, params, _ = bytes.Cut(params, []byte{','})
+		key, value, ok := bytes.Cut(field, []byte{'='})
+		if ok && bytes.EqualFold(bytes.TrimSpace(key), s2b(name)) {
+			return bytes.TrimSpace(value), true
+		}
+	}
+	return nil, false
+}
+
+// paramBool reports whether a parameter value is true, 1 or yes.
+func paramBool(value []byte) bool {
+	return bytes.EqualFold(value, []byte("true")) || bytes.Equal(value, []byte("1")) || bytes.EqualFold(value, []byte("yes"))
+}
+
+// paramLength returns the length a keyword's len parameter asks for, or
+// length when it has none or it is outside [minLength, maxLength].
+func (e *FastEngine) paramLength(kw *keywordSpec, length int) int {
+	if kw.params == nil {
+		return length
+	}
+	if v, ok := keywordParam(kw.params, "len"); ok {
+		if l, ok := parseLengthFast(v); ok && l >= e.minLength && l <= e.maxLength {
+			return l
+		}
+	}
+	return length
+}
+
+// checkParams returns why a keyword's parameter list is malformed, or "".
+func (e *FastEngine) checkParams(kw *keywordSpec, keyword []byte) string {
+	params := kw.params
+	for len(params) > 0 {
+		var field []byte
+		field, params, _ = bytes.Cut(params, []byte{','})
+		key, value, ok := bytes.Cut(field, []byte{'='})
+		key = bytes.TrimSpace(key)
+		if !ok && kw.takesPositional() {
+			continue
+		}
+		if !ok || len(key) == 0 {
+			return fmt.Sprintf("malformed parameter %q in %q: want name=value", field, keyword)
+		}
+		if bytes.EqualFold(key, []byte("len")) {
+			if l, ok := parseLengthFast(bytes.TrimSpace(value)); !ok || l < e.minLength || l > e.maxLength {
+				return fmt.Sprintf("invalid length %q in %q: lengths must be within [%d, %d]", value, keyword, e.minLength, e.maxLength)
+			}
+			continue
+		}
+		known := kw.custom != nil
+		for _, name := range keywordParams[kw.upper()] {
+			known = known || bytes.EqualFold(key, s2b(name))
+		}
+		if !known {
+			return fmt.Sprintf("unknown parameter %q in %q", key, keyword)
+		}
+	}
+	if kw.custom != nil {
+		return ""
+	}
+	var reason string
+	switch kw.upper() {
+	case "TIMESTAMP":
+		return checkTimestampParams(kw.params, keyword, e.now())
+	case "DATE":
+		_, _, reason = dateBounds(kw.params)
+	case "TIME":
+		_, _, reason = clockBounds(kw.params)
+	case "SEQ":
+		_, _, reason = sequenceParams(kw.params)
+	case "BASE32":
+		reason = checkBase32Params(kw.params)
+	case "DOMAIN":
+		_, _, _, reason = domainParams(kw.params)
+	case "URL":
+		_, reason = urlParams(kw.params)
+	case "PASSWORD":
+		_, reason = passwordParams(kw.params, e.maxLength)
+	case "JWT":
+		_, _, reason = jwtParams(kw.params)
+	case "GEO":
+		_, _, reason = geoParams(kw.params)
+	case "FILENAME":
+		_, reason = filenameExt(kw.params)
+	case "PATH":
+		_, reason = pathParams(kw.params)
+	}
+	if reason != "" {
+		return fmt.Sprintf("%s in %q", reason, keyword)
+	}
+	return ""
+}
+
+// checkArg returns why the :arg of a built-in keyword is invalid, or "".
+func checkArg(kw *keywordSpec, keyword []byte) string {
+	if kw.custom != nil || kw.charset != nil {
+		return ""
+	}
+	var reason string
+	switch kw.upper() {
+	case "FLOAT":
+		_, reason = parseFloatArg(kw.arg)
+	case "PICK":
+		reason = checkPick(kw.arg)
+	case "MAC":
+		reason = checkMACArg(kw.arg)
+	case "PHONE":
+		reason = checkPhoneArg(kw.arg)
+	case "COLOR":
+		reason = checkColorArg(kw.arg)
+	case "MIME":
+		reason = checkMIMEArg(kw.arg)
+	case "HTTPMETHOD":
+		reason = checkHTTPMethodArg(kw.arg)
+	case "HEADERVAL":
+		reason = checkHeaderValueArg(kw.arg)
+	case "HOSTNAME":
+		reason = checkHostnameArg(kw.arg)
+	case "COUNTRY":
+		reason = checkCountryArg(kw.arg)
+	case "CC":
+		reason = checkCardArg(kw.arg)
+	case "CCCVV":
+		if len(kw.arg) > 0 {
+			reason = checkCVVArg(kw.arg)
+		}
+	case "IPV4":
+		if len(kw.arg) > 0 {
+			reason = checkIPv4Arg(kw.arg)
+		}
+	case "IPV6":
+		if len(kw.arg) > 0 {
+			reason = checkIPv6Arg(kw.arg)
+		}
+	}
+	if reason != "" {
+		return fmt.Sprintf("%s in %q", reason, keyword)
+	}
+	return ""
+}
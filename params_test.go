@@ -0,0 +1,65 @@
+package fastrand_test
+
+import (
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeywordParams(t *testing.T) {
+	cases := map[string]string{
+		"{RAND;HEX(len=32,upper=true)}":      `^[0-9A-F]{64}$`,
+		"{RAND;HEX(upper=false)}":            `^[0-9a-f]{32}$`,
+		"{RAND;4;HEX(upper=1)}":              `^[0-9A-F]{8}$`,
+		"{RAND;UUID(upper=yes)}":             `^[0-9A-F]{8}-[0-9A-F]{4}-4[0-9A-F]{3}-[89AB][0-9A-F]{3}-[0-9A-F]{12}$`,
+		"{RAND;EMAIL(provider=corp.com)}":    `^[a-z]{16}@corp\.com$`,
+		"{RAND;6;EMAIL(provider=my-co.io)}":  `^[a-z]{6}@my-co\.io$`,
+		"{RAND;ABL(len=5)}":                  `^[a-z]{5}$`,
+		"{RAND;8;DIGIT( LEN = 3 )}":          `^[0-9]{3}$`,
+		"{RAND;[xy](len=4)}":                 `^[xy]{4}$`,
+		"{RAND;ABL(len=500)}":                `^[a-z]{16}$`,
+		"{RAND;HEX(len=2,upper=true);VAR=h}": `^[0-9A-F]{4}$`,
+	}
+	for payload, pattern := range cases {
+		for i := 0; i < 10; i++ {
+			require.Regexp(t, pattern, fastrand.RandomizerString(payload), payload)
+		}
+	}
+}
+
+func TestKeywordParamsInChoices(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		out := fastrand.RandomizerString("{RAND;HEX(len=2,upper=true),DIGIT(len=3):2}")
+		require.Regexp(t, `^([0-9A-F]{4}|[0-9]{3})$`, out)
+	}
+}
+
+func TestKeywordParamsEntropy(t *testing.T) {
+	bits, err := fastrand.TagEntropy("{RAND;HEX(len=32)}")
+	require.NoError(t, err)
+	assert.Equal(t, 256.0, bits)
+
+	bits, err = fastrand.TagEntropy("{RAND;10;EMAIL(provider=corp.com)}")
+	require.NoError(t, err)
+	assert.InDelta(t, fastrand.CharsetEntropy(fastrand.CharsAlphabetLower, 10), bits, 1e-9)
+}
+
+func TestKeywordParamsStrict(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithStrictParsing(true))
+	_, err := engine.RandomizerErr([]byte("{RAND;HEX(len=32,upper=true)}{RAND;EMAIL(provider=corp.com),ABL}"))
+	assert.NoError(t, err)
+
+	for payload, reason := range map[string]string{
+		"{RAND;HEX(color=red)}":  `unknown parameter "color"`,
+		"{RAND;ABL(upper=true)}": `unknown parameter "upper"`,
+		"{RAND;HEX(len)}":        "malformed parameter",
+		"{RAND;HEX(len=0)}":      "invalid length",
+		"{RAND;NOPE(len=3)}":     "unknown keyword",
+		"{RAND;HEX(len=3),}":     "empty keyword",
+	} {
+		_, err := engine.RandomizerErr([]byte(payload))
+		assert.ErrorContains(t, err, reason, payload)
+	}
+}
@@ -0,0 +1,151 @@
+package fastrand
+
+import (
+	"fmt"
+	"math"
+)
+
+// passwordSymbols are the symbols PASSWORD uses by default: ones signup
+// forms accept that need no quoting in JSON, HTML, URLs or shells.
+var passwordSymbols = CharsList("!#%*+-=?@^_")
+
+// passwordPolicy is the least number of characters a password draws from
+// each class, and the symbols it may use.
+type passwordPolicy struct {
+	lower, upper, digit, symbol int
+	symbols                     []byte
+}
+
+var defaultPasswordPolicy = passwordPolicy{lower: 1, upper: 1, digit: 1, symbol: 1, symbols: passwordSymbols}
+
+// Password returns a random password of the given length with at least one
+// lowercase letter, uppercase letter, digit and symbol. It is at least four
+// characters long.
+func Password(length int) string {
+	var out []byte
+	appendPassword(fastUint64, &out, length, defaultPasswordPolicy)
+	return unsafeString(out)
+}
+
+// passwordClass is a character class of a password and the least number
+// of its characters the password holds.
+type passwordClass struct {
+	chars []byte
+	min   int
+}
+
+// classes returns the character classes of p.
+func (p passwordPolicy) classes() [4]passwordClass {
+	return [4]passwordClass{
+		{CharsAlphabetLower, p.lower},
+		{CharsAlphabetUpper, p.upper},
+		{CharsDigits, p.digit},
+		{p.symbols, p.symbol},
+	}
+}
+
+// minLength returns the length the minimum counts of p add up to.
+func (p passwordPolicy) minLength() int {
+	return p.lower + p.upper + p.digit + p.symbol
+}
+
+// appendPassword appends a password of length characters, or of
+// p.minLength() when that is longer, that meets p: the required characters
+// of each class, the rest drawn from all classes, shuffled.
+func appendPassword(next func() uint64, out *[]byte, length int, p passwordPolicy) {
+	length = max(length, p.minLength())
+	start := len(*out)
+	ensureCap(out, start+length)
+	*out = (*out)[:start+length]
+	b := (*out)[start:]
+	classes := p.classes()
+	pos, total := 0, 0
+	for _, c := range classes {
+		fillStringInto(next, b[pos:pos+c.min], c.chars, len(c.chars))
+		pos += c.min
+		total += len(c.chars)
+	}
+	for ; pos < length; pos++ {
+		r := int(uint64N(next, uint64(total)))
+		for _, c := range classes {
+			if r < len(c.chars) {
+				b[pos] = c.chars[r]
+				break
+			}
+			r -= len(c.chars)
+		}
+	}
+	for i := len(b) - 1; i > 0; i-- {
+		j := int(uint64N(next, uint64(i+1)))
+		b[i], b[j] = b[j], b[i]
+	}
+}
+
+// passwordParams returns the policy the lower, upper, digit, symbol and
+// symbols parameters of a PASSWORD keyword ask for, or why one is invalid.
+// Counts default to 1 and must add up to at most maxLength; symbols
+// replaces the default symbol set.
+func passwordParams(params []byte, maxLength int) (passwordPolicy, string) {
+	p := defaultPasswordPolicy
+	if params == nil {
+		return p, ""
+	}
+	var reason string
+	if p.lower, reason = passwordCount(params, "lower"); reason != "" {
+		return defaultPasswordPolicy, reason
+	}
+	if p.upper, reason = passwordCount(params, "upper"); reason != "" {
+		return defaultPasswordPolicy, reason
+	}
+	if p.digit, reason = passwordCount(params, "digit"); reason != "" {
+		return defaultPasswordPolicy, reason
+	}
+	if p.symbol, reason = passwordCount(params, "symbol"); reason != "" {
+		return defaultPasswordPolicy, reason
+	}
+	if p.minLength() > maxLength {
+		return defaultPasswordPolicy, fmt.Sprintf("class counts add up to %d, above the maximum length %d", p.minLength(), maxLength)
+	}
+	if v, ok := keywordParam(params, "symbols"); ok {
+		if len(v) == 0 {
+			return defaultPasswordPolicy, "empty symbols"
+		}
+		p.symbols = v
+	}
+	return p, ""
+}
+
+// passwordCount returns the class count the named parameter of a PASSWORD
+// keyword asks for, 1 by default, or why it is invalid.
+func passwordCount(params []byte, name string) (int, string) {
+	v, ok := keywordParam(params, name)
+	if !ok {
+		return 1, ""
+	}
+	n, ok := parseLengthFast(v)
+	if !ok {
+		return 1, fmt.Sprintf("invalid %s count %q", name, v)
+	}
+	return n, ""
+}
+
+// appendPassword appends a password meeting the policy of a PASSWORD
+// keyword. Invalid parameters fall back to the default policy.
+func (e *FastEngine) appendPassword(out *[]byte, length int, kw *keywordSpec) {
+	p, _ := passwordParams(kw.params, e.maxLength)
+	appendPassword(e.next, out, length, p)
+}
+
+// passwordEntropy returns the bits of entropy of a PASSWORD keyword,
+// counting only the characters drawn from all classes and the required
+// ones, not their shuffled positions.
+func (e *FastEngine) passwordEntropy(kw *keywordSpec, length int) float64 {
+	p, _ := passwordParams(kw.params, e.maxLength)
+	length = max(length, p.minLength())
+	bits, total := 0.0, 0
+	for _, c := range p.classes() {
+		bits += float64(c.min) * math.Log2(float64(len(c.chars)))
+		total += len(c.chars)
+	}
+	return bits + float64(length-p.minLength())*math.Log2(float64(total))
+}
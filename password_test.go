@@ -0,0 +1,98 @@
+package fastrand_test
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// classCounts returns how many lowercase letters, uppercase letters,
+// digits and other characters s holds.
+func classCounts(s string) (lower, upper, digit, other int) {
+	for _, r := range s {
+		switch {
+		case unicode.IsLower(r):
+			lower++
+		case unicode.IsUpper(r):
+			upper++
+		case unicode.IsDigit(r):
+			digit++
+		default:
+			other++
+		}
+	}
+	return lower, upper, digit, other
+}
+
+func TestPassword(t *testing.T) {
+	for i := 0; i < 500; i++ {
+		pw := fastrand.Password(8)
+		require.Len(t, pw, 8)
+		lower, upper, digit, other := classCounts(pw)
+		require.True(t, lower >= 1 && upper >= 1 && digit >= 1 && other >= 1, pw)
+		require.Regexp(t, `^[a-zA-Z0-9!#%*+\-=?@^_]+$`, pw)
+	}
+	assert.Len(t, fastrand.Password(2), 4, "a password is long enough for every required class")
+}
+
+func TestPasswordKeyword(t *testing.T) {
+	for i := 0; i < 300; i++ {
+		pw := fastrand.RandomizerString("{RAND;4;PASSWORD}")
+		lower, upper, digit, other := classCounts(pw)
+		require.Equal(t, [4]int{1, 1, 1, 1}, [4]int{lower, upper, digit, other}, pw)
+
+		pw = fastrand.RandomizerString("{RAND;12;PASSWORD(digit=3,symbol=0,upper=2)}")
+		require.Len(t, pw, 12)
+		lower, upper, digit, _ = classCounts(pw)
+		require.True(t, lower >= 1 && upper >= 2 && digit >= 3, pw)
+
+		pw = fastrand.RandomizerString("{RAND;10;PASSWORD(symbol=2,symbols=$&)}")
+		_, _, _, other = classCounts(pw)
+		require.GreaterOrEqual(t, other, 2, pw)
+		require.Regexp(t, `^[a-zA-Z0-9$&]+$`, pw)
+	}
+	pw := fastrand.RandomizerString("{RAND;2;PASSWORD(lower=6)}")
+	assert.Len(t, pw, 9, "the class counts set the minimum length")
+
+	firsts := map[bool]int{}
+	for i := 0; i < 400; i++ {
+		pw := fastrand.RandomizerString("{RAND;4;PASSWORD}")
+		firsts[strings.ContainsAny(pw[:1], "abcdefghijklmnopqrstuvwxyz")]++
+	}
+	assert.Greater(t, firsts[false], 200, "required characters are shuffled")
+}
+
+func TestPasswordKeywordStrict(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithStrictParsing(true))
+	cases := map[string]string{
+		"{RAND;PASSWORD(digit=x)}":           "invalid digit count",
+		"{RAND;PASSWORD(symbols=)}":          "empty symbols",
+		"{RAND;PASSWORD(lower=50,upper=50)}": "above the maximum length 99",
+		"{RAND;PASSWORD(special=1)}":         "unknown parameter",
+	}
+	for payload, want := range cases {
+		_, err := engine.RandomizerErr([]byte(payload))
+		assert.ErrorContains(t, err, want, payload)
+	}
+	_, err := engine.RandomizerErr([]byte("{RAND;16;PASSWORD(lower=2,upper=2,digit=2,symbol=2,symbols=!@)}"))
+	assert.NoError(t, err)
+
+	bits, err := fastrand.TagEntropy("{RAND;4;PASSWORD(symbols=!)}")
+	require.NoError(t, err)
+	assert.InDelta(t, 2*4.7004+3.3219, bits, 0.001)
+}
+
+func TestAllocsPasswordKeyword(t *testing.T) {
+	engine := fastrand.NewEngine()
+	payload := []byte("password={RAND;12;PASSWORD(symbol=2)}")
+	dst := make([]byte, 0, 64)
+	dst = engine.RandomizerAppend(dst, payload)
+	allocs := testing.AllocsPerRun(100, func() {
+		dst = engine.RandomizerAppend(dst[:0], payload)
+	})
+	assert.Zero(t, allocs)
+}
@@ -0,0 +1,191 @@
+package fastrand
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+)
+
+// phoneCountry is the numbering plan PHONE follows for one country: its
+// calling code and the shapes of its national numbers. In a shape, 'X' is
+// any digit, 'N' is 2-9, 'M' is 1-9 and a digit stands for itself; shapes
+// follow the mobile ranges where a country has them, so numbers look like
+// ones a user would type into a form.
+type phoneCountry struct {
+	code   string
+	shapes []string
+}
+
+var phoneCountries = map[string]phoneCountry{
+	"US": {"1", []string{"NXXNXXXXXX"}},
+	"CA": {"1", []string{"NXXNXXXXXX"}},
+	"MX": {"52", []string{"MXXXXXXXXX"}},
+	"BR": {"55", []string{"MM9XXXXXXXX"}},
+	"GB": {"44", []string{"7XXXXXXXXX"}},
+	"IE": {"353", []string{"8XXXXXXXX"}},
+	"DE": {"49", []string{"15XXXXXXXXX", "16XXXXXXXX", "17XXXXXXXXX"}},
+	"FR": {"33", []string{"6XXXXXXXX", "7XXXXXXXX"}},
+	"ES": {"34", []string{"6XXXXXXXX", "7XXXXXXXX"}},
+	"IT": {"39", []string{"3XXXXXXXXX"}},
+	"NL": {"31", []string{"6XXXXXXXX"}},
+	"BE": {"32", []string{"4XXXXXXXX"}},
+	"CH": {"41", []string{"7XXXXXXXX"}},
+	"AT": {"43", []string{"6XXXXXXXXX"}},
+	"SE": {"46", []string{"7XXXXXXXX"}},
+	"PL": {"48", []string{"5XXXXXXXX", "6XXXXXXXX", "7XXXXXXXX", "8XXXXXXXX"}},
+	"IN": {"91", []string{"6XXXXXXXXX", "7XXXXXXXXX", "8XXXXXXXXX", "9XXXXXXXXX"}},
+	"CN": {"86", []string{"13XXXXXXXXX", "15XXXXXXXXX", "18XXXXXXXXX"}},
+	"JP": {"81", []string{"70XXXXXXXX", "80XXXXXXXX", "90XXXXXXXX"}},
+	"AU": {"61", []string{"4XXXXXXXX"}},
+	"ZA": {"27", []string{"6XXXXXXXX", "7XXXXXXXX", "8XXXXXXXX"}},
+}
+
+// phoneCountryCodes lists the keys of phoneCountries in a fixed order, so
+// a seeded engine picks the same countries on every run.
+var phoneCountryCodes = slices.Sorted(maps.Keys(phoneCountries))
+
+// phoneSep separates the countries of a PHONE keyword.
+const phoneSep = '|'
+
+// Phone returns a random E.164 phone number such as "+14155550123" for one
+// of countries, given as ISO 3166 codes like "US" or "DE". Unknown codes
+// are ignored; with none left, any supported country is used.
+func Phone(countries ...string) string {
+	var valid []string
+	for _, c := range countries {
+		var key [4]byte
+		if len(c) <= len(key) {
+			n := upperASCIIInto(key[:], s2b(c))
+			if _, ok := phoneCountries[unsafeString(key[:n])]; ok {
+				valid = append(valid, string(key[:n]))
+			}
+		}
+	}
+	if len(valid) == 0 {
+		valid = phoneCountryCodes
+	}
+	var out []byte
+	appendPhone(fastUint64, &out, phoneCountries[pickWord(fastUint64, valid)])
+	return unsafeString(out)
+}
+
+func appendPhone(next func() uint64, out *[]byte, c phoneCountry) {
+	*out = append(*out, '+')
+	*out = append(*out, c.code...)
+	for _, d := range []byte(pickWord(next, c.shapes)) {
+		switch d {
+		case 'X':
+			d = '0' + byte(uint64N(next, 10))
+		case 'N':
+			d = '2' + byte(uint64N(next, 8))
+		case 'M':
+			d = '1' + byte(uint64N(next, 9))
+		}
+		*out = append(*out, d)
+	}
+}
+
+// phoneArgCountry returns the country the next '|'-separated item of a
+// PHONE keyword's argument names, and the rest of the argument.
+func phoneArgCountry(arg []byte) (phoneCountry, bool, []byte) {
+	item, rest, _ := bytes.Cut(arg, []byte{phoneSep})
+	var key [4]byte
+	if len(item) > len(key) {
+		return phoneCountry{}, false, rest
+	}
+	n := upperASCIIInto(key[:], item)
+	c, ok := phoneCountries[unsafeString(key[:n])]
+	return c, ok, rest
+}
+
+// phoneArgCountries returns how many known countries the argument of a
+// PHONE keyword names.
+func phoneArgCountries(arg []byte) int {
+	count := 0
+	for len(arg) > 0 {
+		var ok bool
+		_, ok, arg = phoneArgCountry(arg)
+		if ok {
+			count++
+		}
+	}
+	return count
+}
+
+// appendPhone appends an E.164 number for one of the countries the
+// argument of a PHONE keyword names, such as US|DE, or for any supported
+// country when it names none.
+func (e *FastEngine) appendPhone(out *[]byte, arg []byte) {
+	count := phoneArgCountries(arg)
+	if count == 0 {
+		appendPhone(e.next, out, phoneCountries[pickWord(e.next, phoneCountryCodes)])
+		return
+	}
+	r := int(uint64N(e.next, uint64(count)))
+	for {
+		var c phoneCountry
+		var ok bool
+		c, ok, arg = phoneArgCountry(arg)
+		if !ok {
+			continue
+		}
+		if r == 0 {
+			appendPhone(e.next, out, c)
+			return
+		}
+		r--
+	}
+}
+
+// phoneEntropy returns the min-entropy of a PHONE keyword, which its most
+// likely number bounds.
+func phoneEntropy(arg []byte) float64 {
+	count := phoneArgCountries(arg)
+	bits := math.Inf(1)
+	visit := func(c phoneCountry) {
+		for _, shape := range c.shapes {
+			b := math.Log2(float64(len(c.shapes)))
+			for _, d := range []byte(shape) {
+				switch d {
+				case 'X':
+					b += math.Log2(10)
+				case 'N':
+					b += 3
+				case 'M':
+					b += math.Log2(9)
+				}
+			}
+			bits = min(bits, b)
+		}
+	}
+	if count == 0 {
+		count = len(phoneCountryCodes)
+		for _, code := range phoneCountryCodes {
+			visit(phoneCountries[code])
+		}
+	} else {
+		for rest := arg; len(rest) > 0; {
+			var c phoneCountry
+			var ok bool
+			if c, ok, rest = phoneArgCountry(rest); ok {
+				visit(c)
+			}
+		}
+	}
+	return bits + math.Log2(float64(count))
+}
+
+// checkPhoneArg returns why the argument of a PHONE keyword names a
+// country PHONE does not know, or "".
+func checkPhoneArg(arg []byte) string {
+	for len(arg) > 0 {
+		item, _, _ := bytes.Cut(arg, []byte{phoneSep})
+		var ok bool
+		if _, ok, arg = phoneArgCountry(arg); !ok {
+			return fmt.Sprintf("unknown country %q: want ISO codes such as US or DE separated by '|'", item)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,69 @@
+package fastrand_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPhone(t *testing.T) {
+	for i := 0; i < 300; i++ {
+		num := fastrand.Phone()
+		require.Regexp(t, `^\+[1-9][0-9]{7,14}$`, num, "E.164 allows at most 15 digits")
+
+		require.Regexp(t, `^\+1[2-9][0-9]{2}[2-9][0-9]{6}$`, fastrand.Phone("us"))
+		require.Regexp(t, `^\+49(15[0-9]{9}|16[0-9]{8}|17[0-9]{9})$`, fastrand.Phone("DE"))
+		require.Regexp(t, `^\+(44|33)`, fastrand.Phone("GB", "FR", "XX"))
+	}
+	assert.Regexp(t, `^\+[1-9]`, fastrand.Phone("XX"), "unknown codes fall back to any country")
+}
+
+func TestPhoneKeyword(t *testing.T) {
+	seen := map[string]bool{}
+	for i := 0; i < 300; i++ {
+		out := fastrand.RandomizerString("{RAND;PHONE:US|de}")
+		require.Regexp(t, `^\+(1[2-9][0-9]{2}[2-9][0-9]{6}|49(15[0-9]{9}|16[0-9]{8}|17[0-9]{9}))$`, out)
+		seen[out[:2]] = true
+
+		out = fastrand.RandomizerString("{RAND;PHONE:JP}")
+		require.Regexp(t, `^\+81[789]0[0-9]{8}$`, out)
+
+		out = fastrand.RandomizerString("{RAND;PHONE}")
+		require.Regexp(t, `^\+[1-9][0-9]{7,14}$`, out)
+	}
+	assert.True(t, seen["+1"] && seen["+4"], "both countries are drawn")
+
+	out := fastrand.RandomizerString("{RAND;PHONE:US,PHONE:GB}")
+	assert.True(t, strings.HasPrefix(out, "+1") || strings.HasPrefix(out, "+44"), out)
+}
+
+func TestPhoneKeywordStrict(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithStrictParsing(true))
+	for _, payload := range []string{"{RAND;PHONE:US|XX}", "{RAND;PHONE:USA}", "{RAND;PHONE:|US}"} {
+		_, err := engine.RandomizerErr([]byte(payload))
+		assert.ErrorContains(t, err, "unknown country", payload)
+	}
+	_, err := engine.RandomizerErr([]byte("{RAND;PHONE}{RAND;PHONE:gb|Fr}"))
+	assert.NoError(t, err)
+
+	bits, err := fastrand.TagEntropy("{RAND;PHONE:US}")
+	require.NoError(t, err)
+	assert.InDelta(t, 6+8*3.3219, bits, 0.001)
+	bits, err = fastrand.TagEntropy("{RAND;PHONE:FR|GB}")
+	require.NoError(t, err)
+	assert.InDelta(t, 2+8*3.3219, bits, 0.001, "FR's two shapes make each of its numbers less likely than GB's")
+}
+
+func TestAllocsPhoneKeyword(t *testing.T) {
+	engine := fastrand.NewEngine()
+	payload := []byte("tel={RAND;PHONE:US|DE|GB}&alt={RAND;PHONE}")
+	dst := make([]byte, 0, 64)
+	dst = engine.RandomizerAppend(dst, payload)
+	allocs := testing.AllocsPerRun(100, func() {
+		dst = engine.RandomizerAppend(dst[:0], payload)
+	})
+	assert.Zero(t, allocs)
+}
@@ -0,0 +1,68 @@
+package fastrand
+
+import (
+	"bytes"
+	"math"
+)
+
+// pickSep separates the options of a PICK keyword.
+const pickSep = '|'
+
+// pickWeights returns the total weight of the options of a PICK keyword
+// such as PICK:admin:1|guest:8, and the largest single weight. An option
+// without a ":weight" suffix weighs 1; one with weight 0 is never picked.
+// Options may be empty, as in PICK:x|, which emits x or nothing.
+func pickWeights(options []byte) (total, largest int) {
+	for {
+		option, rest, found := bytes.Cut(options, []byte{pickSep})
+		_, weight, _ := splitWeight(option)
+		total += weight
+		largest = max(largest, weight)
+		if !found {
+			return total, largest
+		}
+		options = rest
+	}
+}
+
+// appendPick appends one of the literal options of a PICK keyword, chosen
+// with probability proportional to its weight. It appends nothing when
+// all options weigh 0.
+func (e *FastEngine) appendPick(out *[]byte, options []byte) {
+	total, _ := pickWeights(options)
+	if total == 0 {
+		return
+	}
+	r := int(uint64N(e.next, uint64(total)))
+	for {
+		var option []byte
+		option, options, _ = bytes.Cut(options, []byte{pickSep})
+		value, weight, _ := splitWeight(option)
+		if r < weight {
+			*out = append(*out, value...)
+			return
+		}
+		r -= weight
+	}
+}
+
+// pickEntropy returns the min-entropy of a PICK keyword, which its most
+// likely option bounds.
+func pickEntropy(options []byte) float64 {
+	total, largest := pickWeights(options)
+	if total == 0 {
+		return 0
+	}
+	return math.Log2(float64(total) / float64(largest))
+}
+
+// checkPick returns why the options of a PICK keyword are invalid, or "".
+func checkPick(options []byte) string {
+	if len(options) == 0 {
+		return "no options"
+	}
+	if total, _ := pickWeights(options); total == 0 {
+		return "every option has weight 0"
+	}
+	return ""
+}
@@ -0,0 +1,63 @@
+package fastrand_test
+
+import (
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPick(t *testing.T) {
+	counts := map[string]int{}
+	for i := 0; i < 3000; i++ {
+		counts[fastrand.RandomizerString("{RAND;PICK:admin|guest|root}")]++
+	}
+	require.Len(t, counts, 3)
+	for _, role := range []string{"admin", "guest", "root"} {
+		assert.InDelta(t, 1000, counts[role], 150, role)
+	}
+
+	counts = map[string]int{}
+	for i := 0; i < 4500; i++ {
+		counts[fastrand.RandomizerString("{RAND;pick:admin:1|guest:8|root:0}")]++
+	}
+	assert.Zero(t, counts["root"], "weight 0 is never picked")
+	assert.InDelta(t, 4000, counts["guest"], 200)
+	assert.InDelta(t, 500, counts["admin"], 200)
+}
+
+func TestPickLiterals(t *testing.T) {
+	assert.Equal(t, "Mixed Case", fastrand.RandomizerString("{RAND;PICK:Mixed Case}"), "options keep their case")
+	assert.Equal(t, "a:b", fastrand.RandomizerString("{RAND;PICK:a:b}"), "a non-numeric suffix is part of the option")
+	assert.Contains(t, []string{"x", ""}, fastrand.RandomizerString("{RAND;PICK:x|}"))
+	assert.Equal(t, "ADMIN", fastrand.RandomizerString("{RAND;PICK:admin;upper}"))
+	assert.Equal(t, `"a\"b"`, fastrand.NewEngine(fastrand.WithOutputEncodings(fastrand.RandomizerEncodingJSON)).
+		RandomizerString(`"{RAND;PICK:a"b}"`), "value encodings escape picked options")
+}
+
+func TestPickStrict(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithStrictParsing(true))
+	_, err := engine.RandomizerErr([]byte("{RAND;PICK}"))
+	assert.ErrorContains(t, err, `no options in "PICK"`)
+	_, err = engine.RandomizerErr([]byte("{RAND;PICK:a:0|b:0}"))
+	assert.ErrorContains(t, err, "every option has weight 0")
+	_, err = engine.RandomizerErr([]byte("{RAND;PICK:a|b:2}"))
+	assert.NoError(t, err)
+	assert.Empty(t, fastrand.RandomizerString("{RAND;PICK}"))
+
+	bits, err := fastrand.TagEntropy("{RAND;PICK:a:1|b:1|c:2}")
+	require.NoError(t, err)
+	assert.InDelta(t, 1, bits, 1e-9, "min-entropy of the most likely option")
+}
+
+func TestAllocsPick(t *testing.T) {
+	engine := fastrand.NewEngine()
+	payload := []byte(`{"role":"{RAND;PICK:admin:1|guest:8|root}"}`)
+	dst := make([]byte, 0, 64)
+	dst = engine.RandomizerAppend(dst, payload)
+	allocs := testing.AllocsPerRun(100, func() {
+		dst = engine.RandomizerAppend(dst[:0], payload)
+	})
+	assert.Zero(t, allocs)
+}
@@ -0,0 +1,164 @@
+package fastrand
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+)
+
+var (
+	dnsQueryTypes = []uint16{1, 2, 5, 6, 12, 15, 16, 28, 33, 255} // A NS CNAME SOA PTR MX TXT AAAA SRV ANY
+	httpMethods   = []string{"GET", "POST", "PUT", "DELETE", "PATCH", "HEAD", "OPTIONS"}
+	httpVersions  = []string{"HTTP/1.0", "HTTP/1.1"}
+	smtpVerbs     = []string{"HELO", "EHLO", "MAIL", "RCPT", "DATA", "RSET", "NOOP", "QUIT", "VRFY"}
+	protocolTLDs  = []string{"com", "net", "org", "io", "dev", "example"}
+)
+
+// DNSQuery returns a random wire-format DNS query message: a header with a
+// random ID and one question for a random name, type and the IN class.
+func DNSQuery() []byte {
+	var out []byte
+	appendDNSQuery(fastUint64, &out)
+	return out
+}
+
+// HTTPRequestLine returns a random HTTP/1.x request line such as
+// "GET /a7/kq?x=3 HTTP/1.1", without the trailing CRLF.
+func HTTPRequestLine() string {
+	var out []byte
+	appendHTTPRequestLine(fastUint64, &out)
+	return unsafeString(out)
+}
+
+// SMTPCommand returns a random SMTP command such as
+// "MAIL FROM:<ab@cd.com>", without the trailing CRLF.
+func SMTPCommand() string {
+	var out []byte
+	appendSMTPCommand(fastUint64, &out)
+	return unsafeString(out)
+}
+
+func appendDNSQuery(next func() uint64, out *[]byte) {
+	var header [12]byte
+	binary.BigEndian.PutUint16(header[0:], uint16(next()))
+	if next()&1 == 0 {
+		header[2] = 0x01 // recursion desired
+	}
+	binary.BigEndian.PutUint16(header[4:], 1) // QDCOUNT
+	*out = append(*out, header[:]...)
+
+	labels := 1 + int(uint64N(next, 3))
+	for i := 0; i < labels; i++ {
+		n := 1 + int(uint64N(next, 20))
+		*out = append(*out, byte(n))
+		appendHostLabel(next, out, n)
+	}
+	tld := pickWord(next, protocolTLDs)
+	*out = append(*out, byte(len(tld)))
+	*out = append(*out, tld...)
+	*out = append(*out, 0)
+
+	var question [4]byte
+	binary.BigEndian.PutUint16(question[0:], dnsQueryTypes[int(uint64N(next, uint64(len(dnsQueryTypes))))])
+	binary.BigEndian.PutUint16(question[2:], 1) // IN
+	*out = append(*out, question[:]...)
+}
+
+// appendHostLabel appends an n-byte hostname label of lowercase letters and
+// digits that starts with a letter.
+func appendHostLabel(next func() uint64, out *[]byte, n int) {
+	start := len(*out)
+	ensureCap(out, start+n)
+	*out = (*out)[:start+n]
+	b := (*out)[start:]
+	fillStringInto(next, b[:1], CharsAlphabetLower, len(CharsAlphabetLower))
+	fillStringInto(next, b[1:], hostLabelChars, len(hostLabelChars))
+}
+
+var hostLabelChars = append(append(CharsList{}, CharsAlphabetLower...), CharsDigits...)
+
+func appendDomain(next func() uint64, out *[]byte) {
+	appendHostLabel(next, out, 1+int(uint64N(next, 12)))
+	*out = append(*out, '.')
+	*out = append(*out, pickWord(next, protocolTLDs)...)
+}
+
+func appendHTTPRequestLine(next func() uint64, out *[]byte) {
+	method := pickWord(next, httpMethods)
+	*out = append(*out, method...)
+	*out = append(*out, ' ')
+	if method == "OPTIONS" && next()&1 == 0 {
+		*out = append(*out, '*')
+	} else {
+		segments := int(uint64N(next, 4))
+		if segments == 0 {
+			*out = append(*out, '/')
+		}
+		for i := 0; i < segments; i++ {
+			*out = append(*out, '/')
+			appendHostLabel(next, out, 1+int(uint64N(next, 10)))
+		}
+		if next()&1 == 0 {
+			*out = append(*out, '?')
+			appendRandomLower(next, out, 1+int(uint64N(next, 6)))
+			*out = append(*out, '=')
+			appendHostLabel(next, out, 1+int(uint64N(next, 8)))
+		}
+	}
+	*out = append(*out, ' ')
+	*out = append(*out, pickWord(next, httpVersions)...)
+}
+
+func appendSMTPCommand(next func() uint64, out *[]byte) {
+	verb := pickWord(next, smtpVerbs)
+	*out = append(*out, verb...)
+	switch verb {
+	case "HELO", "EHLO":
+		*out = append(*out, ' ')
+		appendDomain(next, out)
+	case "MAIL", "RCPT":
+		if verb == "MAIL" {
+			*out = append(*out, " FROM:<"...)
+		} else {
+			*out = append(*out, " TO:<"...)
+		}
+		appendHostLabel(next, out, 1+int(uint64N(next, 12)))
+		*out = append(*out, '@')
+		appendDomain(next, out)
+		*out = append(*out, '>')
+	case "VRFY":
+		*out = append(*out, ' ')
+		appendHostLabel(next, out, 1+int(uint64N(next, 12)))
+	}
+}
+
+// appendDNSQuery appends a DNS query encoded as the keyword argument asks:
+// base64 (the default), hex or raw bytes.
+func (e *FastEngine) appendDNSQuery(out *[]byte, encoding []byte) {
+	var key [8]byte
+	n := 0
+	if len(encoding) <= len(key) {
+		n = upperASCIIInto(key[:], encoding)
+	}
+	mode := unsafeString(key[:n])
+	if mode == "RAW" {
+		appendDNSQuery(e.next, out)
+		return
+	}
+	var raw [128]byte
+	msg := raw[:0]
+	appendDNSQuery(e.next, &msg)
+	if mode == "HEX" {
+		*out = hex.AppendEncode(*out, msg)
+		return
+	}
+	*out = base64.StdEncoding.AppendEncode(*out, msg)
+}
+
+func (e *FastEngine) appendHTTPRequestLine(out *[]byte) {
+	appendHTTPRequestLine(e.next, out)
+}
+
+func (e *FastEngine) appendSMTPCommand(out *[]byte) {
+	appendSMTPCommand(e.next, out)
+}
@@ -0,0 +1,82 @@
+package fastrand_test
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// checkDNSQuery walks a wire-format DNS query and checks its structure.
+func checkDNSQuery(tb testing.TB, msg []byte) {
+	tb.Helper()
+	require.GreaterOrEqual(tb, len(msg), 12+1+4)
+	assert.Equal(tb, uint16(1), binary.BigEndian.Uint16(msg[4:]), "QDCOUNT")
+	assert.Zero(tb, binary.BigEndian.Uint16(msg[6:])|binary.BigEndian.Uint16(msg[8:])|binary.BigEndian.Uint16(msg[10:]))
+	assert.Zero(tb, msg[2]&0x80, "QR bit must mark a query")
+
+	pos := 12
+	for {
+		require.Less(tb, pos, len(msg))
+		n := int(msg[pos])
+		pos++
+		if n == 0 {
+			break
+		}
+		require.LessOrEqual(tb, n, 63, "label length")
+		require.LessOrEqual(tb, pos+n, len(msg))
+		assert.Regexp(tb, `^[a-z][a-z0-9]*$`, string(msg[pos:pos+n]))
+		pos += n
+	}
+	require.Equal(tb, pos+4, len(msg), "question must end the message")
+	assert.Equal(tb, uint16(1), binary.BigEndian.Uint16(msg[pos+2:]), "QCLASS IN")
+}
+
+func TestDNSQuery(t *testing.T) {
+	for i := 0; i < 500; i++ {
+		checkDNSQuery(t, fastrand.DNSQuery())
+	}
+}
+
+func TestHTTPRequestLine(t *testing.T) {
+	for i := 0; i < 500; i++ {
+		line := fastrand.HTTPRequestLine()
+		req, err := http.ReadRequest(bufio.NewReader(strings.NewReader(line + "\r\nHost: example.com\r\n\r\n")))
+		require.NoError(t, err, "request line %q should parse", line)
+		assert.Contains(t, []string{"HTTP/1.0", "HTTP/1.1"}, req.Proto)
+	}
+}
+
+var smtpCommandRegex = regexp.MustCompile(`^(HELO [a-z0-9.]+|EHLO [a-z0-9.]+|MAIL FROM:<[a-z0-9]+@[a-z0-9.]+>|RCPT TO:<[a-z0-9]+@[a-z0-9.]+>|DATA|RSET|NOOP|QUIT|VRFY [a-z0-9]+)$`)
+
+func TestSMTPCommand(t *testing.T) {
+	for i := 0; i < 500; i++ {
+		assert.Regexp(t, smtpCommandRegex, fastrand.SMTPCommand())
+	}
+}
+
+func TestRandomizerProtocolKeywords(t *testing.T) {
+	engine := fastrand.NewEngine()
+	for i := 0; i < 200; i++ {
+		msg, err := base64.StdEncoding.DecodeString(engine.RandomizerString("{RAND;DNSQ}"))
+		require.NoError(t, err)
+		checkDNSQuery(t, msg)
+
+		msg, err = hex.DecodeString(engine.RandomizerString("{RAND;DNSQ:hex}"))
+		require.NoError(t, err)
+		checkDNSQuery(t, msg)
+
+		checkDNSQuery(t, engine.Randomizer([]byte("{RAND;dnsq:raw}")))
+
+		assert.Regexp(t, `^[A-Z]+ (\*|/\S*) HTTP/1\.[01]$`, engine.RandomizerString("{RAND;HTTPREQ}"))
+		assert.Regexp(t, smtpCommandRegex, engine.RandomizerString("{RAND;SMTP}"))
+	}
+}
@@ -48,6 +48,7 @@ func init() {
 	var seed1, seed2 uint64
 	seedBytes := make([]byte, 16)
 	if _, err := crand.Read(seedBytes); err != nil {
+		seededFromClock = true
 		nano := uint64(time.Now().UnixNano())
 		seed1 = nano
 		seed2 = bits.Reverse64(nano)
@@ -59,6 +60,7 @@ func init() {
 
 	var chachaSeed [32]byte
 	if _, err := crand.Read(chachaSeed[:]); err != nil {
+		seededFromClock = true
 		nano := uint64(time.Now().UnixNano())
 		binary.LittleEndian.PutUint64(chachaSeed[0:8], nano)
 		binary.LittleEndian.PutUint64(chachaSeed[8:16], bits.Reverse64(nano))
@@ -69,7 +71,8 @@ func init() {
 	chaChaSrc = rand.New(chaChaSource)
 
 	FastReader = &randReader{next: fastUint64}
-	SecureReader = &randReader{next: secureUint64}
+	defaultSecureReader = &randReader{next: secureUint64}
+	SecureReader = defaultSecureReader
 }
 
 type randReader struct {
@@ -100,7 +103,10 @@ func Uint64() uint64 {
 
 // splitmix64 step: fast, lock-free non-crypto generator.
 func fastUint64() uint64 {
-	z := fastState.Add(0x9e3779b97f4a7c15)
+	return mix64(fastState.Add(0x9e3779b97f4a7c15))
+}
+
+func mix64(z uint64) uint64 {
 	z ^= z >> 30
 	z *= 0xbf58476d1ce4e5b9
 	z ^= z >> 27
@@ -109,6 +115,23 @@ func fastUint64() uint64 {
 	return z
 }
 
+// seededSource is a splitmix64 generator with its own state, used by
+// engines that need reproducible output. It is safe for concurrent use;
+// the sequence is deterministic for a single goroutine.
+type seededSource struct {
+	state atomic.Uint64
+}
+
+func newSeededSource(seed uint64) *seededSource {
+	s := &seededSource{}
+	s.state.Store(seed)
+	return s
+}
+
+func (s *seededSource) Uint64() uint64 {
+	return mix64(s.state.Add(0x9e3779b97f4a7c15))
+}
+
 func secureUint64() uint64 {
 	chaChaMu.Lock()
 	v := chaChaSrc.Uint64()
@@ -117,12 +140,17 @@ func secureUint64() uint64 {
 }
 
 func fastUint64N(n uint64) uint64 {
+	return uint64N(fastUint64, n)
+}
+
+// uint64N returns a uniform value in [0, n) drawn from next.
+func uint64N(next func() uint64, n uint64) uint64 {
 	if n == 0 {
 		panic("fastrand: argument n must be positive")
 	}
 	threshold := -n % n
 	for {
-		hi, lo := bits.Mul64(fastUint64(), n)
+		hi, lo := bits.Mul64(next(), n)
 		if lo >= threshold {
 			return hi
 		}
@@ -157,6 +185,11 @@ func fastUint16N(n uint16) uint16 {
 
 // fastUint8N returns a random uint8 in [0, n).
 func fastUint8N(n uint8) uint8 {
+	return uint8N(fastUint64, n)
+}
+
+// uint8N returns a uniform uint8 in [0, n) drawn from next.
+func uint8N(next func() uint64, n uint8) uint8 {
 	if n == 0 {
 		panic("fastrand: argument n must be positive")
 	}
@@ -164,12 +197,12 @@ func fastUint8N(n uint8) uint8 {
 		return 0
 	}
 	if n&(n-1) == 0 {
-		return uint8(fastUint64()) & (n - 1)
+		return uint8(next()) & (n - 1)
 	}
 	mask := uint8(1<<8 - 1)
 	threshold := mask - (mask % n)
 	for {
-		v := uint8(fastUint64())
+		v := uint8(next())
 		if v < threshold || threshold == 0 {
 			return v % n
 		}
@@ -220,13 +253,17 @@ func Bytes(length int) []byte {
 }
 
 func FillBytes(buf []byte) {
+	fillBytes(fastUint64, buf)
+}
+
+func fillBytes(next func() uint64, buf []byte) {
 	i := 0
 	for ; i+8 <= len(buf); i += 8 {
-		binary.LittleEndian.PutUint64(buf[i:], fastUint64())
+		binary.LittleEndian.PutUint64(buf[i:], next())
 	}
 
 	if i < len(buf) {
-		val := fastUint64()
+		val := next()
 		for ; i < len(buf); i++ {
 			buf[i] = byte(val)
 			val >>= 8
@@ -254,11 +291,15 @@ func FillHex(dst []byte) {
 	if hexLen&1 != 0 {
 		panic("fastrand: FillHex dst length must be even")
 	}
-	n := hexLen >> 1
+	fillHex(fastUint64, dst)
+}
+
+func fillHex(next func() uint64, dst []byte) {
+	n := len(dst) >> 1
 	i := 0
 	for ; i+8 <= n; i += 8 {
 		var raw [8]byte
-		binary.LittleEndian.PutUint64(raw[:], fastUint64())
+		binary.LittleEndian.PutUint64(raw[:], next())
 		hex.Encode(dst[(i<<1):], raw[:])
 	}
 	if i < n {
@@ -267,7 +308,7 @@ func FillHex(dst []byte) {
 		var val uint64
 		for j := 0; j < remaining; j++ {
 			if j&7 == 0 {
-				val = fastUint64()
+				val = next()
 			}
 			raw[j] = byte(val)
 			val >>= 8
@@ -335,7 +376,7 @@ func String(length int, charset CharsList) string {
 	}
 
 	b := make([]byte, length)
-	fillStringInto(b, charset, csLen)
+	fillStringInto(fastUint64, b, charset, csLen)
 	return *(*string)(unsafe.Pointer(&b))
 }
 
@@ -343,17 +384,17 @@ func FillString(buf []byte, charset CharsList) {
 	if len(charset) == 0 {
 		panic("fastrand: charset must not be empty")
 	}
-	fillStringInto(buf, charset, len(charset))
+	fillStringInto(fastUint64, buf, charset, len(charset))
 }
 
-func fillStringInto(b []byte, charset CharsList, csLen int) {
+func fillStringInto(next func() uint64, b []byte, charset CharsList, csLen int) {
 	if csLen&(csLen-1) == 0 {
 		mask := uint64(csLen - 1)
 		var val uint64
 		var used int
 		for i := 0; i < len(b); i++ {
 			if used == 0 {
-				val = fastUint64()
+				val = next()
 				used = 8
 			}
 			b[i] = charset[val&mask]
@@ -362,11 +403,11 @@ func fillStringInto(b []byte, charset CharsList, csLen int) {
 		}
 	} else if csLen <= 256 {
 		for i := 0; i < len(b); i++ {
-			b[i] = charset[fastUint8N(uint8(csLen))]
+			b[i] = charset[uint8N(next, uint8(csLen))]
 		}
 	} else {
 		for i := 0; i < len(b); i++ {
-			b[i] = charset[int(fastUint64N(uint64(csLen)))]
+			b[i] = charset[int(uint64N(next, uint64(csLen)))]
 		}
 	}
 }
@@ -442,8 +483,13 @@ func IPv6() net.IP {
 }
 
 func Float64() float64 {
+	return float64From(fastUint64)
+}
+
+// float64From returns a float in [0, 1) built from the top 53 bits of next.
+func float64From(next func() uint64) float64 {
 	const denom = 1.0 / (1 << 53)
-	return float64(fastUint64()>>11) * denom
+	return float64(next()>>11) * denom
 }
 
 func Byte() byte {
@@ -597,8 +643,7 @@ func SecureFillString(buf []byte, charset CharsList) error {
 
 func SecureIPv4() (net.IP, error) {
 	ip := make(net.IP, net.IPv4len)
-	_, err := SecureReader.Read(ip)
-	if err != nil {
+	if err := secureRead(ip); err != nil {
 		return nil, fmt.Errorf("fastrand: failed to generate secure IPv4: %w", err)
 	}
 	return ip, nil
@@ -606,8 +651,7 @@ func SecureIPv4() (net.IP, error) {
 
 func SecureIPv6() (net.IP, error) {
 	ip := make(net.IP, net.IPv6len)
-	_, err := SecureReader.Read(ip)
-	if err != nil {
+	if err := secureRead(ip); err != nil {
 		return nil, fmt.Errorf("fastrand: failed to generate secure IPv6: %w", err)
 	}
 	return ip, nil
@@ -703,10 +747,39 @@ func MustSecureUUID() []byte {
 
 func SecureUUID() ([]byte, error) {
 	var uuid [16]byte
-	if _, err := SecureReader.Read(uuid[:]); err != nil {
+	if err := secureRead(uuid[:]); err != nil {
 		return nil, err
 	}
 	uuid[6] = (uuid[6] & 0x0f) | 0x40
 	uuid[8] = (uuid[8] & 0x3f) | 0x80
 	return uuid[:], nil
 }
+
+// FastUUIDv7 returns a time-ordered RFC 9562 version 7 UUID (16 bytes): a
+// 48-bit millisecond Unix timestamp followed by 74 random bits.
+func FastUUIDv7() ([]byte, error) {
+	var uuid [16]byte
+	if _, err := FastReader.Read(uuid[6:]); err != nil {
+		return nil, err
+	}
+	setUUIDv7(&uuid, time.Now())
+	return uuid[:], nil
+}
+
+// SecureUUIDv7 is like FastUUIDv7 with randomness from crypto/rand.
+func SecureUUIDv7() ([]byte, error) {
+	var uuid [16]byte
+	if err := secureRead(uuid[6:]); err != nil {
+		return nil, err
+	}
+	setUUIDv7(&uuid, time.Now())
+	return uuid[:], nil
+}
+
+// setUUIDv7 writes the timestamp t, the version and the variant into uuid,
+// whose other bytes are random.
+func setUUIDv7(uuid *[16]byte, t time.Time) {
+	putMilliTime(uuid, t)
+	uuid[6] = (uuid[6] & 0x0f) | 0x70
+	uuid[8] = (uuid[8] & 0x3f) | 0x80
+}
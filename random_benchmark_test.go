@@ -193,6 +193,19 @@ func BenchmarkRandomizerAppend(b *testing.B) {
 	}
 }
 
+func BenchmarkTemplateAppend(b *testing.B) {
+	tmpl, err := fastrand.NewEngine().Compile([]byte("User: {RAND;10-20;ABL,ABU} | Session: {RANDOM;32;HEX} | ID: {RAND;UUID,HEX} | IP: {RAND;IPV4} | Data: {RAND;50-99} --- End"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	buf := make([]byte, 0, 512)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf = tmpl.Append(buf[:0])
+	}
+}
+
 func BenchmarkFillBytes(b *testing.B) {
 	buf := make([]byte, 64)
 	b.ReportAllocs()
@@ -3,9 +3,12 @@ package fastrand
 import (
 	"bytes"
 	_ "embed"
+	"encoding/base64"
 	"encoding/binary"
 	"encoding/hex"
+	"io"
 	"strings"
+	"sync/atomic"
 	"unsafe"
 )
 
@@ -15,16 +18,72 @@ const (
 	RandomizerEncodingNone RandomizerEncoding = 0
 	RandomizerEncodingURL  RandomizerEncoding = 1 << iota
 	RandomizerEncodingHTML
+	// RandomizerEncodingJSON, as an output encoding, escapes generated
+	// values so they can sit inside JSON string literals. Unlike URL and
+	// HTML it leaves the template text alone, and it may be combined with
+	// either of them.
+	RandomizerEncodingJSON
+	// RandomizerEncodingBase64 is a standard, padded base64 output
+	// encoding, mostly useful as a step of WithOutputEncodings. As an input
+	// encoding it finds runs of standard base64 whose decoded text holds
+	// tags, expands that text and encodes the result again in place, so
+	// templates inside base64 fields work. RandomizerReader does not look
+	// into base64.
+	RandomizerEncodingBase64
+	// RandomizerEncodingUnicode, as an input encoding, decodes tags whose
+	// delimiters are escaped as in JavaScript or JSON source:
+	// \u007BRAND\u003B8\u003BDIGIT\u007D or \x7bRAND;8;DIGIT\x7d.
+	RandomizerEncodingUnicode
+	// RandomizerEncodingURLDouble is an output encoding that percent-encodes
+	// twice, for stacks that decode their input two times: every byte
+	// outside the unreserved set, space included, becomes %25XX.
+	RandomizerEncodingURLDouble
+	// RandomizerEncodingXML, as an output encoding, escapes generated
+	// values so they can sit in XML text or attribute values: markup
+	// characters and quotes become entities and code points XML does not
+	// allow, such as NUL, are dropped. Like RandomizerEncodingJSON it
+	// leaves the template text alone.
+	RandomizerEncodingXML
+	// RandomizerEncodingShell, as an output encoding, quotes generated
+	// values so each is a single shell word: values made only of letters,
+	// digits and @%+=:,./_- are kept, others are single-quoted with
+	// embedded quotes written as '\'' and NUL bytes dropped.
+	RandomizerEncodingShell
+	// RandomizerEncodingSQL, as an output encoding, escapes generated
+	// values for SQL string literals, which the template quotes as in
+	// 'name = {RAND;8;ABR}': single quotes are doubled and NUL bytes
+	// dropped.
+	RandomizerEncodingSQL
+)
+
+// valueEncodings are the output encodings that apply to generated values
+// rather than to template text. They are applied in the order XML, SQL,
+// shell, JSON.
+const valueEncodings = RandomizerEncodingJSON | RandomizerEncodingXML | RandomizerEncodingShell | RandomizerEncodingSQL
+
+const (
+	// encodedChars are the bytes that start an encoded tag piece.
+	encodedChars = "%&\\"
+	// tagChars are the bytes that start a tag or an encoded tag piece; a
+	// payload without any of them expands to itself.
+	tagChars = "{" + encodedChars
 )
 
 type CustomKeywordGenerator func(length int) []byte
 
 var (
-	defaultEngine     *FastEngine
+	defaultEngine     atomic.Pointer[FastEngine]
 	SafeMailProviders []string
 	allKeywords       = []string{
 		"ABL", "ABU", "ABR", "DIGIT", "HEX", "SPACE", "UUID",
-		"NULL", "IPV4", "IPV6", "BYTES", "EMAIL",
+		"NULL", "IPV4", "IPV6", "BYTES", "EMAIL", "SEQ", "CYCLE",
+		"XML", "FORM", "IDENT", "K8SNAME",
+		"ADJ", "NOUN", "VERB", "NAME", "DNSQ", "HTTPREQ", "SMTP",
+		"TIMESTAMP", "DATE", "TIME", "FLOAT", "PICK", "BASE32", "ULID", "UUIDV7", "MAC",
+		"DOMAIN", "URL", "UA", "PHONE", "CC", "CCEXP", "CCCVV",
+		"FIRSTNAME", "LASTNAME", "USERNAME", "WORD", "WORDS", "SENTENCE",
+		"PASSWORD", "JWT", "COLOR", "GEO", "FILENAME", "PATH",
+		"MIME", "HTTPMETHOD", "HEADERVAL", "HOSTNAME", "COUNTRY", "LOCALE", "CURRENCY", "TZ",
 	}
 )
 
@@ -32,111 +91,353 @@ var (
 var mailProviders string
 
 func init() {
-	lines := strings.Split(mailProviders, "\n")
-	for _, line := range lines {
-		trimmed := strings.TrimSpace(line)
-		if trimmed != "" {
-			SafeMailProviders = append(SafeMailProviders, trimmed)
-		}
+	SafeMailProviders = append(SafeMailProviders, parseLines(mailProviders)...)
+	defaultEngine.Store(NewEngine())
+}
+
+// DefaultEngine returns the engine used by the package-level Randomizer
+// functions.
+func DefaultEngine() *FastEngine {
+	return defaultEngine.Load()
+}
+
+// SetDefaultEngine makes e the engine behind the package-level Randomizer
+// functions, so an application can configure them once, for example to
+// disable EMAIL everywhere, and returns the previous default. A nil e
+// installs a new engine with default options. It is safe to call
+// concurrently with the package-level functions.
+func SetDefaultEngine(e *FastEngine) *FastEngine {
+	if e == nil {
+		e = NewEngine()
 	}
-	defaultEngine = NewEngine()
+	return defaultEngine.Swap(e)
 }
 
 func RandomizerString(payload string) string {
-	return defaultEngine.RandomizerString(payload)
+	return defaultEngine.Load().RandomizerString(payload)
 }
 
 func Randomizer(payload []byte) []byte {
-	return defaultEngine.Randomizer(payload)
+	return defaultEngine.Load().Randomizer(payload)
+}
+
+func RandomizerAppend(dst []byte, payload []byte) []byte {
+	return defaultEngine.Load().RandomizerAppend(dst, payload)
+}
+
+func RandomizerAppendString(dst []byte, payload string) []byte {
+	return defaultEngine.Load().RandomizerAppendString(dst, payload)
+}
+
+func RandomizerTo(w io.Writer, payload []byte) (int, error) {
+	return defaultEngine.Load().RandomizerTo(w, payload)
 }
 
 func (e *FastEngine) RandomizerString(payload string) string {
-	if !strings.ContainsAny(payload, "{%&") && e.outputEncoding == RandomizerEncodingNone {
+	if !strings.ContainsAny(payload, tagChars) && !e.transformsPayload() {
 		return payload
 	}
-	buf := make([]byte, 0, len(payload)+512)
-	buf = e.RandomizerAppendString(buf, payload)
-	return string(buf)
+	buf := e.bufferPool.Get(e.sizeHint(len(payload)))
+	*buf = e.RandomizerAppendString(*buf, payload)
+	e.recordSize(len(*buf))
+	result := string(*buf)
+	e.bufferPool.Put(buf)
+	return result
 }
 
 func (e *FastEngine) Randomizer(payload []byte) []byte {
-	if !bytes.ContainsAny(payload, "{%&") && e.outputEncoding == RandomizerEncodingNone {
+	if !bytes.ContainsAny(payload, tagChars) && !e.transformsPayload() {
 		return payload
 	}
 
-	if e.inputEncoding != RandomizerEncodingNone && bytes.ContainsAny(payload, "%&") {
-		payload = normalize(payload, e.inputEncoding)
-	}
-
+	payload, scratch := e.normalized(payload)
 	buf := make([]byte, 0, len(payload)+512)
 	e.randomizerInto(payload, &buf)
+	e.release(scratch)
 	return buf
 }
 
+// RandomizerAppend appends the expansion of payload to dst and returns the
+// extended buffer, following the append convention: the result may share
+// dst's backing array, and nothing is allocated when dst has enough spare
+// capacity.
 func (e *FastEngine) RandomizerAppend(dst []byte, payload []byte) []byte {
-	if !bytes.ContainsAny(payload, "{%&") && e.outputEncoding == RandomizerEncodingNone {
+	if !bytes.ContainsAny(payload, tagChars) && !e.transformsPayload() {
 		return append(dst, payload...)
 	}
-	if e.inputEncoding != RandomizerEncodingNone && bytes.ContainsAny(payload, "%&") {
-		payload = normalize(payload, e.inputEncoding)
-	}
+	payload, scratch := e.normalized(payload)
 	e.randomizerInto(payload, &dst)
+	e.release(scratch)
 	return dst
 }
 
+// RandomizerAppendString is like RandomizerAppend for a string payload.
 func (e *FastEngine) RandomizerAppendString(dst []byte, payload string) []byte {
-	if !strings.ContainsAny(payload, "{%&") && e.outputEncoding == RandomizerEncodingNone {
+	if !strings.ContainsAny(payload, tagChars) && !e.transformsPayload() {
 		return append(dst, payload...)
 	}
-	var normalized []byte
-	if e.inputEncoding != RandomizerEncodingNone && strings.ContainsAny(payload, "%&") {
-		normalized = normalizeString(payload, e.inputEncoding)
-	} else {
-		normalized = s2b(payload)
-	}
+	normalized, scratch := e.normalized(s2b(payload))
 	e.randomizerInto(normalized, &dst)
+	e.release(scratch)
 	return dst
 }
 
-func (e *FastEngine) randomizerInto(payload []byte, out *[]byte) {
-	cursor := 0
-	for {
-		startIndex := bytes.Index(payload[cursor:], startTag)
+// RandomizerTo expands payload and writes the result to w, using a pooled
+// buffer instead of allocating the output. It returns the number of bytes
+// written and any write error.
+func (e *FastEngine) RandomizerTo(w io.Writer, payload []byte) (int, error) {
+	if !bytes.ContainsAny(payload, tagChars) && !e.transformsPayload() {
+		return w.Write(payload)
+	}
+	buf := e.bufferPool.Get(e.sizeHint(len(payload)))
+	*buf = e.RandomizerAppend(*buf, payload)
+	e.recordSize(len(*buf))
+	n, err := w.Write(*buf)
+	e.bufferPool.Put(buf)
+	return n, err
+}
+
+// normalized runs the engine's input normalizer on payload, rewrites its
+// dialect tags, decodes URL/HTML encoded tags according to its input
+// encoding and unwraps base64 blobs holding tags. When rewriting or decoding is needed the result lives in a
+// pooled scratch buffer that must be handed to release once unused.
+func (e *FastEngine) normalized(payload []byte) ([]byte, *[]byte) {
+	if e.inputNormalizer != nil {
+		payload = e.inputNormalizer(payload)
+	}
+	var scratch *[]byte
+	if len(e.dialects) > 0 && e.hasDialectTags(payload) {
+		scratch = e.bufferPool.Get(len(payload))
+		*scratch = e.rewriteDialects((*scratch)[:0], payload)
+		payload = *scratch
+	}
+	if e.inputEncoding != RandomizerEncodingNone && bytes.ContainsAny(payload, encodedChars) {
+		decoded := e.bufferPool.Get(len(payload))
+		*decoded = normalizeInto((*decoded)[:0], payload, e.inputEncoding)
+		e.release(scratch)
+		payload, scratch = *decoded, decoded
+	}
+	if e.inputEncoding&RandomizerEncodingBase64 != 0 {
+		payload, scratch = e.unwrapBase64(payload, scratch)
+	}
+	return payload, scratch
+}
+
+func (e *FastEngine) release(scratch *[]byte) {
+	if scratch != nil {
+		e.bufferPool.Put(scratch)
+	}
+}
+
+// expansion is the state of one Randomizer call, shared by the tags it
+// expands.
+type expansion struct {
+	depth     int             // nesting level of re-expanded generated values
+	vars      []variable      // values captured by VAR= tags
+	limited   bool            // whether limit applies
+	limit     int             // output length the expansion may not exceed
+	truncated bool            // whether output was cut to limit
+	maxTags   int             // number of tags that may be expanded, 0 for any
+	tags      int             // number of tags seen so far
+	done      <-chan struct{} // closed when the caller's context is cancelled
+	cancelled bool            // whether done closed before expansion finished
+	fallback  Engine          // engine unknown keywords are delegated to
+	wrapped   int             // nesting level of base64 blocks, encoded as a whole
+}
+
+// topLevel reports whether output is written directly rather than as part
+// of a re-expanded value or base64 block, so output encodings apply.
+func (x *expansion) topLevel() bool {
+	return x.depth == 0 && x.wrapped == 0
+}
+
+// stopped reports whether the expansion has run out of its output size or
+// tag budget or been cancelled, so no further tags need to be expanded.
+func (x *expansion) stopped(out []byte) bool {
+	if x.done != nil && !x.cancelled {
+		select {
+		case <-x.done:
+			x.cancelled = true
+		default:
+		}
+	}
+	return x.cancelled || x.limited && len(out) > x.limit || x.maxTags > 0 && x.tags > x.maxTags
+}
+
+// limitFrom applies the engine's maximum output size, for output appended
+// after start bytes, and its maximum tag count.
+func (x *expansion) limitFrom(e *FastEngine, start int) {
+	x.limited, x.limit = e.maxOutputSize > 0, start+e.maxOutputSize
+	x.maxTags = e.maxTags
+}
+
+// countTag records a tag and reports whether it is within the tag budget.
+func (x *expansion) countTag() bool {
+	x.tags++
+	return x.maxTags <= 0 || x.tags <= x.maxTags
+}
+
+// truncate cuts out to the expansion's size limit.
+func (x *expansion) truncate(out *[]byte) {
+	if x.limited && len(*out) > x.limit {
+		*out = (*out)[:x.limit]
+		x.truncated = true
+	}
+}
+
+// err returns the error RandomizerErr reports when the expansion ran out
+// of budget: ErrTooManyTags or ErrOutputTooLarge.
+func (x *expansion) err() error {
+	switch {
+	case x.maxTags > 0 && x.tags > x.maxTags:
+		return ErrTooManyTags
+	case x.truncated:
+		return ErrOutputTooLarge
+	}
+	return nil
+}
+
+// randomizerInto appends the expansion of payload to out. It returns the
+// budget error when the engine's output size or tag limit cut it short.
+func (e *FastEngine) randomizerInto(payload []byte, out *[]byte) error {
+	var x expansion
+	return e.expandInto(payload, out, &x)
+}
+
+// expandInto is randomizerInto with caller-provided expansion state.
+func (e *FastEngine) expandInto(payload []byte, out *[]byte, x *expansion) error {
+	start := len(*out)
+	x.limitFrom(e, start)
+	e.expandPayload(payload, out, x)
+	x.truncate(out)
+	if e.stats != nil {
+		e.stats.countPayload(len(*out) - start)
+	}
+	return x.err()
+}
+
+func (e *FastEngine) expandPayload(payload []byte, out *[]byte, x *expansion) {
+	var lengths [16]weightedLength
+	var keywords [4]keywordSpec
+	cursor, refIndex := 0, -1
+	for !x.stopped(*out) {
+		startIndex, isRef := nextTag(payload, cursor, len(x.vars) > 0, &refIndex)
 		if startIndex == -1 {
-			e.writeEncoded(out, payload[cursor:])
+			e.writeLiteral(out, payload[cursor:], x)
 			return
 		}
-		startIndex += cursor
-		e.writeEncoded(out, payload[cursor:startIndex])
+		e.writeLiteral(out, payload[cursor:startIndex], x)
 
 		cursor = startIndex
 		endIndex := bytes.IndexByte(payload[cursor:], endTag)
 		if endIndex == -1 {
-			e.writeEncoded(out, payload[cursor:])
+			e.writeLiteral(out, payload[cursor:], x)
 			return
 		}
 		endIndex += cursor
 		tag := payload[cursor:endIndex]
 		cursor = endIndex + 1
+		if !x.countTag() {
+			return
+		}
 
-		e.parseAndReplaceFast(tag, out)
+		if isRef {
+			e.appendRef(out, tag, payload[startIndex:cursor], x)
+		} else if bytes.HasPrefix(tag, repeatOpen) {
+			if next, ok := e.expandRepeat(payload, out, startIndex, cursor, x); ok {
+				cursor = next
+			} else {
+				e.writeLiteral(out, payload[startIndex:cursor], x)
+			}
+		} else if bytes.HasPrefix(tag, base64Open) && e.inputEncoding&RandomizerEncodingBase64 != 0 {
+			if next, ok := e.expandBase64(payload, out, startIndex, cursor, x); ok {
+				cursor = next
+			} else {
+				e.writeLiteral(out, payload[startIndex:cursor], x)
+			}
+		} else if spec, ok := e.expandableTag(tag, lengths[:0], keywords[:0], x.fallback != nil); ok {
+			e.expandTag(out, &spec, x)
+		} else {
+			e.writeLiteral(out, payload[startIndex:cursor], x)
+		}
+	}
+}
+
+// writeLiteral writes template text. Text inside re-expanded generated
+// values is itself generated output, so only the top level is encoded.
+func (e *FastEngine) writeLiteral(out *[]byte, data []byte, x *expansion) {
+	if !x.topLevel() {
+		*out = append(*out, data...)
+		return
+	}
+	e.writeEncoded(out, data)
+}
+
+// appendGenerated appends a generated value, re-expanding any tags it
+// contains while the engine's maximum expansion depth allows.
+func (e *FastEngine) appendGenerated(out *[]byte, value []byte, x *expansion) {
+	if x.depth >= e.maxExpansionDepth || !bytes.Contains(value, startTag) {
+		*out = append(*out, value...)
+		return
 	}
+	x.depth++
+	e.expandPayload(value, out, x)
+	x.depth--
+}
+
+// expandGeneratedFrom re-expands, like appendGenerated, the tags in the
+// value written to out after start.
+func (e *FastEngine) expandGeneratedFrom(out *[]byte, start int, x *expansion) {
+	if x.depth >= e.maxExpansionDepth || !bytes.Contains((*out)[start:], startTag) {
+		return
+	}
+	value := e.bufferPool.Get(len(*out) - start)
+	*value = append((*value)[:0], (*out)[start:]...)
+	*out = (*out)[:start]
+	e.appendGenerated(out, *value, x)
+	e.bufferPool.Put(value)
 }
 
 func (e *FastEngine) writeEncoded(out *[]byte, data []byte) {
 	if len(data) == 0 {
 		return
 	}
-	switch e.outputEncoding {
+	if e.outputEncoder != nil {
+		// Encode into a pooled buffer rather than passing out, which often
+		// points at a caller's stack variable and would escape to the heap.
+		scratch := e.bufferPool.Get(2 * len(data))
+		*scratch = (*scratch)[:0]
+		e.outputEncoder(scratch, data)
+		*out = append(*out, *scratch...)
+		e.bufferPool.Put(scratch)
+		return
+	}
+	appendEncoded(out, data, e.outputEncoding&^valueEncodings)
+}
+
+// appendEncoded appends data in a single output encoding.
+func appendEncoded(out *[]byte, data []byte, encoding RandomizerEncoding) {
+	switch encoding {
 	case RandomizerEncodingURL:
 		appendURLEncode(out, data)
+	case RandomizerEncodingURLDouble:
+		appendURLDoubleEncode(out, data)
 	case RandomizerEncodingHTML:
 		appendHTMLEncode(out, data)
+	case RandomizerEncodingBase64:
+		*out = base64.StdEncoding.AppendEncode(*out, data)
 	default:
 		*out = append(*out, data...)
 	}
 }
 
+// transformsPayload reports whether output can differ from the payload even
+// where it has none of tagChars, as dialect tags and base64 blobs do not.
+func (e *FastEngine) transformsPayload() bool {
+	return e.outputEncoding != RandomizerEncodingNone || e.outputEncoder != nil || e.inputNormalizer != nil ||
+		e.maxOutputSize > 0 || len(e.dialects) > 0 || e.inputEncoding&RandomizerEncodingBase64 != 0
+}
+
 func appendURLEncode(out *[]byte, data []byte) {
 	for _, c := range data {
 		if c == ' ' {
@@ -150,6 +451,19 @@ func appendURLEncode(out *[]byte, data []byte) {
 	}
 }
 
+// appendURLDoubleEncode appends the percent-encoding of the
+// percent-encoding of data. Space is encoded as %2520 rather than via '+',
+// so the result decodes correctly with either decoder.
+func appendURLDoubleEncode(out *[]byte, data []byte) {
+	for _, c := range data {
+		if c < 128 && noEscapeTable[c] {
+			*out = append(*out, c)
+		} else {
+			*out = append(*out, '%', '2', '5', hexUpper[c>>4], hexUpper[c&0xf])
+		}
+	}
+}
+
 var noEscapeTable = [128]bool{
 	'A': true, 'B': true, 'C': true, 'D': true, 'E': true, 'F': true, 'G': true,
 	'H': true, 'I': true, 'J': true, 'K': true, 'L': true, 'M': true, 'N': true,
@@ -199,175 +513,374 @@ func s2b(s string) []byte {
 	return unsafe.Slice(unsafe.StringData(s), len(s))
 }
 
-func (e *FastEngine) parseAndReplaceFast(tag []byte, out *[]byte) {
-	tag = tag[len(startTag):]
-	hasOpt := false
-	if bytes.HasPrefix(tag, startTagOpt) {
-		tag = tag[len(startTagOpt):]
-		hasOpt = true
+type lengthKind uint8
+
+const (
+	lengthFixed lengthKind = iota
+	lengthChoice
+	lengthNormal
+	lengthRange
+)
+
+// tagSpec is a parsed {RAND;...} tag. Its slices point into the tag text or
+// into scratch space supplied by the caller, so parsing a tag on the hot
+// path does not allocate.
+type tagSpec struct {
+	lengthSpec
+	keywordChoice bool
+	keywords      []keywordSpec
+	keywordWeight int    // total weight of weighted keyword choices, 0 if uniform
+	variable      []byte // VAR= name the expansion is stored under
+	source        []byte // FROM= name of the variable the value derives from
+	chance        chanceSpec
+	caseMode      caseMode
+}
+
+type lengthSpec struct {
+	lengthKind lengthKind
+	length     int // fixed length, range minimum or normal mean
+	lengthMax  int // range maximum or normal standard deviation
+	dist       LengthDistribution
+	lengths    []weightedLength // candidates for lengthChoice
+	weight     int              // total weight of weighted length choices, 0 if uniform
+}
+
+// weightedLength is one candidate of a length choice list such as "8:70".
+type weightedLength struct {
+	length int
+	weight int
+}
+
+// keywordSpec is a keyword resolved against the engine configuration.
+type keywordSpec struct {
+	key      [16]byte
+	n        uint8
+	text     []byte // the keyword as written
+	arg      []byte
+	custom   customKeyword
+	params   []byte    // parameter list, as in HEX(len=32,upper=true)
+	charset  CharsList // inline character class
+	fallback bool      // unknown or disabled: a CharsAll string
+	excluded bool      // an exclusion removed every character
+	weight   int       // relative weight among keyword choices
+}
+
+func (k *keywordSpec) upper() string {
+	return unsafeString(k.key[:k.n])
+}
+
+// parseTag parses tag, which starts with "{RAND" and excludes the closing
+// brace. Length and keyword candidates are appended to the given scratch
+// slices. It reports false when the text is not a tag and must be copied to
+// the output unchanged.
+func (e *FastEngine) parseTag(tag []byte, lengths []weightedLength, keywords []keywordSpec) (tagSpec, bool) {
+	tag = bytes.TrimPrefix(tag[len(startTag):], startTagOpt)
+	var spec tagSpec
+	var ok bool
+	if spec.chance, tag, ok = parseChance(tag); !ok {
+		return spec, false
 	}
 
 	if len(tag) == 0 {
-		appendString(out, e.defaultLength, e.getCharset(kwABR, CharsAll))
-		return
+		spec.length = e.defaultLength
+		spec.keywords = append(keywords, keywordSpec{fallback: true})
+		return spec, true
 	}
-
 	if tag[0] != sepTag {
-		if e.outputEncoding == RandomizerEncodingNone {
-			*out = append(*out, startTag...)
-			if hasOpt {
-				*out = append(*out, startTagOpt...)
-			}
-			*out = append(*out, tag...)
-			*out = append(*out, endTag)
-			return
-		}
-		var tmp [128]byte
-		n := copy(tmp[:], startTag)
-		if hasOpt {
-			n += copy(tmp[n:], startTagOpt)
-		}
-		n += copy(tmp[n:], tag)
-		tmp[n] = endTag
-		n++
-		e.writeEncoded(out, tmp[:n])
-		return
+		return spec, false
 	}
-	tag = tag[1:]
+	tag, spec.variable = splitVariable(tag[1:])
+	tag, spec.caseMode = splitCaseModifier(tag)
+	tag, spec.source = splitSource(tag)
 
-	length := e.defaultLength
 	var typeKeyword, lenPart []byte
-
-	sepIndex := bytes.IndexByte(tag, sepTag)
-	if sepIndex == -1 {
+	if sepIndex := bytes.IndexByte(tag, sepTag); sepIndex == -1 {
 		lenPart = tag
 	} else {
 		lenPart = tag[:sepIndex]
 		typeKeyword = tag[sepIndex+1:]
 	}
+	if e.tooManyChoices(lenPart) || e.tooManyChoices(typeKeyword) {
+		return spec, false
+	}
+
+	if spec.lengthSpec, ok = e.parseLength(lenPart, lengths); !ok {
+		spec.length = e.defaultLength
+		if typeKeyword == nil {
+			typeKeyword = lenPart
+		}
+		if spec.length < e.minLength {
+			spec.length = e.minLength
+		}
+	}
+
+	if e.keywordChoicesEnabled && indexChoiceSep(typeKeyword) != -1 {
+		start, total, weighted := 0, 0, false
+		for {
+			idx := indexChoiceSep(typeKeyword[start:])
+			end := len(typeKeyword)
+			if idx != -1 {
+				end = start + idx
+			}
+			choice, weight, hasWeight := splitWeight(typeKeyword[start:end])
+			weighted = weighted || hasWeight
+			if weight > 0 && e.isKeywordValid(choice) {
+				kw := e.resolveKeyword(choice)
+				if kw.excluded {
+					return spec, false
+				}
+				kw.weight = weight
+				keywords = append(keywords, kw)
+				total += weight
+			}
+			if idx == -1 {
+				break
+			}
+			start = end + 1
+		}
+		if len(keywords) > 0 {
+			spec.keywordChoice = true
+			spec.keywords = keywords
+			if weighted {
+				spec.keywordWeight = total
+			}
+			return spec, true
+		}
+	}
+	kw := e.resolveKeyword(typeKeyword)
+	if kw.excluded {
+		return spec, false
+	}
+	spec.keywords = append(keywords, kw)
+	return spec, true
+}
+
+// parseLength parses the length part of a tag, appending length choices to
+// lengths, and reports whether it was a valid length specification.
+func (e *FastEngine) parseLength(lenPart []byte, lengths []weightedLength) (lengthSpec, bool) {
+	var spec lengthSpec
+	valid := func(l int) bool { return l >= e.minLength && l <= e.maxLength }
 
-	var lengthParsed bool
 	if e.lengthChoicesEnabled && bytes.IndexByte(lenPart, ',') != -1 {
-		var validLengths [16]int
-		validCount := 0
-		start := 0
+		start, total, weighted := 0, 0, false
 		for {
 			idx := bytes.IndexByte(lenPart[start:], ',')
-			var part []byte
+			end := len(lenPart)
+			if idx != -1 {
+				end = start + idx
+			}
+			choice, weight, hasWeight := splitWeight(lenPart[start:end])
+			weighted = weighted || hasWeight
+			if l, ok := parseLengthFast(choice); ok && valid(l) && weight > 0 {
+				lengths = append(lengths, weightedLength{length: l, weight: weight})
+				total += weight
+			}
 			if idx == -1 {
-				part = lenPart[start:]
-				if l, ok := parseLengthFast(part); ok && l >= e.minLength && l <= e.maxLength {
-					validLengths[validCount] = l
-					validCount++
-				}
 				break
 			}
-			part = lenPart[start : start+idx]
-			if l, ok := parseLengthFast(part); ok && l >= e.minLength && l <= e.maxLength {
-				validLengths[validCount] = l
-				validCount++
+			start = end + 1
+		}
+		if len(lengths) > 0 {
+			spec.lengthKind = lengthChoice
+			spec.lengths = lengths
+			if weighted {
+				spec.weight = total
 			}
-			start += idx + 1
+			return spec, true
 		}
+	}
 
-		if validCount > 0 {
-			length = validLengths[int(fastUint64N(uint64(validCount)))]
-			lengthParsed = true
+	if len(lenPart) > 0 && lenPart[0] == '~' {
+		if mean, stddev, ok := parseNormalLength(lenPart); ok && valid(mean) {
+			spec.lengthKind = lengthNormal
+			spec.length, spec.lengthMax = mean, stddev
+			return spec, true
 		}
 	}
 
-	if !lengthParsed && e.rangesEnabled && bytes.IndexByte(lenPart, '-') != -1 {
-		rangeSepIndex := bytes.IndexByte(lenPart, '-')
-		if rangeSepIndex != -1 {
+	if e.rangesEnabled {
+		if rangeSepIndex := bytes.IndexByte(lenPart, '-'); rangeSepIndex != -1 {
 			minPart := lenPart[:rangeSepIndex]
 			maxPart := lenPart[rangeSepIndex+1:]
+			dist := e.lengthDistribution
+			if i := bytes.IndexByte(maxPart, ':'); i != -1 {
+				if d, ok := parseLengthDistribution(maxPart[i+1:]); ok {
+					dist = d
+					maxPart = maxPart[:i]
+				}
+			}
 			if minX, ok1 := parseLengthFast(minPart); ok1 && minX >= e.minLength {
 				if maxX, ok2 := parseLengthFast(maxPart); ok2 && minX <= maxX && maxX <= e.maxLength {
-					length = int(fastUint64N(uint64(maxX-minX+1))) + minX
-					lengthParsed = true
+					spec.lengthKind = lengthRange
+					spec.length, spec.lengthMax = minX, maxX
+					spec.dist = dist
+					return spec, true
 				}
 			}
 		}
 	}
 
-	if !lengthParsed {
-		if l, ok := parseLengthFast(lenPart); ok && l >= e.minLength && l <= e.maxLength {
-			length = l
-		} else if typeKeyword == nil {
-			typeKeyword = lenPart
+	if l, ok := parseLengthFast(lenPart); ok && valid(l) {
+		spec.length = l
+		return spec, true
+	}
+	return spec, false
+}
+
+// pickChoice draws one of the length choices, by weight when they have
+// weights.
+func (s *lengthSpec) pickChoice(next func() uint64) int {
+	if s.weight == 0 {
+		return s.lengths[int(uint64N(next, uint64(len(s.lengths))))].length
+	}
+	r := int(uint64N(next, uint64(s.weight)))
+	for _, c := range s.lengths {
+		if r < c.weight {
+			return c.length
 		}
+		r -= c.weight
 	}
+	return s.lengths[len(s.lengths)-1].length
+}
 
-	if length < e.minLength {
-		length = e.minLength
+// resolveKeyword looks keyword up among the custom and enabled built-in
+// keywords. Charset keywords and classes may end in a "!chars" exclusion.
+func (e *FastEngine) resolveKeyword(keyword []byte) keywordSpec {
+	k := keywordSpec{text: keyword}
+	keyword, k.params = splitKeywordParams(keyword)
+	if name, excluded := splitExclusion(keyword); excluded != nil {
+		if k := e.resolveName(k, name); e.excludeChars(&k, excluded) {
+			return k
+		}
 	}
+	k = e.resolveName(k, keyword)
+	e.excludeChars(&k, nil)
+	return k
+}
 
-	if e.keywordChoicesEnabled && bytes.IndexByte(typeKeyword, ',') != -1 {
-		var validChoices [16][]byte
-		validCount := 0
-		start := 0
-		for {
-			idx := bytes.IndexByte(typeKeyword[start:], ',')
-			var choice []byte
-			if idx == -1 {
-				choice = typeKeyword[start:]
-				if e.isKeywordValid(choice) {
-					validChoices[validCount] = choice
-					validCount++
-				}
+// resolveName resolves the keyword or character class of k, whose text
+// and parameters are set.
+func (e *FastEngine) resolveName(k keywordSpec, keyword []byte) keywordSpec {
+	if isCharClass(keyword) {
+		if cs, ok := charClass(keyword); ok {
+			k.charset = cs
+		} else {
+			k.fallback = true
+		}
+		return k
+	}
+	name, arg := splitKeywordArg(keyword)
+	k.n = uint8(upperASCIIInto(k.key[:], name))
+	k.arg = arg
+	keywords := e.keywords.Load()
+	if gen, exists := keywords.custom[k.upper()]; exists {
+		k.custom = gen
+		return k
+	}
+	if cs, exists := e.registeredCharset(k.upper()); exists {
+		k.charset = cs
+		return k
+	}
+	if enabled, exists := keywords.enabled[k.upper()]; !exists || !enabled {
+		k.fallback = true
+	}
+	return k
+}
+
+// expandTag appends one expansion of spec to out.
+func (e *FastEngine) expandTag(out *[]byte, spec *tagSpec, x *expansion) {
+	if spec.chance.skip(e.next) {
+		if spec.variable != nil {
+			x.setVar(spec.variable, nil)
+		}
+		return
+	}
+	length := spec.length
+	switch spec.lengthKind {
+	case lengthChoice:
+		length = spec.pickChoice(e.next)
+	case lengthNormal:
+		length = normalLength(e.next, spec.length, spec.lengthMax, e.minLength, e.maxLength)
+	case lengthRange:
+		length = spec.dist.pick(e.next, spec.length, spec.lengthMax, e.defaultLength)
+	}
+
+	kw := &spec.keywords[0]
+	switch {
+	case spec.keywordWeight > 0:
+		r := int(uint64N(e.next, uint64(spec.keywordWeight)))
+		for i := range spec.keywords {
+			if r < spec.keywords[i].weight {
+				kw = &spec.keywords[i]
 				break
 			}
-			choice = typeKeyword[start : start+idx]
-			if e.isKeywordValid(choice) {
-				validChoices[validCount] = choice
-				validCount++
-			}
-			start += idx + 1
-		}
-		if validCount > 0 {
-			typeKeyword = validChoices[int(fastUint64N(uint64(validCount)))]
+			r -= spec.keywords[i].weight
 		}
+	case spec.keywordChoice:
+		kw = &spec.keywords[int(uint64N(e.next, uint64(len(spec.keywords))))]
+	}
+	start := len(*out)
+	if spec.source == nil || !e.deriveKeyword(out, kw, spec.source, x) {
+		e.expandKeyword(out, kw, e.paramLength(kw, length), x)
+	}
+	spec.caseMode.apply((*out)[start:])
+	if e.outputEncoding&valueEncodings != 0 && x.topLevel() {
+		e.escapeValueFrom(out, start)
 	}
+	if spec.variable != nil {
+		x.setVar(spec.variable, (*out)[start:])
+	}
+	if e.stats != nil {
+		e.stats.count(kw)
+	}
+	if e.onReplace != nil {
+		e.onReplace(keywordName(kw), length, (*out)[start:])
+	}
+}
 
-	var upperKey string
-	if len(e.customKeywords) > 0 || !e.isBuiltinKeywordEnabled(typeKeyword) {
-		var key [16]byte
-		n := upperASCIIInto(key[:], typeKeyword)
-		upperKey = unsafeString(key[:n])
-		if customGen, exists := e.customKeywords[upperKey]; exists {
-			*out = append(*out, customGen(length)...)
+func (e *FastEngine) expandKeyword(out *[]byte, kw *keywordSpec, length int, x *expansion) {
+	if kw.custom != nil {
+		start := len(*out)
+		*out = kw.custom(*out, KeywordContext{Length: length, Arg: kw.arg, Params: kw.params, engine: e})
+		e.expandGeneratedFrom(out, start, x)
+		return
+	}
+	if kw.charset != nil {
+		e.appendString(out, length, kw.charset)
+		return
+	}
+	if kw.fallback {
+		if x.fallback != nil && len(kw.text) > 0 {
+			e.appendDelegated(out, kw, length, x)
 			return
 		}
-		enabled, exists := e.enabledKeywords[upperKey]
-		if !exists || !enabled {
-			appendString(out, length, e.getCharset(kwABR, CharsAll))
+		if e.unknownKeywords == UnknownKeywordEmpty && len(kw.text) > 0 {
 			return
 		}
-	} else {
-		var key [16]byte
-		n := upperASCIIInto(key[:], typeKeyword)
-		upperKey = unsafeString(key[:n])
+		e.appendString(out, length, e.getCharset(kwABR, CharsAll))
+		return
 	}
+	keywordArg := kw.arg
 
-	switch upperKey {
+	switch kw.upper() {
 	case "ABL":
-		appendString(out, length, e.getCharset(kwABL, CharsAlphabetLower))
+		e.appendString(out, length, e.getCharset(kwABL, CharsAlphabetLower))
 	case "ABU":
-		appendString(out, length, e.getCharset(kwABU, CharsAlphabetUpper))
+		e.appendString(out, length, e.getCharset(kwABU, CharsAlphabetUpper))
 	case "ABR":
-		appendString(out, length, e.getCharset(kwABR, CharsAlphabet))
+		e.appendString(out, length, e.getCharset(kwABR, CharsAlphabet))
 	case "DIGIT":
-		appendString(out, length, e.getCharset(kwDIGIT, CharsDigits))
+		e.appendString(out, length, e.getCharset(kwDIGIT, CharsDigits))
 	case "NULL":
 		nullCharset := e.getCharset(kwNULL, CharsNull)
 		nsLen := len(nullCharset)
 		if nsLen <= 256 {
 			for i := 0; i < length; i++ {
-				*out = append(*out, nullCharset[fastUint8N(uint8(nsLen))])
+				*out = append(*out, nullCharset[uint8N(e.next, uint8(nsLen))])
 			}
 		} else {
 			for i := 0; i < length; i++ {
-				*out = append(*out, nullCharset[int(fastUint64N(uint64(nsLen)))])
+				*out = append(*out, nullCharset[int(uint64N(e.next, uint64(nsLen)))])
 			}
 		}
 	case "SPACE":
@@ -378,26 +891,148 @@ func (e *FastEngine) parseAndReplaceFast(tag []byte, out *[]byte) {
 			(*out)[i] = ' '
 		}
 	case "UUID":
-		appendUUID(out)
+		start := len(*out)
+		e.appendUUID(out)
+		e.applyUpperParam(out, start, kw)
+	case "UUIDV7":
+		start := len(*out)
+		e.appendUUIDv7(out)
+		e.applyUpperParam(out, start, kw)
 	case "BYTES":
-		*out = append(*out, Bytes(length)...)
+		e.appendBytes(out, length)
 	case "IPV4":
-		appendIPv4(out)
+		if len(keywordArg) > 0 {
+			e.appendIPv4Prefixed(out, keywordArg)
+		} else {
+			e.appendIPv4(out)
+		}
 	case "IPV6":
-		appendIPv6(out)
+		if len(keywordArg) > 0 {
+			e.appendIPv6Prefixed(out, keywordArg)
+		} else {
+			e.appendIPv6(out)
+		}
 	case "EMAIL":
-		e.appendRandomEmail(out, length)
+		provider, _ := keywordParam(kw.params, "provider")
+		e.appendRandomEmail(out, length, provider)
 	case "HEX":
-		appendHex(out, length, e.defaultLength)
+		start := len(*out)
+		e.appendHex(out, length, e.defaultLength)
+		e.applyUpperParam(out, start, kw)
+	case "SEQ":
+		e.appendSequence(out, keywordArg, kw.params)
+	case "CYCLE":
+		e.appendCycle(out, length, keywordArg, x)
+	case "XML":
+		e.appendXML(out, length)
+	case "FORM":
+		e.appendFormValue(out, keywordArg)
+	case "IDENT":
+		e.appendIdentifier(out, length)
+	case "K8SNAME":
+		e.appendK8sName(out)
+	case "ADJ":
+		e.appendWord(out, adjectives)
+	case "NOUN":
+		e.appendWord(out, nouns)
+	case "VERB":
+		e.appendWord(out, verbs)
+	case "NAME":
+		e.appendName(out)
+	case "FIRSTNAME":
+		e.appendWord(out, e.firstNames)
+	case "LASTNAME":
+		e.appendWord(out, e.lastNames)
+	case "USERNAME":
+		e.appendUsername(out)
+	case "WORD":
+		e.appendWord(out, fillerWords)
+	case "WORDS":
+		appendWords(e.next, out, length)
+	case "SENTENCE":
+		appendSentence(e.next, out, length)
+	case "PASSWORD":
+		e.appendPassword(out, length, kw)
+	case "JWT":
+		e.appendJWT(out, kw)
+	case "COLOR":
+		e.appendColor(out, keywordArg)
+	case "GEO":
+		e.appendGeo(out, kw)
+	case "FILENAME":
+		e.appendFilename(out, kw)
+	case "PATH":
+		e.appendPath(out, kw)
+	case "MIME":
+		e.appendMIME(out, keywordArg)
+	case "HTTPMETHOD":
+		e.appendHTTPMethod(out, keywordArg)
+	case "HEADERVAL":
+		e.appendHeaderValue(out, length, keywordArg)
+	case "HOSTNAME":
+		e.appendHostnameKeyword(out, length, keywordArg)
+	case "COUNTRY":
+		e.appendCountry(out, keywordArg)
+	case "LOCALE":
+		e.appendWord(out, locales)
+	case "CURRENCY":
+		e.appendWord(out, currencies)
+	case "TZ":
+		e.appendWord(out, timeZones)
+	case "DNSQ":
+		e.appendDNSQuery(out, keywordArg)
+	case "HTTPREQ":
+		e.appendHTTPRequestLine(out)
+	case "SMTP":
+		e.appendSMTPCommand(out)
+	case "TIMESTAMP":
+		e.appendTimestamp(out, kw.params)
+	case "DATE":
+		e.appendDate(out, kw.params)
+	case "TIME":
+		e.appendTime(out, kw.params)
+	case "FLOAT":
+		e.appendFloat(out, keywordArg)
+	case "PICK":
+		e.appendPick(out, keywordArg)
+	case "BASE32":
+		e.expandBase32(out, length, kw)
+	case "ULID":
+		e.appendULID(out)
+	case "MAC":
+		e.appendMAC(out, kw)
+	case "DOMAIN":
+		e.appendDomain(out, kw)
+	case "URL":
+		e.appendURL(out, kw)
+	case "UA":
+		e.appendUserAgent(out)
+	case "PHONE":
+		e.appendPhone(out, keywordArg)
+	case "CC":
+		e.appendCard(out, keywordArg)
+	case "CCEXP":
+		e.appendCardExpiry(out, e.now())
+	case "CCCVV":
+		e.appendCardCVV(out, keywordArg)
 	default:
-		appendString(out, length, e.getCharset(kwABR, CharsAll))
+		e.appendString(out, length, e.getCharset(kwABR, CharsAll))
+	}
+}
+
+// splitKeywordArg splits a keyword of the form NAME:arg into its name and
+// argument. The argument keeps its original case.
+func splitKeywordArg(keyword []byte) (name, arg []byte) {
+	if i := bytes.IndexByte(keyword, ':'); i != -1 {
+		return keyword[:i], keyword[i+1:]
 	}
+	return keyword, nil
 }
 
 func (e *FastEngine) isBuiltinKeywordEnabled(keyword []byte) bool {
 	var key [16]byte
 	n := upperASCIIInto(key[:], keyword)
-	enabled, exists := e.enabledKeywords[unsafeString(key[:n])]
+	enabled, exists := e.keywords.Load().enabled[unsafeString(key[:n])]
 	return exists && enabled
 }
 
@@ -417,14 +1052,23 @@ func upperASCIIInto(dst, src []byte) int {
 }
 
 func (e *FastEngine) isKeywordValid(choice []byte) bool {
+	choice, _ = splitKeywordParams(choice)
+	if isCharClass(choice) {
+		_, ok := charClass(choice)
+		return ok
+	}
+	choice, _ = splitKeywordArg(choice)
 	var key [16]byte
 	n := upperASCIIInto(key[:], choice)
 	k := unsafeString(key[:n])
-	if _, isCustom := e.customKeywords[k]; isCustom {
+	keywords := e.keywords.Load()
+	if _, isCustom := keywords.custom[k]; isCustom {
+		return true
+	}
+	if _, isCharset := e.registeredCharset(k); isCharset {
 		return true
 	}
-	isEnabled := e.enabledKeywords[k]
-	return isEnabled
+	return keywords.enabled[k]
 }
 
 func ensureCap(out *[]byte, n int) {
@@ -435,7 +1079,7 @@ func ensureCap(out *[]byte, n int) {
 	}
 }
 
-func appendString(out *[]byte, length int, charset CharsList) {
+func (e *FastEngine) appendString(out *[]byte, length int, charset CharsList) {
 	if length <= 0 {
 		return
 	}
@@ -446,14 +1090,36 @@ func appendString(out *[]byte, length int, charset CharsList) {
 	start := len(*out)
 	ensureCap(out, start+length)
 	*out = (*out)[:start+length]
-	fillStringInto((*out)[start:], charset, csLen)
+	fillStringInto(e.next, (*out)[start:], charset, csLen)
 }
 
-func appendUUID(out *[]byte) {
+func (e *FastEngine) appendBytes(out *[]byte, length int) {
+	if length <= 0 {
+		return
+	}
+	start := len(*out)
+	ensureCap(out, start+length)
+	*out = (*out)[:start+length]
+	fillBytes(e.next, (*out)[start:])
+}
+
+func (e *FastEngine) appendUUID(out *[]byte) {
 	var raw [16]byte
-	FillBytes(raw[:])
+	fillBytes(e.next, raw[:])
 	raw[6] = (raw[6] & 0x0f) | 0x40
 	raw[8] = (raw[8] & 0x3f) | 0x80
+	appendUUIDText(out, &raw)
+}
+
+func (e *FastEngine) appendUUIDv7(out *[]byte) {
+	var raw [16]byte
+	fillBytes(e.next, raw[6:])
+	setUUIDv7(&raw, e.now())
+	appendUUIDText(out, &raw)
+}
+
+// appendUUIDText appends the canonical 8-4-4-4-12 hex form of raw.
+func appendUUIDText(out *[]byte, raw *[16]byte) {
 	start := len(*out)
 	ensureCap(out, start+36)
 	*out = (*out)[:start+36]
@@ -469,7 +1135,7 @@ func appendUUID(out *[]byte) {
 	hex.Encode(b[24:], raw[10:])
 }
 
-func appendHex(out *[]byte, byteLength, defaultLen int) {
+func (e *FastEngine) appendHex(out *[]byte, byteLength, defaultLen int) {
 	if byteLength <= 0 {
 		byteLength = defaultLen
 	}
@@ -477,12 +1143,12 @@ func appendHex(out *[]byte, byteLength, defaultLen int) {
 	start := len(*out)
 	ensureCap(out, start+hexLen)
 	*out = (*out)[:start+hexLen]
-	FillHex((*out)[start:])
+	fillHex(e.next, (*out)[start:])
 }
 
-func appendIPv4(out *[]byte) {
+func (e *FastEngine) appendIPv4(out *[]byte) {
 	var raw [4]byte
-	FillBytes(raw[:])
+	fillBytes(e.next, raw[:])
 	appendUintByte(out, raw[0])
 	*out = append(*out, '.')
 	appendUintByte(out, raw[1])
@@ -504,9 +1170,14 @@ func appendUintByte(out *[]byte, v byte) {
 	*out = append(*out, '0'+v/100, '0'+(v/10)%10, '0'+v%10)
 }
 
-func appendIPv6(out *[]byte) {
+func (e *FastEngine) appendIPv6(out *[]byte) {
 	var raw [16]byte
-	FillBytes(raw[:])
+	fillBytes(e.next, raw[:])
+	appendIPv6Addr(out, &raw)
+}
+
+// appendIPv6Addr appends raw as eight uncompressed colon-separated groups.
+func appendIPv6Addr(out *[]byte, raw *[16]byte) {
 	for i := 0; i < 8; i++ {
 		if i > 0 {
 			*out = append(*out, ':')
@@ -531,24 +1202,48 @@ func appendHexUint16(out *[]byte, val uint16) {
 	*out = append(*out, buf[pos:]...)
 }
 
-func (e *FastEngine) appendRandomEmail(out *[]byte, userLength int) {
+// appendRandomEmail appends an address at provider, or at a random mail
+// provider when provider is empty.
+func (e *FastEngine) appendRandomEmail(out *[]byte, userLength int, provider []byte) {
 	if userLength <= 0 {
 		userLength = 8
 	}
-	provider := "gmail.com"
-	if len(e.mailProviders) > 0 {
-		provider = e.mailProviders[int(fastUint64N(uint64(len(e.mailProviders))))]
+	if len(provider) == 0 {
+		provider = e.randomMailProvider()
 	}
 	totalLen := userLength + 1 + len(provider)
 	start := len(*out)
 	ensureCap(out, start+totalLen)
 	*out = (*out)[:start+totalLen]
 	b := (*out)[start:]
-	fillStringInto(b[:userLength], e.getCharset(kwABL, CharsAlphabetLower), len(CharsAlphabetLower))
+	fillStringInto(e.next, b[:userLength], e.getCharset(kwABL, CharsAlphabetLower), len(CharsAlphabetLower))
 	b[userLength] = '@'
 	copy(b[userLength+1:], provider)
 }
 
+// randomMailProvider returns one of the engine's mail providers, or
+// gmail.com when it has none.
+func (e *FastEngine) randomMailProvider() []byte {
+	if len(e.mailProviders) == 0 {
+		return []byte("gmail.com")
+	}
+	return s2b(e.mailProviders[int(uint64N(e.next, uint64(len(e.mailProviders))))])
+}
+
+// applyUpperParam upper-cases the hex digits appended since start when the
+// keyword has upper=true.
+func (e *FastEngine) applyUpperParam(out *[]byte, start int, kw *keywordSpec) {
+	if v, ok := keywordParam(kw.params, "upper"); !ok || !paramBool(v) {
+		return
+	}
+	b := (*out)[start:]
+	for i, c := range b {
+		if c >= 'a' && c <= 'f' {
+			b[i] = c - 32
+		}
+	}
+}
+
 func strconvAppendUint(b []byte, val uint64, base int) []byte {
 	var buf [20]byte
 	pos := strconvPutUint(buf[:], val, base)
@@ -572,7 +1267,7 @@ func strconvPutUint(buf []byte, val uint64, base int) int {
 }
 
 func (e *FastEngine) getCharset(keyword []byte, fallback CharsList) CharsList {
-	if cs, ok := e.customCharsets[unsafeString(keyword)]; ok {
+	if cs, ok := e.keywords.Load().charsets[unsafeString(keyword)]; ok {
 		return cs
 	}
 	return fallback
@@ -605,7 +1300,7 @@ type normalizer struct {
 func (n *normalizer) run() []byte {
 	cursor := 0
 	for cursor < len(n.payload) {
-		idx := bytes.IndexAny(n.payload[cursor:], "%&")
+		idx := bytes.IndexAny(n.payload[cursor:], encodedChars)
 		if idx == -1 {
 			n.out = append(n.out, n.payload[cursor:]...)
 			break
@@ -643,6 +1338,14 @@ func (n *normalizer) run() []byte {
 				n.out = append(n.out, char)
 				cursor++
 			}
+		} else if char == '\\' && (n.encodingFlags&RandomizerEncodingUnicode != 0) {
+			if c, size, ok := unicodeEscape(n.payload[cursor:]); ok && (c != '{' || hasPrefix(n.payload, startTag[1:], cursor+size)) {
+				n.out = append(n.out, c)
+				cursor += size
+			} else {
+				n.out = append(n.out, char)
+				cursor++
+			}
 		} else {
 			n.out = append(n.out, char)
 			cursor++
@@ -651,14 +1354,48 @@ func (n *normalizer) run() []byte {
 	return n.out
 }
 
-func normalize(payload []byte, encodingFlags RandomizerEncoding) []byte {
-	if !bytes.ContainsAny(payload, "%&") {
-		return payload
+func fromHexChar(c byte) (byte, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0', true
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10, true
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
+// unicodeEscape decodes a \u00XX or \xXX escape of '{', '}' or ';' at the
+// start of b and returns the byte and the length of the escape.
+func unicodeEscape(b []byte) (byte, int, bool) {
+	var size int
+	switch {
+	case len(b) >= 6 && b[1] == 'u' && b[2] == '0' && b[3] == '0':
+		size = 6
+	case len(b) >= 4 && b[1] == 'x':
+		size = 4
+	default:
+		return 0, 0, false
+	}
+	hi, ok1 := fromHexChar(b[size-2])
+	lo, ok2 := fromHexChar(b[size-1])
+	if !ok1 || !ok2 {
+		return 0, 0, false
+	}
+	switch c := hi<<4 | lo; c {
+	case '{', endTag, sepTag:
+		return c, size, true
 	}
+	return 0, 0, false
+}
+
+// normalizeInto appends the decoded payload to dst.
+func normalizeInto(dst, payload []byte, encodingFlags RandomizerEncoding) []byte {
 	n := normalizer{
 		payload:       payload,
 		encodingFlags: encodingFlags,
-		out:           make([]byte, 0, len(payload)),
+		out:           dst,
 	}
 	return n.run()
 }
@@ -670,18 +1407,8 @@ func hasPrefix(slice, prefix []byte, pos int) bool {
 	return bytes.Equal(slice[pos:pos+len(prefix)], prefix)
 }
 
-func normalizeString(payload string, encodingFlags RandomizerEncoding) []byte {
-	if !strings.ContainsAny(payload, "%&") {
-		return []byte(payload)
-	}
-	n := normalizer{
-		payload:       s2b(payload),
-		encodingFlags: encodingFlags,
-		out:           make([]byte, 0, len(payload)),
-	}
-	return n.run()
-}
-
+// parseLengthFast parses a decimal length of one to nine digits, with
+// unrolled cases for the common short lengths.
 func parseLengthFast(b []byte) (int, bool) {
 	switch len(b) {
 	case 1:
@@ -699,6 +1426,15 @@ func parseLengthFast(b []byte) (int, bool) {
 		if c1 >= '0' && c1 <= '9' && c2 >= '0' && c2 <= '9' && c3 >= '0' && c3 <= '9' {
 			return int(c1-'0')*100 + int(c2-'0')*10 + int(c3-'0'), true
 		}
+	case 4, 5, 6, 7, 8, 9:
+		v := 0
+		for _, c := range b {
+			if c < '0' || c > '9' {
+				return 0, false
+			}
+			v = v*10 + int(c-'0')
+		}
+		return v, true
 	}
 	return 0, false
 }
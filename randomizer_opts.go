@@ -1,6 +1,16 @@
 package fastrand
 
-import "strings"
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
 
 type Engine interface {
 	Randomizer([]byte) []byte
@@ -16,20 +26,40 @@ type FastEngine struct {
 	rangesEnabled         bool
 	keywordChoicesEnabled bool
 	lengthChoicesEnabled  bool
-	enabledKeywords       map[string]bool
+	keywords              atomic.Pointer[keywordTable]
 	mailProviders         []string
-	customCharsets        map[string][]byte
-	customKeywords        map[string]CustomKeywordGenerator
+	userAgents            []string
+	firstNames            []string
+	lastNames             []string
+	next                  func() uint64
+	seqMu                 sync.Mutex
+	sequences             map[string]*Sequence
+	cycles                map[string]*Cycle[[]byte]
+	xmlNames              []string
+	lengthDistribution    LengthDistribution
+	bufferPool            BufferPool
+	sizeEstimate          atomic.Int64
+	strictParsing         bool
+	requireKeyword        bool
+	maxExpansionDepth     int
+	maxOutputSize         int
+	maxTags               int
+	maxChoices            int
+	unknownKeywords       UnknownKeywordPolicy
+	registryMu            sync.Mutex
+	charsets              atomic.Pointer[map[string]CharsList]
+	outputEncoder         OutputEncoder
+	outputChain           []RandomizerEncoding
+	inputNormalizer       func([]byte) []byte
+	dialects              []Dialect
+	stats                 *engineStats
+	onReplace             ReplaceHook
+	clock                 func() time.Time
 }
 
 type Option func(*FastEngine)
 
 func NewEngine(opts ...Option) *FastEngine {
-	enabledKeywords := make(map[string]bool, len(allKeywords))
-	for _, kw := range allKeywords {
-		enabledKeywords[kw] = true
-	}
-
 	e := &FastEngine{
 		defaultLength:         16,
 		minLength:             1,
@@ -39,11 +69,16 @@ func NewEngine(opts ...Option) *FastEngine {
 		rangesEnabled:         true,
 		keywordChoicesEnabled: true,
 		lengthChoicesEnabled:  true,
-		enabledKeywords:       enabledKeywords,
 		mailProviders:         SafeMailProviders,
-		customCharsets:        make(map[string][]byte),
-		customKeywords:        make(map[string]CustomKeywordGenerator),
+		userAgents:            SafeUserAgents,
+		firstNames:            SafeFirstNames,
+		lastNames:             SafeLastNames,
+		next:                  fastUint64,
+		sequences:             make(map[string]*Sequence),
+		cycles:                make(map[string]*Cycle[[]byte]),
+		bufferPool:            defaultBufferPool,
 	}
+	e.keywords.Store(newKeywordTable())
 
 	for _, opt := range opts {
 		opt(e)
@@ -52,7 +87,105 @@ func NewEngine(opts ...Option) *FastEngine {
 	return e
 }
 
+// NewEngineStrict is like NewEngine but rejects inconsistent
+// configuration instead of accepting it and producing surprising output at
+// call time: a minimum length above the maximum, a default length outside
+// [min, max], empty custom charsets, exclusions that leave a charset empty
+// and nil custom keyword generators. The error lists every problem found.
+func NewEngineStrict(opts ...Option) (*FastEngine, error) {
+	e := NewEngine(opts...)
+	if err := e.validate(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// validate reports the problems in the engine's configuration.
+func (e *FastEngine) validate() error {
+	var errs []error
+	if e.minLength > e.maxLength {
+		errs = append(errs, fmt.Errorf("fastrand: min length %d exceeds max length %d", e.minLength, e.maxLength))
+	}
+	if e.defaultLength < e.minLength || e.defaultLength > e.maxLength {
+		errs = append(errs, fmt.Errorf("fastrand: default length %d is outside [%d, %d]", e.defaultLength, e.minLength, e.maxLength))
+	}
+	keywords := e.keywords.Load()
+	for _, kw := range slices.Sorted(maps.Keys(keywords.charsets)) {
+		if len(keywords.charsets[kw]) == 0 {
+			errs = append(errs, fmt.Errorf("fastrand: custom charset %q is empty", kw))
+		}
+	}
+	for _, kw := range slices.Sorted(maps.Keys(keywords.excluded)) {
+		cs, ok := e.registeredCharset(kw)
+		if !ok {
+			cs, ok = e.builtinCharset(kw)
+		}
+		if ok && len(withoutChars(cs, s2b(keywords.excluded[kw]))) == 0 {
+			errs = append(errs, fmt.Errorf("fastrand: excluded characters %q leave keyword %q no characters", keywords.excluded[kw], kw))
+		}
+	}
+	custom := keywords.custom
+	for _, kw := range slices.Sorted(maps.Keys(custom)) {
+		if custom[kw] == nil {
+			errs = append(errs, fmt.Errorf("fastrand: custom keyword %q has a nil generator", kw))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Reset restores the engine to the state NewEngine() returns, dropping
+// every option and registration. Unlike RegisterKeyword and the other
+// runtime setters it modifies the engine in place, so it must not be called
+// while the engine is in use.
 func (e *FastEngine) Reset() {
+	e.resetSettings()
+	e.mailProviders = SafeMailProviders
+	e.userAgents = SafeUserAgents
+	e.firstNames = SafeFirstNames
+	e.lastNames = SafeLastNames
+	e.next = fastUint64
+	e.xmlNames = nil
+	e.bufferPool = defaultBufferPool
+	e.charsets.Store(nil)
+	e.stats = nil
+	e.onReplace = nil
+	e.clock = nil
+	keywords := e.keywords.Load()
+	for k := range keywords.custom {
+		delete(keywords.custom, k)
+	}
+	for k := range keywords.charsets {
+		delete(keywords.charsets, k)
+	}
+	for k := range keywords.excluded {
+		delete(keywords.excluded, k)
+	}
+	e.seqMu.Lock()
+	for k := range e.sequences {
+		delete(e.sequences, k)
+	}
+	e.seqMu.Unlock()
+	for k := range e.cycles {
+		delete(e.cycles, k)
+	}
+}
+
+// ResetDefaultsOnly restores the engine's settings to their NewEngine
+// defaults while keeping what has been registered with it. Lengths, input
+// and output encodings, input normalizers, dialects, ranges and choices,
+// the length distribution, strict parsing, required keywords, the unknown
+// keyword policy, expansion depth, size and tag limits and disabled
+// keywords are reset. Custom keywords, custom and registered charsets,
+// excluded characters, mail providers, XML element names, sequences and
+// cycles are kept, as are the random source, buffer pool, Stats counters
+// and WithOnReplace hook. Like Reset it must not be called while the
+// engine is in use.
+func (e *FastEngine) ResetDefaultsOnly() {
+	e.resetSettings()
+}
+
+// resetSettings restores the settings ResetDefaultsOnly resets.
+func (e *FastEngine) resetSettings() {
 	e.defaultLength = 16
 	e.minLength = 1
 	e.maxLength = 99
@@ -61,16 +194,86 @@ func (e *FastEngine) Reset() {
 	e.rangesEnabled = true
 	e.keywordChoicesEnabled = true
 	e.lengthChoicesEnabled = true
-	e.mailProviders = SafeMailProviders
-	for k := range e.enabledKeywords {
-		e.enabledKeywords[k] = true
+	e.lengthDistribution = LengthUniform
+	e.sizeEstimate.Store(0)
+	e.strictParsing = false
+	e.requireKeyword = false
+	e.maxExpansionDepth = 0
+	e.maxOutputSize = 0
+	e.maxTags = 0
+	e.maxChoices = 0
+	e.unknownKeywords = UnknownKeywordFallback
+	e.outputEncoder = nil
+	e.outputChain = nil
+	e.inputNormalizer = nil
+	e.dialects = nil
+	keywords := e.keywords.Load()
+	for k := range keywords.enabled {
+		keywords.enabled[k] = true
+	}
+}
+
+// Clone returns a copy of the engine whose keyword, charset, provider,
+// sequence and cycle tables are independent of e's, so per-tenant engines
+// can be derived from a configured base and then adjusted without
+// affecting it. Sequences and cycles continue from their current position,
+// while Stats counters start from zero. The clone shares e's random source,
+// buffer pool and hooks.
+func (e *FastEngine) Clone() *FastEngine {
+	c := &FastEngine{
+		defaultLength:         e.defaultLength,
+		minLength:             e.minLength,
+		maxLength:             e.maxLength,
+		inputEncoding:         e.inputEncoding,
+		outputEncoding:        e.outputEncoding,
+		rangesEnabled:         e.rangesEnabled,
+		keywordChoicesEnabled: e.keywordChoicesEnabled,
+		lengthChoicesEnabled:  e.lengthChoicesEnabled,
+		mailProviders:         slices.Clone(e.mailProviders),
+		userAgents:            slices.Clone(e.userAgents),
+		firstNames:            slices.Clone(e.firstNames),
+		lastNames:             slices.Clone(e.lastNames),
+		next:                  e.next,
+		sequences:             make(map[string]*Sequence),
+		cycles:                make(map[string]*Cycle[[]byte], len(e.cycles)),
+		xmlNames:              slices.Clone(e.xmlNames),
+		lengthDistribution:    e.lengthDistribution,
+		bufferPool:            e.bufferPool,
+		strictParsing:         e.strictParsing,
+		requireKeyword:        e.requireKeyword,
+		maxExpansionDepth:     e.maxExpansionDepth,
+		maxOutputSize:         e.maxOutputSize,
+		maxTags:               e.maxTags,
+		maxChoices:            e.maxChoices,
+		unknownKeywords:       e.unknownKeywords,
+		outputEncoder:         e.outputEncoder,
+		outputChain:           e.outputChain,
+		inputNormalizer:       e.inputNormalizer,
+		dialects:              slices.Clone(e.dialects),
+		onReplace:             e.onReplace,
+		clock:                 e.clock,
+	}
+	if e.stats != nil {
+		c.stats = newEngineStats()
+	}
+	keywords := e.keywords.Load().clone()
+	for k, v := range keywords.charsets {
+		keywords.charsets[k] = bytes.Clone(v)
 	}
-	for k := range e.customCharsets {
-		delete(e.customCharsets, k)
+	c.keywords.Store(keywords)
+	e.seqMu.Lock()
+	for k, s := range e.sequences {
+		c.sequences[k] = newSequence(s.pending.Load(), s.gapMax, c.uint64)
 	}
-	for k := range e.customKeywords {
-		delete(e.customKeywords, k)
+	e.seqMu.Unlock()
+	for k, cy := range e.cycles {
+		n := &Cycle[[]byte]{items: cy.items}
+		n.pos.Store(cy.pos.Load())
+		c.cycles[k] = n
 	}
+	c.charsets.Store(e.charsets.Load())
+	c.sizeEstimate.Store(e.sizeEstimate.Load())
+	return c
 }
 
 func (e *FastEngine) MailProviders() []string {
@@ -93,6 +296,10 @@ func WithMinLength(length int) Option {
 	}
 }
 
+// WithMaxLength sets the largest length a tag may ask for (default 99).
+// Lengths of up to nine digits are parsed, so raising it to, say, 65536
+// allows tags like {RAND;4096;BYTES} for padding payloads; larger lengths
+// fall back to the default length.
 func WithMaxLength(length int) Option {
 	return func(e *FastEngine) {
 		if length > 0 {
@@ -104,7 +311,7 @@ func WithMaxLength(length int) Option {
 func WithDisabledKeywords(keywords ...string) Option {
 	return func(e *FastEngine) {
 		for _, kw := range keywords {
-			e.enabledKeywords[strings.ToUpper(kw)] = false
+			e.keywords.Load().enabled[strings.ToUpper(kw)] = false
 		}
 	}
 }
@@ -125,13 +332,13 @@ func WithMailProviders(providers ...string) Option {
 
 func WithCustomCharset(keyword string, charset []byte) Option {
 	return func(e *FastEngine) {
-		e.customCharsets[strings.ToUpper(keyword)] = charset
+		e.keywords.Load().charsets[strings.ToUpper(keyword)] = charset
 	}
 }
 
 func WithCustomKeyword(keyword string, generator CustomKeywordGenerator) Option {
 	return func(e *FastEngine) {
-		e.customKeywords[strings.ToUpper(keyword)] = generator
+		e.keywords.Load().custom[strings.ToUpper(keyword)] = generator.custom()
 	}
 }
 
@@ -141,18 +348,197 @@ func WithInputEncoding(encoding RandomizerEncoding) Option {
 	}
 }
 
+// WithInputNormalizer passes every payload through fn before tags are
+// scanned, so templates in encodings the engine does not know, such as
+// quoted-printable or a custom wrapper, can be decoded first. The built-in
+// input encoding is applied to fn's result; combine with
+// WithInputEncoding(RandomizerEncodingNone) to use fn alone. fn must not
+// modify its argument, and RandomizerReader calls it on each chunk read
+// from the underlying reader, so it should not rely on seeing whole
+// encoded sequences there. A nil fn removes the normalizer.
+func WithInputNormalizer(fn func([]byte) []byte) Option {
+	return func(e *FastEngine) {
+		e.inputNormalizer = fn
+	}
+}
+
 func WithOutputEncoding(encoding RandomizerEncoding) Option {
 	return func(e *FastEngine) {
 		e.outputEncoding = encoding
 	}
 }
 
+// WithOutputEncodings applies several output encodings to the
+// non-placeholder portions of output in order, so
+// WithOutputEncodings(RandomizerEncodingURL, RandomizerEncodingBase64)
+// URL-encodes template text and then base64-encodes the result, as stacked
+// injection test cases need. RandomizerEncodingJSON and
+// RandomizerEncodingXML in the list escape generated values as they do with
+// WithOutputEncoding. It replaces any
+// earlier output encoding or encoder.
+func WithOutputEncodings(encodings ...RandomizerEncoding) Option {
+	return func(e *FastEngine) {
+		var chain []RandomizerEncoding
+		e.outputEncoding = RandomizerEncodingNone
+		e.outputEncoder = nil
+		e.outputChain = nil
+		for _, enc := range encodings {
+			e.outputEncoding |= enc & valueEncodings
+			if enc &^= valueEncodings; enc != RandomizerEncodingNone {
+				chain = append(chain, enc)
+			}
+		}
+		switch len(chain) {
+		case 0:
+		case 1:
+			e.outputEncoding |= chain[0]
+		default:
+			e.outputEncoder = e.chainEncoder(chain)
+			e.outputChain = chain
+		}
+	}
+}
+
+// OutputEncoder appends an encoded form of src, a run of template text, to
+// dst.
+type OutputEncoder func(dst *[]byte, src []byte)
+
+// WithOutputEncoder encodes the non-placeholder portions of output with enc
+// instead of the built-in URL or HTML encoding, for schemes such as
+// punycode or custom WAF-evasion encodings. RandomizerEncodingJSON and
+// RandomizerEncodingXML still apply to generated values. A nil enc restores
+// the built-in encodings.
+func WithOutputEncoder(enc OutputEncoder) Option {
+	return func(e *FastEngine) {
+		e.outputEncoder = enc
+		e.outputChain = nil
+	}
+}
+
 func WithRanges(enabled bool) Option {
 	return func(e *FastEngine) {
 		e.rangesEnabled = enabled
 	}
 }
 
+// WithLengthDistribution sets how lengths are drawn from range tags that do
+// not name a distribution themselves. The default is LengthUniform.
+func WithLengthDistribution(d LengthDistribution) Option {
+	return func(e *FastEngine) {
+		e.lengthDistribution = d
+	}
+}
+
+// WithBufferPool makes the engine take its scratch buffers from pool
+// instead of the default sync.Pool, for example to use size-classed or
+// arena-backed buffers. A nil pool keeps the default.
+func WithBufferPool(pool BufferPool) Option {
+	return func(e *FastEngine) {
+		if pool != nil {
+			e.bufferPool = pool
+		}
+	}
+}
+
+// WithStrictParsing makes RandomizerErr and Compile reject malformed tags
+// with a *TagError for each, joined with errors.Join when there are
+// several, instead of passing them through or substituting defaults. Randomizer and the other methods without an error result stay
+// lenient.
+func WithStrictParsing(enabled bool) Option {
+	return func(e *FastEngine) {
+		e.strictParsing = enabled
+	}
+}
+
+// WithRequireKeyword makes tags that name no keyword, such as {RAND} and
+// {RAND;8}, count as malformed instead of yielding CharsAll characters, so
+// a keyword forgotten in a template does not turn into random symbols.
+// Such tags are copied verbatim, or reported as a *TagError under
+// WithStrictParsing.
+func WithRequireKeyword(enabled bool) Option {
+	return func(e *FastEngine) {
+		e.requireKeyword = enabled
+	}
+}
+
+// WithMaxExpansionDepth lets values produced by custom keywords and CYCLE
+// lists contain further {RAND;...} tags, which are expanded up to n levels
+// deep. Tags nested deeper are emitted as literal text, so self-referencing
+// generators cannot recurse forever. The default of 0 disables nested
+// expansion.
+func WithMaxExpansionDepth(n int) Option {
+	return func(e *FastEngine) {
+		if n >= 0 {
+			e.maxExpansionDepth = n
+		}
+	}
+}
+
+// ErrOutputTooLarge is returned by RandomizerErr in strict parsing mode
+// when an expansion exceeds the engine's WithMaxOutputSize budget.
+var ErrOutputTooLarge = errors.New("fastrand: expanded output exceeds the maximum output size")
+
+// WithMaxOutputSize caps the output of a single expansion at n bytes, so
+// range tags inside repeat blocks cannot make untrusted templates allocate
+// without bound. Expansion stops once the budget is reached and the output
+// is cut to exactly n bytes; RandomizerErr in strict parsing mode returns
+// ErrOutputTooLarge instead, and RandomizerReader ends its stream. The
+// default of 0 means no limit.
+func WithMaxOutputSize(n int) Option {
+	return func(e *FastEngine) {
+		if n >= 0 {
+			e.maxOutputSize = n
+		}
+	}
+}
+
+// ErrTooManyTags is returned by RandomizerErr in strict parsing mode when
+// an expansion exceeds the engine's WithMaxTags budget.
+var ErrTooManyTags = errors.New("fastrand: payload expands more tags than allowed")
+
+// WithMaxTags limits how many tags a single expansion may process, counting
+// every pass through a repeat block, so hostile templates cannot consume
+// unbounded CPU. Expansion stops at the first tag over the limit and the
+// output produced so far is returned; RandomizerErr in strict parsing mode
+// returns ErrTooManyTags instead, and Validate reports templates that have
+// more tags than n. The default of 0 means no limit.
+func WithMaxTags(n int) Option {
+	return func(e *FastEngine) {
+		if n >= 0 {
+			e.maxTags = n
+		}
+	}
+}
+
+// WithMaxChoicesPerTag makes tags that list more than n length or keyword
+// choices malformed, so they are copied literally instead of being parsed,
+// or reported in strict parsing mode. The default of 0 means no limit.
+func WithMaxChoicesPerTag(n int) Option {
+	return func(e *FastEngine) {
+		if n >= 0 {
+			e.maxChoices = n
+		}
+	}
+}
+
+// tooManyChoices reports whether list has more comma-separated choices
+// than WithMaxChoicesPerTag allows. It stops counting at the limit.
+func (e *FastEngine) tooManyChoices(list []byte) bool {
+	if e.maxChoices <= 0 {
+		return false
+	}
+	for choices := 1; ; choices++ {
+		i := indexChoiceSep(list)
+		if i == -1 {
+			return false
+		}
+		if choices == e.maxChoices {
+			return true
+		}
+		list = list[i+1:]
+	}
+}
+
 func WithKeywordChoices(enabled bool) Option {
 	return func(e *FastEngine) {
 		e.keywordChoicesEnabled = enabled
@@ -164,3 +550,50 @@ func WithLengthChoices(enabled bool) Option {
 		e.lengthChoicesEnabled = enabled
 	}
 }
+
+// WithSeed backs the engine with its own generator seeded by seed, so the
+// same template and sequence of calls always expand to the same output,
+// whatever other goroutines draw from the package generators. Randomizer,
+// Compile and RandomizerReader consume the generator identically. Unless
+// WithClock set one, the engine clock is pinned to 2025-01-01T00:00:00Z, so
+// TIMESTAMP, JWT, UUIDV7, ULID and CCEXP reproduce too. Custom keyword
+// generators bring their own randomness and are not covered.
+func WithSeed(seed uint64) Option {
+	return func(e *FastEngine) {
+		e.next = newSeededSource(seed).Uint64
+		if e.clock == nil {
+			e.clock = fixedClock(seededEpoch)
+		}
+	}
+}
+
+// WithSequence registers a named sequence for {RAND;SEQ:name} tags that
+// starts at start and grows by a random gap in [1, randomGapMax]. Names that
+// are not registered get a consecutive sequence starting at 1.
+func WithSequence(name string, start, randomGapMax uint64) Option {
+	return func(e *FastEngine) {
+		e.sequences[name] = newSequence(start, randomGapMax, e.uint64)
+	}
+}
+
+// WithCycle registers a named list of values that {RAND;CYCLE:name} tags
+// emit in round-robin order.
+func WithCycle(name string, items ...string) Option {
+	return func(e *FastEngine) {
+		if len(items) == 0 {
+			return
+		}
+		values := make([][]byte, len(items))
+		for i, item := range items {
+			values[i] = []byte(item)
+		}
+		e.cycles[name] = NewCycle(values)
+	}
+}
+
+// WithXMLElementNames makes the XML keyword draw element names from names.
+func WithXMLElementNames(names ...string) Option {
+	return func(e *FastEngine) {
+		e.xmlNames = append([]string(nil), names...)
+	}
+}
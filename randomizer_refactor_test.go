@@ -71,6 +71,20 @@ func TestRandomizerAppend(t *testing.T) {
 		assert.Len(t, r1, len(r2), "Randomizer and RandomizerAppend should produce same length")
 	})
 
+	t.Run("PackageLevel", func(t *testing.T) {
+		dst := fastrand.RandomizerAppend([]byte("a="), []byte("{RAND;4;DIGIT}"))
+		assert.Regexp(t, `^a=[0-9]{4}$`, string(dst))
+		dst = fastrand.RandomizerAppendString(dst, "&b={RAND;3;ABU}")
+		assert.Regexp(t, `^a=[0-9]{4}&b=[A-Z]{3}$`, string(dst))
+	})
+
+	t.Run("SeededEqualsRandomizer", func(t *testing.T) {
+		payload := []byte("User:{RAND;10;ABL}|Sess:{RAND;32;HEX}|ID:{RAND;UUID}")
+		want := fastrand.NewEngine(fastrand.WithSeed(4)).Randomizer(payload)
+		got := fastrand.NewEngine(fastrand.WithSeed(4)).RandomizerAppend(nil, payload)
+		assert.Equal(t, string(want), string(got))
+	})
+
 	t.Run("CapacityGrowth", func(t *testing.T) {
 		engine := fastrand.NewEngine()
 		dst := make([]byte, 0, 8)
@@ -566,6 +580,30 @@ func TestRandomizerReset(t *testing.T) {
 	assert.True(t, uuidRegex.MatchString(result), "After Reset: UUID should be re-enabled")
 }
 
+func TestRandomizerResetDefaultsOnly(t *testing.T) {
+	t.Parallel()
+
+	engine := fastrand.NewEngine(
+		fastrand.WithDefaultLength(5),
+		fastrand.WithOutputEncoding(fastrand.RandomizerEncodingBase64),
+		fastrand.WithDisabledKeywords("UUID"),
+		fastrand.WithCustomKeyword("PING", func(int) []byte { return []byte("pong") }),
+		fastrand.WithCustomCharset("DIGIT", []byte("x")),
+		fastrand.WithMailProviders("corp.example"),
+		fastrand.WithCycle("env", "dev"),
+	)
+	require.NoError(t, engine.RegisterCharset("VOWEL", fastrand.CharsList("a")))
+
+	engine.ResetDefaultsOnly()
+	assert.Len(t, engine.RandomizerString("{RAND}"), 16, "default length is restored")
+	assert.Regexp(t, uuidRegex, engine.RandomizerString("{RAND;UUID}"), "disabled keywords are re-enabled")
+	assert.Equal(t, "pong", engine.RandomizerString("{RAND;PING}"), "output encoding is reset, custom keywords kept")
+	assert.Equal(t, "xxx", engine.RandomizerString("{RAND;3;DIGIT}"))
+	assert.Equal(t, "aa", engine.RandomizerString("{RAND;2;VOWEL}"))
+	assert.Equal(t, "dev", engine.RandomizerString("{RAND;CYCLE:env}"))
+	assert.Contains(t, engine.RandomizerString("{RAND;EMAIL}"), "@corp.example")
+}
+
 func TestRandomizerLargePayload(t *testing.T) {
 	t.Parallel()
 
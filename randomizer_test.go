@@ -578,6 +578,32 @@ func TestEngine(t *testing.T) {
 		}
 	})
 
+	t.Run("WithOptions_LongLength", func(t *testing.T) {
+		engine := fastrand.NewEngine(fastrand.WithMaxLength(65536))
+		result := engine.Randomizer([]byte("{RAND;256;HEX}"))
+		if len(result) != 512 {
+			t.Errorf("Expected 256 bytes as 512 hex characters, got %d", len(result))
+		}
+		checkHexFormat(t, result)
+		if l := len(engine.RandomizerString("{RAND;4096;ABL}")); l != 4096 {
+			t.Errorf("Expected length 4096, got %d", l)
+		}
+		if l := len(engine.Randomizer([]byte("{RAND;65536;BYTES}"))); l != 65536 {
+			t.Errorf("Expected length 65536, got %d", l)
+		}
+		for i := 0; i < 50; i++ {
+			if l := len(engine.RandomizerString("{RAND;1000-20000;DIGIT}")); l < 1000 || l > 20000 {
+				t.Errorf("Expected length within [1000, 20000], got %d", l)
+			}
+		}
+		if l := len(engine.RandomizerString("{RAND;65537;ABL}")); l != 16 {
+			t.Errorf("Expected length > maxLength to fall back to the default length 16, got %d", l)
+		}
+		if l := len(fastrand.RandomizerString("{RAND;256;ABL}")); l != 16 {
+			t.Errorf("Expected the default maxLength of 99 to reject 256, got %d", l)
+		}
+	})
+
 	t.Run("WithOptions_DisabledKeyword", func(t *testing.T) {
 		engine := fastrand.NewEngine(fastrand.WithDisabledKeywords("UUID", "HEX"))
 		result := engine.RandomizerString("{RAND;UUID}")
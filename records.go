@@ -0,0 +1,111 @@
+package fastrand
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// FieldGen is a generator usable as a record field. Every Gen[T] satisfies
+// it.
+type FieldGen interface {
+	Any() any
+}
+
+type recordField struct {
+	name string
+	gen  FieldGen
+}
+
+// RecordSet describes a batch of records by their fields. Every call to
+// Maps, WriteJSON or WriteCSV generates fresh values.
+type RecordSet struct {
+	n      int
+	fields []recordField
+}
+
+// Records starts a schema for n records.
+func Records(n int) *RecordSet {
+	if n < 0 {
+		panic("fastrand: record count cannot be negative")
+	}
+	return &RecordSet{n: n}
+}
+
+// Field appends a named field generated by gen. Fields keep their
+// declaration order in JSON and CSV output.
+func (r *RecordSet) Field(name string, gen FieldGen) *RecordSet {
+	r.fields = append(r.fields, recordField{name: name, gen: gen})
+	return r
+}
+
+// Maps generates the records as maps keyed by field name.
+func (r *RecordSet) Maps() []map[string]any {
+	out := make([]map[string]any, r.n)
+	for i := range out {
+		m := make(map[string]any, len(r.fields))
+		for _, f := range r.fields {
+			m[f.name] = f.gen.Any()
+		}
+		out[i] = m
+	}
+	return out
+}
+
+// WriteJSON generates the records and writes them to w as a JSON array of
+// objects.
+func (r *RecordSet) WriteJSON(w io.Writer) error {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i := 0; i < r.n; i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteByte('{')
+		for j, f := range r.fields {
+			if j > 0 {
+				buf.WriteByte(',')
+			}
+			key, err := json.Marshal(f.name)
+			if err != nil {
+				return err
+			}
+			val, err := json.Marshal(f.gen.Any())
+			if err != nil {
+				return fmt.Errorf("fastrand: encoding field %q: %w", f.name, err)
+			}
+			buf.Write(key)
+			buf.WriteByte(':')
+			buf.Write(val)
+		}
+		buf.WriteByte('}')
+	}
+	buf.WriteByte(']')
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// WriteCSV generates the records and writes them to w as CSV with a header
+// row of field names.
+func (r *RecordSet) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	row := make([]string, len(r.fields))
+	for j, f := range r.fields {
+		row[j] = f.name
+	}
+	if err := cw.Write(row); err != nil {
+		return err
+	}
+	for i := 0; i < r.n; i++ {
+		for j, f := range r.fields {
+			row[j] = fmt.Sprint(f.gen.Any())
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
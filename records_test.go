@@ -0,0 +1,84 @@
+package fastrand_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func userRecords(n int) *fastrand.RecordSet {
+	return fastrand.Records(n).
+		Field("id", fastrand.UUIDs()).
+		Field("age", fastrand.IntRange(18, 99)).
+		Field("email", fastrand.FromTemplate("{RAND;8;EMAIL}")).
+		Field("active", fastrand.Map(fastrand.IntRange(0, 1), func(v int) bool { return v == 1 }))
+}
+
+func TestRecordsMaps(t *testing.T) {
+	rows := userRecords(25).Maps()
+	require.Len(t, rows, 25)
+	for _, row := range rows {
+		require.Len(t, row, 4)
+		checkUUIDFormat(t, []byte(row["id"].(string)))
+		age := row["age"].(int)
+		assert.GreaterOrEqual(t, age, 18)
+		assert.LessOrEqual(t, age, 99)
+		checkEmailFormat(t, []byte(row["email"].(string)))
+		assert.IsType(t, true, row["active"])
+	}
+}
+
+func TestRecordsJSON(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, userRecords(10).WriteJSON(&buf))
+
+	var rows []map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &rows))
+	require.Len(t, rows, 10)
+	assert.Contains(t, rows[0], "email")
+
+	first := buf.String()[:40]
+	assert.True(t, strings.HasPrefix(first, `[{"id":`), "fields keep declaration order: %s", first)
+	assert.Less(t, strings.Index(buf.String(), `"age"`), strings.Index(buf.String(), `"email"`))
+}
+
+func TestRecordsJSONEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, fastrand.Records(0).Field("x", fastrand.Const(1)).WriteJSON(&buf))
+	assert.Equal(t, "[]", buf.String())
+}
+
+func TestRecordsCSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, userRecords(5).Field("note", fastrand.Const("a,\"b\"")).WriteCSV(&buf))
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 6)
+	assert.Equal(t, []string{"id", "age", "email", "active", "note"}, rows[0])
+	for _, row := range rows[1:] {
+		age, err := strconv.Atoi(row[1])
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, age, 18)
+		assert.Contains(t, []string{"true", "false"}, row[3])
+		assert.Equal(t, "a,\"b\"", row[4], "CSV values are quoted correctly")
+	}
+}
+
+func TestUUIDsGen(t *testing.T) {
+	g := fastrand.UUIDs()
+	seen := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		u := g()
+		checkUUIDFormat(t, []byte(u))
+		seen[u] = true
+	}
+	assert.Len(t, seen, 100)
+}
@@ -0,0 +1,93 @@
+package fastrand
+
+import "bytes"
+
+// maxRepeat bounds the repetition count of a {RAND-REPEAT} block; blocks
+// asking for more are left as literal text.
+const maxRepeat = 10000
+
+var (
+	repeatOpen  = []byte("{RAND-REPEAT")
+	repeatClose = []byte("{/RAND-REPEAT}")
+)
+
+// repeatSpec is the parsed count of a {RAND-REPEAT;n} or
+// {RAND-REPEAT;min-max} block, which may be optional as in
+// {RAND-REPEAT?50;1}.
+type repeatSpec struct {
+	min, max int
+	chance   chanceSpec
+}
+
+// parseRepeat parses a repeat tag, which starts with "{RAND-REPEAT" and
+// excludes the closing brace.
+func parseRepeat(tag []byte) (repeatSpec, bool) {
+	chance, body, ok := parseChance(tag[len(repeatOpen):])
+	if !ok || len(body) < 2 || body[0] != sepTag {
+		return repeatSpec{}, false
+	}
+	body = body[1:]
+	minPart, maxPart, isRange := bytes.Cut(body, []byte{'-'})
+	if !isRange {
+		maxPart = minPart
+	}
+	minN, ok1 := parseLengthFast(minPart)
+	maxN, ok2 := parseLengthFast(maxPart)
+	if !ok1 || !ok2 || minN > maxN || maxN > maxRepeat {
+		return repeatSpec{}, false
+	}
+	return repeatSpec{min: minN, max: maxN, chance: chance}, true
+}
+
+func (r repeatSpec) count(next func() uint64) int {
+	if r.chance.skip(next) {
+		return 0
+	}
+	if r.min == r.max {
+		return r.min
+	}
+	return r.min + int(uint64N(next, uint64(r.max-r.min+1)))
+}
+
+// findRepeatEnd returns the offset in payload, which follows a repeat tag,
+// of the {/RAND-REPEAT} that closes it, skipping nested blocks, or -1.
+func findRepeatEnd(payload []byte) int {
+	depth, cursor := 0, 0
+	for {
+		closeIndex := bytes.Index(payload[cursor:], repeatClose)
+		if closeIndex == -1 {
+			return -1
+		}
+		closeIndex += cursor
+		openIndex := bytes.Index(payload[cursor:closeIndex], repeatOpen)
+		if openIndex != -1 {
+			depth++
+			cursor += openIndex + len(repeatOpen)
+			continue
+		}
+		if depth == 0 {
+			return closeIndex
+		}
+		depth--
+		cursor = closeIndex + len(repeatClose)
+	}
+}
+
+// expandRepeat expands a repeat block whose opening tag spans
+// payload[start:cursor]. It returns the offset after the block, or false
+// when the tag is malformed or unclosed and must be copied literally.
+func (e *FastEngine) expandRepeat(payload []byte, out *[]byte, start, cursor int, x *expansion) (int, bool) {
+	r, ok := parseRepeat(payload[start : cursor-1])
+	if !ok {
+		return 0, false
+	}
+	bodyEnd := findRepeatEnd(payload[cursor:])
+	if bodyEnd == -1 {
+		return 0, false
+	}
+	body := payload[cursor : cursor+bodyEnd]
+	for n := r.count(e.next); n > 0 && !x.stopped(*out); n-- {
+		e.expandPayload(body, out, x)
+	}
+	return cursor + bodyEnd + len(repeatClose), true
+}
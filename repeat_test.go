@@ -0,0 +1,89 @@
+package fastrand_test
+
+import (
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepeatBlock(t *testing.T) {
+	pattern := regexp.MustCompile(`^\?(item=[a-z]{8}&){3,5}end$`)
+	seen := map[int]bool{}
+	for i := 0; i < 200; i++ {
+		out := fastrand.RandomizerString("?{RAND-REPEAT;3-5}item={RAND;8;ABL}&{/RAND-REPEAT}end")
+		require.Regexp(t, pattern, out)
+		seen[strings.Count(out, "item=")] = true
+	}
+	assert.Len(t, seen, 3)
+
+	assert.Equal(t, "[]", fastrand.RandomizerString("[{RAND-REPEAT;0}x{/RAND-REPEAT}]"))
+	assert.Equal(t, "xxxx", fastrand.RandomizerString("{RAND-REPEAT;4}x{/RAND-REPEAT}"))
+}
+
+func TestRepeatBlockNested(t *testing.T) {
+	out := fastrand.RandomizerString("{RAND-REPEAT;2}[{RAND-REPEAT;3}{RAND;1;DIGIT}{/RAND-REPEAT}]{/RAND-REPEAT}")
+	assert.Regexp(t, `^\[[0-9]{3}\]\[[0-9]{3}\]$`, out)
+}
+
+func TestRepeatBlockMalformed(t *testing.T) {
+	for _, payload := range []string{
+		"{RAND-REPEAT;3}x",
+		"{RAND-REPEAT;5-3}x{/RAND-REPEAT}",
+		"{RAND-REPEAT;99999}x{/RAND-REPEAT}",
+		"{RAND-REPEAT}x{/RAND-REPEAT}",
+	} {
+		assert.Equal(t, payload, fastrand.RandomizerString(payload))
+	}
+}
+
+func TestRepeatBlockVariables(t *testing.T) {
+	out := fastrand.RandomizerString("{RAND-REPEAT;3}{RAND;4;DIGIT;VAR=n}={REF;n},{/RAND-REPEAT}")
+	m := regexp.MustCompile(`([0-9]{4})=([0-9]{4}),`).FindAllStringSubmatch(out, -1)
+	require.Len(t, m, 3)
+	for _, pair := range m {
+		assert.Equal(t, pair[1], pair[2])
+	}
+}
+
+func TestRepeatBlockTemplateAndStream(t *testing.T) {
+	payload := `{"ids":[{RAND-REPEAT;2-4}"{RAND;6;HEX}",{/RAND-REPEAT}"x"]}`
+	pattern := regexp.MustCompile(`^\{"ids":\[("[0-9a-f]{12}",){2,4}"x"\]\}$`)
+
+	tmpl, err := fastrand.Compile([]byte(payload))
+	require.NoError(t, err)
+	assert.Regexp(t, pattern, tmpl.ExecuteString())
+
+	out, err := io.ReadAll(fastrand.RandomizerReader(iotest.OneByteReader(strings.NewReader(payload))))
+	require.NoError(t, err)
+	assert.Regexp(t, pattern, string(out))
+}
+
+func TestRepeatBlockSeeded(t *testing.T) {
+	payload := []byte("{RAND-REPEAT;1-9}{RAND;4;ABL}{/RAND-REPEAT}")
+	tmpl, err := fastrand.NewEngine(fastrand.WithSeed(7)).Compile(payload)
+	require.NoError(t, err)
+	assert.Equal(t, string(fastrand.NewEngine(fastrand.WithSeed(7)).Randomizer(payload)), tmpl.ExecuteString())
+}
+
+func TestRepeatBlockStrict(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithStrictParsing(true))
+	for payload, reason := range map[string]string{
+		"ab{RAND-REPEAT;3}x":             "unclosed repeat block",
+		"{RAND-REPEAT;x}y{/RAND-REPEAT}": "invalid repeat count",
+	} {
+		_, err := engine.RandomizerErr([]byte(payload))
+		var tagErr *fastrand.TagError
+		if assert.True(t, errors.As(err, &tagErr), payload) {
+			assert.Contains(t, tagErr.Reason, reason, payload)
+		}
+	}
+	_, err := engine.RandomizerErr([]byte("{RAND-REPEAT;2}{RAND;4;DIGIT}{/RAND-REPEAT}"))
+	assert.NoError(t, err)
+}
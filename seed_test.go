@@ -0,0 +1,67 @@
+package fastrand_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const seedTemplate = "{RAND}|{RAND;8;ABL}|{RAND;5-10;DIGIT}|{RAND;4,8;HEX}|{RAND;UUID,IPV4,IPV6}|" +
+	"{RAND;6;EMAIL}|{RAND;5;NULL}|{RAND;7;BYTES}|{RAND;UUID}"
+
+func TestWithSeed_Reproducible(t *testing.T) {
+	a := fastrand.NewEngine(fastrand.WithSeed(1234))
+	b := fastrand.NewEngine(fastrand.WithSeed(1234))
+
+	for i := 0; i < 50; i++ {
+		assert.Equal(t, a.RandomizerString(seedTemplate), b.RandomizerString(seedTemplate),
+			"engines with the same seed should produce identical output")
+	}
+}
+
+func TestWithSeed_DifferentSeeds(t *testing.T) {
+	a := fastrand.NewEngine(fastrand.WithSeed(1))
+	b := fastrand.NewEngine(fastrand.WithSeed(2))
+	assert.NotEqual(t, a.RandomizerString(seedTemplate), b.RandomizerString(seedTemplate))
+}
+
+func TestWithSeed_IndependentOfGlobalState(t *testing.T) {
+	a := fastrand.NewEngine(fastrand.WithSeed(99))
+	want := a.RandomizerString(seedTemplate)
+
+	b := fastrand.NewEngine(fastrand.WithSeed(99))
+	for i := 0; i < 100; i++ {
+		_ = fastrand.Uint64()
+		_ = fastrand.RandomizerString(seedTemplate)
+	}
+	assert.Equal(t, want, b.RandomizerString(seedTemplate), "global draws should not affect a seeded engine")
+}
+
+func TestWithSeed_ResetRestoresGlobalSource(t *testing.T) {
+	a := fastrand.NewEngine(fastrand.WithSeed(5))
+	b := fastrand.NewEngine(fastrand.WithSeed(5))
+	a.Reset()
+	assert.NotEqual(t, a.RandomizerString(seedTemplate), b.RandomizerString(seedTemplate))
+}
+
+const seedExtendedTemplate = "{RAND;8:70,6:30;HEX;VAR=id}|{REF;id}|{RAND?50;6;ABL}|{RAND;4;UUID:3,IPV4:1}|" +
+	"{RAND-REPEAT;1-4}[{RAND;3;[a-f0-9]}]{/RAND-REPEAT}|{RAND;5;HEX(upper=1)}|{RAND;EMAIL(provider=example.com)}"
+
+func TestWithSeed_ExtendedSyntax(t *testing.T) {
+	want := fastrand.NewEngine(fastrand.WithSeed(42)).RandomizerString(seedExtendedTemplate)
+	require.NotContains(t, want, "{")
+
+	tmpl, err := fastrand.NewEngine(fastrand.WithSeed(42)).Compile([]byte(seedExtendedTemplate))
+	require.NoError(t, err)
+	assert.Equal(t, want, tmpl.ExecuteString(), "a seeded template matches a seeded Randomizer")
+
+	out, err := io.ReadAll(fastrand.NewEngine(fastrand.WithSeed(42)).RandomizerReader(strings.NewReader(seedExtendedTemplate)))
+	require.NoError(t, err)
+	assert.Equal(t, want, string(out), "a seeded stream matches a seeded Randomizer")
+
+	assert.NotEqual(t, want, fastrand.NewEngine(fastrand.WithSeed(43)).RandomizerString(seedExtendedTemplate))
+}
@@ -0,0 +1,129 @@
+package fastrand
+
+import (
+	"fmt"
+	"strconv"
+	"sync/atomic"
+)
+
+// maxSequencePad bounds the pad parameter of SEQ: a uint64 has at most 20
+// digits.
+const maxSequencePad = 20
+
+// Sequence yields monotonically increasing values. Each value is a random
+// gap of 1 to gapMax above the previous one, which gives fixture IDs a
+// realistic jitter. It is safe for concurrent use.
+type Sequence struct {
+	pending atomic.Uint64
+	gapMax  uint64
+	next    func() uint64
+}
+
+// NewSequence returns a Sequence whose first value is start and whose
+// subsequent values grow by a random gap in [1, randomGapMax]. A gap max of
+// 0 or 1 yields consecutive values.
+func NewSequence(start, randomGapMax uint64) *Sequence {
+	return newSequence(start, randomGapMax, fastUint64)
+}
+
+func newSequence(start, gapMax uint64, next func() uint64) *Sequence {
+	if gapMax == 0 {
+		gapMax = 1
+	}
+	s := &Sequence{gapMax: gapMax, next: next}
+	s.pending.Store(start)
+	return s
+}
+
+// Next returns the next value of the sequence.
+func (s *Sequence) Next() uint64 {
+	gap := uint64(1)
+	if s.gapMax > 1 {
+		gap += uint64N(s.next, s.gapMax)
+	}
+	for {
+		v := s.pending.Load()
+		if s.pending.CompareAndSwap(v, v+gap) {
+			return v
+		}
+	}
+}
+
+// Cycle yields the items it was built with in round-robin order. It is safe
+// for concurrent use.
+type Cycle[T any] struct {
+	items []T
+	pos   atomic.Uint64
+}
+
+// NewCycle returns a Cycle over a copy of items. It panics if items is empty.
+func NewCycle[T any](items []T) *Cycle[T] {
+	if len(items) == 0 {
+		panic("fastrand: NewCycle requires at least one item")
+	}
+	return &Cycle[T]{items: clone(items)}
+}
+
+// Next returns the next item, wrapping around after the last one.
+func (c *Cycle[T]) Next() T {
+	i := c.pos.Add(1) - 1
+	return c.items[i%uint64(len(c.items))]
+}
+
+// sequence returns the engine's named sequence, creating a consecutive one
+// starting at start the first time an unregistered name is used.
+func (e *FastEngine) sequence(name []byte, start uint64) *Sequence {
+	e.seqMu.Lock()
+	defer e.seqMu.Unlock()
+	if s, ok := e.sequences[string(name)]; ok {
+		return s
+	}
+	s := newSequence(start, 1, e.uint64)
+	e.sequences[string(name)] = s
+	return s
+}
+
+// sequenceParams returns the start and pad parameters of a SEQ keyword,
+// 1 and 0 when absent, and why they are invalid, or "".
+func sequenceParams(params []byte) (start uint64, pad int, reason string) {
+	start = 1
+	if v, ok := keywordParam(params, "start"); ok {
+		n, err := strconv.ParseUint(unsafeString(v), 10, 64)
+		if err != nil {
+			return 1, 0, fmt.Sprintf("invalid start %q: want a non-negative integer", v)
+		}
+		start = n
+	}
+	if v, ok := keywordParam(params, "pad"); ok {
+		n, ok := parseLengthFast(v)
+		if !ok || n > maxSequencePad {
+			return start, 0, fmt.Sprintf("invalid pad %q: want 0 to %d digits", v, maxSequencePad)
+		}
+		pad = n
+	}
+	return start, pad, ""
+}
+
+// appendSequence appends the next value of the sequence name, zero-padded
+// to the keyword's pad parameter. Its start parameter applies when the tag
+// creates the sequence; sequences registered with WithSequence or already
+// used keep counting from where they are.
+func (e *FastEngine) appendSequence(out *[]byte, name, params []byte) {
+	start, pad, _ := sequenceParams(params)
+	*out = appendPadded(*out, e.sequence(name, start).Next(), pad)
+}
+
+func (e *FastEngine) appendCycle(out *[]byte, length int, name []byte, x *expansion) {
+	c, ok := e.cycles[string(name)]
+	if !ok {
+		e.appendString(out, length, e.getCharset(kwABR, CharsAll))
+		return
+	}
+	e.appendGenerated(out, c.Next(), x)
+}
+
+// uint64 draws from the engine's current source. Unlike the e.next field, the
+// method value e.uint64 keeps following the engine if its source changes.
+func (e *FastEngine) uint64() uint64 {
+	return e.next()
+}
@@ -0,0 +1,149 @@
+package fastrand_test
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSequence(t *testing.T) {
+	t.Run("Consecutive", func(t *testing.T) {
+		s := fastrand.NewSequence(10, 0)
+		for want := uint64(10); want < 20; want++ {
+			assert.Equal(t, want, s.Next())
+		}
+	})
+
+	t.Run("Jitter", func(t *testing.T) {
+		s := fastrand.NewSequence(1000, 5)
+		prev := s.Next()
+		assert.Equal(t, uint64(1000), prev)
+		gaps := map[uint64]bool{}
+		for i := 0; i < 1000; i++ {
+			v := s.Next()
+			gap := v - prev
+			assert.GreaterOrEqual(t, gap, uint64(1))
+			assert.LessOrEqual(t, gap, uint64(5))
+			gaps[gap] = true
+			prev = v
+		}
+		assert.Greater(t, len(gaps), 1, "gaps should vary")
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		s := fastrand.NewSequence(0, 3)
+		var mu sync.Mutex
+		seen := map[uint64]bool{}
+		var wg sync.WaitGroup
+		for g := 0; g < 20; g++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 200; i++ {
+					v := s.Next()
+					mu.Lock()
+					seen[v] = true
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+		assert.Len(t, seen, 4000, "concurrent Next calls must never repeat a value")
+	})
+}
+
+func TestCycle(t *testing.T) {
+	c := fastrand.NewCycle([]string{"a", "b", "c"})
+	var got []string
+	for i := 0; i < 7; i++ {
+		got = append(got, c.Next())
+	}
+	assert.Equal(t, []string{"a", "b", "c", "a", "b", "c", "a"}, got)
+	assert.Panics(t, func() { fastrand.NewCycle([]int{}) })
+}
+
+func TestRandomizerSequenceKeyword(t *testing.T) {
+	t.Run("UnregisteredNamesAreIndependent", func(t *testing.T) {
+		engine := fastrand.NewEngine()
+		assert.Equal(t, "1-1-2-2", engine.RandomizerString("{RAND;SEQ:order}-{RAND;SEQ:user}-{RAND;SEQ:order}-{RAND;SEQ:user}"))
+	})
+
+	t.Run("NamesAreCaseSensitive", func(t *testing.T) {
+		engine := fastrand.NewEngine()
+		assert.Equal(t, "1 1", engine.RandomizerString("{RAND;seq:id} {RAND;SEQ:ID}"))
+	})
+
+	t.Run("Registered", func(t *testing.T) {
+		engine := fastrand.NewEngine(fastrand.WithSequence("orderid", 5000, 10))
+		prev := uint64(0)
+		for i := 0; i < 100; i++ {
+			v, err := strconv.ParseUint(engine.RandomizerString("{RAND;SEQ:orderid}"), 10, 64)
+			require.NoError(t, err)
+			assert.GreaterOrEqual(t, v, uint64(5000))
+			assert.Greater(t, v, prev)
+			prev = v
+		}
+	})
+
+	t.Run("Seeded", func(t *testing.T) {
+		tmpl := strings.Repeat("{RAND;SEQ:id},", 20)
+		a := fastrand.NewEngine(fastrand.WithSequence("id", 1, 100), fastrand.WithSeed(3))
+		b := fastrand.NewEngine(fastrand.WithSeed(3), fastrand.WithSequence("id", 1, 100))
+		assert.Equal(t, a.RandomizerString(tmpl), b.RandomizerString(tmpl), "option order should not matter")
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		engine := fastrand.NewEngine(fastrand.WithSequence("id", 100, 1))
+		assert.Equal(t, "100", engine.RandomizerString("{RAND;SEQ:id}"))
+		engine.Reset()
+		assert.Equal(t, "1", engine.RandomizerString("{RAND;SEQ:id}"))
+	})
+
+	t.Run("Unnamed", func(t *testing.T) {
+		engine := fastrand.NewEngine()
+		assert.Equal(t, "1,2,3", engine.RandomizerString("{RAND;SEQ},{RAND;SEQ},{RAND;seq}"))
+	})
+
+	t.Run("StartAndPad", func(t *testing.T) {
+		engine := fastrand.NewEngine()
+		assert.Equal(t, "001000 001001 1002", engine.RandomizerString("{RAND;SEQ(start=1000,pad=6)} {RAND;SEQ(pad=6)} {RAND;SEQ}"))
+		assert.Equal(t, "0001", engine.RandomizerString("{RAND;SEQ:order(pad=4)}"))
+		assert.Equal(t, "123456", engine.RandomizerString("{RAND;SEQ:big(start=123456,pad=3)}"), "padding never truncates")
+
+		registered := fastrand.NewEngine(fastrand.WithSequence("id", 7, 1))
+		assert.Equal(t, "7", registered.RandomizerString("{RAND;SEQ:id(start=500)}"), "start only applies to new sequences")
+	})
+
+	t.Run("StrictParams", func(t *testing.T) {
+		engine := fastrand.NewEngine(fastrand.WithStrictParsing(true))
+		_, err := engine.RandomizerErr([]byte("{RAND;SEQ(start=-1)}"))
+		assert.ErrorContains(t, err, `invalid start "-1"`)
+		_, err = engine.RandomizerErr([]byte("{RAND;SEQ(pad=21)}"))
+		assert.ErrorContains(t, err, `invalid pad "21"`)
+		_, err = engine.RandomizerErr([]byte("{RAND;SEQ(step=2)}"))
+		assert.ErrorContains(t, err, `unknown parameter "step"`)
+		_, err = engine.RandomizerErr([]byte("{RAND;SEQ:id(start=10,pad=8)}"))
+		assert.NoError(t, err)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		engine := fastrand.NewEngine(fastrand.WithDisabledKeywords("SEQ"))
+		assert.Len(t, engine.RandomizerString("{RAND;SEQ:id}"), 16)
+	})
+}
+
+func TestRandomizerCycleKeyword(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithCycle("env", "dev", "staging", "prod"))
+	assert.Equal(t, "dev,staging,prod,dev", engine.RandomizerString("{RAND;CYCLE:env},{RAND;CYCLE:env},{RAND;CYCLE:env},{RAND;CYCLE:env}"))
+
+	result := engine.RandomizerString("{RAND;8;CYCLE:missing}")
+	assert.Len(t, result, 8, "unregistered cycles fall back to a random string")
+
+	choice := engine.RandomizerString("{RAND;CYCLE:env,SEQ:n}")
+	assert.Contains(t, []string{"dev", "staging", "prod", "1"}, choice)
+}
@@ -0,0 +1,146 @@
+package fastrand
+
+import "math"
+
+type integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64
+}
+
+// Shrinker returns simpler candidates derived from v, most aggressive first.
+// It returns nil when v cannot be simplified further.
+type Shrinker[T any] func(v T) []T
+
+// maxShrinkSteps bounds Minimize so a shrinker that never converges cannot
+// hang a failing test.
+const maxShrinkSteps = 1000
+
+// Minimize repeatedly replaces v with the first candidate from shrink that
+// still fails, until no candidate fails, and returns the smallest failing
+// value found.
+func Minimize[T any](v T, shrink Shrinker[T], fails func(T) bool) T {
+	if shrink == nil {
+		return v
+	}
+	for step := 0; step < maxShrinkSteps; step++ {
+		improved := false
+		for _, c := range shrink(v) {
+			if fails(c) {
+				v = c
+				improved = true
+				break
+			}
+		}
+		if !improved {
+			return v
+		}
+	}
+	return v
+}
+
+// ShrinkInt shrinks an integer toward zero.
+func ShrinkInt[T integer](v T) []T {
+	if v == 0 {
+		return nil
+	}
+	out := []T{0}
+	var zero T
+	if v < zero && -v > zero {
+		out = append(out, -v)
+	}
+	for d := v / 2; d != 0; d /= 2 {
+		out = append(out, v-d)
+	}
+	return out
+}
+
+// ShrinkFloat64 shrinks a float toward zero and toward whole numbers.
+func ShrinkFloat64(v float64) []float64 {
+	if v == 0 || math.IsNaN(v) {
+		return nil
+	}
+	out := []float64{0}
+	if v < 0 {
+		out = append(out, -v)
+	}
+	if t := math.Trunc(v); t != v {
+		out = append(out, t)
+	}
+	if h := v / 2; h != v && h != 0 {
+		out = append(out, h)
+	}
+	return out
+}
+
+// ShrinkString shrinks a string by dropping halves, dropping single bytes
+// and replacing bytes with 'a'.
+func ShrinkString(s string) []string {
+	shrunk := ShrinkBytes([]byte(s))
+	if shrunk == nil {
+		return nil
+	}
+	out := make([]string, len(shrunk))
+	for i, b := range shrunk {
+		out[i] = string(b)
+	}
+	return out
+}
+
+// ShrinkBytes shrinks a byte slice the same way ShrinkString does.
+func ShrinkBytes(b []byte) [][]byte {
+	n := len(b)
+	if n == 0 {
+		return nil
+	}
+	out := [][]byte{{}}
+	if n > 1 {
+		out = append(out, clone(b[:n/2]), clone(b[n/2:]))
+	}
+	for i := 0; i < n && n > 1; i++ {
+		c := make([]byte, 0, n-1)
+		c = append(c, b[:i]...)
+		out = append(out, append(c, b[i+1:]...))
+	}
+	for i := 0; i < n; i++ {
+		if b[i] != 'a' {
+			c := clone(b)
+			c[i] = 'a'
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// ShrinkSlice shrinks a slice by dropping halves and single elements, then
+// by shrinking individual elements with elem when it is non-nil.
+func ShrinkSlice[T any](s []T, elem Shrinker[T]) [][]T {
+	n := len(s)
+	if n == 0 {
+		return nil
+	}
+	out := [][]T{{}}
+	if n > 1 {
+		out = append(out, clone(s[:n/2]), clone(s[n/2:]))
+	}
+	for i := 0; i < n && n > 1; i++ {
+		c := make([]T, 0, n-1)
+		c = append(c, s[:i]...)
+		out = append(out, append(c, s[i+1:]...))
+	}
+	if elem != nil {
+		for i := 0; i < n; i++ {
+			for _, v := range elem(s[i]) {
+				c := clone(s)
+				c[i] = v
+				out = append(out, c)
+			}
+		}
+	}
+	return out
+}
+
+func clone[T any](s []T) []T {
+	c := make([]T, len(s))
+	copy(c, s)
+	return c
+}
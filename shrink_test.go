@@ -0,0 +1,88 @@
+package fastrand_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShrinkInt(t *testing.T) {
+	assert.Nil(t, fastrand.ShrinkInt(0))
+	assert.Equal(t, []int{0}, fastrand.ShrinkInt(1))
+	assert.Equal(t, []int{0, 50, 75, 88, 94, 97, 99}, fastrand.ShrinkInt(100))
+
+	neg := fastrand.ShrinkInt(-8)
+	assert.Equal(t, 0, neg[0])
+	assert.Contains(t, neg, 8, "negative values should offer their absolute value")
+	for _, c := range neg[2:] {
+		assert.Less(t, c, 0)
+		assert.Greater(t, c, -8)
+	}
+
+	assert.Equal(t, []uint8{0, 128, 192, 224, 240, 248, 252, 254}, fastrand.ShrinkInt(uint8(255)))
+	assert.Equal(t, int8(0), fastrand.ShrinkInt(int8(-128))[0], "MinInt8 must not overflow")
+}
+
+func TestShrinkFloat64(t *testing.T) {
+	assert.Nil(t, fastrand.ShrinkFloat64(0))
+	assert.Equal(t, []float64{0, 3, 1.75}, fastrand.ShrinkFloat64(3.5))
+	assert.Contains(t, fastrand.ShrinkFloat64(-2), 2.0)
+}
+
+func TestShrinkString(t *testing.T) {
+	assert.Nil(t, fastrand.ShrinkString(""))
+	c := fastrand.ShrinkString("xyz")
+	assert.Equal(t, "", c[0])
+	assert.Contains(t, c, "x")
+	assert.Contains(t, c, "yz")
+	assert.Contains(t, c, "xz")
+	assert.Contains(t, c, "ayz")
+}
+
+func TestShrinkSlice(t *testing.T) {
+	assert.Nil(t, fastrand.ShrinkSlice[int](nil, nil))
+
+	in := []int{4, 5}
+	c := fastrand.ShrinkSlice(in, fastrand.ShrinkInt[int])
+	assert.Equal(t, []int{}, c[0])
+	assert.Contains(t, c, []int{4})
+	assert.Contains(t, c, []int{5})
+	assert.Contains(t, c, []int{0, 5}, "elements should be shrunk individually")
+	assert.Equal(t, []int{4, 5}, in, "input must not be mutated")
+}
+
+func TestMinimize(t *testing.T) {
+	t.Run("Int", func(t *testing.T) {
+		got := fastrand.Minimize(98765, fastrand.ShrinkInt[int], func(v int) bool { return v >= 1000 })
+		assert.Equal(t, 1000, got)
+	})
+
+	t.Run("String", func(t *testing.T) {
+		fails := func(s string) bool { return strings.Contains(s, "!") }
+		got := fastrand.Minimize("hello, world!!", fastrand.ShrinkString, fails)
+		assert.Equal(t, "!", got)
+	})
+
+	t.Run("Slice", func(t *testing.T) {
+		fails := func(s []int) bool {
+			sum := 0
+			for _, v := range s {
+				sum += v
+			}
+			return sum > 10
+		}
+		shrink := func(s []int) [][]int { return fastrand.ShrinkSlice(s, fastrand.ShrinkInt[int]) }
+		got := fastrand.Minimize([]int{3, 9, 1, 12, 7}, shrink, fails)
+		assert.True(t, fails(got))
+		assert.LessOrEqual(t, len(got), 2)
+		for _, c := range shrink(got) {
+			assert.False(t, fails(c), "result should be locally minimal, but %v still fails", c)
+		}
+	})
+
+	t.Run("NilShrinker", func(t *testing.T) {
+		assert.Equal(t, 7, fastrand.Minimize(7, nil, func(int) bool { return true }))
+	})
+}
@@ -0,0 +1,49 @@
+package fastrand
+
+import (
+	"encoding/binary"
+	"io"
+	"sync"
+)
+
+// WithUint64Source backs the engine with next instead of the package's
+// fast generator, for example a hardware RNG or a test double. next must be
+// safe for concurrent use if the engine is. A nil next keeps the current
+// source.
+func WithUint64Source(next func() uint64) Option {
+	return func(e *FastEngine) {
+		if next != nil {
+			e.next = next
+		}
+	}
+}
+
+// WithRandSource backs the engine with bytes read from src, such as
+// crypto/rand.Reader or a recorded stream, eight at a time in little-endian
+// order. Reads are serialized, so src need not be safe for concurrent use.
+// Like the strict secure mode source, the engine panics if src returns an
+// error, since a tag cannot be expanded without randomness. A nil src keeps
+// the current source.
+func WithRandSource(src io.Reader) Option {
+	return func(e *FastEngine) {
+		if src != nil {
+			e.next = (&readerSource{r: src}).Uint64
+		}
+	}
+}
+
+// readerSource adapts an io.Reader to a uint64 generator.
+type readerSource struct {
+	mu  sync.Mutex
+	r   io.Reader
+	buf [8]byte
+}
+
+func (s *readerSource) Uint64() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := io.ReadFull(s.r, s.buf[:]); err != nil {
+		panic("fastrand: random source failed: " + err.Error())
+	}
+	return binary.LittleEndian.Uint64(s.buf[:])
+}
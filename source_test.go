@@ -0,0 +1,50 @@
+package fastrand_test
+
+import (
+	"bytes"
+	crand "crypto/rand"
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithUint64Source(t *testing.T) {
+	var calls int
+	zero := func() uint64 { calls++; return 0 }
+	engine := fastrand.NewEngine(fastrand.WithUint64Source(zero))
+	assert.Equal(t, "aaaa|0000", engine.RandomizerString("{RAND;4;ABL}|{RAND;4;DIGIT}"))
+	assert.Positive(t, calls)
+
+	calls = 0
+	engine.Reset()
+	engine.RandomizerString("{RAND;4;ABL}")
+	assert.Zero(t, calls, "Reset restores the default source")
+
+	engine = fastrand.NewEngine(fastrand.WithSeed(1), fastrand.WithUint64Source(nil))
+	assert.Equal(t, fastrand.NewEngine(fastrand.WithSeed(1)).RandomizerString(seedTemplate), engine.RandomizerString(seedTemplate),
+		"nil keeps the current source")
+}
+
+func TestWithRandSource(t *testing.T) {
+	var recorded bytes.Buffer
+	for i := range 256 {
+		binary.Write(&recorded, binary.LittleEndian, uint64(i)*0x9e3779b97f4a7c15)
+	}
+	data := recorded.Bytes()
+
+	a := fastrand.NewEngine(fastrand.WithRandSource(bytes.NewReader(data)))
+	b := fastrand.NewEngine(fastrand.WithRandSource(bytes.NewReader(data)))
+	assert.Equal(t, a.RandomizerString(seedTemplate), b.RandomizerString(seedTemplate),
+		"the same recorded stream replays the same output")
+
+	engine := fastrand.NewEngine(fastrand.WithRandSource(crand.Reader))
+	assert.Regexp(t, `^[0-9a-f]{16}$`, engine.RandomizerString("{RAND;8;HEX}"))
+
+	engine = fastrand.NewEngine(fastrand.WithRandSource(strings.NewReader("short")))
+	assert.PanicsWithValue(t, "fastrand: random source failed: unexpected EOF", func() {
+		engine.RandomizerString("{RAND;8;HEX}")
+	})
+}
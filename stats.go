@@ -0,0 +1,144 @@
+package fastrand
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// EngineStats is a snapshot of an engine's counters, as returned by Stats.
+type EngineStats struct {
+	// Payloads counts expanded payloads, template executions and finished
+	// RandomizerReader streams. Payloads without tags that are returned
+	// unchanged are not counted.
+	Payloads uint64
+	// Bytes counts the bytes those expansions produced.
+	Bytes uint64
+	// Tags counts expanded tags. Optional tags that were left out are not
+	// counted.
+	Tags uint64
+	// Fallbacks counts tags that named no keyword, or an unknown or
+	// disabled one, and so expanded to CharsAll characters.
+	Fallbacks uint64
+	// Keywords counts expansions per upper-case keyword name. Inline
+	// character classes are counted together under "[]".
+	Keywords map[string]uint64
+}
+
+// classStatsKey is the Keywords key inline character classes are counted
+// under, so templates with many distinct classes cannot grow the map.
+const classStatsKey = "[]"
+
+// engineStats holds the live counters behind EngineStats.
+type engineStats struct {
+	payloads  atomic.Uint64
+	bytes     atomic.Uint64
+	tags      atomic.Uint64
+	fallbacks atomic.Uint64
+	mu        sync.RWMutex
+	keywords  map[string]*atomic.Uint64
+}
+
+func newEngineStats() *engineStats {
+	return &engineStats{keywords: make(map[string]*atomic.Uint64)}
+}
+
+// count records one expansion of kw.
+func (s *engineStats) count(kw *keywordSpec) {
+	s.tags.Add(1)
+	if kw.fallback {
+		s.fallbacks.Add(1)
+		return
+	}
+	name := classStatsKey
+	if kw.charset == nil || kw.n > 0 {
+		name = kw.upper()
+	}
+	s.mu.RLock()
+	c, ok := s.keywords[name]
+	s.mu.RUnlock()
+	if !ok {
+		s.mu.Lock()
+		if c, ok = s.keywords[name]; !ok {
+			c = new(atomic.Uint64)
+			s.keywords[strings.Clone(name)] = c
+		}
+		s.mu.Unlock()
+	}
+	c.Add(1)
+}
+
+// countPayload records one expanded payload of n bytes.
+func (s *engineStats) countPayload(n int) {
+	s.payloads.Add(1)
+	s.bytes.Add(uint64(n))
+}
+
+func (s *engineStats) snapshot() EngineStats {
+	st := EngineStats{
+		Payloads:  s.payloads.Load(),
+		Bytes:     s.bytes.Load(),
+		Tags:      s.tags.Load(),
+		Fallbacks: s.fallbacks.Load(),
+	}
+	s.mu.RLock()
+	st.Keywords = make(map[string]uint64, len(s.keywords))
+	for name, c := range s.keywords {
+		st.Keywords[name] = c.Load()
+	}
+	s.mu.RUnlock()
+	return st
+}
+
+// WithStats makes the engine count expanded payloads, generated bytes and
+// tags per keyword for Stats.
+// Counting costs a little time per tag, so it is off by default.
+func WithStats(enabled bool) Option {
+	return func(e *FastEngine) {
+		e.stats = nil
+		if enabled {
+			e.stats = newEngineStats()
+		}
+	}
+}
+
+// Stats returns a snapshot of the engine's counters. It is empty unless the
+// engine was built with WithStats(true).
+func (e *FastEngine) Stats() EngineStats {
+	if e.stats == nil {
+		return EngineStats{}
+	}
+	return e.stats.snapshot()
+}
+
+// ReplaceHook is called by engines built with WithOnReplace after each tag
+// is expanded, with the tag's keyword, the length it was expanded with and
+// the bytes it produced. output is only valid during the call.
+type ReplaceHook func(keyword string, length int, output []byte)
+
+// WithOnReplace calls hook after every tag expansion, for example to log
+// or meter generated values. keyword is the upper-case keyword name, an
+// inline character class as written, or "" for a tag without a keyword.
+// Optional tags that are left out are not reported. hook must be safe for
+// concurrent use if the engine is.
+func WithOnReplace(hook ReplaceHook) Option {
+	return func(e *FastEngine) {
+		e.onReplace = hook
+	}
+}
+
+// keywordName returns the name WithOnReplace reports for kw.
+func keywordName(kw *keywordSpec) string {
+	if kw.n == 0 {
+		name, _ := splitKeywordParams(kw.text)
+		name, _ = splitExclusion(name)
+		return string(name)
+	}
+	upper := kw.upper()
+	for _, builtin := range allKeywords {
+		if builtin == upper {
+			return builtin
+		}
+	}
+	return strings.Clone(upper)
+}
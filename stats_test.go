@@ -0,0 +1,108 @@
+package fastrand_test
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStats(t *testing.T) {
+	engine := fastrand.NewEngine(
+		fastrand.WithStats(true),
+		fastrand.WithDisabledKeywords("IPV6"),
+		fastrand.WithCustomKeyword("ping", func(int) []byte { return []byte("pong") }),
+	)
+	for range 3 {
+		engine.RandomizerString("{RAND;8;HEX}{RAND;uuid}{RAND;4;[a-f]}{RAND;4;[0-9]}{RAND;PING}{RAND}{RAND;IPV6}{RAND?0;DIGIT}")
+	}
+	assert.Equal(t, fastrand.EngineStats{
+		Payloads:  3,
+		Bytes:     3 * (8 + 36 + 4 + 4 + 4 + 20 + 20),
+		Tags:      21,
+		Fallbacks: 6,
+		Keywords:  map[string]uint64{"HEX": 3, "UUID": 3, "[]": 6, "PING": 3},
+	}, engine.Stats())
+
+	tmpl, err := engine.Compile([]byte("{RAND;DIGIT}"))
+	require.NoError(t, err)
+	tmpl.Execute()
+	assert.Equal(t, uint64(1), engine.Stats().Keywords["DIGIT"])
+
+	assert.Equal(t, fastrand.EngineStats{}, fastrand.NewEngine().Stats(), "off by default")
+	engine.Reset()
+	assert.Equal(t, fastrand.EngineStats{}, engine.Stats())
+}
+
+func TestStatsPayloads(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithStats(true))
+	engine.Randomizer([]byte("id={RAND;8;DIGIT}"))
+	engine.RandomizerAppend([]byte("prefix"), []byte("{RAND;4;DIGIT}"))
+	engine.Randomizer([]byte("no tags here"))
+
+	tmpl, err := engine.Compile([]byte("{RAND;6;DIGIT}"))
+	require.NoError(t, err)
+	tmpl.Execute()
+
+	out, err := io.ReadAll(engine.RandomizerReader(strings.NewReader("{RAND;10;DIGIT}-{RAND;2;DIGIT}")))
+	require.NoError(t, err)
+	require.Len(t, out, 13)
+
+	st := engine.Stats()
+	assert.Equal(t, uint64(4), st.Payloads)
+	assert.Equal(t, uint64(11+4+6+13), st.Bytes)
+}
+
+func TestStatsConcurrent(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithStats(true))
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				engine.RandomizerString("{RAND;HEX}{RAND;DIGIT,ABL}")
+			}
+		}()
+	}
+	wg.Wait()
+	st := engine.Stats()
+	assert.Equal(t, uint64(1600), st.Tags)
+	assert.Equal(t, uint64(800), st.Keywords["HEX"])
+	assert.Equal(t, uint64(800), st.Keywords["DIGIT"]+st.Keywords["ABL"])
+}
+
+func TestWithOnReplace(t *testing.T) {
+	type call struct {
+		keyword string
+		length  int
+		output  string
+	}
+	var calls []call
+	engine := fastrand.NewEngine(
+		fastrand.WithOnReplace(func(keyword string, length int, output []byte) {
+			calls = append(calls, call{keyword, length, string(output)})
+		}),
+		fastrand.WithCycle("env", "prod"),
+	)
+	out := engine.RandomizerString("a={RAND;4;hex}&b={RAND;CYCLE:env}&c={RAND;3;[x]}&d={RAND;2;NOPE}&e={RAND?0;DIGIT}")
+
+	require.Len(t, calls, 4)
+	assert.Equal(t, "HEX", calls[0].keyword)
+	assert.Equal(t, 4, calls[0].length)
+	assert.Contains(t, out, "a="+calls[0].output+"&")
+	assert.Equal(t, call{"CYCLE", 16, "prod"}, calls[1])
+	assert.Equal(t, call{"[x]", 3, "xxx"}, calls[2])
+	assert.Equal(t, "NOPE", calls[3].keyword)
+	assert.Len(t, calls[3].output, 2)
+
+	calls = nil
+	engine.RandomizerString("{RAND}")
+	require.Len(t, calls, 1)
+	assert.Equal(t, "", calls[0].keyword)
+	assert.Len(t, calls[0].output, 16)
+}
@@ -0,0 +1,208 @@
+package fastrand
+
+import (
+	"bytes"
+	"io"
+)
+
+const (
+	// streamChunkSize is how much input RandomizerReader reads at a time.
+	streamChunkSize = 32 << 10
+	// maxStreamTag bounds how far RandomizerReader looks for the closing
+	// brace of a tag. Longer tags are passed through as literal text so an
+	// unterminated "{RAND" cannot make the reader buffer the whole stream.
+	maxStreamTag = 4096
+	// maxStreamBlock bounds how far RandomizerReader looks for the end of a
+	// {RAND-REPEAT} block before passing its opening tag through literally.
+	maxStreamBlock = 1 << 20
+)
+
+// encodedTokens are the encoded tag pieces the input decoder recognizes.
+var encodedTokens = [][]byte{
+	startUrlEncoded, endTagUrl, sepTagUrl, startHtmlEncoded, endTagHtml, sepTagHtml,
+	[]byte("\\u007BRAND"), []byte("\\u007bRAND"), []byte("\\x7BRAND"), []byte("\\x7bRAND"),
+	[]byte("\\u007D"), []byte("\\u007d"), []byte("\\x7D"), []byte("\\x7d"),
+	[]byte("\\u003B"), []byte("\\u003b"), []byte("\\x3B"), []byte("\\x3b"),
+}
+
+// RandomizerReader returns a reader that expands the tags in r as it is
+// read, with the default engine. See FastEngine.RandomizerReader.
+func RandomizerReader(r io.Reader) io.Reader {
+	return defaultEngine.Load().RandomizerReader(r)
+}
+
+// RandomizerReader returns a reader that expands the tags in r on the fly,
+// so arbitrarily large payloads can be randomized in constant memory. Tags
+// and encoded tags split across reads of r are handled; the output matches
+// Randomizer on the whole input except that a tag whose closing brace is
+// more than 4 KiB away, or a repeat block longer than 1 MiB, is passed
+// through literally. An error from r is returned after the output produced
+// so far.
+func (e *FastEngine) RandomizerReader(r io.Reader) io.Reader {
+	return &randomizerReader{e: e, r: r, chunk: make([]byte, streamChunkSize)}
+}
+
+type randomizerReader struct {
+	e         *FastEngine
+	r         io.Reader
+	chunk     []byte
+	raw       []byte // input not yet decoded
+	rewritten []byte // raw input with its dialect tags rewritten
+	decoded   []byte // decoded input not yet expanded
+	out       []byte
+	off       int   // read position in out
+	err       error // sticky error from r, io.EOF at the end
+	x         expansion
+	written   int // bytes produced so far, for Stats
+}
+
+func (s *randomizerReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	for s.off == len(s.out) {
+		if s.err != nil {
+			return 0, s.err
+		}
+		s.out, s.off = s.out[:0], 0
+		n, err := s.r.Read(s.chunk)
+		if s.e.inputNormalizer != nil && n > 0 {
+			s.raw = append(s.raw, s.e.inputNormalizer(s.chunk[:n])...)
+		} else {
+			s.raw = append(s.raw, s.chunk[:n]...)
+		}
+		if err != nil {
+			s.err = err
+		}
+		s.x.limitFrom(s.e, -s.written)
+		s.process(s.err != nil)
+		s.x.truncate(&s.out)
+		if err := s.x.err(); err != nil {
+			s.err = io.EOF
+			if s.e.strictParsing {
+				s.err = err
+			}
+		}
+		s.written += len(s.out)
+		if s.err != nil && s.e.stats != nil {
+			s.e.stats.countPayload(s.written)
+		}
+	}
+	n := copy(p, s.out[s.off:])
+	s.off += n
+	return n, nil
+}
+
+// process decodes and expands as much buffered input as can be handled
+// without seeing more of the stream. When final is set everything is
+// flushed.
+func (s *randomizerReader) process(final bool) {
+	enc := s.e.inputEncoding
+	cut := len(s.raw)
+	if !final && len(s.e.dialects) > 0 {
+		cut -= s.e.partialDialectSuffix(s.raw)
+	}
+	if !final && enc != RandomizerEncodingNone {
+		cut -= partialEncodedSuffix(s.raw[:cut])
+	}
+	input := s.raw[:cut]
+	if len(s.e.dialects) > 0 && s.e.hasDialectTags(input) {
+		s.rewritten = s.e.rewriteDialects(s.rewritten[:0], input)
+		input = s.rewritten
+	}
+	if enc != RandomizerEncodingNone && bytes.ContainsAny(input, encodedChars) {
+		s.decoded = normalizeInto(s.decoded, input, enc)
+	} else {
+		s.decoded = append(s.decoded, input...)
+	}
+	s.raw = append(s.raw[:0], s.raw[cut:]...)
+
+	n := s.expand(final)
+	s.decoded = append(s.decoded[:0], s.decoded[n:]...)
+}
+
+// expand appends the expansion of the complete part of s.decoded to s.out
+// and returns how many bytes were consumed.
+func (s *randomizerReader) expand(final bool) int {
+	e, d, x := s.e, s.decoded, &s.x
+	var lengths [16]weightedLength
+	var keywords [4]keywordSpec
+	cursor, refIndex := 0, -1
+	for {
+		if x.stopped(s.out) {
+			return len(d)
+		}
+		startIndex, isRef := nextTag(d, cursor, len(x.vars) > 0, &refIndex)
+		if startIndex == -1 {
+			end := len(d)
+			if !final {
+				held := partialPrefix(d[cursor:], startTag)
+				if len(x.vars) > 0 {
+					held = max(held, partialPrefix(d[cursor:], refTag))
+				}
+				end -= held
+			}
+			e.writeEncoded(&s.out, d[cursor:end])
+			return end
+		}
+		e.writeEncoded(&s.out, d[cursor:startIndex])
+
+		endIndex := bytes.IndexByte(d[startIndex:], endTag)
+		if endIndex == -1 {
+			switch {
+			case final:
+				e.writeEncoded(&s.out, d[startIndex:])
+				return len(d)
+			case len(d)-startIndex <= maxStreamTag:
+				return startIndex
+			}
+			cursor = startIndex + 1
+			e.writeEncoded(&s.out, d[startIndex:cursor])
+			continue
+		}
+		endIndex += startIndex
+		cursor = endIndex + 1
+		if !x.countTag() {
+			return len(d)
+		}
+
+		if isRef {
+			e.appendRef(&s.out, d[startIndex:endIndex], d[startIndex:cursor], x)
+		} else if bytes.HasPrefix(d[startIndex:endIndex], repeatOpen) {
+			if next, ok := e.expandRepeat(d, &s.out, startIndex, cursor, x); ok {
+				cursor = next
+			} else if _, valid := parseRepeat(d[startIndex:endIndex]); valid && !final &&
+				len(d)-startIndex <= maxStreamBlock && findRepeatEnd(d[cursor:]) == -1 {
+				x.tags-- // counted again once the block is complete
+				return startIndex
+			} else {
+				e.writeEncoded(&s.out, d[startIndex:cursor])
+			}
+		} else if spec, ok := e.expandableTag(d[startIndex:endIndex], lengths[:0], keywords[:0], x.fallback != nil); ok {
+			e.expandTag(&s.out, &spec, x)
+		} else {
+			e.writeEncoded(&s.out, d[startIndex:cursor])
+		}
+	}
+}
+
+// partialPrefix returns the length of the longest proper prefix of prefix
+// that b ends with.
+func partialPrefix(b, prefix []byte) int {
+	for k := min(len(prefix)-1, len(b)); k > 0; k-- {
+		if bytes.HasSuffix(b, prefix[:k]) {
+			return k
+		}
+	}
+	return 0
+}
+
+// partialEncodedSuffix returns the length of the longest suffix of b that
+// could be the start of an encoded tag piece.
+func partialEncodedSuffix(b []byte) int {
+	longest := 0
+	for _, token := range encodedTokens {
+		longest = max(longest, partialPrefix(b, token))
+	}
+	return longest
+}
@@ -0,0 +1,92 @@
+package fastrand_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRandomizerReaderMatchesRandomizer(t *testing.T) {
+	payloads := []string{
+		"User: {RAND;10-20;ABL,ABU} | Session: {RANDOM;32;HEX} | ID: {RAND;UUID,HEX} | IP: {RAND;IPV4} --- End",
+		"{RAND}{RAND;5,7,9;DIGIT}{RAND;~20±4;ABL}{RAND",
+		"{RAND;5;NOSUCH}{RANDX}{RA",
+		"%7BRAND%3B8%3BDIGIT%7D and &lbrace;RAND&semi;4&semi;HEX&rbrace; trailing %7BRA",
+		strings.Repeat("line {RAND;12;ABL} {RAND;UUID}\n", 5000),
+	}
+	wrappers := map[string]func(io.Reader) io.Reader{
+		"whole":   func(r io.Reader) io.Reader { return r },
+		"onebyte": iotest.OneByteReader,
+		"half":    iotest.HalfReader,
+		"dataerr": iotest.DataErrReader,
+	}
+	for _, payload := range payloads {
+		for name, wrap := range wrappers {
+			want := fastrand.NewEngine(fastrand.WithSeed(5)).Randomizer([]byte(payload))
+			r := fastrand.NewEngine(fastrand.WithSeed(5)).RandomizerReader(wrap(strings.NewReader(payload)))
+			got, err := io.ReadAll(r)
+			require.NoError(t, err, name)
+			assert.Equal(t, string(want), string(got), "%s: %.40q", name, payload)
+		}
+	}
+}
+
+func TestRandomizerReaderIOContract(t *testing.T) {
+	payload := strings.Repeat("k={RAND;8;HEX}&", 100)
+	want := fastrand.NewEngine(fastrand.WithSeed(9)).Randomizer([]byte(payload))
+	r := fastrand.NewEngine(fastrand.WithSeed(9)).RandomizerReader(strings.NewReader(payload))
+	assert.NoError(t, iotest.TestReader(r, want))
+}
+
+func TestRandomizerReaderUnterminatedTag(t *testing.T) {
+	payload := "{RAND;8;DIGIT" + strings.Repeat("x", 50000) + "}"
+	got, err := io.ReadAll(fastrand.RandomizerReader(iotest.HalfReader(strings.NewReader(payload))))
+	require.NoError(t, err)
+	assert.Equal(t, payload, string(got), "a tag without a nearby closing brace is passed through")
+}
+
+func TestRandomizerReaderError(t *testing.T) {
+	boom := errors.New("boom")
+	r := io.MultiReader(strings.NewReader("id={RAND;4;DIGIT};"), iotest.ErrReader(boom))
+	var out bytes.Buffer
+	_, err := io.Copy(&out, fastrand.RandomizerReader(r))
+	assert.ErrorIs(t, err, boom)
+	assert.Regexp(t, `^id=[0-9]{4};$`, out.String())
+}
+
+type failingWriter struct{ err error }
+
+func (w failingWriter) Write([]byte) (int, error) { return 0, w.err }
+
+func TestRandomizerTo(t *testing.T) {
+	var out bytes.Buffer
+	n, err := fastrand.RandomizerTo(&out, []byte("id={RAND;8;DIGIT}"))
+	require.NoError(t, err)
+	assert.Equal(t, 11, n)
+	assert.Regexp(t, `^id=[0-9]{8}$`, out.String())
+
+	out.Reset()
+	_, err = fastrand.RandomizerTo(&out, []byte("plain"))
+	require.NoError(t, err)
+	assert.Equal(t, "plain", out.String())
+
+	boom := errors.New("boom")
+	_, err = fastrand.RandomizerTo(failingWriter{boom}, []byte("{RAND;4;HEX}"))
+	assert.ErrorIs(t, err, boom)
+}
+
+func TestAllocsRandomizerTo(t *testing.T) {
+	engine := fastrand.NewEngine()
+	payload := []byte("hello {RAND;16;ABL} {RAND;8;DIGIT} %7BRAND%3B4%3BHEX%7D")
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = engine.RandomizerTo(io.Discard, payload)
+	})
+	assert.Zero(t, allocs)
+}
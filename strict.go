@@ -0,0 +1,73 @@
+package fastrand
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"sync/atomic"
+)
+
+// ErrInsecureSource is returned by Secure* functions in strict secure mode
+// when SecureReader has been replaced with a reader other than the one this
+// package installs.
+var ErrInsecureSource = errors.New("fastrand: SecureReader is not backed by crypto/rand in strict secure mode")
+
+var (
+	strictSecure        atomic.Bool
+	seededFromClock     bool
+	defaultSecureReader *randReader
+)
+
+// StrictSecureMode switches the package into strict secure mode for the
+// rest of the process. In strict mode every Secure* function draws directly
+// from crypto/rand instead of the ChaCha8 generator, so a clock-based seed
+// chosen when crypto/rand failed at startup is never used for secure
+// output, and Secure* functions that read SecureReader return
+// ErrInsecureSource if it has been reassigned, for example to FastReader.
+// The fast, non-Secure APIs are unaffected. Strict mode cannot be turned
+// off; it returns an error if crypto/rand is unusable.
+func StrictSecureMode() error {
+	var probe [8]byte
+	if _, err := crand.Read(probe[:]); err != nil {
+		return err
+	}
+	chaChaMu.Lock()
+	chaChaSrc = rand.New(cryptoSource{})
+	chaChaMu.Unlock()
+	strictSecure.Store(true)
+	return nil
+}
+
+// StrictSecureModeEnabled reports whether StrictSecureMode has been called.
+func StrictSecureModeEnabled() bool {
+	return strictSecure.Load()
+}
+
+// SeededFromClock reports whether crypto/rand failed at startup and the
+// generators were seeded from the clock instead.
+func SeededFromClock() bool {
+	return seededFromClock
+}
+
+// cryptoSource is a rand.Source reading from crypto/rand.
+type cryptoSource struct{}
+
+func (cryptoSource) Uint64() uint64 {
+	var b [8]byte
+	if _, err := crand.Read(b[:]); err != nil {
+		panic("fastrand: crypto/rand failed in strict secure mode: " + err.Error())
+	}
+	return binary.LittleEndian.Uint64(b[:])
+}
+
+// secureRead fills p from SecureReader, refusing replaced readers in strict
+// secure mode.
+func secureRead(p []byte) error {
+	if strictSecure.Load() && SecureReader != io.Reader(defaultSecureReader) {
+		return ErrInsecureSource
+	}
+	_, err := SecureReader.Read(p)
+	return err
+}
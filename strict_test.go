@@ -0,0 +1,40 @@
+package fastrand_test
+
+import (
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestStrictSecureMode enables strict mode for the rest of the test binary;
+// every Secure* API must keep working under it.
+func TestStrictSecureMode(t *testing.T) {
+	require.NoError(t, fastrand.StrictSecureMode())
+	assert.True(t, fastrand.StrictSecureModeEnabled())
+	assert.False(t, fastrand.SeededFromClock())
+
+	b, err := fastrand.SecureBytes(32)
+	require.NoError(t, err)
+	assert.Len(t, b, 32)
+	s, err := fastrand.SecureString(16, fastrand.CharsAlphabet)
+	require.NoError(t, err)
+	assert.Len(t, s, 16)
+	n, err := fastrand.SecureIntN(10)
+	require.NoError(t, err)
+	assert.Less(t, n, 10)
+	_, err = fastrand.SecureUUID()
+	require.NoError(t, err)
+	_, err = fastrand.SecureIPv6()
+	require.NoError(t, err)
+
+	original := fastrand.SecureReader
+	fastrand.SecureReader = fastrand.FastReader
+	defer func() { fastrand.SecureReader = original }()
+
+	_, err = fastrand.SecureUUID()
+	assert.ErrorIs(t, err, fastrand.ErrInsecureSource)
+	_, err = fastrand.SecureIPv4()
+	assert.ErrorIs(t, err, fastrand.ErrInsecureSource)
+}
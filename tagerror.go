@@ -0,0 +1,234 @@
+package fastrand
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// TagError describes a malformed tag found in strict parsing mode. Offset
+// is the byte offset of the tag in the payload after input decoding, which
+// is the payload itself unless it contains URL or HTML encoded tags, and
+// Length the length of the tag there, braces included, or of the rest of
+// the payload for an unterminated tag, so editors can highlight it.
+type TagError struct {
+	Offset int
+	Length int
+	Reason string
+}
+
+// missingKeyword is the reason reported for tags that name no keyword under
+// WithRequireKeyword.
+const missingKeyword = "missing keyword: the engine requires one in every tag"
+
+func (e *TagError) Error() string {
+	return fmt.Sprintf("fastrand: tag at offset %d: %s", e.Offset, e.Reason)
+}
+
+// RandomizerErr is like Randomizer but, when the engine was built with
+// WithStrictParsing(true), returns a *TagError for a malformed tag
+// (missing '}', bad length, unknown or disabled keyword, reference to an
+// undefined variable) instead of passing it through or substituting
+// defaults. Several malformed tags are reported together, as one *TagError
+// each joined with errors.Join. It returns ErrOutputTooLarge or ErrTooManyTags when the
+// expansion exceeds the WithMaxOutputSize or WithMaxTags budget. Without
+// strict parsing the error is nil, unless the engine uses
+// UnknownKeywordError, and such output is cut short.
+func (e *FastEngine) RandomizerErr(payload []byte) ([]byte, error) {
+	return e.RandomizerCtx(context.Background(), payload)
+}
+
+// Validate checks payload with the default engine. See FastEngine.Validate.
+func Validate(payload []byte) error {
+	return defaultEngine.Load().Validate(payload)
+}
+
+// Validate reports the malformed tags in payload as *TagError values,
+// joined with errors.Join when there are several, without expanding it: unterminated tags, lengths outside [min, max],
+// malformed ranges, disabled or unknown keywords and the other mistakes
+// that WithStrictParsing rejects, so template authors get feedback up front
+// instead of fallback CharsAll strings. It works whether or not the engine
+// parses strictly.
+func (e *FastEngine) Validate(payload []byte) error {
+	normalized, scratch := e.normalized(payload)
+	defer e.release(scratch)
+	return e.checkTags(normalized)
+}
+
+// checkTags returns a *TagError for each malformed tag in payload, joined
+// by joinTagErrors. References and FROM= segments must follow the tag that
+// sets their variable. Checking stops at an unterminated tag or once the
+// engine's tag limit is exceeded.
+func (e *FastEngine) checkTags(payload []byte) error {
+	var errs []error
+	var defined [][]byte
+	cursor, refIndex, tags := 0, -1, 0
+	for {
+		startIndex, isRef := nextTag(payload, cursor, true, &refIndex)
+		if startIndex == -1 {
+			return joinTagErrors(errs)
+		}
+		endIndex := bytes.IndexByte(payload[startIndex:], endTag)
+		if endIndex == -1 {
+			errs = append(errs, &TagError{Offset: startIndex, Length: len(payload) - startIndex, Reason: "unterminated tag: missing '}'"})
+			return joinTagErrors(errs)
+		}
+		endIndex += startIndex
+		tag := payload[startIndex:endIndex]
+		cursor = endIndex + 1
+		fail := func(reason string) {
+			errs = append(errs, &TagError{Offset: startIndex, Length: cursor - startIndex, Reason: reason})
+		}
+		if tags++; e.maxTags > 0 && tags > e.maxTags {
+			fail(fmt.Sprintf("too many tags: the limit is %d", e.maxTags))
+			return joinTagErrors(errs)
+		}
+
+		if isRef {
+			name, ok := refName(tag)
+			switch {
+			case !ok:
+				fail("expected ';' and a variable name after \"{REF\"")
+			case !slices.ContainsFunc(defined, func(d []byte) bool { return bytes.Equal(d, name) }):
+				fail(fmt.Sprintf("undefined variable %q", name))
+			}
+			continue
+		}
+		if bytes.HasPrefix(tag, repeatOpen) {
+			if _, ok := parseRepeat(tag); !ok {
+				fail(fmt.Sprintf("invalid repeat count in %q: want n or min-max up to %d, optionally after ?percent", tag[len(repeatOpen):], maxRepeat))
+			} else if findRepeatEnd(payload[cursor:]) == -1 {
+				fail("unclosed repeat block: missing {/RAND-REPEAT}")
+			}
+			continue
+		}
+		if bytes.HasPrefix(tag, base64Open) && e.inputEncoding&RandomizerEncodingBase64 != 0 {
+			continue
+		}
+		if reason := e.checkTag(tag); reason != "" {
+			fail(reason)
+		} else if spec, ok := e.parseTag(tag, nil, nil); ok && spec.source != nil {
+			if reason := checkSource(&spec, defined); reason != "" {
+				fail(reason)
+			}
+		}
+		if _, body, _ := parseChance(bytes.TrimPrefix(tag[len(startTag):], startTagOpt)); len(body) > 0 {
+			if _, name := splitVariable(body[1:]); name != nil {
+				defined = append(defined, name)
+			}
+		}
+	}
+}
+
+// joinTagErrors returns nil when errs is empty, its only error, so that a
+// single malformed tag is reported as a plain *TagError, or all of them
+// joined with errors.Join.
+func joinTagErrors(errs []error) error {
+	if len(errs) == 1 {
+		return errs[0]
+	}
+	return errors.Join(errs...)
+}
+
+// checkTag returns why tag, which starts with "{RAND" and excludes the
+// closing brace, is malformed, or "" if it is well formed.
+func (e *FastEngine) checkTag(tag []byte) string {
+	body := bytes.TrimPrefix(tag[len(startTag):], startTagOpt)
+	_, body, ok := parseChance(body)
+	if !ok {
+		return fmt.Sprintf("invalid probability in %q: want ?0 to ?100", tag)
+	}
+	if len(body) == 0 {
+		if e.requireKeyword {
+			return missingKeyword
+		}
+		return ""
+	}
+	if body[0] != sepTag {
+		return fmt.Sprintf("expected ';' or '}' after %q", tag[:len(tag)-len(body)])
+	}
+	body, name := splitVariable(body[1:])
+	if name != nil && len(name) == 0 {
+		return "empty variable name after \"VAR=\""
+	}
+	body, _ = splitCaseModifier(body)
+	body, source := splitSource(body)
+	if source != nil && len(source) == 0 {
+		return "empty variable name after \"FROM=\""
+	}
+
+	var typeKeyword, lenPart []byte
+	if sepIndex := bytes.IndexByte(body, sepTag); sepIndex == -1 {
+		lenPart = body
+	} else {
+		lenPart = body[:sepIndex]
+		typeKeyword = body[sepIndex+1:]
+	}
+	for _, list := range [][]byte{lenPart, typeKeyword} {
+		if e.tooManyChoices(list) {
+			return fmt.Sprintf("too many choices in %q: the limit is %d", list, e.maxChoices)
+		}
+	}
+	if _, ok = e.parseLength(lenPart, nil); !ok {
+		switch {
+		case len(lenPart) > 0 && (lenPart[0] == '~' || lenPart[0] >= '0' && lenPart[0] <= '9'):
+			return fmt.Sprintf("invalid length %q: lengths must be within [%d, %d]", lenPart, e.minLength, e.maxLength)
+		case typeKeyword != nil && len(lenPart) > 0:
+			return fmt.Sprintf("invalid length %q", lenPart)
+		case typeKeyword == nil:
+			typeKeyword = lenPart
+		}
+	}
+
+	if e.keywordChoicesEnabled && indexChoiceSep(typeKeyword) != -1 {
+		for len(typeKeyword) > 0 {
+			var choice []byte
+			if i := indexChoiceSep(typeKeyword); i != -1 {
+				choice, typeKeyword = typeKeyword[:i], typeKeyword[i+1:]
+				if len(typeKeyword) == 0 {
+					return "empty keyword in choice list"
+				}
+			} else {
+				choice, typeKeyword = typeKeyword, nil
+			}
+			choice, _, _ = splitWeight(choice)
+			if len(choice) == 0 {
+				return "empty keyword in choice list"
+			}
+			if reason := e.checkKeyword(choice); reason != "" {
+				return reason
+			}
+		}
+		return ""
+	}
+	if len(typeKeyword) == 0 && e.requireKeyword {
+		return missingKeyword
+	}
+	return e.checkKeyword(typeKeyword)
+}
+
+func (e *FastEngine) checkKeyword(keyword []byte) string {
+	if len(keyword) == 0 {
+		return ""
+	}
+	kw := e.resolveKeyword(keyword)
+	if kw.excluded {
+		return fmt.Sprintf("exclusion in %q leaves no characters", keyword)
+	}
+	if !kw.fallback {
+		if reason := e.checkParams(&kw, keyword); reason != "" {
+			return reason
+		}
+		return checkArg(&kw, keyword)
+	}
+	name, _ := splitKeywordParams(keyword)
+	if isCharClass(name) {
+		return fmt.Sprintf("invalid character class %q", keyword)
+	}
+	if _, exists := e.keywords.Load().enabled[kw.upper()]; exists {
+		return fmt.Sprintf("keyword %q is disabled", keyword)
+	}
+	return fmt.Sprintf("unknown keyword %q", keyword)
+}
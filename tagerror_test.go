@@ -0,0 +1,164 @@
+package fastrand_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRandomizerErrStrict(t *testing.T) {
+	engine := fastrand.NewEngine(
+		fastrand.WithStrictParsing(true),
+		fastrand.WithDisabledKeywords("IPV6"),
+	)
+	cases := []struct {
+		payload string
+		offset  int
+		reason  string
+	}{
+		{"id={RAND;8;DIGIT", 3, "unterminated"},
+		{"ok={RAND;4;ABL} bad={RAND;500;ABL}", 20, `invalid length "500"`},
+		{"{RAND;5-3;ABL}", 0, `invalid length "5-3"`},
+		{"{RAND;abc;ABL}", 0, `invalid length "abc"`},
+		{"xx{RAND;8;NOPE}", 2, `unknown keyword "NOPE"`},
+		{"{RAND;8;ABL,NOPE}", 0, `unknown keyword "NOPE"`},
+		{"{RAND;8;ABL,}", 0, "empty keyword"},
+		{"{RAND;IPV6}", 0, `keyword "IPV6" is disabled`},
+		{"{RANDX}", 0, "expected ';' or '}'"},
+		{"{RAND;~200±5;ABL}", 0, "invalid length"},
+	}
+	for _, tc := range cases {
+		out, err := engine.RandomizerErr([]byte(tc.payload))
+		var tagErr *fastrand.TagError
+		if assert.True(t, errors.As(err, &tagErr), "%q should fail, got %v", tc.payload, err) {
+			assert.Equal(t, tc.offset, tagErr.Offset, tc.payload)
+			assert.Contains(t, tagErr.Reason, tc.reason, tc.payload)
+		}
+		assert.Nil(t, out)
+	}
+}
+
+func TestRandomizerErrValid(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithStrictParsing(true))
+	for _, payload := range []string{
+		"plain text",
+		"{RAND}{RANDOM;4;HEX}{RAND;UUID}{RAND;;ABL}",
+		"{RAND;5-10:zipf;ABL,ABU}{RAND;3,4;DIGIT}{RAND;~20±3;ABR}",
+		"{RAND;SEQ:ids}{RAND;FORM:email}",
+		"%7BRAND%3B8%3BDIGIT%7D",
+	} {
+		out, err := engine.RandomizerErr([]byte(payload))
+		require.NoError(t, err, payload)
+		assert.NotEmpty(t, out)
+	}
+}
+
+func TestRandomizerErrLenient(t *testing.T) {
+	engine := fastrand.NewEngine()
+	out, err := engine.RandomizerErr([]byte("x={RAND;8;NOPE"))
+	require.NoError(t, err)
+	assert.Equal(t, "x={RAND;8;NOPE", string(out))
+}
+
+func TestCompileStrict(t *testing.T) {
+	_, err := fastrand.NewEngine(fastrand.WithStrictParsing(true)).Compile([]byte("a {RAND;8;NOPE}"))
+	var tagErr *fastrand.TagError
+	require.ErrorAs(t, err, &tagErr)
+	assert.Equal(t, 2, tagErr.Offset)
+	assert.EqualError(t, err, `fastrand: tag at offset 2: unknown keyword "NOPE"`)
+
+	_, err = fastrand.NewEngine().Compile([]byte("a {RAND;8;NOPE}"))
+	assert.NoError(t, err)
+}
+
+func TestValidate(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithDisabledKeywords("EMAIL"), fastrand.WithMaxLength(32))
+	cases := []struct {
+		payload string
+		offset  int
+		reason  string
+	}{
+		{"a={RAND;8;HEX", 2, "unterminated"},
+		{"{RAND;64;HEX}", 0, `invalid length "64": lengths must be within [1, 32]`},
+		{"{RAND;9-4;HEX}", 0, `invalid length "9-4"`},
+		{"x{RAND;8;EMAIL}", 1, `keyword "EMAIL" is disabled`},
+		{"{RAND;8;BOGUS}", 0, `unknown keyword "BOGUS"`},
+		{"%7BRAND%3B8%3BNOPE%7D", 0, `unknown keyword "NOPE"`},
+	}
+	for _, tc := range cases {
+		err := engine.Validate([]byte(tc.payload))
+		var tagErr *fastrand.TagError
+		if assert.True(t, errors.As(err, &tagErr), "%q should fail, got %v", tc.payload, err) {
+			assert.Equal(t, tc.offset, tagErr.Offset, tc.payload)
+			assert.Contains(t, tagErr.Reason, tc.reason, tc.payload)
+		}
+	}
+
+	assert.NoError(t, engine.Validate([]byte("id={RAND;8;HEX}&n={RAND;2-6;DIGIT}")))
+	assert.NoError(t, fastrand.Validate([]byte("{RAND;UUID}")))
+	assert.Error(t, fastrand.Validate([]byte("{RAND;UUID")), "the default engine is lenient but still validates")
+}
+
+func TestRequireKeyword(t *testing.T) {
+	lenient := fastrand.NewEngine(fastrand.WithRequireKeyword(true))
+	for _, tag := range []string{"{RAND}", "{RANDOM}", "{RAND;8}", "{RAND;4-8}", "{RAND?50;8}", "{RAND;8;VAR=x}", "{RAND;8;upper}"} {
+		assert.Equal(t, "a="+tag, lenient.RandomizerString("a="+tag), "%s should be copied verbatim", tag)
+	}
+	assert.Regexp(t, `^[0-9]{8}$`, lenient.RandomizerString("{RAND;8;DIGIT}"))
+	assert.Regexp(t, `^[a-f0-9]{3}$`, lenient.RandomizerString("{RAND;3;[a-f0-9]}"))
+	assert.Len(t, lenient.RandomizerString("{RAND;8;NOPE}"), 8, "unknown keywords follow the unknown keyword policy")
+
+	tmpl, err := lenient.Compile([]byte("{RAND;8}"))
+	require.NoError(t, err)
+	assert.Equal(t, "{RAND;8}", tmpl.ExecuteString())
+
+	strict := fastrand.NewEngine(fastrand.WithRequireKeyword(true), fastrand.WithStrictParsing(true))
+	for _, payload := range []string{"{RAND}", "ok={RAND;4;ABL}{RAND;8}"} {
+		_, err := strict.RandomizerErr([]byte(payload))
+		var tagErr *fastrand.TagError
+		if assert.True(t, errors.As(err, &tagErr), payload) {
+			assert.Contains(t, tagErr.Reason, "missing keyword", payload)
+		}
+	}
+	assert.Error(t, lenient.Validate([]byte("{RAND;8}")))
+	assert.NoError(t, fastrand.NewEngine().Validate([]byte("{RAND;8}")))
+
+	c := strict.Config()
+	assert.True(t, c.RequireKeyword)
+	restored, err := c.NewEngine()
+	require.NoError(t, err)
+	assert.Equal(t, "{RAND}", restored.RandomizerString("{RAND}"))
+}
+
+func TestTagErrorsJoined(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithStrictParsing(true))
+	payload := "a={RAND;8;NOPE} b={RAND;8;HEX} c={REF;x} d={RAND;500;ABL} e={RAND;4"
+	_, err := engine.RandomizerErr([]byte(payload))
+	require.Error(t, err)
+	joined, ok := err.(interface{ Unwrap() []error })
+	require.True(t, ok, "several malformed tags are joined: %v", err)
+
+	var got []fastrand.TagError
+	for _, e := range joined.Unwrap() {
+		var tagErr *fastrand.TagError
+		require.ErrorAs(t, e, &tagErr)
+		got = append(got, *tagErr)
+	}
+	require.Len(t, got, 4)
+	for i, want := range []string{"{RAND;8;NOPE}", "{REF;x}", "{RAND;500;ABL}", "{RAND;4"} {
+		assert.Equal(t, want, payload[got[i].Offset:got[i].Offset+got[i].Length])
+	}
+	assert.Contains(t, got[1].Reason, "undefined variable")
+
+	var first *fastrand.TagError
+	require.ErrorAs(t, err, &first)
+	assert.Equal(t, 2, first.Offset, "errors.As finds the first tag")
+
+	err = engine.Validate([]byte("ok {RAND;8;NOPE}"))
+	single, ok := err.(*fastrand.TagError)
+	require.True(t, ok, "a single error is a plain *TagError")
+	assert.Equal(t, fastrand.TagError{Offset: 3, Length: 13, Reason: `unknown keyword "NOPE"`}, *single)
+}
@@ -0,0 +1,220 @@
+package fastrand
+
+import (
+	"bytes"
+	"io"
+)
+
+// Template is a payload whose tags have been parsed once by Compile, so it
+// can be expanded repeatedly without rescanning. It reflects the engine
+// configuration at the time it was compiled and is safe for concurrent use.
+type Template struct {
+	engine   *FastEngine
+	segments []templateSegment
+	literals int // total size of the literal segments
+}
+
+// templateSegment is either literal text, a parsed tag, a {REF;name}
+// reference, whose literal is the reference text itself, or a repeat block.
+type templateSegment struct {
+	literal []byte
+	tag     *tagSpec
+	ref     []byte
+	repeat  *templateRepeat
+	base64  *templateBase64
+}
+
+func (s *templateSegment) isLiteral() bool {
+	return s.tag == nil && s.ref == nil && s.repeat == nil && s.base64 == nil
+}
+
+// templateRepeat is a compiled {RAND-REPEAT} block.
+type templateRepeat struct {
+	repeatSpec
+	body []templateSegment
+}
+
+// templateBase64 is a compiled {RAND-BASE64} block.
+type templateBase64 struct {
+	raw  bool
+	body []templateSegment
+}
+
+// Compile parses payload with the default engine. See FastEngine.Compile.
+func Compile(payload []byte) (*Template, error) {
+	return defaultEngine.Load().Compile(payload)
+}
+
+// Compile decodes and parses the tags in payload once and returns a
+// Template that expands to the same output Randomizer would produce.
+// Malformed tags are kept as literal text, as Randomizer does, unless the
+// engine uses WithStrictParsing, in which case they are reported as
+// RandomizerErr reports them. The payload is copied and may be reused by
+// the caller.
+func (e *FastEngine) Compile(payload []byte) (*Template, error) {
+	normalized, scratch := e.normalized(payload)
+	payload = bytes.Clone(normalized)
+	e.release(scratch)
+	if err := e.checkPayload(payload); err != nil {
+		return nil, err
+	}
+
+	t := &Template{engine: e}
+	t.segments = t.compile(payload)
+	return t, nil
+}
+
+// compile splits payload into segments.
+func (t *Template) compile(payload []byte) []templateSegment {
+	e := t.engine
+	var segments []templateSegment
+	cursor, refIndex := 0, -1
+	for cursor < len(payload) {
+		startIndex, isRef := nextTag(payload, cursor, true, &refIndex)
+		if startIndex == -1 {
+			t.addLiteral(&segments, payload[cursor:])
+			break
+		}
+		t.addLiteral(&segments, payload[cursor:startIndex])
+
+		endIndex := bytes.IndexByte(payload[startIndex:], endTag)
+		if endIndex == -1 {
+			t.addLiteral(&segments, payload[startIndex:])
+			break
+		}
+		endIndex += startIndex
+		cursor = endIndex + 1
+		tag := payload[startIndex:endIndex]
+
+		if isRef {
+			name, ok := refName(tag)
+			if !ok {
+				t.addLiteral(&segments, payload[startIndex:cursor])
+				continue
+			}
+			t.literals += cursor - startIndex
+			segments = append(segments, templateSegment{literal: payload[startIndex:cursor], ref: name})
+			continue
+		}
+		if bytes.HasPrefix(tag, repeatOpen) {
+			r, ok := parseRepeat(tag)
+			bodyEnd := findRepeatEnd(payload[cursor:])
+			if !ok || bodyEnd == -1 {
+				t.addLiteral(&segments, payload[startIndex:cursor])
+				continue
+			}
+			body := t.compile(payload[cursor : cursor+bodyEnd])
+			segments = append(segments, templateSegment{repeat: &templateRepeat{repeatSpec: r, body: body}})
+			cursor += bodyEnd + len(repeatClose)
+			continue
+		}
+		if bytes.HasPrefix(tag, base64Open) && e.inputEncoding&RandomizerEncodingBase64 != 0 {
+			raw, ok := parseBase64Block(tag)
+			bodyEnd := bytes.Index(payload[cursor:], base64Close)
+			if !ok || bodyEnd == -1 {
+				t.addLiteral(&segments, payload[startIndex:cursor])
+				continue
+			}
+			body := t.compile(payload[cursor : cursor+bodyEnd])
+			segments = append(segments, templateSegment{base64: &templateBase64{raw: raw, body: body}})
+			cursor += bodyEnd + len(base64Close)
+			continue
+		}
+		spec, ok := e.expandableTag(tag, nil, nil, false)
+		if !ok {
+			t.addLiteral(&segments, payload[startIndex:cursor])
+			continue
+		}
+		segments = append(segments, templateSegment{tag: &spec})
+	}
+	return segments
+}
+
+// addLiteral appends literal text to segments, merging it with a preceding
+// literal.
+func (t *Template) addLiteral(segments *[]templateSegment, text []byte) {
+	if len(text) == 0 {
+		return
+	}
+	t.literals += len(text)
+	if n := len(*segments); n > 0 && (*segments)[n-1].isLiteral() {
+		prev := (*segments)[n-1].literal
+		(*segments)[n-1].literal = append(prev[:len(prev):len(prev)], text...)
+		return
+	}
+	*segments = append(*segments, templateSegment{literal: text})
+}
+
+// Execute returns a new expansion of the template.
+func (t *Template) Execute() []byte {
+	return t.Append(make([]byte, 0, t.literals+512))
+}
+
+// ExecuteString returns a new expansion of the template as a string.
+func (t *Template) ExecuteString() string {
+	buf := t.engine.bufferPool.Get(t.engine.sizeHint(t.literals))
+	*buf = t.Append(*buf)
+	t.engine.recordSize(len(*buf))
+	result := string(*buf)
+	t.engine.bufferPool.Put(buf)
+	return result
+}
+
+// Append appends a new expansion of the template to dst and returns the
+// extended buffer. It does not allocate when dst has enough capacity and
+// the template stores no variables.
+func (t *Template) Append(dst []byte) []byte {
+	var x expansion
+	start := len(dst)
+	x.limitFrom(t.engine, start)
+	dst = t.appendSegments(dst, t.segments, &x)
+	x.truncate(&dst)
+	if t.engine.stats != nil {
+		t.engine.stats.countPayload(len(dst) - start)
+	}
+	return dst
+}
+
+func (t *Template) appendSegments(dst []byte, segments []templateSegment, x *expansion) []byte {
+	e := t.engine
+	for i := 0; i < len(segments) && !x.stopped(dst); i++ {
+		seg := &segments[i]
+		if !seg.isLiteral() && !x.countTag() {
+			return dst
+		}
+		switch {
+		case seg.tag != nil:
+			e.expandTag(&dst, seg.tag, x)
+		case seg.ref != nil:
+			if value, ok := x.lookupVar(seg.ref); ok {
+				dst = append(dst, value...)
+			} else {
+				e.writeLiteral(&dst, seg.literal, x)
+			}
+		case seg.repeat != nil:
+			for n := seg.repeat.count(e.next); n > 0 && !x.stopped(dst); n-- {
+				dst = t.appendSegments(dst, seg.repeat.body, x)
+			}
+		case seg.base64 != nil:
+			start := len(dst)
+			x.wrapped++
+			dst = t.appendSegments(dst, seg.base64.body, x)
+			x.wrapped--
+			e.encodeBase64From(&dst, start, seg.base64.raw, x)
+		default:
+			e.writeLiteral(&dst, seg.literal, x)
+		}
+	}
+	return dst
+}
+
+// ExecuteTo writes a new expansion of the template to w, using a pooled
+// buffer instead of allocating the result.
+func (t *Template) ExecuteTo(w io.Writer) (int, error) {
+	buf := t.engine.bufferPool.Get(t.engine.sizeHint(t.literals))
+	*buf = t.Append(*buf)
+	t.engine.recordSize(len(*buf))
+	n, err := w.Write(*buf)
+	t.engine.bufferPool.Put(buf)
+	return n, err
+}
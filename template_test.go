@@ -0,0 +1,63 @@
+package fastrand_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateMatchesRandomizer(t *testing.T) {
+	payloads := []string{
+		"User: {RAND;10-20;ABL,ABU} | Session: {RANDOM;32;HEX} | ID: {RAND;UUID,HEX} | IP: {RAND;IPV4} | Data: {RAND;50-99} --- End",
+		"{RAND}{RAND;5,7,9;DIGIT}{RAND;~20±4;ABL}{RAND;2-90:zipf;BYTES}",
+		"no tags at all",
+		"{RAND;5;NOSUCH}{RANDX}{RAND;8;ABL",
+		"%7BRAND%3B8%3BDIGIT%7D and &lbrace;RAND&semi;4&semi;HEX&rbrace;",
+		"{RAND;SEQ:a}-{RAND;SEQ:a}-{RAND;K8SNAME}",
+	}
+	for _, payload := range payloads {
+		compiled := fastrand.NewEngine(fastrand.WithSeed(11))
+		direct := fastrand.NewEngine(fastrand.WithSeed(11))
+		tmpl, err := compiled.Compile([]byte(payload))
+		require.NoError(t, err)
+		for i := 0; i < 5; i++ {
+			assert.Equal(t, string(direct.Randomizer([]byte(payload))), string(tmpl.Execute()), payload)
+		}
+	}
+}
+
+func TestTemplateOutputs(t *testing.T) {
+	payload := []byte("id={RAND;8;DIGIT}")
+	tmpl, err := fastrand.Compile(payload)
+	require.NoError(t, err)
+	payload[0] = 'X'
+	assert.Regexp(t, `^id=[0-9]{8}$`, string(tmpl.Execute()), "the payload is copied at compile time")
+	assert.Regexp(t, `^id=[0-9]{8}$`, tmpl.ExecuteString())
+	assert.Regexp(t, `^pre:id=[0-9]{8}$`, string(tmpl.Append([]byte("pre:"))))
+
+	var w bytes.Buffer
+	n, err := tmpl.ExecuteTo(&w)
+	require.NoError(t, err)
+	assert.Equal(t, 11, n)
+	assert.Regexp(t, `^id=[0-9]{8}$`, w.String())
+}
+
+func TestTemplateOutputEncoding(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithOutputEncoding(fastrand.RandomizerEncodingURL))
+	tmpl, err := engine.Compile([]byte("a b={RAND;6;ABL}"))
+	require.NoError(t, err)
+	assert.Regexp(t, `^a\+b%3D[a-z]{6}$`, tmpl.ExecuteString())
+}
+
+func TestAllocsTemplateAppend(t *testing.T) {
+	tmpl, err := fastrand.NewEngine().Compile([]byte("hello {RAND;16;ABL} {RAND;5,10;DIGIT} {RAND;UUID,HEX}"))
+	require.NoError(t, err)
+	dst := make([]byte, 0, 512)
+	allocs := testing.AllocsPerRun(100, func() {
+		dst = tmpl.Append(dst[:0])
+	})
+	assert.Zero(t, allocs)
+}
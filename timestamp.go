@@ -0,0 +1,150 @@
+package fastrand
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// timestampLayouts maps the layout names TIMESTAMP accepts, in upper case,
+// to time layouts. UNIX, UNIXMILLI and UNIXNANO map to themselves and
+// write epoch numbers instead.
+var timestampLayouts = map[string]string{
+	"RFC3339":     time.RFC3339,
+	"RFC3339NANO": time.RFC3339Nano,
+	"RFC1123":     time.RFC1123,
+	"RFC1123Z":    time.RFC1123Z,
+	"RFC822":      time.RFC822,
+	"RFC822Z":     time.RFC822Z,
+	"RFC850":      time.RFC850,
+	"ANSIC":       time.ANSIC,
+	"UNIXDATE":    time.UnixDate,
+	"RUBYDATE":    time.RubyDate,
+	"KITCHEN":     time.Kitchen,
+	"STAMP":       time.Stamp,
+	"STAMPMILLI":  time.StampMilli,
+	"DATETIME":    time.DateTime,
+	"DATEONLY":    time.DateOnly,
+	"TIMEONLY":    time.TimeOnly,
+	"UNIX":        "UNIX",
+	"UNIXMILLI":   "UNIXMILLI",
+	"UNIXNANO":    "UNIXNANO",
+}
+
+// defaultTimestampMin is the lower bound of TIMESTAMP without a min
+// parameter; the upper bound defaults to the current time.
+var defaultTimestampMin = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// parseTimestampBound parses a TIMESTAMP min or max value: "now", "now"
+// followed by a signed duration such as "now-720h", an RFC 3339 time, a
+// "2006-01-02 15:04:05" date and time or a "2006-01-02" date, the last two
+// in UTC.
+func parseTimestampBound(value []byte, now time.Time) (time.Time, bool) {
+	if len(value) >= 3 && bytes.EqualFold(value[:3], []byte("now")) {
+		rest := value[3:]
+		if len(rest) == 0 {
+			return now, true
+		}
+		if rest[0] != '+' && rest[0] != '-' {
+			return time.Time{}, false
+		}
+		d, err := time.ParseDuration(unsafeString(rest))
+		return now.Add(d), err == nil
+	}
+	layout := time.DateTime
+	switch {
+	case len(value) == len(time.DateOnly):
+		layout = time.DateOnly
+	case bytes.IndexByte(value, 'T') != -1:
+		layout = time.RFC3339Nano
+	}
+	t, err := time.Parse(layout, unsafeString(value))
+	return t, err == nil
+}
+
+// timestampBounds returns the range a TIMESTAMP keyword draws from,
+// keeping the default for a bound that does not parse and swapping bounds
+// given in the wrong order.
+func timestampBounds(params []byte, now time.Time) (lo, hi time.Time) {
+	lo, hi = defaultTimestampMin, now
+	if v, ok := keywordParam(params, "min"); ok {
+		if t, ok := parseTimestampBound(v, now); ok {
+			lo = t
+		}
+	}
+	if v, ok := keywordParam(params, "max"); ok {
+		if t, ok := parseTimestampBound(v, now); ok {
+			hi = t
+		}
+	}
+	if hi.Before(lo) {
+		lo, hi = hi, lo
+	}
+	return lo, hi
+}
+
+// timestampLayout returns the time layout a TIMESTAMP layout parameter
+// names, or the parameter itself when it is not a known name, so that Go
+// layouts such as "2006-01-02T15:04" work too.
+func timestampLayout(value []byte) string {
+	if len(value) == 0 {
+		return time.RFC3339
+	}
+	var key [16]byte
+	if len(value) <= len(key) {
+		n := upperASCIIInto(key[:], value)
+		if layout, ok := timestampLayouts[unsafeString(key[:n])]; ok {
+			return layout
+		}
+	}
+	return unsafeString(value)
+}
+
+// appendTimestamp appends a time drawn uniformly from the range of a
+// TIMESTAMP keyword's min and max parameters, formatted in UTC with its
+// layout parameter.
+func (e *FastEngine) appendTimestamp(out *[]byte, params []byte) {
+	lo, hi := timestampBounds(params, e.now())
+	span := uint64(hi.Unix() - lo.Unix())
+	sec := lo.Unix() + int64(uint64N(e.next, span+1))
+	t := time.Unix(sec, int64(uint64N(e.next, uint64(time.Second)))).UTC()
+	if t.Before(lo) {
+		t = lo.UTC()
+	} else if t.After(hi) {
+		t = hi.UTC()
+	}
+
+	v, _ := keywordParam(params, "layout")
+	switch layout := timestampLayout(v); layout {
+	case "UNIX":
+		*out = strconv.AppendInt(*out, t.Unix(), 10)
+	case "UNIXMILLI":
+		*out = strconv.AppendInt(*out, t.UnixMilli(), 10)
+	case "UNIXNANO":
+		*out = strconv.AppendInt(*out, t.UnixNano(), 10)
+	default:
+		*out = t.AppendFormat(*out, layout)
+	}
+}
+
+// checkTimestampParams returns why the min or max parameter of a TIMESTAMP
+// keyword does not parse or the bounds are reversed, or "".
+func checkTimestampParams(params, keyword []byte, now time.Time) string {
+	bounds := [2]time.Time{defaultTimestampMin, now}
+	for i, name := range [...]string{"min", "max"} {
+		v, ok := keywordParam(params, name)
+		if !ok {
+			continue
+		}
+		t, ok := parseTimestampBound(v, now)
+		if !ok {
+			return fmt.Sprintf("invalid %s %q in %q: want now, now±duration, RFC 3339 or 2006-01-02", name, v, keyword)
+		}
+		bounds[i] = t
+	}
+	if bounds[1].Before(bounds[0]) {
+		return fmt.Sprintf("min is after max in %q", keyword)
+	}
+	return ""
+}
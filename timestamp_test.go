@@ -0,0 +1,90 @@
+package fastrand_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimestamp(t *testing.T) {
+	lo := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 200; i++ {
+		out := fastrand.RandomizerString("{RAND;TIMESTAMP(layout=RFC3339,min=2020-01-01,max=now)}")
+		ts, err := time.Parse(time.RFC3339, out)
+		require.NoError(t, err, out)
+		assert.False(t, ts.Before(lo), out)
+		assert.False(t, ts.After(time.Now()), out)
+	}
+
+	out := fastrand.RandomizerString("{RAND;TIMESTAMP}")
+	ts, err := time.Parse(time.RFC3339, out)
+	require.NoError(t, err, out)
+	assert.False(t, ts.Before(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Regexp(t, `Z$`, out, "timestamps are written in UTC")
+}
+
+func TestTimestampLayouts(t *testing.T) {
+	cases := map[string]string{
+		"dateonly":         `^2021-03-0[1-3]$`,
+		"DateTime":         `^2021-03-0[1-3] \d\d:\d\d:\d\d$`,
+		"Kitchen":          `^\d{1,2}:\d\d[AP]M$`,
+		"2006-01-02T15:04": `^2021-03-0[1-3]T\d\d:\d\d$`,
+		"RFC3339Nano":      `^2021-03-0[1-3]T\d\d:\d\d:\d\d(\.\d+)?Z$`,
+	}
+	for layout, pattern := range cases {
+		payload := "{RAND;TIMESTAMP(layout=" + layout + ",min=2021-03-01,max=2021-03-03 12:00:00)}"
+		assert.Regexp(t, pattern, fastrand.RandomizerString(payload), layout)
+	}
+
+	unix, err := strconv.ParseInt(fastrand.RandomizerString("{RAND;TIMESTAMP(layout=unix,min=2021-03-01T00:00:00Z,max=2021-03-02T00:00:00Z)}"), 10, 64)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, unix, int64(1614556800))
+	assert.LessOrEqual(t, unix, int64(1614643200))
+
+	milli, err := strconv.ParseInt(fastrand.RandomizerString("{RAND;TIMESTAMP(layout=UnixMilli,min=now-1h)}"), 10, 64)
+	require.NoError(t, err)
+	assert.InDelta(t, time.Now().Add(-30*time.Minute).UnixMilli(), milli, float64(31*time.Minute/time.Millisecond))
+}
+
+func TestTimestampBounds(t *testing.T) {
+	assert.Equal(t, "2022-05-06", fastrand.RandomizerString("{RAND;TIMESTAMP(layout=DateOnly,min=2022-05-06 10:00:00,max=2022-05-06 10:00:00)}"))
+	assert.Equal(t, "2022-05-06T10:00:00+00:00",
+		fastrand.RandomizerString("{RAND;TIMESTAMP(layout=2006-01-02T15:04:05-07:00,min=2022-05-06T12:00:00+02:00,max=2022-05-06T10:00:00Z)}"),
+		"offsets are converted to UTC")
+
+	out := fastrand.RandomizerString("{RAND;TIMESTAMP(layout=DateOnly,min=2023-01-02,max=2023-01-01)}")
+	assert.Contains(t, []string{"2023-01-01", "2023-01-02"}, out, "reversed bounds are swapped")
+}
+
+func TestTimestampStrict(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithStrictParsing(true))
+	for payload, reason := range map[string]string{
+		"{RAND;TIMESTAMP(min=yesterday)}":                 `invalid min "yesterday"`,
+		"{RAND;TIMESTAMP(max=now~1h)}":                    `invalid max "now~1h"`,
+		"{RAND;TIMESTAMP(min=2023-01-02,max=2023-01-01)}": "min is after max",
+		"{RAND;TIMESTAMP(zone=UTC)}":                      `unknown parameter "zone"`,
+	} {
+		_, err := engine.RandomizerErr([]byte(payload))
+		assert.ErrorContains(t, err, reason, payload)
+	}
+	_, err := engine.RandomizerErr([]byte("{RAND;TIMESTAMP(layout=Unix,min=now-24h,max=now+1h)}"))
+	assert.NoError(t, err)
+
+	_, err = fastrand.TagEntropy("{RAND;TIMESTAMP}")
+	assert.ErrorIs(t, err, fastrand.ErrUnknownEntropy)
+}
+
+func TestAllocsTimestamp(t *testing.T) {
+	engine := fastrand.NewEngine()
+	payload := []byte("ts={RAND;TIMESTAMP(layout=RFC3339,min=2020-01-01,max=now)}")
+	dst := make([]byte, 0, 64)
+	dst = engine.RandomizerAppend(dst, payload)
+	allocs := testing.AllocsPerRun(100, func() {
+		dst = engine.RandomizerAppend(dst[:0], payload)
+	})
+	assert.Zero(t, allocs)
+}
@@ -0,0 +1,71 @@
+package fastrand
+
+import (
+	"encoding/binary"
+	"time"
+)
+
+// ulidLength is the length of a ULID's text form.
+const ulidLength = 26
+
+// ULID returns a ULID: a 48-bit millisecond Unix timestamp followed by 80
+// random bits, in its 26-character Crockford base32 form such as
+// 01ARZ3NDEKTSV4RRFFQ69G5FAV. ULIDs sort by creation time to the
+// millisecond.
+func ULID() string {
+	var raw [16]byte
+	putMilliTime(&raw, time.Now())
+	fillBytes(fastUint64, raw[6:])
+	return ulidString(&raw)
+}
+
+// SecureULID is like ULID with randomness from crypto/rand.
+func SecureULID() (string, error) {
+	var raw [16]byte
+	putMilliTime(&raw, time.Now())
+	if err := secureRead(raw[6:]); err != nil {
+		return "", err
+	}
+	return ulidString(&raw), nil
+}
+
+func ulidString(raw *[16]byte) string {
+	out := make([]byte, 0, ulidLength)
+	appendULIDText(&out, raw)
+	return unsafeString(out)
+}
+
+// putMilliTime writes the millisecond timestamp of t into the first six
+// bytes of raw, big-endian.
+func putMilliTime(raw *[16]byte, t time.Time) {
+	var ms [8]byte
+	binary.BigEndian.PutUint64(ms[:], uint64(t.UnixMilli()))
+	copy(raw[:6], ms[2:])
+}
+
+// appendULIDText appends the 128 bits of raw as 26 Crockford base32
+// characters, the first of which holds only the top three bits.
+func appendULIDText(out *[]byte, raw *[16]byte) {
+	hi := binary.BigEndian.Uint64(raw[:8])
+	lo := binary.BigEndian.Uint64(raw[8:])
+	for i := range ulidLength {
+		shift := uint(125 - 5*i)
+		var v uint64
+		switch {
+		case shift >= 64:
+			v = hi >> (shift - 64)
+		case shift+5 <= 64:
+			v = lo >> shift
+		default:
+			v = lo>>shift | hi<<(64-shift)
+		}
+		*out = append(*out, base32Crockford[v&31])
+	}
+}
+
+func (e *FastEngine) appendULID(out *[]byte) {
+	var raw [16]byte
+	putMilliTime(&raw, e.now())
+	fillBytes(e.next, raw[6:])
+	appendULIDText(out, &raw)
+}
@@ -0,0 +1,87 @@
+package fastrand_test
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ulidTime decodes a ULID and returns its timestamp.
+func ulidTime(tb testing.TB, id string) time.Time {
+	tb.Helper()
+	require.Len(tb, id, 26)
+	require.LessOrEqual(tb, id[0], byte('7'), "the first character holds three bits")
+	v := new(big.Int)
+	for _, c := range id {
+		i := strings.IndexRune(crockford, c)
+		require.NotEqual(tb, -1, i, id)
+		v.Lsh(v, 5).Or(v, big.NewInt(int64(i)))
+	}
+	require.LessOrEqual(tb, v.BitLen(), 128)
+	return time.UnixMilli(new(big.Int).Rsh(v, 80).Int64())
+}
+
+func TestULID(t *testing.T) {
+	before := time.Now().Truncate(time.Millisecond)
+	ids := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		id := fastrand.ULID()
+		ts := ulidTime(t, id)
+		assert.False(t, ts.Before(before), id)
+		assert.False(t, ts.After(time.Now()), id)
+		ids[id] = true
+
+		secure, err := fastrand.SecureULID()
+		require.NoError(t, err)
+		ulidTime(t, secure)
+	}
+	assert.Len(t, ids, 100)
+}
+
+func TestULIDSortsByTime(t *testing.T) {
+	first := fastrand.ULID()
+	time.Sleep(2 * time.Millisecond)
+	assert.Less(t, first, fastrand.ULID())
+}
+
+func TestULIDKeyword(t *testing.T) {
+	out := fastrand.RandomizerString("id={RAND;ULID}")
+	require.True(t, strings.HasPrefix(out, "id="))
+	assert.WithinDuration(t, time.Now(), ulidTime(t, out[3:]), time.Second)
+	assert.Regexp(t, `^[0-7][0-9a-hjkmnp-tv-z]{25}$`, fastrand.RandomizerString("{RAND;ULID;lower}"))
+
+	bits, err := fastrand.TagEntropy("{RAND;ULID}")
+	require.NoError(t, err)
+	assert.Equal(t, 80.0, bits)
+}
+
+func TestULIDKeywordClock(t *testing.T) {
+	at := time.Date(2030, time.June, 15, 12, 0, 0, 0, time.UTC)
+	engine := fastrand.NewEngine(fastrand.WithClock(func() time.Time { return at }))
+	assert.True(t, at.Equal(ulidTime(t, engine.RandomizerString("{RAND;ULID}"))))
+
+	a := fastrand.NewEngine(fastrand.WithSeed(5))
+	b := fastrand.NewEngine(fastrand.WithSeed(5))
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, a.RandomizerString("{RAND;ULID}"), b.RandomizerString("{RAND;ULID}"),
+			"seeded engines generate identical ULIDs")
+	}
+}
+
+func TestAllocsULIDKeyword(t *testing.T) {
+	engine := fastrand.NewEngine()
+	payload := []byte("id={RAND;ULID}")
+	dst := make([]byte, 0, 64)
+	dst = engine.RandomizerAppend(dst, payload)
+	allocs := testing.AllocsPerRun(100, func() {
+		dst = engine.RandomizerAppend(dst[:0], payload)
+	})
+	assert.Zero(t, allocs)
+}
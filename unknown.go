@@ -0,0 +1,137 @@
+package fastrand
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// UnknownKeywordPolicy selects what a tag naming an unknown or disabled
+// keyword, or an invalid character class, expands to. Tags that name no
+// keyword, such as {RAND;8}, always yield CharsAll characters.
+type UnknownKeywordPolicy int
+
+const (
+	// UnknownKeywordFallback expands the tag to CharsAll characters of the
+	// requested length, as if it named no keyword.
+	UnknownKeywordFallback UnknownKeywordPolicy = iota
+	// UnknownKeywordPassthrough copies the tag to the output verbatim.
+	UnknownKeywordPassthrough
+	// UnknownKeywordEmpty expands the tag to nothing.
+	UnknownKeywordEmpty
+	// UnknownKeywordError makes RandomizerErr, RandomizerCtx and Compile
+	// return a *TagError for the tag, even without strict parsing. Methods
+	// without an error result copy it verbatim.
+	UnknownKeywordError
+)
+
+// unknownKeywordPolicyNames are the names EngineConfig uses for policies,
+// indexed by policy.
+var unknownKeywordPolicyNames = []string{"fallback", "passthrough", "empty", "error"}
+
+func (p UnknownKeywordPolicy) String() string {
+	if p < 0 || int(p) >= len(unknownKeywordPolicyNames) {
+		return fmt.Sprintf("UnknownKeywordPolicy(%d)", int(p))
+	}
+	return unknownKeywordPolicyNames[p]
+}
+
+func parseUnknownKeywordPolicy(name string) (UnknownKeywordPolicy, bool) {
+	for i, n := range unknownKeywordPolicyNames {
+		if strings.EqualFold(name, n) {
+			return UnknownKeywordPolicy(i), true
+		}
+	}
+	return 0, false
+}
+
+// WithUnknownKeywordPolicy sets what tags naming unknown or disabled
+// keywords expand to, so that a typo such as {RAND;8;HXE} can be kept
+// visible or rejected instead of silently becoming random CharsAll text.
+// The default is UnknownKeywordFallback.
+func WithUnknownKeywordPolicy(policy UnknownKeywordPolicy) Option {
+	return func(e *FastEngine) {
+		if policy >= UnknownKeywordFallback && policy <= UnknownKeywordError {
+			e.unknownKeywords = policy
+		}
+	}
+}
+
+// unknownKeyword returns the unknown or disabled keyword spec names, or nil
+// if it names a known keyword or none at all.
+func (s *tagSpec) unknownKeyword() *keywordSpec {
+	for i := range s.keywords {
+		if k := &s.keywords[i]; k.fallback && len(k.text) > 0 {
+			return k
+		}
+	}
+	return nil
+}
+
+// namesKeyword reports whether s names a keyword, known or not, rather
+// than asking for CharsAll characters as {RAND;8} does.
+func (s *tagSpec) namesKeyword() bool {
+	for i := range s.keywords {
+		if len(s.keywords[i].text) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// expandableTag parses tag like parseTag but reports false, so that the tag
+// is copied verbatim, when it names an unknown keyword and the engine's
+// policy says to pass it through, or names none and WithRequireKeyword is
+// set. Unknown keywords are always expandable
+// when delegated to a chained fallback engine.
+func (e *FastEngine) expandableTag(tag []byte, lengths []weightedLength, keywords []keywordSpec, delegated bool) (tagSpec, bool) {
+	spec, ok := e.parseTag(tag, lengths, keywords)
+	if ok && e.requireKeyword && !spec.namesKeyword() {
+		return spec, false
+	}
+	if !ok || delegated || e.unknownKeywords == UnknownKeywordFallback || e.unknownKeywords == UnknownKeywordEmpty {
+		return spec, ok
+	}
+	return spec, spec.unknownKeyword() == nil
+}
+
+// checkKeywords returns a *TagError for each tag in payload that names an
+// unknown or disabled keyword, joined by joinTagErrors.
+func (e *FastEngine) checkKeywords(payload []byte) error {
+	var errs []error
+	cursor, refIndex := 0, -1
+	for {
+		startIndex, isRef := nextTag(payload, cursor, true, &refIndex)
+		if startIndex == -1 {
+			return joinTagErrors(errs)
+		}
+		endIndex := bytes.IndexByte(payload[startIndex:], endTag)
+		if endIndex == -1 {
+			return joinTagErrors(errs)
+		}
+		endIndex += startIndex
+		tag := payload[startIndex:endIndex]
+		cursor = endIndex + 1
+		if isRef || bytes.HasPrefix(tag, repeatOpen) || bytes.HasPrefix(tag, base64Open) {
+			continue
+		}
+		if spec, ok := e.parseTag(tag, nil, nil); ok {
+			if spec.unknownKeyword() != nil {
+				errs = append(errs, &TagError{Offset: startIndex, Length: cursor - startIndex, Reason: e.checkTag(tag)})
+			}
+		}
+	}
+}
+
+// checkPayload returns the error RandomizerErr reports for payload before
+// expanding it: the first malformed tag in strict parsing mode, or the
+// first unknown keyword under UnknownKeywordError.
+func (e *FastEngine) checkPayload(payload []byte) error {
+	switch {
+	case e.strictParsing:
+		return e.checkTags(payload)
+	case e.unknownKeywords == UnknownKeywordError:
+		return e.checkKeywords(payload)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package fastrand_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnknownKeywordPolicy_Fallback(t *testing.T) {
+	engine := fastrand.NewEngine()
+	assert.Len(t, engine.RandomizerString("{RAND;8;HXE}"), 8, "unknown keywords fall back by default")
+}
+
+func TestUnknownKeywordPolicy_Passthrough(t *testing.T) {
+	engine := fastrand.NewEngine(
+		fastrand.WithUnknownKeywordPolicy(fastrand.UnknownKeywordPassthrough),
+		fastrand.WithDisabledKeywords("EMAIL"),
+	)
+	out := engine.RandomizerString("a={RAND;8;HXE}&b={RAND;4;DIGIT}&c={RAND;EMAIL}&d={RAND;4;[z-a]}")
+	assert.Regexp(t, `^a=\{RAND;8;HXE\}&b=[0-9]{4}&c=\{RAND;EMAIL\}&d=\{RAND;4;\[z-a\]\}$`, out)
+
+	assert.Len(t, engine.RandomizerString("{RAND;8}"), 8, "tags without a keyword still expand")
+	assert.Len(t, engine.RandomizerString("{RAND;4;NOPE,DIGIT}"), 4, "invalid choices are skipped")
+
+	tmpl, err := engine.Compile([]byte("x{RAND;HXE}"))
+	require.NoError(t, err)
+	assert.Equal(t, "x{RAND;HXE}", tmpl.ExecuteString())
+
+	out2, err := io.ReadAll(engine.RandomizerReader(strings.NewReader("x{RAND;HXE}")))
+	require.NoError(t, err)
+	assert.Equal(t, "x{RAND;HXE}", string(out2))
+}
+
+func TestUnknownKeywordPolicy_Empty(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithUnknownKeywordPolicy(fastrand.UnknownKeywordEmpty))
+	assert.Equal(t, "a=&b=", engine.RandomizerString("a={RAND;8;HXE}&b={RAND;NOPE,ALSO}"))
+	assert.Len(t, engine.RandomizerString("{RAND;8}"), 8)
+	assert.Equal(t, "[]", engine.RandomizerString("[{RAND;HXE;VAR=v}{REF;v}]"))
+}
+
+func TestUnknownKeywordPolicy_Error(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithUnknownKeywordPolicy(fastrand.UnknownKeywordError))
+
+	_, err := engine.RandomizerErr([]byte("id={RAND;8;DIGIT}&x={RAND;8;HXE}"))
+	var tagErr *fastrand.TagError
+	require.ErrorAs(t, err, &tagErr)
+	assert.Equal(t, 20, tagErr.Offset)
+	assert.Equal(t, `unknown keyword "HXE"`, tagErr.Reason)
+
+	_, err = engine.RandomizerErr([]byte("{RAND;999}"))
+	require.ErrorAs(t, err, &tagErr, "a lone invalid length is read as a keyword")
+	assert.Contains(t, tagErr.Reason, "invalid length")
+
+	out, err := engine.RandomizerErr([]byte("id={RAND;5-3;DIGIT}"))
+	require.NoError(t, err, "other malformed tags are only rejected in strict mode")
+	assert.Regexp(t, "^id=[0-9]{16}$", string(out))
+
+	_, err = engine.Compile([]byte("{RAND;HXE}"))
+	assert.ErrorAs(t, err, &tagErr)
+
+	assert.Equal(t, "{RAND;HXE}", engine.RandomizerString("{RAND;HXE}"), "Randomizer copies the tag verbatim")
+}
+
+func TestUnknownKeywordPolicy_Config(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithUnknownKeywordPolicy(fastrand.UnknownKeywordEmpty))
+	assert.Equal(t, "empty", engine.Config().UnknownKeywords)
+	assert.Equal(t, "fallback", fastrand.NewEngine().Config().UnknownKeywords)
+
+	data, err := engine.MarshalConfig()
+	require.NoError(t, err)
+	restored, err := fastrand.NewEngineFromConfig(data)
+	require.NoError(t, err)
+	assert.Equal(t, "", restored.RandomizerString("{RAND;HXE}"))
+
+	_, err = fastrand.NewEngineFromConfig([]byte(`{"unknown_keywords": "explode"}`))
+	assert.Error(t, err)
+
+	engine.ResetDefaultsOnly()
+	assert.Len(t, engine.RandomizerString("{RAND;8;HXE}"), 8)
+}
@@ -0,0 +1,143 @@
+package fastrand
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// maxURLParts bounds the path and query parameters of URL.
+const maxURLParts = 16
+
+// urlHost is the kind of host a URL keyword generates.
+type urlHost uint8
+
+const (
+	urlHostDomain urlHost = iota
+	urlHostIPv4
+	urlHostIPv6
+)
+
+// urlShape is the shape of the URLs a URL keyword generates. An empty
+// scheme picks http or https.
+type urlShape struct {
+	scheme             string
+	host               urlHost
+	pathMin, pathMax   int
+	queryMin, queryMax int
+}
+
+var (
+	defaultURLShape = urlShape{pathMax: 3, queryMax: 2}
+	urlSchemes      = []string{"http", "https"}
+)
+
+// URL returns a random http or https URL such as
+// "https://xk3f.brave-otter.io/q7/kd2?ab=x9": a domain host, up to three
+// path segments and up to two query parameters.
+func URL() string {
+	var out []byte
+	appendURL(fastUint64, &out, defaultURLShape)
+	return unsafeString(out)
+}
+
+func appendURL(next func() uint64, out *[]byte, shape urlShape) {
+	scheme := shape.scheme
+	if scheme == "" {
+		scheme = pickWord(next, urlSchemes)
+	}
+	*out = append(*out, scheme...)
+	*out = append(*out, "://"...)
+	switch shape.host {
+	case urlHostIPv4:
+		appendIPv4Addr(out, uint32(next()))
+	case urlHostIPv6:
+		var raw [16]byte
+		fillBytes(next, raw[:])
+		*out = append(*out, '[')
+		appendIPv6Addr(out, &raw)
+		*out = append(*out, ']')
+	default:
+		appendHostname(next, out, 0, 1, "")
+	}
+	segments := shape.pathMin + int(uint64N(next, uint64(shape.pathMax-shape.pathMin+1)))
+	if segments == 0 {
+		*out = append(*out, '/')
+	}
+	for i := 0; i < segments; i++ {
+		*out = append(*out, '/')
+		appendHostLabel(next, out, 1+int(uint64N(next, 10)))
+	}
+	params := shape.queryMin + int(uint64N(next, uint64(shape.queryMax-shape.queryMin+1)))
+	for i := 0; i < params; i++ {
+		if i == 0 {
+			*out = append(*out, '?')
+		} else {
+			*out = append(*out, '&')
+		}
+		appendRandomLower(next, out, 1+int(uint64N(next, 6)))
+		*out = append(*out, '=')
+		appendHostLabel(next, out, 1+int(uint64N(next, 8)))
+	}
+}
+
+// validScheme reports whether s is a URL scheme: a letter followed by
+// letters, digits, '+', '-' or '.'.
+func validScheme(s []byte) bool {
+	if len(s) == 0 || !isASCIILetter(s[0]) {
+		return false
+	}
+	for _, c := range s[1:] {
+		if !isASCIILetter(c) && (c < '0' || c > '9') && c != '+' && c != '-' && c != '.' {
+			return false
+		}
+	}
+	return true
+}
+
+func isASCIILetter(c byte) bool {
+	return c|0x20 >= 'a' && c|0x20 <= 'z'
+}
+
+// urlParams returns the URL shape the scheme, host, path and query
+// parameters of a URL keyword ask for, or why one is invalid.
+func urlParams(params []byte) (urlShape, string) {
+	shape := defaultURLShape
+	if v, ok := keywordParam(params, "scheme"); ok {
+		if !validScheme(v) {
+			return defaultURLShape, fmt.Sprintf("invalid scheme %q", v)
+		}
+		shape.scheme = unsafeString(v)
+	}
+	if v, ok := keywordParam(params, "host"); ok {
+		switch {
+		case bytes.EqualFold(v, []byte("domain")):
+			shape.host = urlHostDomain
+		case bytes.EqualFold(v, []byte("ipv4")):
+			shape.host = urlHostIPv4
+		case bytes.EqualFold(v, []byte("ipv6")):
+			shape.host = urlHostIPv6
+		default:
+			return defaultURLShape, fmt.Sprintf("invalid host %q: want domain, ipv4 or ipv6", v)
+		}
+	}
+	if v, ok := keywordParam(params, "path"); ok {
+		var valid bool
+		if shape.pathMin, shape.pathMax, valid = parseIntRange(v, 0, maxURLParts); !valid {
+			return defaultURLShape, fmt.Sprintf("invalid path depth %q: want N or MIN-MAX within [0, %d]", v, maxURLParts)
+		}
+	}
+	if v, ok := keywordParam(params, "query"); ok {
+		var valid bool
+		if shape.queryMin, shape.queryMax, valid = parseIntRange(v, 0, maxURLParts); !valid {
+			return defaultURLShape, fmt.Sprintf("invalid query count %q: want N or MIN-MAX within [0, %d]", v, maxURLParts)
+		}
+	}
+	return shape, ""
+}
+
+// appendURL appends a URL shaped by the parameters of a URL keyword. An
+// invalid parameter falls back to the default shape.
+func (e *FastEngine) appendURL(out *[]byte, kw *keywordSpec) {
+	shape, _ := urlParams(kw.params)
+	appendURL(e.next, out, shape)
+}
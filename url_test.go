@@ -0,0 +1,79 @@
+package fastrand_test
+
+import (
+	"net/netip"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestURL(t *testing.T) {
+	for i := 0; i < 300; i++ {
+		raw := fastrand.URL()
+		u, err := url.Parse(raw)
+		require.NoError(t, err, raw)
+		require.Contains(t, []string{"http", "https"}, u.Scheme)
+		require.Regexp(t, hostnamePattern, u.Host)
+		require.LessOrEqual(t, strings.Count(u.Path, "/"), 3, raw)
+		require.LessOrEqual(t, len(u.Query()), 2, raw)
+	}
+}
+
+func TestURLKeyword(t *testing.T) {
+	for i := 0; i < 200; i++ {
+		raw := fastrand.RandomizerString("{RAND;URL(scheme=ftp,host=ipv4,path=2,query=3)}")
+		u, err := url.Parse(raw)
+		require.NoError(t, err, raw)
+		assert.Equal(t, "ftp", u.Scheme)
+		addr, err := netip.ParseAddr(u.Hostname())
+		require.NoError(t, err, raw)
+		assert.True(t, addr.Is4())
+		assert.Equal(t, 2, strings.Count(u.Path, "/"), raw)
+		assert.Equal(t, 3, strings.Count(u.RawQuery, "="), raw)
+
+		raw = fastrand.RandomizerString("{RAND;URL(host=IPV6,path=0,query=0)}")
+		u, err = url.Parse(raw)
+		require.NoError(t, err, raw)
+		addr, err = netip.ParseAddr(u.Hostname())
+		require.NoError(t, err, raw)
+		assert.True(t, addr.Is6())
+		assert.Equal(t, "/", u.Path)
+		assert.Empty(t, u.RawQuery)
+	}
+	assert.Regexp(t, `^https?://[a-z0-9.-]+/`, fastrand.RandomizerString("{RAND;URL}"))
+}
+
+func TestURLKeywordStrict(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithStrictParsing(true))
+	cases := map[string]string{
+		"{RAND;URL(scheme=1http)}": "invalid scheme",
+		"{RAND;URL(host=ipx)}":     "invalid host",
+		"{RAND;URL(path=17)}":      "invalid path depth",
+		"{RAND;URL(query=3-1)}":    "invalid query count",
+		"{RAND;URL(port=80)}":      "unknown parameter",
+	}
+	for payload, want := range cases {
+		_, err := engine.RandomizerErr([]byte(payload))
+		assert.ErrorContains(t, err, want, payload)
+	}
+	_, err := engine.RandomizerErr([]byte("{RAND;URL(scheme=git+ssh,host=domain,path=1-4,query=0-16)}"))
+	assert.NoError(t, err)
+
+	_, err = fastrand.TagEntropy("{RAND;URL}")
+	assert.ErrorIs(t, err, fastrand.ErrUnknownEntropy)
+}
+
+func TestAllocsURLKeyword(t *testing.T) {
+	engine := fastrand.NewEngine()
+	payload := []byte("Referer: {RAND;URL(scheme=https,query=1-3)}")
+	dst := make([]byte, 0, 512)
+	dst = engine.RandomizerAppend(dst, payload)
+	allocs := testing.AllocsPerRun(100, func() {
+		dst = engine.RandomizerAppend(dst[:0], payload)
+	})
+	assert.Zero(t, allocs)
+}
@@ -0,0 +1,40 @@
+package fastrand
+
+import _ "embed"
+
+//go:embed user_agents.txt
+var userAgentsList string
+
+// SafeUserAgents is the default User-Agent corpus of the UA keyword: current
+// desktop and mobile browser strings, one per line of user_agents.txt.
+var SafeUserAgents = parseLines(userAgentsList)
+
+// UserAgent returns a random User-Agent string from SafeUserAgents.
+func UserAgent() string {
+	return pickWord(fastUint64, SafeUserAgents)
+}
+
+// UserAgents returns the User-Agent strings the UA keyword draws from.
+func (e *FastEngine) UserAgents() []string {
+	return e.userAgents
+}
+
+// WithUserAgents replaces the User-Agent strings the UA keyword draws from.
+// Empty strings are dropped, and a list with none left is ignored.
+func WithUserAgents(agents ...string) Option {
+	return func(e *FastEngine) {
+		filtered := make([]string, 0, len(agents))
+		for _, a := range agents {
+			if a != "" {
+				filtered = append(filtered, a)
+			}
+		}
+		if len(filtered) > 0 {
+			e.userAgents = filtered
+		}
+	}
+}
+
+func (e *FastEngine) appendUserAgent(out *[]byte) {
+	*out = append(*out, pickWord(e.next, e.userAgents)...)
+}
@@ -0,0 +1,72 @@
+package fastrand_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserAgent(t *testing.T) {
+	require.NotEmpty(t, fastrand.SafeUserAgents)
+	for _, ua := range fastrand.SafeUserAgents {
+		assert.Regexp(t, `^Mozilla/5\.0 \(`, ua)
+	}
+	for i := 0; i < 100; i++ {
+		assert.Contains(t, fastrand.SafeUserAgents, fastrand.UserAgent())
+		assert.Contains(t, fastrand.SafeUserAgents, fastrand.RandomizerString("{RAND;UA}"))
+	}
+}
+
+func TestWithUserAgents(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithUserAgents("scanner/1.0", "", "curl/8.7.1"))
+	assert.Equal(t, []string{"scanner/1.0", "curl/8.7.1"}, engine.UserAgents())
+	for i := 0; i < 50; i++ {
+		assert.Contains(t, []string{"User-Agent: scanner/1.0", "User-Agent: curl/8.7.1"},
+			engine.RandomizerString("User-Agent: {RAND;UA}"))
+	}
+	bits, err := engine.TagEntropy("{RAND;UA}")
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, bits)
+
+	ignored := fastrand.NewEngine(fastrand.WithUserAgents("", ""))
+	assert.Equal(t, fastrand.SafeUserAgents, ignored.UserAgents())
+
+	engine.Reset()
+	assert.Equal(t, fastrand.SafeUserAgents, engine.UserAgents())
+}
+
+func TestUserAgentsConfig(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithUserAgents("scanner/1.0"))
+	assert.Equal(t, engine.UserAgents(), engine.Clone().UserAgents())
+
+	data, err := engine.MarshalConfig()
+	require.NoError(t, err)
+	restored, err := fastrand.NewEngineFromConfig(data)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"scanner/1.0"}, restored.UserAgents())
+
+	data, err = fastrand.NewEngine().MarshalConfig()
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "user_agents", "the built-in corpus is left out")
+
+	path := filepath.Join(t.TempDir(), "fastrand.conf")
+	require.NoError(t, os.WriteFile(path, []byte("[providers]\nuser_agents = [\"scanner/1.0\", \"curl/8.7.1\"]\n"), 0o600))
+	loaded, err := fastrand.LoadEngine(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"scanner/1.0", "curl/8.7.1"}, loaded.UserAgents())
+}
+
+func TestAllocsUserAgentKeyword(t *testing.T) {
+	engine := fastrand.NewEngine()
+	payload := []byte("User-Agent: {RAND;UA}")
+	dst := make([]byte, 0, 256)
+	dst = engine.RandomizerAppend(dst, payload)
+	allocs := testing.AllocsPerRun(100, func() {
+		dst = engine.RandomizerAppend(dst[:0], payload)
+	})
+	assert.Zero(t, allocs)
+}
@@ -0,0 +1,87 @@
+package fastrand_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// checkUUIDv7 checks the version, variant and timestamp of a v7 UUID.
+func checkUUIDv7(tb testing.TB, uuid []byte, before time.Time) {
+	tb.Helper()
+	require.Len(tb, uuid, 16)
+	assert.Equal(tb, byte(0x70), uuid[6]&0xf0, "version 7")
+	assert.Equal(tb, byte(0x80), uuid[8]&0xc0, "RFC 9562 variant")
+	var ms [8]byte
+	copy(ms[2:], uuid[:6])
+	ts := time.UnixMilli(int64(binary.BigEndian.Uint64(ms[:])))
+	assert.False(tb, ts.Before(before.Truncate(time.Millisecond)))
+	assert.False(tb, ts.After(time.Now()))
+}
+
+func TestUUIDv7(t *testing.T) {
+	before := time.Now()
+	fast, err := fastrand.FastUUIDv7()
+	require.NoError(t, err)
+	checkUUIDv7(t, fast, before)
+
+	secure, err := fastrand.SecureUUIDv7()
+	require.NoError(t, err)
+	checkUUIDv7(t, secure, before)
+	assert.NotEqual(t, fast, secure)
+
+	time.Sleep(2 * time.Millisecond)
+	later, err := fastrand.FastUUIDv7()
+	require.NoError(t, err)
+	assert.Negative(t, bytes.Compare(fast, later), "v7 UUIDs sort by creation time")
+}
+
+func TestUUIDv7Keyword(t *testing.T) {
+	before := time.Now()
+	out := fastrand.RandomizerString("{RAND;UUIDV7}")
+	require.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, out)
+	raw, err := hex.DecodeString(strings.ReplaceAll(out, "-", ""))
+	require.NoError(t, err)
+	checkUUIDv7(t, raw, before)
+
+	assert.Regexp(t, `^[0-9A-F-]{36}$`, fastrand.RandomizerString("{RAND;uuidv7(upper=true)}"))
+
+	bits, err := fastrand.TagEntropy("{RAND;UUIDV7}")
+	require.NoError(t, err)
+	assert.Equal(t, 74.0, bits)
+}
+
+func TestAllocsUUIDv7Keyword(t *testing.T) {
+	engine := fastrand.NewEngine()
+	payload := []byte("id={RAND;UUIDV7}")
+	dst := make([]byte, 0, 64)
+	dst = engine.RandomizerAppend(dst, payload)
+	allocs := testing.AllocsPerRun(100, func() {
+		dst = engine.RandomizerAppend(dst[:0], payload)
+	})
+	assert.Zero(t, allocs)
+}
+
+func TestUUIDv7KeywordClock(t *testing.T) {
+	at := time.Date(2030, time.June, 15, 12, 0, 0, 0, time.UTC)
+	engine := fastrand.NewEngine(fastrand.WithClock(func() time.Time { return at }))
+	raw, err := hex.DecodeString(strings.ReplaceAll(engine.RandomizerString("{RAND;UUIDV7}"), "-", ""))
+	require.NoError(t, err)
+	var ms [8]byte
+	copy(ms[2:], raw[:6])
+	assert.Equal(t, uint64(at.UnixMilli()), binary.BigEndian.Uint64(ms[:]))
+
+	a := fastrand.NewEngine(fastrand.WithSeed(5))
+	b := fastrand.NewEngine(fastrand.WithSeed(5))
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, a.RandomizerString("{RAND;UUIDV7}"), b.RandomizerString("{RAND;UUIDV7}"),
+			"seeded engines generate identical UUIDs")
+	}
+}
@@ -0,0 +1,110 @@
+package fastrand
+
+import "bytes"
+
+// valueEncodingOrder lists the value encodings in the order they are
+// applied.
+var valueEncodingOrder = [...]RandomizerEncoding{
+	RandomizerEncodingXML, RandomizerEncodingSQL, RandomizerEncodingShell, RandomizerEncodingJSON,
+}
+
+// escapeValueFrom escapes the generated value appended to out since start
+// with the engine's value encodings.
+func (e *FastEngine) escapeValueFrom(out *[]byte, start int) {
+	for _, enc := range valueEncodingOrder {
+		if e.outputEncoding&enc == 0 || valueSafe((*out)[start:], enc) {
+			continue
+		}
+		scratch := e.bufferPool.Get(len(*out) - start)
+		*scratch = append((*scratch)[:0], (*out)[start:]...)
+		*out = (*out)[:start]
+		appendValueEscaped(out, *scratch, enc)
+		e.bufferPool.Put(scratch)
+	}
+}
+
+// valueSafe reports whether value needs no escaping in a value encoding.
+func valueSafe(value []byte, encoding RandomizerEncoding) bool {
+	switch encoding {
+	case RandomizerEncodingXML:
+		return xmlSafe(value)
+	case RandomizerEncodingSQL:
+		return sqlSafe(value)
+	case RandomizerEncodingShell:
+		return shellSafe(value)
+	default:
+		return jsonSafe(value)
+	}
+}
+
+// appendValueEscaped appends value escaped in a single value encoding.
+func appendValueEscaped(out *[]byte, value []byte, encoding RandomizerEncoding) {
+	switch encoding {
+	case RandomizerEncodingXML:
+		appendXMLEscaped(out, value)
+	case RandomizerEncodingSQL:
+		appendSQLEscaped(out, value)
+	case RandomizerEncodingShell:
+		appendShellQuoted(out, value)
+	default:
+		appendJSONEscaped(out, value)
+	}
+}
+
+// shellSafeChars are the bytes a shell word may hold without quoting.
+var shellSafeChars = func() (t [256]bool) {
+	for _, c := range []byte("ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789@%+=:,./_-") {
+		t[c] = true
+	}
+	return t
+}()
+
+// shellSafe reports whether b is a non-empty shell word that needs no
+// quoting.
+func shellSafe(b []byte) bool {
+	for _, c := range b {
+		if !shellSafeChars[c] {
+			return false
+		}
+	}
+	return len(b) > 0
+}
+
+// appendShellQuoted appends src single-quoted as one shell word. A single
+// quote ends the quoted string, is written escaped and reopens it, and NUL
+// bytes, which no shell argument can hold, are dropped.
+func appendShellQuoted(out *[]byte, src []byte) {
+	*out = append(*out, '\'')
+	for _, c := range src {
+		switch c {
+		case 0:
+		case '\'':
+			*out = append(*out, `'\''`...)
+		default:
+			*out = append(*out, c)
+		}
+	}
+	*out = append(*out, '\'')
+}
+
+// sqlSafe reports whether b can be placed in an SQL string literal
+// unchanged.
+func sqlSafe(b []byte) bool {
+	return !bytes.ContainsAny(b, "'\x00")
+}
+
+// appendSQLEscaped appends src escaped for use inside a single-quoted SQL
+// string literal: quotes are doubled and NUL bytes, which many databases
+// reject in text, are dropped. Backslashes are kept, as standard SQL
+// requires.
+func appendSQLEscaped(out *[]byte, src []byte) {
+	for _, c := range src {
+		switch c {
+		case 0:
+		case '\'':
+			*out = append(*out, '\'', '\'')
+		default:
+			*out = append(*out, c)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package fastrand_test
+
+import (
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+)
+
+func quoteEngine(enc fastrand.RandomizerEncoding, value string) *fastrand.FastEngine {
+	return fastrand.NewEngine(
+		fastrand.WithOutputEncoding(enc),
+		fastrand.WithCustomKeyword("Q", func(int) []byte { return []byte(value) }),
+	)
+}
+
+func TestOutputEncodingShell(t *testing.T) {
+	cases := map[string]string{
+		"abc-1.2_x":        "abc-1.2_x",
+		"":                 "''",
+		"a b":              "'a b'",
+		"it's; rm -rf /":   `'it'\''s; rm -rf /'`,
+		"$(id)`id`\x00\n*": "'$(id)`id`\n*'",
+	}
+	for value, want := range cases {
+		assert.Equal(t, "echo "+want+" done", quoteEngine(fastrand.RandomizerEncodingShell, value).RandomizerString("echo {RAND;Q} done"), value)
+	}
+	engine := fastrand.NewEngine(fastrand.WithOutputEncoding(fastrand.RandomizerEncodingShell))
+	assert.Regexp(t, `^touch [a-z]{8}$`, engine.RandomizerString("touch {RAND;8;ABL}"))
+}
+
+func TestOutputEncodingSQL(t *testing.T) {
+	engine := quoteEngine(fastrand.RandomizerEncodingSQL, "O'Brien\x00\\")
+	assert.Equal(t, `SELECT 'O''Brien\' -- it's`, engine.RandomizerString("SELECT '{RAND;Q}' -- it's"))
+
+	engine = fastrand.NewEngine(fastrand.WithOutputEncoding(fastrand.RandomizerEncodingSQL))
+	for i := 0; i < 50; i++ {
+		out := engine.RandomizerString("'{RAND;64;BYTES}'")
+		inner := out[1 : len(out)-1]
+		assert.NotContains(t, inner, "\x00")
+		for j := 0; j < len(inner); j++ {
+			if inner[j] == '\'' {
+				assert.Equal(t, byte('\''), inner[j+1], "quotes are doubled in %q", out)
+				j++
+			}
+		}
+	}
+}
+
+func TestOutputEncodingShellSQLOrder(t *testing.T) {
+	engine := quoteEngine(fastrand.RandomizerEncodingSQL|fastrand.RandomizerEncodingShell, "a'b")
+	assert.Equal(t, `'a'\'''\''b'`, engine.RandomizerString("{RAND;Q}"), "SQL escaping comes before shell quoting")
+	assert.Equal(t, []string{"shell", "sql"}, engine.Config().OutputEncodings)
+}
@@ -0,0 +1,106 @@
+package fastrand
+
+import "bytes"
+
+var (
+	refTag    = []byte("{REF")
+	varPrefix = []byte("VAR=")
+)
+
+// variable is a value captured by a VAR= tag for later {REF;name} tags.
+type variable struct {
+	name  string
+	value []byte
+}
+
+// setVar stores a copy of value under name, replacing any earlier value.
+func (x *expansion) setVar(name, value []byte) {
+	for i := range x.vars {
+		if x.vars[i].name == string(name) {
+			x.vars[i].value = append(x.vars[i].value[:0], value...)
+			return
+		}
+	}
+	x.vars = append(x.vars, variable{name: string(name), value: bytes.Clone(value)})
+}
+
+func (x *expansion) lookupVar(name []byte) ([]byte, bool) {
+	for i := range x.vars {
+		if x.vars[i].name == string(name) {
+			return x.vars[i].value, true
+		}
+	}
+	return nil, false
+}
+
+// splitVariable splits a trailing "VAR=name" segment off a tag body (the
+// text after "{RAND;") and returns the remaining body and the name, which
+// is nil when there is no such segment.
+func splitVariable(body []byte) ([]byte, []byte) {
+	return splitNamed(body, varPrefix)
+}
+
+// splitNamed splits a trailing segment that starts with prefix, such as
+// "VAR=", off a tag body and returns the remaining body and the rest of the
+// segment, which is nil when there is no such segment.
+func splitNamed(body, prefix []byte) ([]byte, []byte) {
+	seg := body
+	sepIndex := bytes.LastIndexByte(body, sepTag)
+	if sepIndex != -1 {
+		seg = body[sepIndex+1:]
+	}
+	if len(seg) < len(prefix) || !bytes.EqualFold(seg[:len(prefix)], prefix) {
+		return body, nil
+	}
+	name := seg[len(prefix):]
+	if sepIndex == -1 {
+		return body[:0], name
+	}
+	return body[:sepIndex], name
+}
+
+// refName returns the variable name of a reference tag, which starts with
+// "{REF" and excludes the closing brace.
+func refName(tag []byte) ([]byte, bool) {
+	name := tag[len(refTag):]
+	if len(name) < 2 || name[0] != sepTag {
+		return nil, false
+	}
+	return name[1:], true
+}
+
+// nextTag returns the offset of the next tag in payload at or after cursor,
+// or -1, and whether it is a {REF;name} reference. References are only
+// looked for when refs is set; refIndex caches the position of the next
+// one between calls and must start at -1.
+func nextTag(payload []byte, cursor int, refs bool, refIndex *int) (int, bool) {
+	startIndex := bytes.Index(payload[cursor:], startTag)
+	if startIndex != -1 {
+		startIndex += cursor
+	}
+	if !refs {
+		return startIndex, false
+	}
+	if *refIndex < cursor {
+		*refIndex = len(payload)
+		if i := bytes.Index(payload[cursor:], refTag); i != -1 {
+			*refIndex = cursor + i
+		}
+	}
+	if *refIndex < len(payload) && (startIndex == -1 || *refIndex < startIndex) {
+		return *refIndex, true
+	}
+	return startIndex, false
+}
+
+// appendRef appends the value of the variable a reference tag names, or the
+// tag itself when it is malformed or the variable has not been set.
+func (e *FastEngine) appendRef(out *[]byte, tag, text []byte, x *expansion) {
+	if name, ok := refName(tag); ok {
+		if value, ok := x.lookupVar(name); ok {
+			*out = append(*out, value...)
+			return
+		}
+	}
+	e.writeLiteral(out, text, x)
+}
@@ -0,0 +1,100 @@
+package fastrand_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var sessionPattern = regexp.MustCompile(`^Cookie: sid=([0-9a-f]{32})\n\{"sid":"([0-9a-f]{32})"\}$`)
+
+const sessionPayload = "Cookie: sid={RAND;16;HEX;VAR=sid}\n{\"sid\":\"{REF;sid}\"}"
+
+func assertSameSession(t *testing.T, out string) {
+	t.Helper()
+	m := sessionPattern.FindStringSubmatch(out)
+	if assert.NotNil(t, m, out) {
+		assert.Equal(t, m[1], m[2])
+	}
+}
+
+func TestVariables(t *testing.T) {
+	assertSameSession(t, fastrand.RandomizerString(sessionPayload))
+
+	out := fastrand.RandomizerString("{RAND;8;ABL;VAR=a}-{RAND;8;ABL;VAR=b}-{REF;b}-{REF;a}")
+	parts := strings.Split(out, "-")
+	require.Len(t, parts, 4)
+	assert.Equal(t, parts[0], parts[3])
+	assert.Equal(t, parts[1], parts[2])
+}
+
+func TestVariablesPerInvocation(t *testing.T) {
+	first := fastrand.RandomizerString("{RAND;8;DIGIT;VAR=n}")
+	assert.Len(t, first, 8)
+	assert.Equal(t, "{REF;n}", fastrand.RandomizerString("{REF;n}"))
+}
+
+func TestVariablesUndefined(t *testing.T) {
+	out := fastrand.RandomizerString("{REF;x}{RAND;4;DIGIT;VAR=y}{REF;x}{REF}")
+	assert.Regexp(t, `^\{REF;x\}[0-9]{4}\{REF;x\}\{REF\}$`, out)
+}
+
+func TestVariablesRedefined(t *testing.T) {
+	out := fastrand.RandomizerString("{RAND;4;ABL;VAR=v}{RAND;4;DIGIT;VAR=v}={REF;v}")
+	assert.Regexp(t, `^[a-z]{4}([0-9]{4})=[0-9]{4}$`, out)
+	assert.Equal(t, out[4:8], out[9:])
+}
+
+func TestVariablesDefaultTag(t *testing.T) {
+	out := fastrand.RandomizerString("{RAND;UUID;VAR=id}/{REF;id}")
+	parts := strings.Split(out, "/")
+	require.Len(t, parts, 2)
+	assert.Len(t, parts[0], 36)
+	assert.Equal(t, parts[0], parts[1])
+}
+
+func TestVariablesTemplate(t *testing.T) {
+	tmpl, err := fastrand.Compile([]byte(sessionPayload))
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		assertSameSession(t, tmpl.ExecuteString())
+	}
+}
+
+func TestVariablesStream(t *testing.T) {
+	padding := strings.Repeat(".", 40<<10)
+	payload := sessionPayload + padding + "{REF;sid}"
+	out, err := io.ReadAll(fastrand.RandomizerReader(iotest.OneByteReader(strings.NewReader(payload))))
+	require.NoError(t, err)
+	head, tail, found := bytes.Cut(out, []byte(padding))
+	require.True(t, found)
+	assertSameSession(t, string(head))
+	assert.Equal(t, head[len(head)-34:len(head)-2], tail)
+}
+
+func TestVariablesStrict(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithStrictParsing(true))
+	_, err := engine.RandomizerErr([]byte(sessionPayload))
+	assert.NoError(t, err)
+
+	cases := map[string]string{
+		"{REF;sid}{RAND;8;HEX;VAR=sid}": `undefined variable "sid"`,
+		"{RAND;8;HEX;VAR=}":             "empty variable name",
+		"{REF}":                         "variable name",
+	}
+	for payload, reason := range cases {
+		_, err := engine.RandomizerErr([]byte(payload))
+		var tagErr *fastrand.TagError
+		if assert.True(t, errors.As(err, &tagErr), payload) {
+			assert.Contains(t, tagErr.Reason, reason, payload)
+		}
+	}
+}
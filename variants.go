@@ -0,0 +1,52 @@
+package fastrand
+
+import (
+	"bytes"
+	"iter"
+)
+
+// Variants ranges over expansions of payload with the default engine. See
+// FastEngine.Variants.
+func Variants(payload []byte, n int) iter.Seq[[]byte] {
+	return defaultEngine.Load().Variants(payload, n)
+}
+
+// Variants returns an iterator over n independent expansions of payload,
+// each produced only when the loop asks for it:
+//
+//	for v := range engine.Variants(payload, 1000) {
+//		send(v)
+//	}
+//
+// The payload is normalized once and every expansion is written into the
+// same pooled buffer, so a yielded slice is only valid until the next
+// iteration; use bytes.Clone to keep one. As with Randomizer, a payload
+// without tags may be yielded as is. Breaking out of the loop stops the
+// expansions early.
+func (e *FastEngine) Variants(payload []byte, n int) iter.Seq[[]byte] {
+	return func(yield func([]byte) bool) {
+		if n <= 0 {
+			return
+		}
+		if !bytes.ContainsAny(payload, tagChars) && !e.transformsPayload() {
+			for range n {
+				if !yield(payload) {
+					return
+				}
+			}
+			return
+		}
+		normalized, scratch := e.normalized(payload)
+		defer e.release(scratch)
+		buf := e.bufferPool.Get(e.sizeHint(len(normalized)))
+		defer e.bufferPool.Put(buf)
+		for range n {
+			*buf = (*buf)[:0]
+			e.randomizerInto(normalized, buf)
+			e.recordSize(len(*buf))
+			if !yield(*buf) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package fastrand_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVariants(t *testing.T) {
+	seen := make(map[string]bool)
+	count := 0
+	for v := range fastrand.Variants([]byte("id={RAND;16;HEX}"), 100) {
+		require.Regexp(t, `^id=[0-9a-f]{32}$`, string(v))
+		seen[string(v)] = true
+		count++
+	}
+	assert.Equal(t, 100, count)
+	assert.Len(t, seen, 100, "every variant is expanded independently")
+}
+
+func TestVariants_Break(t *testing.T) {
+	count := 0
+	for range fastrand.NewEngine().Variants([]byte("{RAND;8;DIGIT}"), 1000) {
+		count++
+		if count == 3 {
+			break
+		}
+	}
+	assert.Equal(t, 3, count)
+}
+
+func TestVariants_NoTags(t *testing.T) {
+	var got [][]byte
+	for v := range fastrand.Variants([]byte("plain"), 3) {
+		got = append(got, v)
+	}
+	assert.Equal(t, [][]byte{[]byte("plain"), []byte("plain"), []byte("plain")}, got)
+
+	for range fastrand.Variants([]byte("{RAND}"), 0) {
+		t.Fatal("n of 0 yields nothing")
+	}
+}
+
+func TestVariants_Deterministic(t *testing.T) {
+	collect := func() []string {
+		var out []string
+		for v := range fastrand.NewEngine(fastrand.WithSeed(7)).Variants([]byte("{RAND;8;ABR}-{RAND;1-9;DIGIT}"), 5) {
+			out = append(out, string(bytes.Clone(v)))
+		}
+		return out
+	}
+	assert.Equal(t, collect(), collect())
+}
+
+func TestAllocsVariants(t *testing.T) {
+	engine := fastrand.NewEngine()
+	payload := []byte("id={RAND;16;HEX}&n={RAND;1-9;DIGIT}")
+	seq := engine.Variants(payload, 100)
+	for range seq {
+	}
+	allocs := testing.AllocsPerRun(20, func() {
+		for range seq {
+		}
+	})
+	assert.LessOrEqual(t, allocs, 1.0)
+}
@@ -0,0 +1,17 @@
+package fastrand
+
+import "bytes"
+
+// splitWeight splits a trailing ":weight" off a choice such as "ABL:3" and
+// reports whether one was present. The weight is a non-negative integer.
+func splitWeight(choice []byte) ([]byte, int, bool) {
+	i := bytes.LastIndexByte(choice, ':')
+	if i <= 0 {
+		return choice, 1, false
+	}
+	weight, ok := parseLengthFast(choice[i+1:])
+	if !ok {
+		return choice, 1, false
+	}
+	return choice[:i], weight, true
+}
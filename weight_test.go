@@ -0,0 +1,79 @@
+package fastrand_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWeightedKeywordChoices(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithSeed(1))
+	digits := regexp.MustCompile(`^[0-9]{16}$`)
+	lower := regexp.MustCompile(`^[a-z]{16}$`)
+
+	const n = 4000
+	var abl, digit int
+	for i := 0; i < n; i++ {
+		out := engine.RandomizerString("{RAND;16;ABL:3,DIGIT:1}")
+		switch {
+		case lower.MatchString(out):
+			abl++
+		case digits.MatchString(out):
+			digit++
+		default:
+			t.Fatalf("unexpected output %q", out)
+		}
+	}
+	assert.InDelta(t, 0.75, float64(abl)/n, 0.03)
+	assert.InDelta(t, 0.25, float64(digit)/n, 0.03)
+}
+
+func TestWeightedKeywordChoicesZeroWeight(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		require.Regexp(t, `^[0-9]{8}$`, fastrand.RandomizerString("{RAND;8;ABL:0,DIGIT:5}"))
+	}
+}
+
+func TestWeightedKeywordChoicesWithArgument(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithCycle("env", "dev"))
+	for i := 0; i < 50; i++ {
+		require.Equal(t, "dev", engine.RandomizerString("{RAND;4;CYCLE:env:1,DIGIT:0}"))
+	}
+}
+
+func TestWeightedKeywordChoicesStrict(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithStrictParsing(true))
+	_, err := engine.RandomizerErr([]byte("{RAND;16;ABL:3,DIGIT:1}"))
+	assert.NoError(t, err)
+	_, err = engine.RandomizerErr([]byte("{RAND;16;ABL:3,NOPE:1}"))
+	assert.ErrorContains(t, err, `unknown keyword "NOPE"`)
+}
+
+func TestWeightedLengthChoices(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithSeed(2))
+	const n = 4000
+	counts := map[int]int{}
+	for i := 0; i < n; i++ {
+		out := engine.RandomizerString("{RAND;8:70,64:30;HEX}")
+		counts[len(out)]++
+	}
+	require.Len(t, counts, 2)
+	assert.InDelta(t, 0.70, float64(counts[16])/n, 0.03)
+	assert.InDelta(t, 0.30, float64(counts[128])/n, 0.03)
+}
+
+func TestWeightedLengthChoicesMixed(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		out := fastrand.RandomizerString("{RAND;4:0,6,9:2;DIGIT}")
+		require.Regexp(t, `^([0-9]{6}|[0-9]{9})$`, out)
+	}
+}
+
+func TestWeightedLengthChoicesEntropy(t *testing.T) {
+	bits, err := fastrand.TagEntropy("{RAND;8:70,64:30;HEX}")
+	require.NoError(t, err)
+	assert.Equal(t, 64.0, bits)
+}
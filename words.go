@@ -0,0 +1,157 @@
+package fastrand
+
+import (
+	_ "embed"
+	"strings"
+)
+
+//go:embed adjectives.txt
+var adjectivesList string
+
+//go:embed firstnames.txt
+var firstNamesList string
+
+//go:embed nouns.txt
+var nounsList string
+
+//go:embed surnames.txt
+var surnamesList string
+
+//go:embed verbs.txt
+var verbsList string
+
+//go:embed words.txt
+var fillerWordsList string
+
+var (
+	adjectives = parseLines(adjectivesList)
+	firstNames = parseLines(firstNamesList)
+	nouns      = parseLines(nounsList)
+	surnames   = parseLines(surnamesList)
+	verbs      = parseLines(verbsList)
+)
+
+// fillerWords are the common English words of WORD, WORDS and SENTENCE.
+var fillerWords = parseLines(fillerWordsList)
+
+// k8sSuffixChars is the alphabet Kubernetes uses for generateName suffixes;
+// it has no vowels or easily confused characters, so suffixes never spell
+// words.
+var k8sSuffixChars = CharsList("bcdfghjklmnpqrstvwxz2456789")
+
+// parseLines splits s into trimmed, non-empty lines.
+func parseLines(s string) []string {
+	var out []string
+	for _, line := range strings.Split(s, "\n") {
+		if trimmed := strings.TrimSpace(line); trimmed != "" {
+			out = append(out, trimmed)
+		}
+	}
+	return out
+}
+
+func pickWord(next func() uint64, words []string) string {
+	return words[int(uint64N(next, uint64(len(words))))]
+}
+
+// K8sName returns a Kubernetes-style resource name such as
+// "brave-otter-x7k2p". Names are valid DNS-1123 labels: lowercase
+// alphanumerics and '-', starting and ending with an alphanumeric, at most
+// 63 characters.
+func K8sName() string {
+	var out []byte
+	appendK8sName(fastUint64, &out)
+	return unsafeString(out)
+}
+
+func appendK8sName(next func() uint64, out *[]byte) {
+	*out = append(*out, pickWord(next, adjectives)...)
+	*out = append(*out, '-')
+	*out = append(*out, pickWord(next, nouns)...)
+	*out = append(*out, '-')
+	start := len(*out)
+	ensureCap(out, start+5)
+	*out = (*out)[:start+5]
+	fillStringInto(next, (*out)[start:], k8sSuffixChars, len(k8sSuffixChars))
+}
+
+// DockerName returns a Docker-style container name such as
+// "brave_lovelace": an adjective and a surname joined by an underscore.
+func DockerName() string {
+	var out []byte
+	appendDockerName(fastUint64, &out)
+	return unsafeString(out)
+}
+
+// DockerNameUnique returns a DockerName for which exists reports false. When
+// the plain name is taken a random numeric suffix is appended, widening the
+// suffix range every ten attempts, so it terminates as long as exists does
+// not reject every name.
+func DockerNameUnique(exists func(name string) bool) string {
+	var out []byte
+	appendDockerName(fastUint64, &out)
+	if !exists(unsafeString(out)) {
+		return unsafeString(out)
+	}
+	base := len(out)
+	limit := uint64(10)
+	for attempt := 1; ; attempt++ {
+		out = strconvAppendUint(out[:base], uint64N(fastUint64, limit), 10)
+		if !exists(string(out)) {
+			return string(out)
+		}
+		if attempt%10 == 0 && limit < 1e18 {
+			limit *= 10
+		}
+	}
+}
+
+func appendDockerName(next func() uint64, out *[]byte) {
+	*out = append(*out, pickWord(next, adjectives)...)
+	*out = append(*out, '_')
+	*out = append(*out, pickWord(next, surnames)...)
+}
+
+func (e *FastEngine) appendK8sName(out *[]byte) {
+	appendK8sName(e.next, out)
+}
+
+func (e *FastEngine) appendWord(out *[]byte, words []string) {
+	*out = append(*out, pickWord(e.next, words)...)
+}
+
+// Words returns n random common English words separated by spaces, such as
+// "river open light".
+func Words(n int) string {
+	var out []byte
+	appendWords(fastUint64, &out, n)
+	return unsafeString(out)
+}
+
+// Sentence returns a sentence of n random common English words: the first
+// capitalized and the last followed by a period, such as "River open
+// light."
+func Sentence(n int) string {
+	var out []byte
+	appendSentence(fastUint64, &out, n)
+	return unsafeString(out)
+}
+
+func appendWords(next func() uint64, out *[]byte, n int) {
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			*out = append(*out, ' ')
+		}
+		*out = append(*out, pickWord(next, fillerWords)...)
+	}
+}
+
+func appendSentence(next func() uint64, out *[]byte, n int) {
+	if n <= 0 {
+		return
+	}
+	start := len(*out)
+	appendWords(next, out, n)
+	(*out)[start] -= 'a' - 'A'
+	*out = append(*out, '.')
+}
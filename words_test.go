@@ -0,0 +1,100 @@
+package fastrand_test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var dns1123Label = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+func TestK8sName(t *testing.T) {
+	seen := map[string]bool{}
+	for i := 0; i < 1000; i++ {
+		name := fastrand.K8sName()
+		assert.Regexp(t, dns1123Label, name)
+		assert.LessOrEqual(t, len(name), 63)
+		assert.Regexp(t, `^[a-z]+-[a-z]+-[bcdfghjklmnpqrstvwxz2456789]{5}$`, name)
+		seen[name] = true
+	}
+	assert.Greater(t, len(seen), 990, "names should rarely repeat")
+}
+
+func TestRandomizerK8sNameKeyword(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		result := fastrand.RandomizerString("pod/{RAND;k8sname}")
+		assert.Regexp(t, `^pod/[a-z]+-[a-z]+-[a-z0-9]{5}$`, result)
+	}
+
+	a := fastrand.NewEngine(fastrand.WithSeed(3)).RandomizerString("{RAND;K8SNAME}")
+	b := fastrand.NewEngine(fastrand.WithSeed(3)).RandomizerString("{RAND;K8SNAME}")
+	assert.Equal(t, a, b)
+}
+
+func TestDockerName(t *testing.T) {
+	for i := 0; i < 500; i++ {
+		assert.Regexp(t, `^[a-z]+_[a-z]+$`, fastrand.DockerName())
+	}
+}
+
+func TestDockerNameUnique(t *testing.T) {
+	taken := map[string]bool{}
+	for i := 0; i < 2000; i++ {
+		name := fastrand.DockerNameUnique(func(name string) bool { return taken[name] })
+		assert.Regexp(t, `^[a-z]+_[a-z]+[0-9]*$`, name)
+		assert.False(t, taken[name], "name %q was already taken", name)
+		taken[name] = true
+	}
+}
+
+func TestRandomizerWordKeywords(t *testing.T) {
+	for i := 0; i < 200; i++ {
+		result := fastrand.RandomizerString("{RAND;ADJ}-{RAND;NOUN}-{RAND;4;DIGIT}")
+		assert.Regexp(t, `^[a-z]+-[a-z]+-[0-9]{4}$`, result)
+		assert.Regexp(t, `^[a-z]+$`, fastrand.RandomizerString("{RAND;10;verb}"))
+	}
+
+	engine := fastrand.NewEngine(fastrand.WithDisabledKeywords("NOUN"))
+	assert.Len(t, engine.RandomizerString("{RAND;7;NOUN}"), 7, "a disabled keyword falls back to a random string")
+}
+
+func TestWords(t *testing.T) {
+	assert.Empty(t, fastrand.Words(0))
+	assert.Empty(t, fastrand.Sentence(0))
+	for i := 0; i < 100; i++ {
+		assert.Len(t, strings.Fields(fastrand.Words(7)), 7)
+		assert.Regexp(t, `^[A-Z][a-z]*( [a-z]+){3}\.$`, fastrand.Sentence(4))
+	}
+}
+
+func TestWordKeywords(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		assert.Regexp(t, `^[a-z]+$`, fastrand.RandomizerString("{RAND;WORD}"))
+		assert.Regexp(t, `^[a-z]+( [a-z]+){4}$`, fastrand.RandomizerString("{RAND;5;WORDS}"))
+		assert.Regexp(t, `^[A-Z][a-z]*( [a-z]+){2,7}\.$`, fastrand.RandomizerString("{RAND;3-8;SENTENCE}"))
+	}
+	assert.Len(t, strings.Fields(fastrand.RandomizerString("{RAND;WORDS}")), 16, "the default length is the word count")
+	assert.NotEqual(t, fastrand.RandomizerString("{RAND;8;WORDS}"), fastrand.RandomizerString("{RAND;8;WORDS}"))
+
+	one, err := fastrand.TagEntropy("{RAND;WORD}")
+	require.NoError(t, err)
+	assert.Greater(t, one, 8.0)
+	five, err := fastrand.TagEntropy("{RAND;5;WORDS}")
+	require.NoError(t, err)
+	assert.InDelta(t, 5*one, five, 1e-9)
+}
+
+func TestAllocsWordKeywords(t *testing.T) {
+	engine := fastrand.NewEngine()
+	payload := []byte(`{"title":"{RAND;3;WORDS}","body":"{RAND;12;SENTENCE} {RAND;8;SENTENCE}"}`)
+	dst := make([]byte, 0, 512)
+	dst = engine.RandomizerAppend(dst, payload)
+	allocs := testing.AllocsPerRun(100, func() {
+		dst = engine.RandomizerAppend(dst[:0], payload)
+	})
+	assert.Zero(t, allocs)
+}
@@ -0,0 +1,121 @@
+package fastrand
+
+// maxXMLKeywordDepth caps the depth of {RAND;n;XML} so the default length
+// does not produce very large documents.
+const maxXMLKeywordDepth = 6
+
+// XML returns a random well-formed XML document at most depth levels deep
+// in which every element has up to breadth child elements. Element names
+// are random lowercase identifiers.
+func XML(depth, breadth int) []byte {
+	return XMLWithNames(depth, breadth, nil)
+}
+
+// XMLWithNames is like XML but draws element names from names when it is
+// not empty. The names must be valid XML names. Attribute names are always
+// random.
+func XMLWithNames(depth, breadth int, names []string) []byte {
+	if depth <= 0 {
+		panic("fastrand: XML depth must be positive")
+	}
+	if breadth < 0 {
+		panic("fastrand: XML breadth cannot be negative")
+	}
+	var out []byte
+	appendXML(fastUint64, &out, depth, breadth, names)
+	return out
+}
+
+func appendXML(next func() uint64, out *[]byte, depth, breadth int, names []string) {
+	x := xmlWriter{next: next, out: out, breadth: breadth, names: names}
+	x.element(depth, true)
+}
+
+type xmlWriter struct {
+	next    func() uint64
+	out     *[]byte
+	breadth int
+	names   []string
+}
+
+func (x *xmlWriter) element(depth int, root bool) {
+	nameStart := len(*x.out) + 1
+	*x.out = append(*x.out, '<')
+	x.name()
+	nameEnd := len(*x.out)
+
+	attrs := int(uint64N(x.next, 3))
+	for i := 0; i < attrs; i++ {
+		*x.out = append(*x.out, ' ')
+		x.attrName(i)
+		*x.out = append(*x.out, '=', '"')
+		x.text()
+		*x.out = append(*x.out, '"')
+	}
+
+	children := 0
+	if depth > 1 && x.breadth > 0 {
+		children = int(uint64N(x.next, uint64(x.breadth)+1))
+		if root && children == 0 {
+			children = 1
+		}
+	}
+	if children == 0 && uint64N(x.next, 4) == 0 {
+		*x.out = append(*x.out, '/', '>')
+		return
+	}
+	*x.out = append(*x.out, '>')
+	if children == 0 {
+		x.text()
+	}
+	for i := 0; i < children; i++ {
+		x.element(depth-1, false)
+	}
+	*x.out = append(*x.out, '<', '/')
+	*x.out = append(*x.out, (*x.out)[nameStart:nameEnd]...)
+	*x.out = append(*x.out, '>')
+}
+
+func (x *xmlWriter) name() {
+	if len(x.names) > 0 {
+		*x.out = append(*x.out, x.names[int(uint64N(x.next, uint64(len(x.names))))]...)
+		return
+	}
+	n := 3 + int(uint64N(x.next, 6))
+	start := len(*x.out)
+	ensureCap(x.out, start+n)
+	*x.out = (*x.out)[:start+n]
+	b := (*x.out)[start:]
+	fillStringInto(x.next, b[:1], CharsAlphabetLower, len(CharsAlphabetLower))
+	fillStringInto(x.next, b[1:], CharsAlphabetDigits, len(CharsAlphabetDigits))
+}
+
+// attrName appends a random attribute name ending in the attribute index,
+// so names within one element never collide.
+func (x *xmlWriter) attrName(i int) {
+	n := 2 + int(uint64N(x.next, 5))
+	start := len(*x.out)
+	ensureCap(x.out, start+n+1)
+	*x.out = (*x.out)[:start+n]
+	fillStringInto(x.next, (*x.out)[start:], CharsAlphabetLower, len(CharsAlphabetLower))
+	*x.out = append(*x.out, byte('0'+i))
+}
+
+// text appends random character data that needs no escaping.
+func (x *xmlWriter) text() {
+	n := 1 + int(uint64N(x.next, 12))
+	start := len(*x.out)
+	ensureCap(x.out, start+n)
+	*x.out = (*x.out)[:start+n]
+	fillStringInto(x.next, (*x.out)[start:], xmlTextChars, len(xmlTextChars))
+}
+
+var xmlTextChars = append(CharsList(" "), CharsAlphabetDigits...)
+
+func (e *FastEngine) appendXML(out *[]byte, length int) {
+	depth := length
+	if depth > maxXMLKeywordDepth {
+		depth = maxXMLKeywordDepth
+	}
+	appendXML(e.next, out, depth, 3, e.xmlNames)
+}
@@ -0,0 +1,88 @@
+package fastrand_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// xmlShape parses doc and returns its maximum element depth, the largest
+// number of direct children of any element, and the element names seen.
+func xmlShape(tb testing.TB, doc []byte) (depth, breadth int, names map[string]bool) {
+	tb.Helper()
+	names = map[string]bool{}
+	dec := xml.NewDecoder(bytes.NewReader(doc))
+	var stack []int
+	roots := 0
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(tb, err, "document should be well-formed: %s", doc)
+		switch el := tok.(type) {
+		case xml.StartElement:
+			names[el.Name.Local] = true
+			if len(stack) > 0 {
+				stack[len(stack)-1]++
+				breadth = max(breadth, stack[len(stack)-1])
+			} else {
+				roots++
+			}
+			stack = append(stack, 0)
+			depth = max(depth, len(stack))
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		}
+	}
+	require.Equal(tb, 1, roots, "document should have exactly one root element")
+	return depth, breadth, names
+}
+
+func TestXML(t *testing.T) {
+	for i := 0; i < 200; i++ {
+		doc := fastrand.XML(4, 3)
+		depth, breadth, _ := xmlShape(t, doc)
+		assert.LessOrEqual(t, depth, 4)
+		assert.LessOrEqual(t, breadth, 3)
+	}
+
+	depth, _, _ := xmlShape(t, fastrand.XML(1, 5))
+	assert.Equal(t, 1, depth, "depth 1 is a single element")
+
+	assert.Panics(t, func() { fastrand.XML(0, 1) })
+	assert.Panics(t, func() { fastrand.XML(1, -1) })
+}
+
+func TestXMLWithNames(t *testing.T) {
+	pool := []string{"order", "item", "price"}
+	for i := 0; i < 100; i++ {
+		_, _, names := xmlShape(t, fastrand.XMLWithNames(3, 4, pool))
+		for name := range names {
+			assert.Contains(t, pool, name)
+		}
+	}
+}
+
+func TestRandomizerXMLKeyword(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithXMLElementNames("envelope", "body", "field"))
+	for i := 0; i < 100; i++ {
+		result := engine.Randomizer([]byte("{RAND;3;XML}"))
+		depth, _, names := xmlShape(t, result)
+		assert.LessOrEqual(t, depth, 3)
+		for name := range names {
+			assert.Contains(t, []string{"envelope", "body", "field"}, name)
+		}
+	}
+
+	depth, _, _ := xmlShape(t, fastrand.Randomizer([]byte("{RAND;XML}")))
+	assert.LessOrEqual(t, depth, 6, "default length is capped to a modest depth")
+
+	soap := fastrand.RandomizerString("<soap>{RAND;2;XML}</soap>")
+	xmlShape(t, []byte(soap))
+}
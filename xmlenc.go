@@ -0,0 +1,68 @@
+package fastrand
+
+import "unicode/utf8"
+
+// xmlEscapes maps the ASCII bytes that need escaping in XML text and
+// attribute values to their entity.
+var xmlEscapes = [utf8.RuneSelf]string{
+	'<': "&lt;", '>': "&gt;", '&': "&amp;", '"': "&quot;", '\'': "&apos;",
+}
+
+// xmlDropped reports whether the ASCII byte c is not an XML character.
+func xmlDropped(c byte) bool {
+	return c < 0x20 && c != '\t' && c != '\n' && c != '\r'
+}
+
+// xmlSafe reports whether b can be placed in XML text or an attribute value
+// unchanged.
+func xmlSafe(b []byte) bool {
+	for i := 0; i < len(b); {
+		c := b[i]
+		if c < utf8.RuneSelf {
+			if xmlEscapes[c] != "" || xmlDropped(c) {
+				return false
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRune(b[i:])
+		if !xmlChar(r, size) {
+			return false
+		}
+		i += size
+	}
+	return true
+}
+
+// xmlChar reports whether the non-ASCII rune r, decoded from size bytes,
+// is allowed in XML 1.0. Bytes that are not valid UTF-8 are not.
+func xmlChar(r rune, size int) bool {
+	if r == utf8.RuneError && size == 1 {
+		return false
+	}
+	return r <= 0xd7ff || r >= 0xe000 && r <= 0xfffd || r >= 0x10000
+}
+
+// appendXMLEscaped appends src escaped for use in XML text or attribute
+// values. Markup characters and both quotes become entities, valid UTF-8 is
+// kept, and control bytes, U+FFFE, U+FFFF and bytes that are not valid
+// UTF-8 are dropped so the document stays well formed.
+func appendXMLEscaped(out *[]byte, src []byte) {
+	for i := 0; i < len(src); {
+		c := src[i]
+		if c < utf8.RuneSelf {
+			if esc := xmlEscapes[c]; esc != "" {
+				*out = append(*out, esc...)
+			} else if !xmlDropped(c) {
+				*out = append(*out, c)
+			}
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRune(src[i:])
+		if xmlChar(r, size) {
+			*out = append(*out, src[i:i+size]...)
+		}
+		i += size
+	}
+}
@@ -0,0 +1,40 @@
+package fastrand_test
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/obeliskdev/fastrand"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputEncodingXML(t *testing.T) {
+	engine := fastrand.NewEngine(fastrand.WithOutputEncoding(fastrand.RandomizerEncodingXML))
+	payload := []byte(`<env a="{RAND;16;BYTES}"><raw>{RAND;64;BYTES}</raw><null>{RAND;32;NULL}</null><x>{RAND;XML}</x></env>`)
+	for i := 0; i < 200; i++ {
+		out := engine.Randomizer(payload)
+		var doc struct {
+			Raw string `xml:"raw"`
+		}
+		require.NoError(t, xml.Unmarshal(out, &doc), "%q", out)
+	}
+}
+
+func TestOutputEncodingXMLEscapes(t *testing.T) {
+	engine := fastrand.NewEngine(
+		fastrand.WithOutputEncoding(fastrand.RandomizerEncodingXML),
+		fastrand.WithCustomKeyword("Q", func(int) []byte { return []byte("<a href=\"x\">'&'</a>\x00\x01\t\né\xff￾") }),
+	)
+	assert.Equal(t, "<v>&lt;a href=&quot;x&quot;&gt;&apos;&amp;&apos;&lt;/a&gt;\t\né</v>", engine.RandomizerString("<v>{RAND;Q}</v>"))
+	assert.Regexp(t, `^<v>[a-z]{8} é</v>$`, engine.RandomizerString("<v>{RAND;8;ABL} é</v>"), "safe values are unchanged")
+}
+
+func TestOutputEncodingXMLWithJSON(t *testing.T) {
+	engine := fastrand.NewEngine(
+		fastrand.WithOutputEncodings(fastrand.RandomizerEncodingXML, fastrand.RandomizerEncodingJSON),
+		fastrand.WithCustomKeyword("Q", func(int) []byte { return []byte(`<"\`) }),
+	)
+	assert.Equal(t, `"&lt;&quot;\\"`, engine.RandomizerString(`"{RAND;Q}"`))
+	assert.Equal(t, []string{"json", "xml"}, engine.Config().OutputEncodings)
+}